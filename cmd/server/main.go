@@ -3,6 +3,10 @@ package main
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -20,10 +24,45 @@ func main() {
 	}
 
 	// Initialize audit client
-	auditClient := audit.NewClient(auditAPIURL)
+	clientOpts := audit.ClientOptions{
+		Timeout:     parseAuditTimeout(os.Getenv("AUDIT_API_TIMEOUT")),
+		BearerToken: os.Getenv("AUDIT_API_TOKEN"),
+	}
+	caFile, certFile, keyFile := os.Getenv("AUDIT_API_CA_FILE"), os.Getenv("AUDIT_API_CERT_FILE"), os.Getenv("AUDIT_API_KEY_FILE")
+	if caFile != "" || certFile != "" || keyFile != "" {
+		tlsConfig, err := audit.LoadTLSConfig(caFile, certFile, keyFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load audit API TLS configuration: %v\n", err)
+			os.Exit(1)
+		}
+		clientOpts.TLSConfig = tlsConfig
+	}
+	auditClient := audit.NewClientWithOptions(auditAPIURL, clientOpts)
+
+	// Load the optional namespace to team mapping
+	var teams tools.TeamMapping
+	if path := os.Getenv("MCP_TEAM_MAPPING"); path != "" {
+		var err error
+		teams, err = tools.LoadTeamMapping(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load MCP_TEAM_MAPPING: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Load the optional prompt templates, added to the built-in prompts
+	var promptTemplates []*prompts.Template
+	if path := os.Getenv("MCP_PROMPT_TEMPLATES"); path != "" {
+		var err error
+		promptTemplates, err = prompts.LoadTemplates(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load MCP_PROMPT_TEMPLATES: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	// Initialize handlers
-	toolHandlers := tools.NewToolHandlers(auditClient)
+	toolHandlers := tools.NewToolHandlers(auditClient, parseEventBudget(os.Getenv("MCP_EVENT_BUDGET")), teams)
 	resourceHandlers := resources.NewResourceHandlers(auditClient)
 	promptHandlers := prompts.NewPromptHandlers()
 
@@ -37,69 +76,97 @@ func main() {
 		server.WithInstructions("This server provides access to Kubernetes audit logs for incident investigation. Use the diagnostic tools to analyze cluster health, pod issues, volume problems, and recent changes. Prompt templates guide investigation workflows for common scenarios."),
 	)
 
-	// Register diagnostic tools
-	mcpServer.AddTool(
+	// Register diagnostic tools, limited to MCP_ENABLED_TOOLS when set
+	enabledTools := parseEnabledTools(os.Getenv("MCP_ENABLED_TOOLS"))
+	var registeredTools []string
+	addTool := func(tool mcp.Tool, handler server.ToolHandlerFunc) {
+		registeredTools = append(registeredTools, tool.Name)
+		if enabledTools.Enabled(tool.Name) {
+			mcpServer.AddTool(tool, handler)
+		}
+	}
+
+	// Shared by the tools that query a time range, as an alternative to
+	// start_time and end_time
+	durationParam := mcp.WithString("duration",
+		mcp.Description("Query the window ending now, e.g. 2h, 90m or 7d, instead of passing start_time and end_time, which take precedence when set"),
+	)
+
+	// Shared by the tools that can report their findings as JSON
+	formatParam := mcp.WithString("format",
+		mcp.Description("Output format: 'text' (default) for a readable report, or 'json' for the categorized findings with counts"),
+		mcp.Enum("text", "json"),
+	)
+
+	// Shared by the tools that truncate each category of findings
+	sortOrderParam := mcp.WithString("sort_order",
+		mcp.Description("Which findings to show first when a category is truncated: 'newest' (default) or 'oldest'"),
+		mcp.Enum("newest", "oldest"),
+	)
+
+	addTool(
 		mcp.NewTool("check_node_health",
 			mcp.WithDescription("Check for node health issues (NotReady, pressure, network, kubelet failures)"),
 			mcp.WithString("start_time",
-				mcp.Required(),
-				mcp.Description("Start time in RFC3339 format (e.g., 2024-01-01T00:00:00Z)"),
+				mcp.Description("Start time in RFC3339 format (e.g., 2024-01-01T00:00:00Z); optional when duration is set"),
 			),
 			mcp.WithString("end_time",
-				mcp.Required(),
-				mcp.Description("End time in RFC3339 format (e.g., 2024-01-01T23:59:59Z)"),
+				mcp.Description("End time in RFC3339 format (e.g., 2024-01-01T23:59:59Z); optional when duration is set"),
 			),
+			durationParam,
+			sortOrderParam,
+			formatParam,
 		),
 		toolHandlers.CheckNodeHealth,
 	)
 
-	mcpServer.AddTool(
+	addTool(
 		mcp.NewTool("check_pod_issues",
 			mcp.WithDescription("Analyze pod problems (CrashLoopBackOff, ImagePullBackOff, OOMKilled, probe failures)"),
 			mcp.WithString("start_time",
-				mcp.Required(),
-				mcp.Description("Start time in RFC3339 format"),
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
 			),
 			mcp.WithString("end_time",
-				mcp.Required(),
-				mcp.Description("End time in RFC3339 format"),
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
 			),
+			durationParam,
 			mcp.WithString("namespace",
 				mcp.Description("Kubernetes namespace to filter by (optional)"),
 			),
+			sortOrderParam,
+			formatParam,
 		),
 		toolHandlers.CheckPodIssues,
 	)
 
-	mcpServer.AddTool(
+	addTool(
 		mcp.NewTool("check_volume_issues",
 			mcp.WithDescription("Check volume and storage problems (PVC pending, binding failures, StorageClass errors)"),
 			mcp.WithString("start_time",
-				mcp.Required(),
-				mcp.Description("Start time in RFC3339 format"),
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
 			),
 			mcp.WithString("end_time",
-				mcp.Required(),
-				mcp.Description("End time in RFC3339 format"),
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
 			),
+			durationParam,
 			mcp.WithString("namespace",
 				mcp.Description("Kubernetes namespace to filter by (optional)"),
 			),
+			sortOrderParam,
 		),
 		toolHandlers.CheckVolumeIssues,
 	)
 
-	mcpServer.AddTool(
+	addTool(
 		mcp.NewTool("analyze_recent_changes",
 			mcp.WithDescription("Show recent resource modifications (deployments, configs, secrets, network policies)"),
 			mcp.WithString("start_time",
-				mcp.Required(),
-				mcp.Description("Start time in RFC3339 format"),
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
 			),
 			mcp.WithString("end_time",
-				mcp.Required(),
-				mcp.Description("End time in RFC3339 format"),
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
 			),
+			durationParam,
 			mcp.WithString("resource_types",
 				mcp.Description("Comma-separated list of resource types to filter (e.g., 'deployments,configmaps')"),
 			),
@@ -107,17 +174,16 @@ func main() {
 		toolHandlers.AnalyzeRecentChanges,
 	)
 
-	mcpServer.AddTool(
+	addTool(
 		mcp.NewTool("investigate_pod_startup",
 			mcp.WithDescription("Investigate why a specific pod won't start (image, secrets, volumes, init containers)"),
 			mcp.WithString("start_time",
-				mcp.Required(),
-				mcp.Description("Start time in RFC3339 format"),
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
 			),
 			mcp.WithString("end_time",
-				mcp.Required(),
-				mcp.Description("End time in RFC3339 format"),
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
 			),
+			durationParam,
 			mcp.WithString("pod_name",
 				mcp.Required(),
 				mcp.Description("Name of the pod to investigate"),
@@ -126,67 +192,705 @@ func main() {
 				mcp.Required(),
 				mcp.Description("Namespace of the pod"),
 			),
+			sortOrderParam,
 		),
 		toolHandlers.InvestigatePodStartup,
 	)
 
-	mcpServer.AddTool(
+	addTool(
 		mcp.NewTool("check_resource_limits",
 			mcp.WithDescription("Analyze resource limit issues (CPU throttling, OOM kills, node exhaustion)"),
 			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+			sortOrderParam,
+		),
+		toolHandlers.CheckResourceLimits,
+	)
+
+	addTool(
+		mcp.NewTool("detect_unmanaged_resources",
+			mcp.WithDescription("Find pods and replicasets created without owner references (manual changes bypassing controllers/GitOps)"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+		),
+		toolHandlers.DetectUnmanagedResources,
+	)
+
+	addTool(
+		mcp.NewTool("check_resource_coverage",
+			mcp.WithDescription("Check whether a resource type is watched, since when, and how many events are stored (distinguishes 'not watched' from 'watched but quiet')"),
+			mcp.WithString("resource_type",
 				mcp.Required(),
-				mcp.Description("Start time in RFC3339 format"),
+				mcp.Description("Resource type (plural, e.g. 'deployments') or Kind (e.g. 'Deployment')"),
+			),
+		),
+		toolHandlers.CheckResourceCoverage,
+	)
+
+	addTool(
+		mcp.NewTool("detect_secrets_in_configmaps",
+			mcp.WithDescription("Flag ConfigMap values that look like credentials (password/token/apikey keys, high-entropy strings) that belong in Secrets"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+		),
+		toolHandlers.DetectSecretsInConfigMaps,
+	)
+
+	addTool(
+		mcp.NewTool("namespace_lifecycle",
+			mcp.WithDescription("List namespaces created and deleted in a window and how long short-lived namespaces existed (ephemeral/preview environments, unexpected deletions)"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+		),
+		toolHandlers.NamespaceLifecycle,
+	)
+
+	addTool(
+		mcp.NewTool("after_hours_changes",
+			mcp.WithDescription("Report mutating changes made outside business hours, grouped by user (compliance/anomaly check); changes recorded without the requesting user are grouped as unattributed"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
 			),
 			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("timezone",
+				mcp.Description("IANA timezone for business hours (e.g. 'Europe/Berlin', default 'UTC')"),
+			),
+			mcp.WithNumber("business_start_hour",
+				mcp.Description("Hour business starts, 0-23 (default 9)"),
+			),
+			mcp.WithNumber("business_end_hour",
+				mcp.Description("Hour business ends, 0-23, exclusive (default 17); may be less than the start hour for overnight shifts"),
+			),
+			mcp.WithBoolean("include_weekends",
+				mcp.Description("Treat weekends as business days (default false)"),
+			),
+		),
+		toolHandlers.AfterHoursChanges,
+	)
+
+	addTool(
+		mcp.NewTool("show_change_diff",
+			mcp.WithDescription("Show exactly which fields one update changed (path: old → new), using the update event closest to a timestamp"),
+			mcp.WithString("resource_type",
+				mcp.Required(),
+				mcp.Description("Resource type (plural, e.g. 'deployments')"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name of the object"),
+			),
+			mcp.WithString("namespace",
+				mcp.Description("Namespace of the object (omit for cluster-scoped resources)"),
+			),
+			mcp.WithString("timestamp",
 				mcp.Required(),
-				mcp.Description("End time in RFC3339 format"),
+				mcp.Description("Approximate time of the change in RFC3339 format; the closest update within an hour is used"),
 			),
+		),
+		toolHandlers.ShowChangeDiff,
+	)
+
+	addTool(
+		mcp.NewTool("get_object_timeline",
+			mcp.WithDescription("Full timeline of one object: its watch events interleaved with the Kubernetes Events that refer to it (e.g. a pod's updates alongside its BackOff and FailedMount Events)"),
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("Namespace of the object"),
+			),
+			mcp.WithString("resource_type",
+				mcp.Required(),
+				mcp.Description("Resource type (plural, e.g. 'pods')"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name of the object"),
+			),
+		),
+		toolHandlers.GetObjectTimeline,
+	)
+
+	addTool(
+		mcp.NewTool("event_summary",
+			mcp.WithDescription("Overview of event counts per namespace and resource type in a time range (where is activity concentrated)"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithBoolean("group_by_team",
+				mcp.Description("Aggregate the counts by owning team instead of namespace (requires MCP_TEAM_MAPPING)"),
+			),
+		),
+		toolHandlers.EventSummary,
+	)
+
+	addTool(
+		mcp.NewTool("detect_stale_config",
+			mcp.WithDescription("Find ConfigMaps/Secrets updated in a window whose consuming workloads did not start new pods afterward (config changed but nothing happened)"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
 			mcp.WithString("namespace",
 				mcp.Description("Kubernetes namespace to filter by (optional)"),
 			),
 		),
-		toolHandlers.CheckResourceLimits,
+		toolHandlers.DetectStaleConfig,
+	)
+
+	addTool(
+		mcp.NewTool("detect_replica_pinning",
+			mcp.WithDescription("Find autoscaled workloads (HPAs) that stayed at their max or min replica bound for the whole window (under- or over-provisioning)"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+		),
+		toolHandlers.DetectReplicaPinning,
+	)
+
+	addTool(
+		mcp.NewTool("analyze_taint_impact",
+			mcp.WithDescription("Correlate node taint changes with the pod evictions and scheduling failures that followed (sudden pod disappearance)"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+		),
+		toolHandlers.AnalyzeTaintImpact,
+	)
+
+	addTool(
+		mcp.NewTool("query_by_label_selector",
+			mcp.WithDescription("List events of objects matching a label selector across all namespaces (app-centric investigation)"),
+			mcp.WithString("label_selector",
+				mcp.Required(),
+				mcp.Description("Kubernetes label selector, e.g. 'app=web,tier!=cache' or 'env in (prod,staging)'"),
+			),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("resource_type",
+				mcp.Description("Resource type to restrict the search to, e.g. 'deployments' (optional)"),
+			),
+		),
+		toolHandlers.QueryByLabelSelector,
+	)
+
+	addTool(
+		mcp.NewTool("get_recent_events",
+			mcp.WithDescription("Show the most recent events anywhere in the cluster, newest first (what changed most recently, everywhere)"),
+			mcp.WithNumber("limit",
+				mcp.Description("Number of events to return (default 20, capped by the watch server's max query limit)"),
+			),
+		),
+		toolHandlers.GetRecentEvents,
+	)
+
+	addTool(
+		mcp.NewTool("check_immutable_images",
+			mcp.WithDescription("Flag workloads using mutable image tags (:latest, :main, no digest) and tags redeployed without changing, which may have pulled a different digest"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+		),
+		toolHandlers.CheckImmutableImages,
+	)
+
+	addTool(
+		mcp.NewTool("measure_rollout_duration",
+			mcp.WithDescription("Measure how long a Deployment's rollouts took (template change to all replicas updated and available) and whether deploys are getting slower"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("deployment_name",
+				mcp.Required(),
+				mcp.Description("Name of the Deployment"),
+			),
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("Namespace of the Deployment"),
+			),
+		),
+		toolHandlers.MeasureRolloutDuration,
+	)
+
+	addTool(
+		mcp.NewTool("detect_config_churn",
+			mcp.WithDescription("Find ConfigMaps/Secrets updated far more often than expected (rotation storms), e.g. an operator rewriting the same object in a loop"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+			mcp.WithNumber("min_updates_per_hour",
+				mcp.Description("Report objects updated more often than this many times per hour (default 6)"),
+			),
+		),
+		toolHandlers.DetectConfigChurn,
+	)
+
+	addTool(
+		mcp.NewTool("show_uncategorized_events",
+			mcp.WithDescription("List warning events that none of the diagnostic tools' keyword heuristics recognize (new failure modes that would otherwise be reported as 'no issues')"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+		),
+		toolHandlers.ShowUncategorizedEvents,
+	)
+
+	addTool(
+		mcp.NewTool("correlate_pvc_pod_stalls",
+			mcp.WithDescription("Explain which pods stuck in ContainerCreating are blocked by which PVC and why (claim unbound or mount failing)"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+		),
+		toolHandlers.CorrelatePVCWithPodStalls,
+	)
+
+	addTool(
+		mcp.NewTool("find_problem_onset",
+			mcp.WithDescription("Find when a problem started: the earliest retained event mentioning a keyword (e.g. CrashLoopBackOff) and how often it occurred since"),
+			mcp.WithString("keyword",
+				mcp.Required(),
+				mcp.Description("Problem keyword to search for, case-insensitive (e.g. CrashLoopBackOff, FailedMount)"),
+			),
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+			mcp.WithString("resource_type",
+				mcp.Description("Resource type to filter by, e.g. pods or events (optional)"),
+			),
+		),
+		toolHandlers.FindProblemOnset,
+	)
+
+	addTool(
+		mcp.NewTool("generate_postmortem_timeline",
+			mcp.WithDescription("Generate a neutral, chronological markdown timeline of changes, failures and recoveries in a window, grouped into detection, impact and mitigation, for pasting into a postmortem"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+		),
+		toolHandlers.GeneratePostmortemTimeline,
+	)
+
+	addTool(
+		mcp.NewTool("track_node_version_changes",
+			mcp.WithDescription("Report node upgrades (kubelet or container runtime version changes) with old/new version and timing, the pod deletions and node warnings around them, and the resulting kubelet version skew"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+		),
+		toolHandlers.TrackNodeVersionChanges,
+	)
+
+	addTool(
+		mcp.NewTool("detect_recreate_loops",
+			mcp.WithDescription("Find objects deleted and recreated with the same name repeatedly (create→delete→create cycles), e.g. a controller fighting with something or a CI loop, with who acted at each step"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Kubernetes namespace to filter by (optional)"),
+			),
+			mcp.WithNumber("min_cycles",
+				mcp.Description("Report objects recreated at least this many times (default 3)"),
+			),
+		),
+		toolHandlers.DetectRecreateLoops,
 	)
 
-	// Register resources
-	mcpServer.AddResource(
-		mcp.NewResource(
+	addTool(
+		mcp.NewTool("replica_drift_timeline",
+			mcp.WithDescription("Show desired (spec.replicas) vs. ready (status.readyReplicas) replicas of a workload over time as sparklines and a table, with the periods it ran degraded"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name of the workload"),
+			),
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("Namespace of the workload"),
+			),
+			mcp.WithString("kind",
+				mcp.Description("Kind of the workload (default Deployment)"),
+				mcp.Enum("Deployment", "StatefulSet", "ReplicaSet"),
+			),
+		),
+		toolHandlers.ReplicaDriftTimeline,
+	)
+
+	addTool(
+		mcp.NewTool("audit_serviceaccount_changes",
+			mcp.WithDescription("Audit service account creations, new token secrets and RoleBinding/ClusterRoleBinding subjects added for service accounts, flagging newly privileged or cluster-wide access (lateral movement)"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Namespace of the service accounts (optional)"),
+			),
+		),
+		toolHandlers.AuditServiceAccountChanges,
+	)
+
+	addTool(
+		mcp.NewTool("find_peak_activity",
+			mcp.WithDescription("Find the busiest minutes of a time window by event volume, with the resource types, verbs and namespaces dominating each one; useful for pinpointing when an incident began"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Namespace to analyze (optional)"),
+			),
+			mcp.WithNumber("top",
+				mcp.Description("Number of peak minutes to return (default 3)"),
+			),
+		),
+		toolHandlers.FindPeakActivity,
+	)
+
+	addTool(
+		mcp.NewTool("detect_eviction_storms",
+			mcp.WithDescription("Detect bursts of pod evictions per node, correlate them with the node's memory, disk or PID pressure conditions and report whether each evicted pod was replaced by a Running pod"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithNumber("min_evictions",
+				mcp.Description("Evictions on one node, at most 5 minutes apart, that make a storm (default 3)"),
+			),
+		),
+		toolHandlers.DetectEvictionStorms,
+	)
+
+	addTool(
+		mcp.NewTool("controller_activity",
+			mcp.WithDescription("Rank the Kubernetes system controllers (e.g. replicaset-controller, parsed from their kube-system service accounts) by the mutations they made in a time window, with mutation counts for controllers, other service accounts and human users to tell controller-driven churn from manual changes"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Namespace to analyze (optional)"),
+			),
+			mcp.WithNumber("top",
+				mcp.Description("Number of controllers to return (default 10)"),
+			),
+		),
+		toolHandlers.ControllerActivity,
+	)
+
+	addTool(
+		mcp.NewTool("check_object_growth",
+			mcp.WithDescription("Find namespaces whose object counts of a type (e.g. ConfigMaps, Secrets, Jobs) grow steadily over a time window, created without matching deletes, with the net growth rate and the time left until a ResourceQuota count limit is reached"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Namespace to analyze (optional)"),
+			),
+			mcp.WithString("resource_types",
+				mcp.Description("Comma-separated list of resource types to check (default 'configmaps,secrets,jobs')"),
+			),
+			mcp.WithNumber("min_net_growth",
+				mcp.Description("Minimum net number of created objects to report (default 10)"),
+			),
+		),
+		toolHandlers.CheckObjectGrowth,
+	)
+
+	addTool(
+		mcp.NewTool("detect_control_plane_extensions",
+			mcp.WithDescription("Report MutatingWebhookConfigurations, ValidatingWebhookConfigurations and APIServices created, modified or removed in a time window, with who changed them, the resources and namespaces each webhook intercepts, its failure policy and the availability of each APIService; these high-blast-radius changes can silently break cluster operations"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+		),
+		toolHandlers.DetectControlPlaneExtensions,
+	)
+
+	addTool(
+		mcp.NewTool("event_histogram",
+			mcp.WithDescription("Chart event counts per time bucket over a window, optionally filtered by namespace, resource type and verb, to see when activity rose or fell"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("bucket",
+				mcp.Description("Bucket width, e.g. 5m or 1h (default: the narrowest of 1m, 5m, 15m, 30m, 1h, ... giving at most 48 buckets)"),
+			),
+			mcp.WithString("namespace",
+				mcp.Description("Namespace to count (optional)"),
+			),
+			mcp.WithString("resource_type",
+				mcp.Description("Resource type to count, e.g. pods (optional)"),
+			),
+			mcp.WithString("verb",
+				mcp.Description("Verb to count, e.g. delete (optional)"),
+			),
+		),
+		toolHandlers.EventHistogram,
+	)
+
+	addTool(
+		mcp.NewTool("check_rbac_changes",
+			mcp.WithDescription("Report created, updated and deleted Roles, ClusterRoles and their bindings in a time window, flagging grants of cluster-admin or wildcard verbs"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Namespace to check (optional); cluster-scoped changes are always included"),
+			),
+		),
+		toolHandlers.CheckRBACChanges,
+	)
+
+	addTool(
+		mcp.NewTool("correlate_events_around_incident",
+			mcp.WithDescription("List everything that happened in a namespace around a timestamp in time order, across all resource types, with Kubernetes events linked to earlier changes of the objects they are about"),
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("Namespace to correlate"),
+			),
+			mcp.WithString("timestamp",
+				mcp.Required(),
+				mcp.Description("Center of the window in RFC3339 format, e.g. when an alert fired"),
+			),
+			mcp.WithString("window",
+				mcp.Description("Time on each side of timestamp, e.g. 2m or 15m (default: 5m)"),
+			),
+		),
+		toolHandlers.CorrelateEvents,
+	)
+
+	addTool(
+		mcp.NewTool("summarize_cluster_issues",
+			mcp.WithDescription("Run the node, pod and volume analyses and list the most urgent issues across all of them, ranked Critical, High, Medium or Low"),
+			mcp.WithString("start_time",
+				mcp.Description("Start time in RFC3339 format; optional when duration is set"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("End time in RFC3339 format; optional when duration is set"),
+			),
+			durationParam,
+			mcp.WithString("namespace",
+				mcp.Description("Namespace of the pods and PVCs to check (optional); node and PV issues are always included"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Number of issues to list (default 10)"),
+			),
+		),
+		toolHandlers.SummarizeClusterIssues,
+	)
+
+	for _, name := range enabledTools.Unknown(registeredTools) {
+		fmt.Fprintf(os.Stderr, "Warning: MCP_ENABLED_TOOLS lists unknown tool %q\n", name)
+	}
+
+	// Register resource templates. All of them route through the validating
+	// dispatcher, which also answers malformed audit:// URIs via the fallback
+	// template with the list of supported patterns.
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(
 			"audit://events/{namespace}",
 			"Namespace Audit Events",
-			mcp.WithResourceDescription("All audit events for a specific namespace (last 24 hours)"),
-			mcp.WithMIMEType("application/json"),
+			mcp.WithTemplateDescription("All audit events for a specific namespace (last 24 hours)"),
+			mcp.WithTemplateMIMEType("application/json"),
 		),
-		resourceHandlers.HandleNamespaceEvents,
+		resourceHandlers.Dispatch,
 	)
 
-	mcpServer.AddResource(
-		mcp.NewResource(
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(
 			"audit://events/{namespace}/{resource-type}",
 			"Resource Type Audit Events",
-			mcp.WithResourceDescription("Audit events for a specific resource type in a namespace (last 24 hours)"),
-			mcp.WithMIMEType("application/json"),
+			mcp.WithTemplateDescription("Audit events for a specific resource type in a namespace (last 24 hours)"),
+			mcp.WithTemplateMIMEType("application/json"),
 		),
-		resourceHandlers.HandleResourceTypeEvents,
+		resourceHandlers.Dispatch,
 	)
 
-	mcpServer.AddResource(
-		mcp.NewResource(
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(
 			"audit://changes/{time-range}",
 			"Recent Changes",
-			mcp.WithResourceDescription("Recent resource modifications (time-range: 1h, 24h, 7d)"),
-			mcp.WithMIMEType("application/json"),
+			mcp.WithTemplateDescription("Recent resource modifications (time-range: 1h, 24h, 7d)"),
+			mcp.WithTemplateMIMEType("application/json"),
 		),
-		resourceHandlers.HandleRecentChanges,
+		resourceHandlers.Dispatch,
 	)
 
-	mcpServer.AddResource(
-		mcp.NewResource(
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(
 			"audit://node-events/{node-name}",
 			"Node Audit Events",
-			mcp.WithResourceDescription("Audit events for a specific node (last 24 hours)"),
-			mcp.WithMIMEType("application/json"),
+			mcp.WithTemplateDescription("Audit events for a specific node (last 24 hours)"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		resourceHandlers.Dispatch,
+	)
+
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(
+			"audit://cluster-events/{resource-type}",
+			"Cluster-Scoped Audit Events",
+			mcp.WithTemplateDescription("Audit events for a cluster-scoped resource type such as nodes or persistentvolumes (last 24 hours)"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		resourceHandlers.Dispatch,
+	)
+
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(
+			"audit://object/{namespace}/{resource-type}/{name}",
+			"Object History",
+			mcp.WithTemplateDescription("All watch events of a single object and the Kubernetes Events that refer to it"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		resourceHandlers.Dispatch,
+	)
+
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(
+			"audit://{+path}",
+			"Unsupported Audit URI",
+			mcp.WithTemplateDescription("Fallback that reports the supported audit:// URI patterns"),
+			mcp.WithTemplateMIMEType("application/json"),
 		),
-		resourceHandlers.HandleNodeEvents,
+		resourceHandlers.Dispatch,
 	)
 
 	// Register investigation prompts
@@ -254,9 +958,105 @@ func main() {
 		promptHandlers.TroubleshootVolumeIssues,
 	)
 
+	mcpServer.AddPrompt(
+		mcp.NewPrompt("investigate_rbac_escalation",
+			mcp.WithPromptDescription("Guide for investigating privilege escalation through RBAC changes"),
+			mcp.WithArgument("subject",
+				mcp.ArgumentDescription("User or service account (system:serviceaccount:<namespace>:<name>) suspected of gaining privileges"),
+				mcp.RequiredArgument(),
+			),
+			mcp.WithArgument("time_window",
+				mcp.ArgumentDescription("Time window to investigate (e.g., '24 hours', '7 days')"),
+			),
+		),
+		promptHandlers.InvestigatePrivilegeEscalation,
+	)
+
+	// Register prompt templates; one named like a built-in prompt replaces it
+	for _, t := range promptTemplates {
+		mcpServer.AddPrompt(t.Prompt(), t.Handle)
+	}
+
 	// Start server with stdio transport
 	if err := server.ServeStdio(mcpServer); err != nil {
 		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// enabledTools is the set of tool names an operator chose to expose
+type enabledTools struct {
+	all   bool
+	names map[string]bool
+}
+
+// parseEventBudget parses the per-query event cap of the tools. An empty or
+// invalid value falls back to the default.
+func parseEventBudget(value string) int {
+	if value == "" {
+		return tools.DefaultEventBudget
+	}
+	budget, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || budget <= 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid MCP_EVENT_BUDGET %q, using %d\n", value, tools.DefaultEventBudget)
+		return tools.DefaultEventBudget
+	}
+	return budget
+}
+
+// parseAuditTimeout parses the audit API request timeout, a Go duration
+// such as "45s". An empty or invalid value selects the client default.
+func parseAuditTimeout(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || timeout <= 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid AUDIT_API_TIMEOUT %q, using %s\n", value, audit.DefaultTimeout)
+		return 0
+	}
+	return timeout
+}
+
+// parseEnabledTools parses a comma-separated list of tool names.
+// An empty value or "all" enables every tool.
+func parseEnabledTools(value string) enabledTools {
+	enabled := enabledTools{names: make(map[string]bool)}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			enabled.all = true
+			continue
+		}
+		enabled.names[name] = true
+	}
+	if len(enabled.names) == 0 {
+		enabled.all = true
+	}
+	return enabled
+}
+
+// Enabled reports whether the named tool should be registered
+func (e enabledTools) Enabled(name string) bool {
+	return e.all || e.names[name]
+}
+
+// Unknown returns the configured names that don't match any registered tool
+func (e enabledTools) Unknown(registered []string) []string {
+	known := make(map[string]bool, len(registered))
+	for _, name := range registered {
+		known[name] = true
+	}
+
+	var unknown []string
+	for name := range e.names {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
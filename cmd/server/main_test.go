@@ -0,0 +1,84 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/tools"
+)
+
+func TestParseEnabledTools(t *testing.T) {
+	tests := []struct {
+		value    string
+		enabled  []string
+		disabled []string
+	}{
+		{value: "", enabled: []string{"check_node_health", "detect_secrets_in_configmaps"}},
+		{value: "all", enabled: []string{"check_node_health", "detect_secrets_in_configmaps"}},
+		{
+			value:    "check_node_health, check_pod_issues",
+			enabled:  []string{"check_node_health", "check_pod_issues"},
+			disabled: []string{"detect_secrets_in_configmaps"},
+		},
+		{value: "check_node_health,all", enabled: []string{"check_pod_issues"}},
+		{value: " , ", enabled: []string{"check_node_health"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			enabled := parseEnabledTools(tt.value)
+			for _, name := range tt.enabled {
+				if !enabled.Enabled(name) {
+					t.Errorf("expected %s to be enabled", name)
+				}
+			}
+			for _, name := range tt.disabled {
+				if enabled.Enabled(name) {
+					t.Errorf("expected %s to be disabled", name)
+				}
+			}
+		})
+	}
+}
+
+func TestEnabledToolsUnknown(t *testing.T) {
+	enabled := parseEnabledTools("check_node_health,check_nodes_health,typo")
+
+	unknown := enabled.Unknown([]string{"check_node_health", "check_pod_issues"})
+	if want := []string{"check_nodes_health", "typo"}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("expected unknown %v, got %v", want, unknown)
+	}
+}
+
+func TestParseAuditTimeout(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":      0,
+		"45s":   45 * time.Second,
+		" 2m ":  2 * time.Minute,
+		"0s":    0,
+		"-5s":   0,
+		"later": 0,
+	}
+	for value, want := range tests {
+		if got := parseAuditTimeout(value); got != want {
+			t.Errorf("parseAuditTimeout(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestParseEventBudget(t *testing.T) {
+	tests := map[string]int{
+		"":     tools.DefaultEventBudget,
+		"500":  500,
+		" 50 ": 50,
+		"0":    tools.DefaultEventBudget,
+		"-1":   tools.DefaultEventBudget,
+		"lots": tools.DefaultEventBudget,
+	}
+	for value, want := range tests {
+		if got := parseEventBudget(value); got != want {
+			t.Errorf("parseEventBudget(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
@@ -2,39 +2,62 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/moritz/mcp-toolkit/internal/watch/api"
 	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
 	"github.com/moritz/mcp-toolkit/internal/watch/storage"
 	"github.com/moritz/mcp-toolkit/internal/watch/watchers"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 )
 
 func main() {
-	// Setup logger
-	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
-	log := ctrl.Log.WithName("watch-server")
+	readOnly := flag.Bool("readonly", false, "Serve the API from an existing store without watching the cluster")
+	flag.Parse()
 
-	// Load configuration
+	// Load configuration, logging to the console until the configured
+	// logger is set up
 	configPath := os.Getenv("CONFIG_PATH")
 	if configPath == "" {
 		configPath = "/config/resources.yaml"
 	}
 
-	cfg, err := loadConfig(configPath, log)
+	bootLog := zap.New(zap.UseDevMode(true)).WithName("watch-server")
+	cfg, err := loadConfig(configPath, bootLog)
+	if err != nil {
+		bootLog.Error(err, "Failed to load configuration")
+		os.Exit(1)
+	}
+
+	// Setup logger
+	logger, err := newLogger(cfg.LogFormat, cfg.LogLevel, os.Stderr)
 	if err != nil {
-		log.Error(err, "Failed to load configuration")
+		bootLog.Error(err, "Invalid logging configuration")
 		os.Exit(1)
 	}
+	ctrl.SetLogger(logger)
+	log := ctrl.Log.WithName("watch-server")
+	if adminToken := os.Getenv("ADMIN_TOKEN"); adminToken != "" {
+		cfg.AdminToken = adminToken
+	}
+	if *readOnly {
+		cfg.ReadOnly = true
+	}
 
 	log.Info("Configuration loaded",
 		"storagePath", cfg.StoragePath,
@@ -42,15 +65,21 @@ func main() {
 		"serverPort", cfg.ServerPort,
 		"maxQueryLimit", cfg.MaxQueryLimit,
 		"resourceCount", len(cfg.Resources),
-		"discoverCRDs", cfg.DiscoverCRDs)
+		"discoverCRDs", cfg.DiscoverCRDs,
+		"syncWrites", cfg.Storage.SyncWrites,
+		"readOnly", cfg.ReadOnly)
 
 	// Initialize BadgerDB storage
-	store, err := storage.NewStore(cfg.StoragePath, cfg.RetentionDays)
+	// Metrics are served by the API server; the controller-runtime metrics
+	// server stays disabled
+	registry := prometheus.NewRegistry()
+
+	store, err := openStore(cfg, registry)
 	if err != nil {
 		log.Error(err, "Failed to initialize storage")
 		os.Exit(1)
 	}
-	defer store.Close()
+	store.SetLogger(log.WithName("storage"))
 	log.Info("Storage initialized", "path", cfg.StoragePath)
 
 	// Create context for graceful shutdown
@@ -58,50 +87,48 @@ func main() {
 	defer cancel()
 
 	// Start garbage collection routine
-	go store.StartGCRoutine(ctx)
-	log.Info("Started background GC routine")
+	if !cfg.ReadOnly {
+		go store.StartGCRoutine(ctx)
+		log.Info("Started background GC routine")
 
-	// Create controller-runtime manager
-	kubeConfig := ctrl.GetConfigOrDie()
-	mgr, err := ctrl.NewManager(kubeConfig, ctrl.Options{
-		Cache: cache.Options{
-			// Watch all namespaces
-			DefaultNamespaces: map[string]cache.Config{},
-		},
-		// Disable metrics server
-	})
-	if err != nil {
-		log.Error(err, "Failed to create controller manager")
-		os.Exit(1)
+		if cfg.Storage.SweepInterval > 0 {
+			go store.StartSweepRoutine(ctx, cfg.Storage.SweepInterval)
+			log.Info("Started retention sweep routine", "interval", cfg.Storage.SweepInterval)
+		}
 	}
-	log.Info("Controller-runtime manager created")
 
-	// Initialize watcher manager
-	watcherMgr := watchers.NewManager(mgr, store, cfg)
-	if err := watcherMgr.Start(ctx); err != nil {
+	// Set once the watchers' caches have synced; /readyz fails until then
+	var cacheSynced atomic.Bool
+	watcherMgr, err := startWatchers(ctx, cfg, store, registry, &cacheSynced, log)
+	if err != nil {
 		log.Error(err, "Failed to start watchers")
 		os.Exit(1)
 	}
-	log.Info("Watchers initialized")
 
-	// Start the controller-runtime manager
-	go func() {
-		log.Info("Starting controller-runtime manager")
-		if err := mgr.Start(ctx); err != nil {
-			log.Error(err, "Manager stopped with error")
-			os.Exit(1)
-		}
-	}()
-
-	// Wait for cache to sync
-	if !mgr.GetCache().WaitForCacheSync(ctx) {
-		log.Error(fmt.Errorf("cache sync failed"), "Failed to sync cache")
-		os.Exit(1)
+	// Without watchers there is nothing to list or stream
+	var watched api.WatchedLister
+	var events api.EventSubscriber
+	if watcherMgr != nil {
+		watched, events = watcherMgr, watcherMgr
+		watchConfig(ctx, configPath, watcherMgr, log)
 	}
-	log.Info("Cache synced successfully")
 
 	// Create and start HTTP server
-	apiServer := api.NewServer(store, cfg.MaxQueryLimit)
+	authTokens, err := cfg.LoadAuthTokens()
+	if err != nil {
+		log.Error(err, "Failed to load API auth tokens")
+		os.Exit(1)
+	}
+	if len(authTokens) == 0 {
+		log.Info("API authentication disabled (no authTokens configured)")
+	}
+	apiServer := api.NewServer(store, watched, events, cfg.MaxQueryLimit, cfg.AdminToken, authTokens, registry)
+	apiServer.SetMaxResponseBytes(cfg.MaxResponseBytes)
+	if watcherMgr != nil {
+		apiServer.SetKindResolver(watcherMgr)
+		apiServer.SetDeadLetters(watcherMgr)
+		apiServer.SetCacheSynced(&cacheSynced)
+	}
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.ServerPort),
 		Handler:      apiServer,
@@ -126,7 +153,7 @@ func main() {
 
 	log.Info("Shutting down gracefully...")
 
-	// Shutdown HTTP server
+	// Shutdown HTTP server first, so no request reads a closing store
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
@@ -134,12 +161,169 @@ func main() {
 		log.Error(err, "HTTP server shutdown error")
 	}
 
-	// Cancel context to stop watchers and GC
+	// Store the events the watchers already received
+	if watcherMgr != nil {
+		if err := watcherMgr.Stop(shutdownCtx); err != nil {
+			log.Error(err, "Failed to store all pending events")
+		}
+	}
+
+	// Cancel context to stop the informers and GC, then close the store
 	cancel()
+	if err := store.Close(); err != nil {
+		log.Error(err, "Failed to close storage")
+	}
 
 	log.Info("Shutdown complete")
 }
 
+// openStore opens the BadgerDB store, read-only when the server only serves
+// queries, and registers its metrics with reg
+func openStore(cfg *config.Config, reg prometheus.Registerer) (*storage.Store, error) {
+	if cfg.ReadOnly {
+		store, err := storage.NewReadOnlyStore(cfg.StoragePath)
+		if err != nil {
+			return nil, err
+		}
+		store.SetAggregateBudget(cfg.AggregateScanBudget)
+		return store, nil
+	}
+	store, err := storage.NewStore(cfg.StoragePath, cfg.RetentionDays, cfg.Storage.SyncWrites, reg)
+	if err != nil {
+		return nil, err
+	}
+	store.SetRetentionOverrides(retentionOverrides(cfg.Resources))
+	store.SetAggregateBudget(cfg.AggregateScanBudget)
+	store.SetGC(time.Duration(cfg.Storage.GCIntervalMinutes)*time.Minute, cfg.Storage.GCDiscardRatio)
+	return store, nil
+}
+
+// retentionOverrides collects the per-resource retention periods, keyed by
+// the resource type events are stored under: the configured plural, or the
+// one derived from the Kind
+func retentionOverrides(resources []config.ResourceWatch) map[string]time.Duration {
+	overrides := make(map[string]time.Duration)
+	for _, resource := range resources {
+		if resource.RetentionDays > 0 {
+			resourceType := resource.Plural
+			if resourceType == "" {
+				resourceType = models.KindToResourceType(resource.Kind)
+			}
+			overrides[resourceType] = time.Duration(resource.RetentionDays) * 24 * time.Hour
+		}
+	}
+	return overrides
+}
+
+// watchConfig applies changes to the resources of the configuration file
+// while running. Without a file, the default configuration is kept.
+func watchConfig(ctx context.Context, path string, watcherMgr *watchers.Manager, log logr.Logger) {
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+
+	watcher := config.NewWatcher(path,
+		func(cfg *config.Config) {
+			if err := watcherMgr.Reconcile(cfg); err != nil {
+				log.Error(err, "Failed to apply reloaded configuration")
+				return
+			}
+			log.Info("Reloaded configuration", "resources", len(cfg.Resources))
+		},
+		func(err error) {
+			log.Error(err, "Configuration reload failed")
+		},
+	)
+	go func() {
+		if err := watcher.Run(ctx); err != nil {
+			log.Error(err, "Configuration hot-reload disabled")
+		}
+	}()
+}
+
+// startWatchers connects to the cluster and starts the configured watchers.
+// Their caches sync in the background, so the API serves meanwhile; synced is
+// set once they did. Read-only servers never connect to the cluster and
+// return no manager.
+func startWatchers(ctx context.Context, cfg *config.Config, store *storage.Store, reg prometheus.Registerer, synced *atomic.Bool, log logr.Logger) (*watchers.Manager, error) {
+	if cfg.ReadOnly {
+		log.Info("Read-only mode: serving the API without watchers")
+		return nil, nil
+	}
+
+	// Create controller-runtime manager
+	kubeConfig := ctrl.GetConfigOrDie()
+	mgr, err := ctrl.NewManager(kubeConfig, ctrl.Options{
+		Cache: cache.Options{
+			// Watch all namespaces
+			DefaultNamespaces: map[string]cache.Config{},
+		},
+		// Disable the metrics server, whose default port collides with the
+		// API; metrics are served by the API server instead
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create controller manager: %w", err)
+	}
+	log.Info("Controller-runtime manager created")
+
+	// Initialize watcher manager
+	watcherMgr := watchers.NewManager(mgr, store, cfg, reg, log.WithName("watchers"))
+	if err := watcherMgr.Start(ctx); err != nil {
+		return nil, err
+	}
+	log.Info("Watchers initialized")
+
+	// Start the controller-runtime manager
+	go func() {
+		log.Info("Starting controller-runtime manager")
+		if err := mgr.Start(ctx); err != nil {
+			log.Error(err, "Manager stopped with error")
+			os.Exit(1)
+		}
+	}()
+
+	// Wait for cache to sync
+	go func() {
+		if !mgr.GetCache().WaitForCacheSync(ctx) {
+			if ctx.Err() == nil {
+				log.Error(fmt.Errorf("cache sync failed"), "Watchers did not start")
+				os.Exit(1)
+			}
+			return
+		}
+		synced.Store(true)
+		log.Info("Cache synced successfully")
+	}()
+
+	return watcherMgr, nil
+}
+
+// newLogger builds the logger for a log format and level. The console format
+// is the development logger; JSON logs are production-style. An empty level
+// keeps the format's default.
+func newLogger(format, level string, out io.Writer) (logr.Logger, error) {
+	opts := []zap.Opts{zap.WriteTo(out)}
+	switch format {
+	case "", config.LogFormatConsole:
+		opts = append(opts, zap.UseDevMode(true))
+	case config.LogFormatJSON:
+		opts = append(opts, zap.UseDevMode(false), zap.JSONEncoder())
+	default:
+		return logr.Logger{}, fmt.Errorf("invalid logFormat %q: must be %q or %q", format, config.LogFormatConsole, config.LogFormatJSON)
+	}
+
+	if level != "" {
+		lvl, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return logr.Logger{}, fmt.Errorf("invalid logLevel: %w", err)
+		}
+		opts = append(opts, zap.Level(lvl))
+	}
+
+	return zap.New(opts...), nil
+}
+
 // loadConfig loads configuration from file or returns default
 func loadConfig(path string, log logr.Logger) (*config.Config, error) {
 	// Try to load from file
@@ -163,5 +347,8 @@ func loadConfig(path string, log logr.Logger) (*config.Config, error) {
 		}
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
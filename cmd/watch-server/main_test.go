@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/moritz/mcp-toolkit/internal/watch/api"
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	"github.com/moritz/mcp-toolkit/internal/watch/storage"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newPod returns a minimal pod object
+func newPod(name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(types.UID(name))
+	return obj
+}
+
+func TestReadOnlyModeServesQueriesWithoutWatchers(t *testing.T) {
+	path := t.TempDir()
+	ctx := context.Background()
+
+	// Populate the store the way a writer replica would, then close it
+	writer, err := storage.NewStore(path, 1, false, nil)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	event, err := models.TransformWatchEvent(newPod("web"), models.EventTypeAdded)
+	if err != nil {
+		t.Fatalf("failed to transform pod: %v", err)
+	}
+	if err := writer.StoreEvent(ctx, event, newPod("web")); err != nil {
+		t.Fatalf("failed to store event: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close store: %v", err)
+	}
+
+	// Any attempt to reach a cluster would fail without a kubeconfig
+	t.Setenv("KUBECONFIG", filepath.Join(path, "missing-kubeconfig"))
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	cfg := &config.Config{StoragePath: path, MaxQueryLimit: 100, ReadOnly: true}
+	store, err := openStore(cfg, nil)
+	if err != nil {
+		t.Fatalf("failed to open read-only store: %v", err)
+	}
+	defer store.Close()
+
+	if !store.ReadOnly() {
+		t.Error("expected a read-only store")
+	}
+	if err := store.StoreEvent(ctx, event, newPod("api")); err == nil {
+		t.Error("expected writes to a read-only store to fail")
+	}
+
+	watcherMgr, err := startWatchers(ctx, cfg, store, nil, nil, logr.Discard())
+	if err != nil {
+		t.Fatalf("startWatchers failed: %v", err)
+	}
+	if watcherMgr != nil {
+		t.Fatal("expected no watchers in read-only mode")
+	}
+
+	server := api.NewServer(store, nil, nil, cfg.MaxQueryLimit, "", nil, nil)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?resourceType=pods", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var events []models.AuditEvent
+	if err := json.NewDecoder(rec.Body).Decode(&events); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(events) != 1 || events[0].ResourceName != "web" {
+		t.Errorf("expected the stored pod event, got %+v", events)
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/watched", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "[]\n" {
+		t.Errorf("expected an empty watched list, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		format string
+		level  string
+		json   bool
+		debug  bool
+	}{
+		{format: "", debug: true},
+		{format: config.LogFormatConsole, debug: true},
+		{format: config.LogFormatJSON, json: true},
+		{format: config.LogFormatJSON, level: "debug", json: true, debug: true},
+		{format: config.LogFormatConsole, level: "warn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.level, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := newLogger(tt.format, tt.level, &buf)
+			if err != nil {
+				t.Fatalf("newLogger failed: %v", err)
+			}
+			logger.V(1).Info("debug message")
+			logger.Error(nil, "error message", "key", "value")
+
+			output := buf.String()
+			if got := strings.Contains(output, "debug message"); got != tt.debug {
+				t.Errorf("expected debug output %v, got:\n%s", tt.debug, output)
+			}
+
+			lines := strings.Split(strings.TrimSpace(output), "\n")
+			var entry map[string]any
+			isJSON := json.Unmarshal([]byte(lines[len(lines)-1]), &entry) == nil
+			if isJSON != tt.json {
+				t.Errorf("expected JSON output %v, got:\n%s", tt.json, output)
+			}
+			if tt.json && (entry["msg"] != "error message" || entry["key"] != "value") {
+				t.Errorf("unexpected JSON entry %v", entry)
+			}
+		})
+	}
+
+	if _, err := newLogger("xml", "", io.Discard); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if _, err := newLogger(config.LogFormatJSON, "loud", io.Discard); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -1,32 +1,269 @@
 package audit
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 )
 
+// ErrNoData is returned when the API has no events matching a query
+var ErrNoData = errors.New("no audit data available for the specified time range")
+
+// ErrResponseTruncated is returned when the API left out events matching a
+// query to stay under its response size limit
+var ErrResponseTruncated = errors.New("response truncated by the server's size limit; narrow the query")
+
+// APIError is an error response of the API
+type APIError struct {
+	StatusCode int
+	// Code classifies the error, e.g. "bad_request"; it is empty when the
+	// response had no JSON error body
+	Code    string
+	Message string
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// apiError decodes the {"error": ..., "code": ...} body of a failed
+// response. A 404 with code "no_data" is a query without matches and
+// returns ErrNoData.
+func apiError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	var envelope struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error == "" {
+		return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	if resp.StatusCode == http.StatusNotFound && envelope.Code == "no_data" {
+		return ErrNoData
+	}
+	return &APIError{StatusCode: resp.StatusCode, Code: envelope.Code, Message: envelope.Error}
+}
+
 // Client provides access to Kubernetes audit logs via REST API
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
 }
 
-// NewClient creates a new audit log API client
+// Client defaults
+const (
+	DefaultTimeout     = 30 * time.Second
+	DefaultMaxAttempts = 4
+	DefaultBaseDelay   = 250 * time.Millisecond
+	DefaultMaxDelay    = 5 * time.Second
+	// DefaultMaxIdleConns is the number of idle connections kept open to
+	// the API, so concurrent tool queries reuse them
+	DefaultMaxIdleConns = 10
+)
+
+// ClientOptions tunes an audit log API client. Zero values use the defaults.
+type ClientOptions struct {
+	// Timeout caps a request including its retries
+	Timeout time.Duration
+
+	// MaxAttempts caps the attempts of a request failing with a network
+	// error or a 502, 503 or 504 response, the first one included. 1
+	// disables retries.
+	MaxAttempts int
+
+	// BaseDelay is the backoff before the first retry; it doubles with
+	// every further retry up to MaxDelay. Each delay is jittered.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// BearerToken is sent as "Authorization: Bearer <token>" on every
+	// request, like SetBearerToken
+	BearerToken string
+
+	// TLSConfig is used for https:// API URLs, e.g. to trust a private CA
+	// or present a client certificate for mTLS (see LoadTLSConfig)
+	TLSConfig *tls.Config
+
+	// MaxIdleConns caps the idle connections kept open to the API
+	MaxIdleConns int
+
+	// Transport replaces the HTTP transport requests are sent with.
+	// TLSConfig and MaxIdleConns only apply to the default transport.
+	// Compression, authentication and retries are layered on top of it.
+	Transport http.RoundTripper
+}
+
+// NewClient creates a new audit log API client with the default options
 func NewClient(baseURL string) *Client {
+	return NewClientWithOptions(baseURL, ClientOptions{})
+}
+
+// NewClientWithOptions creates a new audit log API client
+func NewClientWithOptions(baseURL string, opts ClientOptions) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = DefaultBaseDelay
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = DefaultMaxDelay
+	}
+	if opts.MaxIdleConns <= 0 {
+		opts.MaxIdleConns = DefaultMaxIdleConns
+	}
+	base := opts.Transport
+	if base == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConns = opts.MaxIdleConns
+		transport.MaxIdleConnsPerHost = opts.MaxIdleConns
+		if opts.TLSConfig != nil {
+			transport.TLSClientConfig = opts.TLSConfig
+		}
+		base = transport
+	}
+
 	return &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: opts.Timeout,
+			Transport: &apiTransport{
+				token:       opts.BearerToken,
+				base:        base,
+				maxAttempts: opts.MaxAttempts,
+				baseDelay:   opts.BaseDelay,
+				maxDelay:    opts.MaxDelay,
+			},
 		},
 	}
 }
 
+// SetBearerToken sends token as "Authorization: Bearer <token>" on every
+// request, for APIs that require authentication
+func (c *Client) SetBearerToken(token string) {
+	c.httpClient.Transport.(*apiTransport).token = token
+}
+
+// apiTransport requests gzip-compressed responses, decompressing them
+// transparently, adds the bearer token if one is set and retries transient
+// failures
+type apiTransport struct {
+	token       string
+	base        http.RoundTripper
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *apiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", "gzip")
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+
+	resp, err := t.roundTripWithRetries(req)
+	if err != nil || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp, err
+	}
+
+	body, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to decompress response: %w", err)
+	}
+	resp.Body = &gzipBody{Reader: body, raw: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// roundTripWithRetries sends req, retrying network errors and 502, 503 and
+// 504 responses with exponential backoff until maxAttempts is reached or the
+// next attempt would start past the request's deadline. Only requests
+// without a body are retried.
+func (t *apiTransport) roundTripWithRetries(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxAttempts || req.Body != nil || !retryable(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether a failed attempt may succeed when repeated,
+// i.e. the server was unreachable or is restarting. ErrNoData (404) and
+// other client errors are final.
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the retry following the given attempt:
+// baseDelay doubled per attempt, capped at maxDelay, with the upper half
+// jittered so clients don't retry in lockstep
+func (t *apiTransport) backoff(attempt int) time.Duration {
+	delay := t.baseDelay
+	for i := 1; i < attempt && delay < t.maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, t.maxDelay)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// gzipBody decompresses a response body, closing the raw body with it
+type gzipBody struct {
+	*gzip.Reader
+	raw io.ReadCloser
+}
+
+// Close implements io.Closer
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.raw.Close()
+}
+
 // AuditEvent represents a Kubernetes audit log event
 type AuditEvent struct {
 	Timestamp      time.Time         `json:"timestamp"`
@@ -42,6 +279,73 @@ type AuditEvent struct {
 	Stage          string            `json:"stage"`
 	RequestURI     string            `json:"requestURI"`
 	SourceIPs      []string          `json:"sourceIPs,omitempty"`
+	ChangedFields  []FieldChange     `json:"changedFields,omitempty"`
+	// EventInfo is set on the events of Kubernetes Event objects
+	EventInfo *EventInfo `json:"eventInfo,omitempty"`
+}
+
+// EventInfo holds the fields of a Kubernetes Event object
+type EventInfo struct {
+	Reason string `json:"reason,omitempty"`
+	// Type is Normal or Warning
+	Type      string    `json:"type,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	Count     int64     `json:"count,omitempty"`
+	FirstSeen time.Time `json:"firstSeen,omitzero"`
+	LastSeen  time.Time `json:"lastSeen,omitzero"`
+}
+
+// FieldChange is a single field modified by an update event. Old or New is
+// nil when the field was added or removed.
+type FieldChange struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// WatchedResource describes a resource type the watch server is actively watching
+type WatchedResource struct {
+	Group        string    `json:"group"`
+	Version      string    `json:"version"`
+	Kind         string    `json:"kind"`
+	ResourceType string    `json:"resourceType"`
+	Since        time.Time `json:"since"`
+	EventCount   int       `json:"eventCount"`
+}
+
+// EventSummary is a namespace x resourceType matrix of event counts.
+// Cluster-scoped resources are counted under the "" namespace.
+type EventSummary struct {
+	Total  int                       `json:"total"`
+	Counts map[string]map[string]int `json:"counts"`
+}
+
+// HistogramBucket is the number of events from Start up to the start of the
+// next bucket
+type HistogramBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// EventHistogram holds event counts per time bucket
+type EventHistogram struct {
+	Bucket  string            `json:"bucket"`
+	Total   int               `json:"total"`
+	Buckets []HistogramBucket `json:"buckets"`
+
+	// Truncated is set when the server's scan budget ran out; the buckets
+	// then cover the events up to that point
+	Truncated bool `json:"truncated"`
+}
+
+// ObjectHistory holds the events of a single object: the watch events of
+// the object itself and the Kubernetes Events whose involvedObject is it
+type ObjectHistory struct {
+	Namespace     string       `json:"namespace"`
+	ResourceType  string       `json:"resourceType"`
+	ResourceName  string       `json:"resourceName"`
+	WatchEvents   []AuditEvent `json:"watchEvents"`
+	RelatedEvents []AuditEvent `json:"relatedEvents"`
 }
 
 // QueryOptions defines parameters for querying audit events
@@ -54,10 +358,66 @@ type QueryOptions struct {
 	Verb         string
 	User         string
 	Limit        int
+
+	// Verbs keeps events with any of the verbs in a single request. Verb,
+	// if set, counts as one more of them.
+	Verbs []string
+
+	// Namespaces keeps events in any of the namespaces in a single request.
+	// Namespace, if set, counts as one more of them.
+	Namespaces []string
+
+	// Cursor continues from a previous EventPage.NextCursor
+	Cursor string
 }
 
-// QueryEvents retrieves audit events based on the provided options
+// EventPage is a page of events returned by QueryEventsPage. Count is the
+// number of items on the page, not of all matching events.
+type EventPage struct {
+	Items      []AuditEvent `json:"items"`
+	Count      int          `json:"count"`
+	HasMore    bool         `json:"hasMore"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+
+	// Truncated is set when the server's response size limit cut the page
+	// short; NextCursor resumes at the first event left out
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// QueryEvents retrieves audit events based on the provided options. When
+// the server's response size limit cut the response short, the events
+// received are returned along with ErrResponseTruncated.
 func (c *Client) QueryEvents(ctx context.Context, opts QueryOptions) ([]AuditEvent, error) {
+	var events []AuditEvent
+	if err := c.getEvents(ctx, queryParams(opts), &events); err != nil {
+		if errors.Is(err, ErrResponseTruncated) {
+			return events, err
+		}
+		return nil, err
+	}
+	return events, nil
+}
+
+// QueryEventsPage retrieves a single page of audit events using the
+// self-describing envelope response. An empty page is not an error. A page
+// cut short by the server's response size limit is flagged as Truncated;
+// ErrResponseTruncated is only returned if not even one event fit.
+func (c *Client) QueryEventsPage(ctx context.Context, opts QueryOptions) (*EventPage, error) {
+	params := queryParams(opts)
+	params.Add("envelope", "true")
+
+	var page EventPage
+	if err := c.getEvents(ctx, params, &page); err != nil {
+		return nil, err
+	}
+	if page.Truncated && len(page.Items) == 0 {
+		return nil, ErrResponseTruncated
+	}
+	return &page, nil
+}
+
+// queryParams encodes query options as URL parameters
+func queryParams(opts QueryOptions) url.Values {
 	params := url.Values{}
 
 	if !opts.StartTime.IsZero() {
@@ -66,8 +426,12 @@ func (c *Client) QueryEvents(ctx context.Context, opts QueryOptions) ([]AuditEve
 	if !opts.EndTime.IsZero() {
 		params.Add("end", opts.EndTime.Format(time.RFC3339))
 	}
+	namespaces := opts.Namespaces
 	if opts.Namespace != "" {
-		params.Add("namespace", opts.Namespace)
+		namespaces = append([]string{opts.Namespace}, namespaces...)
+	}
+	if len(namespaces) > 0 {
+		params.Add("namespace", strings.Join(namespaces, ","))
 	}
 	if opts.ResourceType != "" {
 		params.Add("resourceType", opts.ResourceType)
@@ -78,41 +442,53 @@ func (c *Client) QueryEvents(ctx context.Context, opts QueryOptions) ([]AuditEve
 	if opts.Verb != "" {
 		params.Add("verb", opts.Verb)
 	}
+	for _, verb := range opts.Verbs {
+		params.Add("verb", verb)
+	}
 	if opts.User != "" {
 		params.Add("user", opts.User)
 	}
 	if opts.Limit > 0 {
 		params.Add("limit", fmt.Sprintf("%d", opts.Limit))
 	}
+	if opts.Cursor != "" {
+		params.Add("cursor", opts.Cursor)
+	}
+	return params
+}
 
+// getEvents queries /api/v1/events and decodes the response into out
+func (c *Client) getEvents(ctx context.Context, params url.Values, out any) error {
 	reqURL := fmt.Sprintf("%s/api/v1/events?%s", c.baseURL, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("no audit data available for the specified time range")
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	var events []AuditEvent
-	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	// Trailers are only available once the body is read to the end
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.Trailer.Get("X-Truncated") == "true" {
+		return ErrResponseTruncated
 	}
 
-	return events, nil
+	return nil
 }
 
 // GetNodeEvents retrieves audit events related to a specific node
@@ -144,43 +520,185 @@ func (c *Client) GetResourceTypeEvents(ctx context.Context, namespace, resourceT
 	})
 }
 
-// GetRecentChanges retrieves create, update, patch, and delete events
-func (c *Client) GetRecentChanges(ctx context.Context, startTime, endTime time.Time, resourceTypes []string) ([]AuditEvent, error) {
-	verbs := []string{"create", "update", "patch", "delete"}
+// GetObjectHistory retrieves the watch events and related Kubernetes Events
+// of a single object. It returns ErrNoData when there are neither.
+func (c *Client) GetObjectHistory(ctx context.Context, namespace, resourceType, name string) (*ObjectHistory, error) {
+	reqURL := fmt.Sprintf("%s/api/v1/events/%s/%s/%s", c.baseURL,
+		url.PathEscape(namespace), url.PathEscape(resourceType), url.PathEscape(name))
 
-	// Build a single query with multiple verbs if API supports it, otherwise query separately
-	opts := QueryOptions{
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
+	}
+
+	var history ObjectHistory
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &history, nil
+}
+
+// GetRecentChanges retrieves up to 1000 create, update, patch and delete
+// events in a single request, oldest first
+func (c *Client) GetRecentChanges(ctx context.Context, startTime, endTime time.Time, resourceTypes []string) ([]AuditEvent, error) {
+	events, err := c.QueryEvents(ctx, QueryOptions{
 		StartTime: startTime,
 		EndTime:   endTime,
+		Verbs:     []string{"create", "update", "patch", "delete"},
 		Limit:     1000,
+	})
+	if errors.Is(err, ErrNoData) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// For simplicity, query with verb filter - in production might use OR conditions
-	var allEvents []AuditEvent
-	for _, verb := range verbs {
-		opts.Verb = verb
-		events, err := c.QueryEvents(ctx, opts)
-		if err != nil {
-			// Don't fail on individual verb errors
-			continue
-		}
+	// The API returns events in time order; keep it that way regardless
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
 
-		// Filter by resource types if specified
-		if len(resourceTypes) > 0 {
-			filtered := make([]AuditEvent, 0)
-			for _, event := range events {
-				for _, rt := range resourceTypes {
-					if strings.EqualFold(event.ResourceType, rt) {
-						filtered = append(filtered, event)
-						break
-					}
-				}
+	// Filter by resource types if specified
+	if len(resourceTypes) == 0 {
+		return events, nil
+	}
+	filtered := make([]AuditEvent, 0)
+	for _, event := range events {
+		for _, rt := range resourceTypes {
+			if strings.EqualFold(event.ResourceType, rt) {
+				filtered = append(filtered, event)
+				break
 			}
-			allEvents = append(allEvents, filtered...)
-		} else {
-			allEvents = append(allEvents, events...)
 		}
 	}
+	return filtered, nil
+}
+
+// GetRecentEvents retrieves the most recent events across all namespaces and
+// resource types, newest first
+func (c *Client) GetRecentEvents(ctx context.Context, limit int) ([]AuditEvent, error) {
+	reqURL := fmt.Sprintf("%s/api/v1/recent?limit=%d", c.baseURL, limit)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
+	}
+
+	var events []AuditEvent
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetWatchedResources retrieves the resource types the watch server is actively watching
+func (c *Client) GetWatchedResources(ctx context.Context) ([]WatchedResource, error) {
+	reqURL := fmt.Sprintf("%s/api/v1/watched", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
+	}
+
+	var resources []WatchedResource
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return resources, nil
+}
+
+// GetEventSummary retrieves per-namespace, per-resource-type event counts for a time range
+func (c *Client) GetEventSummary(ctx context.Context, startTime, endTime time.Time) (*EventSummary, error) {
+	params := url.Values{}
+	params.Add("start", startTime.Format(time.RFC3339))
+	params.Add("end", endTime.Format(time.RFC3339))
+	reqURL := fmt.Sprintf("%s/api/v1/events/summary?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
+	}
+
+	var summary EventSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// GetEventHistogram retrieves the counts of events matching opts per bucket
+// of width bucket, in a single request. Limit and Cursor are ignored.
+func (c *Client) GetEventHistogram(ctx context.Context, opts QueryOptions, bucket time.Duration) (*EventHistogram, error) {
+	params := queryParams(opts)
+	params.Del("limit")
+	params.Del("cursor")
+	params.Add("bucket", bucket.String())
+	reqURL := fmt.Sprintf("%s/api/v1/histogram?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
+	}
+
+	var histogram EventHistogram
+	if err := json.NewDecoder(resp.Body).Decode(&histogram); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
 
-	return allEvents, nil
+	return &histogram, nil
 }
@@ -0,0 +1,462 @@
+package audit
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueryEventsBareArray(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("envelope") != "" {
+			t.Errorf("bare query should not request the envelope")
+		}
+		w.Write([]byte(`[{"verb":"create","resourceName":"web"}]`))
+	}))
+	defer server.Close()
+
+	events, err := NewClient(server.URL).QueryEvents(context.Background(), QueryOptions{})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].ResourceName != "web" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
+
+func TestQueryEventsPageEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("envelope") != "true" {
+			t.Errorf("expected envelope=true")
+		}
+		if r.URL.Query().Get("cursor") != "abc" {
+			t.Errorf("expected cursor to be forwarded")
+		}
+		w.Write([]byte(`{"items":[{"verb":"update","resourceName":"web"}],"count":1,"hasMore":true,"nextCursor":"def"}`))
+	}))
+	defer server.Close()
+
+	page, err := NewClient(server.URL).QueryEventsPage(context.Background(), QueryOptions{Cursor: "abc"})
+	if err != nil {
+		t.Fatalf("QueryEventsPage failed: %v", err)
+	}
+	if len(page.Items) != 1 || page.Count != 1 || !page.HasMore || page.NextCursor != "def" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+}
+
+func TestQueryEventsTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("envelope") == "true" {
+			if r.URL.Query().Get("cursor") == "" {
+				w.Write([]byte(`{"items":[{"resourceName":"web"}],"count":1,"hasMore":true,"nextCursor":"def","truncated":true}`))
+			} else {
+				w.Write([]byte(`{"items":[],"count":0,"hasMore":true,"nextCursor":"def","truncated":true}`))
+			}
+			return
+		}
+		w.Header().Set("Trailer", "X-Truncated")
+		w.Write([]byte(`[{"resourceName":"web"}]`))
+		w.Header().Set("X-Truncated", "true")
+	}))
+	defer server.Close()
+	client := NewClient(server.URL)
+
+	events, err := client.QueryEvents(context.Background(), QueryOptions{})
+	if !errors.Is(err, ErrResponseTruncated) {
+		t.Errorf("expected ErrResponseTruncated, got %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("expected the events received, got %+v", events)
+	}
+
+	page, err := client.QueryEventsPage(context.Background(), QueryOptions{})
+	if err != nil {
+		t.Fatalf("QueryEventsPage failed: %v", err)
+	}
+	if !page.Truncated || !page.HasMore || page.NextCursor != "def" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	// A page no event fit into can't make progress
+	if _, err := client.QueryEventsPage(context.Background(), QueryOptions{Cursor: "def"}); !errors.Is(err, ErrResponseTruncated) {
+		t.Errorf("expected ErrResponseTruncated, got %v", err)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, `{"error":"missing or invalid bearer token"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"resourceName":"web"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if _, err := client.QueryEvents(context.Background(), QueryOptions{}); err == nil {
+		t.Error("expected an unauthenticated request to fail")
+	}
+
+	client.SetBearerToken("secret")
+	if _, err := client.QueryEvents(context.Background(), QueryOptions{}); err != nil {
+		t.Errorf("QueryEvents failed: %v", err)
+	}
+}
+
+func TestBearerTokenOption(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, `{"error":"missing or invalid bearer token"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"resourceName":"web"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL, ClientOptions{BearerToken: "secret"})
+	if _, err := client.QueryEvents(context.Background(), QueryOptions{}); err != nil {
+		t.Errorf("QueryEvents failed: %v", err)
+	}
+}
+
+func TestTimeoutOption(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	client := NewClientWithOptions(server.URL, ClientOptions{Timeout: 50 * time.Millisecond})
+	if _, err := client.QueryEvents(context.Background(), QueryOptions{}); err == nil {
+		t.Fatal("expected the slow request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the request to give up after the timeout, took %s", elapsed)
+	}
+}
+
+func TestTLSConfigOption(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"resourceName":"web"}]`))
+	}))
+	// Silence the log of the rejected handshake
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	if _, err := NewClientWithOptions(server.URL, ClientOptions{MaxAttempts: 1}).QueryEvents(context.Background(), QueryOptions{}); err == nil {
+		t.Error("expected the self-signed certificate to be rejected")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig, err := LoadTLSConfig(caFile, "", "")
+	if err != nil {
+		t.Fatalf("LoadTLSConfig failed: %v", err)
+	}
+	if _, err := NewClientWithOptions(server.URL, ClientOptions{TLSConfig: tlsConfig}).QueryEvents(context.Background(), QueryOptions{}); err != nil {
+		t.Errorf("QueryEvents failed: %v", err)
+	}
+}
+
+func TestLoadTLSConfigInvalid(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.crt")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTLSConfig(empty, "", ""); err == nil {
+		t.Error("expected a CA file without certificates to be rejected")
+	}
+	if _, err := LoadTLSConfig("", "tls.crt", ""); err == nil {
+		t.Error("expected a certificate without key to be rejected")
+	}
+}
+
+func TestGetRecentChangesSingleRequest(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if verbs := strings.Join(r.URL.Query()["verb"], ","); verbs != "create,update,patch,delete" {
+			t.Errorf("expected every mutating verb in one request, got %q", verbs)
+		}
+		w.Write([]byte(`[
+			{"timestamp":"2025-03-04T10:00:02Z","verb":"delete","resourceType":"pods","resourceName":"c"},
+			{"timestamp":"2025-03-04T10:00:00Z","verb":"create","resourceType":"pods","resourceName":"a"},
+			{"timestamp":"2025-03-04T10:00:01Z","verb":"patch","resourceType":"configmaps","resourceName":"b"}
+		]`))
+	}))
+	defer server.Close()
+
+	events, err := NewClient(server.URL).GetRecentChanges(context.Background(), time.Time{}, time.Time{}, []string{"Pods"})
+	if err != nil {
+		t.Fatalf("GetRecentChanges failed: %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected a single request, got %d", requests.Load())
+	}
+	if len(events) != 2 || events[0].ResourceName != "a" || events[1].ResourceName != "c" {
+		t.Errorf("expected the pod events oldest first, got %+v", events)
+	}
+}
+
+func TestQueryEventsNamespaces(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if namespaces := r.URL.Query()["namespace"]; len(namespaces) != 1 || namespaces[0] != "app,app-canary,app-staging" {
+			t.Errorf("expected the namespaces in one comma-separated parameter, got %q", namespaces)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).QueryEvents(context.Background(), QueryOptions{
+		Namespace:  "app",
+		Namespaces: []string{"app-canary", "app-staging"},
+	})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+}
+
+// writeError writes an API error envelope
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error":%q,"code":%q}`, msg, code)
+}
+
+func TestQueryEventsNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "no_data", "no audit data")
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).QueryEvents(context.Background(), QueryOptions{})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("expected ErrNoData, got %v", err)
+	}
+}
+
+func TestAPIErrorDecoded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/events":
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid start time")
+		default:
+			// An unknown route is not an empty result
+			writeError(w, http.StatusNotFound, "not_found", "no such endpoint")
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.QueryEvents(context.Background(), QueryOptions{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Code != "bad_request" {
+		t.Fatalf("expected a bad_request APIError, got %v", err)
+	}
+	if err.Error() != "API returned status 400: invalid start time" {
+		t.Errorf("expected the message without the JSON body, got %q", err.Error())
+	}
+
+	_, err = client.GetEventSummary(context.Background(), time.Now().Add(-time.Hour), time.Now())
+	if errors.Is(err, ErrNoData) || !errors.As(err, &apiErr) || apiErr.Code != "not_found" {
+		t.Errorf("expected a not_found APIError, got %v", err)
+	}
+}
+
+func TestGetEventSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/events/summary" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("start") == "" || r.URL.Query().Get("end") == "" {
+			t.Errorf("expected start and end to be forwarded")
+		}
+		w.Write([]byte(`{"total":3,"counts":{"default":{"pods":2},"":{"nodes":1}}}`))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	summary, err := NewClient(server.URL).GetEventSummary(context.Background(), now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("GetEventSummary failed: %v", err)
+	}
+	if summary.Total != 3 || summary.Counts["default"]["pods"] != 2 || summary.Counts[""]["nodes"] != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestGetObjectHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/events/default/pods/web" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"namespace":"default","resourceType":"pods","resourceName":"web",` +
+			`"watchEvents":[{"verb":"create"},{"verb":"update"}],"relatedEvents":[{"verb":"create","resourceType":"events"}]}`))
+	}))
+	defer server.Close()
+
+	history, err := NewClient(server.URL).GetObjectHistory(context.Background(), "default", "pods", "web")
+	if err != nil {
+		t.Fatalf("GetObjectHistory failed: %v", err)
+	}
+	if history.ResourceName != "web" || len(history.WatchEvents) != 2 || len(history.RelatedEvents) != 1 {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+func TestGetObjectHistoryNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "no_data", "no events found for this object")
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL).GetObjectHistory(context.Background(), "default", "pods", "web"); !errors.Is(err, ErrNoData) {
+		t.Errorf("expected ErrNoData, got %v", err)
+	}
+}
+
+func TestGetEventHistogram(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/v1/histogram" || query.Get("bucket") != "5m0s" || query.Get("namespace") != "shop" ||
+			query.Get("start") == "" || query.Has("limit") {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"bucket":"5m0s","total":3,"buckets":[{"start":"2025-03-04T10:00:00Z","count":1},{"start":"2025-03-04T10:05:00Z","count":2}]}`))
+	}))
+	defer server.Close()
+
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	histogram, err := NewClient(server.URL).GetEventHistogram(context.Background(), QueryOptions{
+		StartTime: start, EndTime: start.Add(10 * time.Minute), Namespace: "shop", Limit: 10,
+	}, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("GetEventHistogram failed: %v", err)
+	}
+	if histogram.Total != 3 || len(histogram.Buckets) != 2 || !histogram.Buckets[1].Start.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("unexpected histogram: %+v", histogram)
+	}
+}
+
+func TestGetRecentEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/recent" || r.URL.Query().Get("limit") != "2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[{"verb":"update","resourceName":"b"},{"verb":"create","resourceName":"a"}]`))
+	}))
+	defer server.Close()
+
+	events, err := NewClient(server.URL).GetRecentEvents(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("GetRecentEvents failed: %v", err)
+	}
+	if len(events) != 2 || events[0].ResourceName != "b" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
+
+// flakyServer fails the first failures requests with status, then returns
+// one event; it counts the requests it served
+func flakyServer(t *testing.T, failures, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(requests.Add(1)) <= failures {
+			http.Error(w, "restarting", status)
+			return
+		}
+		w.Write([]byte(`[{"resourceName":"web"}]`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestQueryEventsRetries(t *testing.T) {
+	opts := ClientOptions{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	for _, status := range []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		server, requests := flakyServer(t, 3, status)
+		events, err := NewClientWithOptions(server.URL, opts).QueryEvents(context.Background(), QueryOptions{})
+		if err != nil {
+			t.Fatalf("%d: expected the fourth attempt to succeed, got %v", status, err)
+		}
+		if len(events) != 1 || requests.Load() != 4 {
+			t.Errorf("%d: expected 1 event after 4 requests, got %d after %d", status, len(events), requests.Load())
+		}
+	}
+
+	// Attempts are capped
+	server, requests := flakyServer(t, 10, http.StatusServiceUnavailable)
+	if _, err := NewClientWithOptions(server.URL, opts).QueryEvents(context.Background(), QueryOptions{}); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("expected the last 503 to be returned, got %v", err)
+	}
+	if requests.Load() != 4 {
+		t.Errorf("expected 4 attempts, got %d", requests.Load())
+	}
+
+	// Final answers aren't retried
+	for _, status := range []int{http.StatusNotFound, http.StatusInternalServerError, http.StatusUnauthorized} {
+		server, requests := flakyServer(t, 1, status)
+		if _, err := NewClientWithOptions(server.URL, opts).QueryEvents(context.Background(), QueryOptions{}); err == nil {
+			t.Errorf("%d: expected an error", status)
+		}
+		if requests.Load() != 1 {
+			t.Errorf("%d: expected a single attempt, got %d", status, requests.Load())
+		}
+	}
+}
+
+func TestQueryEventsRetriesNetworkErrors(t *testing.T) {
+	// A closed server refuses connections
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	start := time.Now()
+	client := NewClientWithOptions(server.URL, ClientOptions{MaxAttempts: 3, BaseDelay: 20 * time.Millisecond})
+	if _, err := client.QueryEvents(context.Background(), QueryOptions{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	// Two backoffs of at least half of 20ms and 40ms
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected the client to back off between attempts, took %s", elapsed)
+	}
+}
+
+func TestQueryEventsRetriesRespectDeadline(t *testing.T) {
+	server, requests := flakyServer(t, 10, http.StatusServiceUnavailable)
+	client := NewClientWithOptions(server.URL, ClientOptions{MaxAttempts: 10, BaseDelay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.QueryEvents(ctx, QueryOptions{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	// The first backoff would outlast the deadline
+	if requests.Load() != 1 || time.Since(start) > 150*time.Millisecond {
+		t.Errorf("expected to give up right away, got %d requests in %s", requests.Load(), time.Since(start))
+	}
+}
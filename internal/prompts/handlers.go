@@ -246,9 +246,9 @@ Investigation Steps:
    - Check for disk full events
 
 4. **Verify Pod Attachment**
-   - Run check_pod_issues for namespace %s
-   - Find pods trying to use this PVC
-   - Check if pods are stuck in ContainerCreating state
+   - Run correlate_pvc_pod_stalls for namespace %s
+   - Find pods stuck in ContainerCreating because of this PVC
+   - Check whether the claim is unbound or failing to mount
 
 5. **Review Recent Changes**
    - Run analyze_recent_changes
@@ -299,3 +299,73 @@ Please run the diagnostic tools to identify the root cause.`, pvcName, namespace
 		},
 	}, nil
 }
+
+// InvestigatePrivilegeEscalation guides investigation of RBAC privilege escalation
+func (h *PromptHandlers) InvestigatePrivilegeEscalation(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	subject := request.Params.Arguments["subject"]
+	timeWindow := request.Params.Arguments["time_window"]
+
+	if timeWindow == "" {
+		timeWindow = "24 hours"
+	}
+
+	prompt := fmt.Sprintf(`I need to investigate whether "%s" gained privileges it should not have.
+
+Time Window: Last %s
+
+What the Data Can Show:
+- Events are recorded by a watcher, not taken from the API server audit log.
+  Their user field is the watcher's, so it does not tell who made a change
+  or what %s did.
+- Only object changes are recorded. Reads (e.g. of Secrets) and exec into
+  pods never appear.
+- The available signal is RBAC object changes: Roles, ClusterRoles,
+  RoleBindings and ClusterRoleBindings being created or updated, and what
+  they grant.
+
+Investigation Steps:
+
+1. **Find RBAC Changes**
+   - Run check_rbac_changes for the last %s
+   - Look for RoleBindings and ClusterRoleBindings that name %s as a subject
+   - Pay special attention to flagged grants of cluster-admin or wildcard verbs
+   - Note any Roles or ClusterRoles edited to add verbs or resources
+
+2. **Trace How Each Grant Came About**
+   - Run show_change_diff on suspicious bindings and roles to see exactly what changed
+   - Run get_object_timeline on them to see when they were created, edited or deleted
+   - Check their labels, annotations and ownerReferences to tell grants
+     managed by Helm, GitOps or an operator from manual ones
+   - Check whether roles %s was already bound to were edited
+
+3. **Check Service Account Changes**
+   - If %s is a service account (system:serviceaccount:<namespace>:<name>),
+     run audit_serviceaccount_changes for its namespace
+   - Look for new tokens or service accounts created around the RBAC change
+
+4. **Review What Changed After the Grant**
+   - Access audit://events/{namespace} for the namespaces the new bindings cover
+   - Look for changes the new permissions allow that followed the grant:
+     new RoleBindings, changed Secrets, workloads created in kube-system
+   - These changes cannot be attributed to %s; treat them as correlated
+     in time, not as proof
+
+Escalation Patterns to Look For:
+- A binding that grants the subject (or a group it belongs to) a more powerful role
+- Roles edited to include "*" verbs or resources, "escalate", "bind" or "impersonate"
+- Short-lived bindings created and deleted within the window
+- Grants not managed by the usual deployment tooling, or made after hours
+
+Please run the diagnostic tools and report which RBAC changes granted %s new privileges, when they happened and what changed afterwards in the namespaces they cover. State that the data cannot show who made the changes or what %s read or executed.`,
+		subject, timeWindow, subject, timeWindow, subject, subject, subject, subject, subject, subject)
+
+	return &mcp.GetPromptResult{
+		Description: fmt.Sprintf("Privilege escalation investigation for %s", subject),
+		Messages: []mcp.PromptMessage{
+			{
+				Role:    mcp.RoleUser,
+				Content: mcp.NewTextContent(prompt),
+			},
+		},
+	}, nil
+}
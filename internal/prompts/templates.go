@@ -0,0 +1,223 @@
+package prompts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a prompt loaded from a template file, rendered with the
+// arguments of each request
+type Template struct {
+	Name        string             `yaml:"name"`
+	Description string             `yaml:"description"`
+	Arguments   []TemplateArgument `yaml:"arguments"`
+	// Template is the text/template body of a YAML template file. Markdown
+	// template files hold it after their front matter.
+	Template string `yaml:"template"`
+
+	body *template.Template
+}
+
+// TemplateArgument is an argument of a template prompt
+type TemplateArgument struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	// Default is used when the argument is not given
+	Default string `yaml:"default"`
+}
+
+// templateFuncs are available in template bodies in addition to the
+// text/template builtins
+var templateFuncs = template.FuncMap{
+	// default returns value, or fallback when value is empty:
+	// {{ default "1 hour" .time_window }}
+	"default": func(fallback, value string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// LoadTemplates reads the prompt templates of a directory, in file name
+// order. YAML files (.yaml, .yml) declare the name, description, arguments
+// and template body:
+//
+//	name: restart_runbook
+//	description: Steps before restarting a workload
+//	arguments:
+//	  - name: namespace
+//	    required: true
+//	  - name: time_window
+//	    default: 1 hour
+//	template: |
+//	  Check {{ .namespace }} for changes in the last {{ .time_window }}.
+//
+// Markdown files (.md) declare the same fields in YAML front matter between
+// "---" lines and hold the template body after it. Bodies are Go
+// text/template and reference arguments by name; arguments that are not
+// given read as their default or empty. Other files are skipped.
+func LoadTemplates(path string) ([]*Template, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt templates: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var templates []*Template
+	names := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		file := filepath.Join(path, entry.Name())
+		var t *Template
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml":
+			t, err = loadYAMLTemplate(file)
+		case ".md":
+			t, err = loadMarkdownTemplate(file)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if other, ok := names[t.Name]; ok {
+			return nil, fmt.Errorf("%s: prompt %q is already defined in %s", entry.Name(), t.Name, other)
+		}
+		names[t.Name] = entry.Name()
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// loadYAMLTemplate reads a template declared entirely in YAML
+func loadYAMLTemplate(file string) (*Template, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse template YAML: %w", err)
+	}
+	return &t, t.parse()
+}
+
+// loadMarkdownTemplate reads a template from YAML front matter and the
+// Markdown body after it
+func loadMarkdownTemplate(file string) (*Template, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	rest, ok := strings.CutPrefix(content, "---\n")
+	if !ok {
+		return nil, fmt.Errorf("missing front matter")
+	}
+	frontMatter, body, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		return nil, fmt.Errorf("unterminated front matter")
+	}
+
+	var t Template
+	if err := yaml.Unmarshal([]byte(frontMatter), &t); err != nil {
+		return nil, fmt.Errorf("failed to parse front matter: %w", err)
+	}
+	t.Template = strings.TrimLeft(body, "\n")
+	return &t, t.parse()
+}
+
+// parse validates the declaration and parses the template body
+func (t *Template) parse() error {
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(t.Template) == "" {
+		return fmt.Errorf("prompt %q has an empty template", t.Name)
+	}
+	seen := make(map[string]bool)
+	for _, arg := range t.Arguments {
+		if arg.Name == "" {
+			return fmt.Errorf("prompt %q has an argument without a name", t.Name)
+		}
+		if seen[arg.Name] {
+			return fmt.Errorf("prompt %q declares argument %q twice", t.Name, arg.Name)
+		}
+		seen[arg.Name] = true
+	}
+
+	body, err := template.New(t.Name).Funcs(templateFuncs).Option("missingkey=zero").Parse(t.Template)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	t.body = body
+	return nil
+}
+
+// Prompt returns the MCP prompt declaration of the template
+func (t *Template) Prompt() mcp.Prompt {
+	opts := []mcp.PromptOption{mcp.WithPromptDescription(t.Description)}
+	for _, arg := range t.Arguments {
+		argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(arg.Description)}
+		if arg.Required {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+		}
+		opts = append(opts, mcp.WithArgument(arg.Name, argOpts...))
+	}
+	return mcp.NewPrompt(t.Name, opts...)
+}
+
+// Render executes the template with args. Declared arguments that are not
+// given take their default; a missing required argument is an error.
+func (t *Template) Render(args map[string]string) (string, error) {
+	data := make(map[string]string, len(args)+len(t.Arguments))
+	for name, value := range args {
+		data[name] = value
+	}
+	for _, arg := range t.Arguments {
+		if data[arg.Name] != "" {
+			continue
+		}
+		if arg.Required {
+			return "", fmt.Errorf("missing required argument %q", arg.Name)
+		}
+		data[arg.Name] = arg.Default
+	}
+
+	var out bytes.Buffer
+	if err := t.body.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %q: %w", t.Name, err)
+	}
+	return out.String(), nil
+}
+
+// Handle is the prompt handler of the template
+func (t *Template) Handle(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	prompt, err := t.Render(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcp.GetPromptResult{
+		Description: t.Description,
+		Messages: []mcp.PromptMessage{
+			{
+				Role:    mcp.RoleUser,
+				Content: mcp.NewTextContent(prompt),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,155 @@
+package prompts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// writeTemplates writes files into a temporary template directory
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const runbookYAML = `name: restart_runbook
+description: Steps before restarting a workload
+arguments:
+  - name: workload
+    description: Deployment to restart
+    required: true
+  - name: namespace
+    default: default
+  - name: time_window
+template: |
+  Restart {{ .workload }} in {{ .namespace }} after checking the last {{ default "1 hour" .time_window }}.{{ if .ticket }} Ticket: {{ .ticket }}.{{ end }}
+`
+
+const outageMarkdown = `---
+name: outage_runbook
+description: First steps of an outage
+arguments:
+  - name: namespace
+    required: true
+---
+# Outage in {{ .namespace }}
+
+Run summarize_cluster_issues for {{ .namespace }}.
+`
+
+func TestLoadTemplates(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"restart.yaml": runbookYAML,
+		"outage.md":    outageMarkdown,
+		"README.txt":   "not a template",
+	})
+
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "outage_runbook" || templates[1].Name != "restart_runbook" {
+		t.Fatalf("expected both templates in file name order, got %+v", templates)
+	}
+
+	prompt := templates[1].Prompt()
+	if prompt.Name != "restart_runbook" || prompt.Description != "Steps before restarting a workload" || len(prompt.Arguments) != 3 {
+		t.Fatalf("unexpected prompt %+v", prompt)
+	}
+	if !prompt.Arguments[0].Required || prompt.Arguments[1].Required {
+		t.Errorf("expected only workload to be required, got %+v", prompt.Arguments)
+	}
+
+	rendered, err := templates[0].Render(map[string]string{"namespace": "payments"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.HasPrefix(rendered, "# Outage in payments\n") || !strings.Contains(rendered, "summarize_cluster_issues for payments") {
+		t.Errorf("unexpected Markdown rendering %q", rendered)
+	}
+}
+
+func TestTemplateRender(t *testing.T) {
+	templates, err := LoadTemplates(writeTemplates(t, map[string]string{"restart.yaml": runbookYAML}))
+	if err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+	runbook := templates[0]
+
+	tests := []struct {
+		name string
+		args map[string]string
+		want string
+	}{
+		{
+			name: "all arguments",
+			args: map[string]string{"workload": "web", "namespace": "shop", "time_window": "2 hours", "ticket": "OPS-1"},
+			want: "Restart web in shop after checking the last 2 hours. Ticket: OPS-1.\n",
+		},
+		{
+			name: "declared and inline defaults",
+			args: map[string]string{"workload": "web"},
+			want: "Restart web in default after checking the last 1 hour.\n",
+		},
+	}
+	for _, tt := range tests {
+		got, err := runbook.Render(tt.args)
+		if err != nil {
+			t.Fatalf("%s: Render failed: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+
+	if _, err := runbook.Render(map[string]string{"namespace": "shop"}); err == nil || !strings.Contains(err.Error(), `"workload"`) {
+		t.Errorf("expected the missing required argument to be reported, got %v", err)
+	}
+
+	var request mcp.GetPromptRequest
+	request.Params.Arguments = map[string]string{"workload": "web"}
+	result, err := runbook.Handle(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if len(result.Messages) != 1 || result.Description != runbook.Description {
+		t.Errorf("unexpected prompt result %+v", result)
+	}
+}
+
+func TestLoadTemplatesInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"missing name", "template: hello\n", "name is required"},
+		{"empty template", "name: empty\n", "empty template"},
+		{"invalid template", "name: broken\ntemplate: '{{ .x '\n", "failed to parse template"},
+		{"duplicate argument", "name: dup\narguments: [{name: a}, {name: a}]\ntemplate: x\n", `argument "a" twice`},
+	}
+	for _, tt := range tests {
+		_, err := LoadTemplates(writeTemplates(t, map[string]string{"prompt.yaml": tt.content}))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	_, err := LoadTemplates(writeTemplates(t, map[string]string{
+		"a.yaml": "name: same\ntemplate: a\n",
+		"b.md":   "---\nname: same\n---\nb\n",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "already defined in a.yaml") {
+		t.Errorf("expected duplicate prompt names to be rejected, got %v", err)
+	}
+}
@@ -3,8 +3,8 @@ package resources
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -23,35 +23,40 @@ func NewResourceHandlers(auditClient *audit.Client) *ResourceHandlers {
 	}
 }
 
-// parseURIPath extracts components from a URI path
-func parseURIPath(uri string) map[string]string {
-	parts := make(map[string]string)
-
-	// Remove scheme
-	uri = strings.TrimPrefix(uri, "audit://")
-
-	// Split by /
-	segments := strings.Split(uri, "/")
-
-	if len(segments) >= 2 {
-		parts["type"] = segments[0]
-		parts["param1"] = segments[1]
-	}
-	if len(segments) >= 3 {
-		parts["param2"] = segments[2]
+// Dispatch validates an audit:// URI and routes it to the matching handler.
+// Every registered template points here, so overlapping templates resolve the
+// same way regardless of which one the MCP server matched first.
+func (h *ResourceHandlers) Dispatch(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	match, err := parseURIPath(request.Params.URI)
+	if err != nil {
+		return nil, err
 	}
 
-	return parts
+	switch match.Pattern.Template {
+	case namespaceEventsPattern.Template:
+		return h.HandleNamespaceEvents(ctx, request)
+	case resourceTypeEventsPattern.Template:
+		return h.HandleResourceTypeEvents(ctx, request)
+	case recentChangesPattern.Template:
+		return h.HandleRecentChanges(ctx, request)
+	case nodeEventsPattern.Template:
+		return h.HandleNodeEvents(ctx, request)
+	case clusterEventsPattern.Template:
+		return h.HandleClusterEvents(ctx, request)
+	case objectHistoryPattern.Template:
+		return h.HandleObjectHistory(ctx, request)
+	default:
+		return nil, unsupportedURIError(request.Params.URI)
+	}
 }
 
 // HandleNamespaceEvents returns audit events for a specific namespace
 func (h *ResourceHandlers) HandleNamespaceEvents(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	parts := parseURIPath(request.Params.URI)
-	namespace := parts["param1"]
-
-	if namespace == "" {
-		return nil, fmt.Errorf("namespace not specified in URI")
+	params, err := expectPattern(request.Params.URI, namespaceEventsPattern)
+	if err != nil {
+		return nil, err
 	}
+	namespace := params["namespace"]
 
 	// Default to last 24 hours
 	endTime := time.Now()
@@ -86,13 +91,12 @@ func (h *ResourceHandlers) HandleNamespaceEvents(ctx context.Context, request mc
 
 // HandleResourceTypeEvents returns audit events for a specific resource type in a namespace
 func (h *ResourceHandlers) HandleResourceTypeEvents(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	parts := parseURIPath(request.Params.URI)
-	namespace := parts["param1"]
-	resourceType := parts["param2"]
-
-	if namespace == "" || resourceType == "" {
-		return nil, fmt.Errorf("namespace and resource type must be specified in URI")
+	params, err := expectPattern(request.Params.URI, resourceTypeEventsPattern)
+	if err != nil {
+		return nil, err
 	}
+	namespace := params["namespace"]
+	resourceType := params["resource-type"]
 
 	// Default to last 24 hours
 	endTime := time.Now()
@@ -128,8 +132,11 @@ func (h *ResourceHandlers) HandleResourceTypeEvents(ctx context.Context, request
 
 // HandleRecentChanges returns recent modification events
 func (h *ResourceHandlers) HandleRecentChanges(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	parts := parseURIPath(request.Params.URI)
-	timeRange := parts["param1"]
+	params, err := expectPattern(request.Params.URI, recentChangesPattern)
+	if err != nil {
+		return nil, err
+	}
+	timeRange := params["time-range"]
 
 	var startTime time.Time
 	endTime := time.Now()
@@ -174,12 +181,11 @@ func (h *ResourceHandlers) HandleRecentChanges(ctx context.Context, request mcp.
 
 // HandleNodeEvents returns audit events for a specific node
 func (h *ResourceHandlers) HandleNodeEvents(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	parts := parseURIPath(request.Params.URI)
-	nodeName := parts["param1"]
-
-	if nodeName == "" {
-		return nil, fmt.Errorf("node name not specified in URI")
+	params, err := expectPattern(request.Params.URI, nodeEventsPattern)
+	if err != nil {
+		return nil, err
 	}
+	nodeName := params["node-name"]
 
 	// Default to last 24 hours
 	endTime := time.Now()
@@ -211,3 +217,92 @@ func (h *ResourceHandlers) HandleNodeEvents(ctx context.Context, request mcp.Rea
 		},
 	}, nil
 }
+
+// HandleClusterEvents returns audit events for a cluster-scoped resource type
+// such as nodes or persistentvolumes
+func (h *ResourceHandlers) HandleClusterEvents(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	params, err := expectPattern(request.Params.URI, clusterEventsPattern)
+	if err != nil {
+		return nil, err
+	}
+	resourceType := params["resource-type"]
+
+	// Default to last 24 hours
+	endTime := time.Now()
+	startTime := endTime.Add(-24 * time.Hour)
+
+	// An empty namespace filter matches every namespace, so keep only the
+	// events of objects that have none
+	events, err := h.auditClient.GetResourceTypeEvents(ctx, "", resourceType, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cluster events: %w", err)
+	}
+	clusterEvents := make([]audit.AuditEvent, 0, len(events))
+	for _, event := range events {
+		if event.Namespace == "" {
+			clusterEvents = append(clusterEvents, event)
+		}
+	}
+
+	data, err := json.MarshalIndent(map[string]any{
+		"resourceType": resourceType,
+		"timeRange": map[string]string{
+			"start": startTime.Format(time.RFC3339),
+			"end":   endTime.Format(time.RFC3339),
+		},
+		"eventCount": len(clusterEvents),
+		"events":     clusterEvents,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+// HandleObjectHistory returns the full history of a single object: its watch
+// events and the Kubernetes Events that refer to it
+func (h *ResourceHandlers) HandleObjectHistory(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	params, err := expectPattern(request.Params.URI, objectHistoryPattern)
+	if err != nil {
+		return nil, err
+	}
+	namespace := params["namespace"]
+	resourceType := params["resource-type"]
+	name := params["name"]
+
+	history, err := h.auditClient.GetObjectHistory(ctx, namespace, resourceType, name)
+	if errors.Is(err, audit.ErrNoData) {
+		// An object without events has an empty history
+		history = &audit.ObjectHistory{WatchEvents: []audit.AuditEvent{}, RelatedEvents: []audit.AuditEvent{}}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch object history: %w", err)
+	}
+
+	data, err := json.MarshalIndent(map[string]any{
+		"namespace":         namespace,
+		"resourceType":      resourceType,
+		"resourceName":      name,
+		"watchEventCount":   len(history.WatchEvents),
+		"watchEvents":       history.WatchEvents,
+		"relatedEventCount": len(history.RelatedEvents),
+		"relatedEvents":     history.RelatedEvents,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
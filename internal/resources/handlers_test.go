@@ -0,0 +1,142 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// readClusterEvents dispatches a cluster-events URI against a fake watch
+// server that returns body for the requested resource type
+func readClusterEvents(t *testing.T, uri, wantResourceType, body string) map[string]any {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("resourceType") != wantResourceType {
+			t.Errorf("expected resourceType=%s, got %q", wantResourceType, query.Get("resourceType"))
+		}
+		if query.Get("namespace") != "" {
+			t.Errorf("expected no namespace filter, got %q", query.Get("namespace"))
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	contents, err := NewResourceHandlers(audit.NewClient(server.URL)).Dispatch(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	if len(contents) != 1 {
+		t.Fatalf("expected 1 resource content, got %d", len(contents))
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	return result
+}
+
+func TestClusterEventsNodes(t *testing.T) {
+	result := readClusterEvents(t, "audit://cluster-events/nodes", "nodes",
+		`[{"verb":"update","resourceType":"nodes","resourceName":"node-1"},{"verb":"create","resourceType":"nodes","resourceName":"node-2"}]`)
+
+	if result["resourceType"] != "nodes" || result["eventCount"] != float64(2) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestClusterEventsPersistentVolumesSkipsNamespaced(t *testing.T) {
+	result := readClusterEvents(t, "audit://cluster-events/persistentvolumes", "persistentvolumes",
+		`[{"verb":"create","resourceType":"persistentvolumes","resourceName":"pv-1"},{"verb":"create","namespace":"default","resourceType":"persistentvolumes","resourceName":"bogus"}]`)
+
+	events, _ := result["events"].([]any)
+	if result["eventCount"] != float64(1) || len(events) != 1 {
+		t.Fatalf("expected only the cluster-scoped event, got %+v", result)
+	}
+	if name := events[0].(map[string]any)["resourceName"]; name != "pv-1" {
+		t.Errorf("expected pv-1, got %v", name)
+	}
+}
+
+func TestObjectHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/events/default/pods/web" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"namespace":"default","resourceType":"pods","resourceName":"web",` +
+			`"watchEvents":[{"verb":"create","resourceName":"web"}],"relatedEvents":[{"verb":"create","resourceType":"events","message":"BackOff: Back-off restarting failed container"}]}`))
+	}))
+	defer server.Close()
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "audit://object/default/pods/web"
+	contents, err := NewResourceHandlers(audit.NewClient(server.URL)).Dispatch(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result["resourceName"] != "web" || result["watchEventCount"] != float64(1) || result["relatedEventCount"] != float64(1) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestObjectHistoryEmpty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"no events found for this object","code":"no_data"}`))
+	}))
+	defer server.Close()
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "audit://object/default/pods/gone"
+	contents, err := NewResourceHandlers(audit.NewClient(server.URL)).Dispatch(context.Background(), request)
+	if err != nil {
+		t.Fatalf("expected an empty history, got %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if events, ok := result["watchEvents"].([]any); !ok || len(events) != 0 || result["watchEventCount"] != float64(0) {
+		t.Errorf("expected no watch events, got %+v", result)
+	}
+}
+
+func TestObjectHistoryEncodedName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v1/events/default/pods/my%2Fpod" {
+			t.Errorf("expected the name to stay one path segment, got %s", r.URL.EscapedPath())
+		}
+		w.Write([]byte(`{"watchEvents":[],"relatedEvents":[]}`))
+	}))
+	defer server.Close()
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "audit://object/default/pods/my%2Fpod"
+	contents, err := NewResourceHandlers(audit.NewClient(server.URL)).Dispatch(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result["resourceName"] != "my/pod" {
+		t.Errorf("expected the decoded name, got %v", result["resourceName"])
+	}
+}
@@ -0,0 +1,104 @@
+package resources
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// uriScheme is the scheme of all audit resource URIs
+const uriScheme = "audit://"
+
+// uriPattern describes one supported audit:// URI template
+type uriPattern struct {
+	// Template is the URI template as registered with the MCP server
+	Template string
+	// Type is the first path segment, e.g. "events"
+	Type string
+	// Params names the remaining path segments in order
+	Params []string
+}
+
+// Supported resource URI patterns
+var (
+	namespaceEventsPattern    = uriPattern{Template: "audit://events/{namespace}", Type: "events", Params: []string{"namespace"}}
+	resourceTypeEventsPattern = uriPattern{Template: "audit://events/{namespace}/{resource-type}", Type: "events", Params: []string{"namespace", "resource-type"}}
+	recentChangesPattern      = uriPattern{Template: "audit://changes/{time-range}", Type: "changes", Params: []string{"time-range"}}
+	nodeEventsPattern         = uriPattern{Template: "audit://node-events/{node-name}", Type: "node-events", Params: []string{"node-name"}}
+	clusterEventsPattern      = uriPattern{Template: "audit://cluster-events/{resource-type}", Type: "cluster-events", Params: []string{"resource-type"}}
+	objectHistoryPattern      = uriPattern{Template: "audit://object/{namespace}/{resource-type}/{name}", Type: "object", Params: []string{"namespace", "resource-type", "name"}}
+)
+
+// uriPatterns lists every supported pattern; more specific patterns sharing a
+// Type are distinguished by segment count
+var uriPatterns = []uriPattern{
+	namespaceEventsPattern,
+	resourceTypeEventsPattern,
+	recentChangesPattern,
+	nodeEventsPattern,
+	clusterEventsPattern,
+	objectHistoryPattern,
+}
+
+// uriMatch is the result of matching a URI against the supported patterns
+type uriMatch struct {
+	Pattern uriPattern
+	Params  map[string]string
+}
+
+// parseURIPath matches a URI against the supported patterns and extracts its
+// named parameters. A trailing slash is ignored, and segments are
+// percent-decoded after splitting, so "my%2Fpod" names the object "my/pod".
+func parseURIPath(uri string) (*uriMatch, error) {
+	path, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok {
+		return nil, unsupportedURIError(uri)
+	}
+
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for i, segment := range segments {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource URI %q: %w", uri, err)
+		}
+		if decoded == "" {
+			return nil, unsupportedURIError(uri)
+		}
+		segments[i] = decoded
+	}
+
+	for _, pattern := range uriPatterns {
+		if pattern.Type != segments[0] || len(pattern.Params) != len(segments)-1 {
+			continue
+		}
+
+		params := make(map[string]string, len(pattern.Params))
+		for i, name := range pattern.Params {
+			params[name] = segments[i+1]
+		}
+		return &uriMatch{Pattern: pattern, Params: params}, nil
+	}
+
+	return nil, unsupportedURIError(uri)
+}
+
+// expectPattern parses a URI and requires it to match the given pattern
+func expectPattern(uri string, pattern uriPattern) (map[string]string, error) {
+	match, err := parseURIPath(uri)
+	if err != nil {
+		return nil, err
+	}
+	if match.Pattern.Template != pattern.Template {
+		return nil, fmt.Errorf("resource URI %q does not match %s", uri, pattern.Template)
+	}
+	return match.Params, nil
+}
+
+// unsupportedURIError lists the valid patterns for a URI that matched none of them
+func unsupportedURIError(uri string) error {
+	templates := make([]string, 0, len(uriPatterns))
+	for _, pattern := range uriPatterns {
+		templates = append(templates, pattern.Template)
+	}
+	return fmt.Errorf("unsupported resource URI %q; valid patterns: %s", uri, strings.Join(templates, ", "))
+}
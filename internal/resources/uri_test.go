@@ -0,0 +1,118 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseURIPathValid(t *testing.T) {
+	tests := []struct {
+		uri     string
+		pattern uriPattern
+		params  map[string]string
+	}{
+		{
+			uri:     "audit://events/default",
+			pattern: namespaceEventsPattern,
+			params:  map[string]string{"namespace": "default"},
+		},
+		{
+			uri:     "audit://events/default/pods",
+			pattern: resourceTypeEventsPattern,
+			params:  map[string]string{"namespace": "default", "resource-type": "pods"},
+		},
+		{
+			uri:     "audit://changes/7d",
+			pattern: recentChangesPattern,
+			params:  map[string]string{"time-range": "7d"},
+		},
+		{
+			uri:     "audit://node-events/node-1",
+			pattern: nodeEventsPattern,
+			params:  map[string]string{"node-name": "node-1"},
+		},
+		{
+			uri:     "audit://cluster-events/persistentvolumes",
+			pattern: clusterEventsPattern,
+			params:  map[string]string{"resource-type": "persistentvolumes"},
+		},
+		{
+			uri:     "audit://object/default/pods/web",
+			pattern: objectHistoryPattern,
+			params:  map[string]string{"namespace": "default", "resource-type": "pods", "name": "web"},
+		},
+		{
+			uri:     "audit://events/default/pods/",
+			pattern: resourceTypeEventsPattern,
+			params:  map[string]string{"namespace": "default", "resource-type": "pods"},
+		},
+		{
+			uri:     "audit://object/my%20ns/pods/my%2Fpod",
+			pattern: objectHistoryPattern,
+			params:  map[string]string{"namespace": "my ns", "resource-type": "pods", "name": "my/pod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			match, err := parseURIPath(tt.uri)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if match.Pattern.Template != tt.pattern.Template {
+				t.Errorf("expected pattern %s, got %s", tt.pattern.Template, match.Pattern.Template)
+			}
+			for name, value := range tt.params {
+				if match.Params[name] != value {
+					t.Errorf("expected %s=%s, got %q", name, value, match.Params[name])
+				}
+			}
+		})
+	}
+}
+
+func TestParseURIPathMalformed(t *testing.T) {
+	uris := []string{
+		"audit://",
+		"audit://events",
+		"audit://events/",
+		"audit://events//pods",
+		"audit://events/default/pods/web",
+		"audit://unknown/default",
+		"audit://node-events/node-1/extra",
+		"audit://cluster-events",
+		"audit://cluster-events/nodes/node-1",
+		"audit://object/default/pods",
+		"audit://object/default//web",
+		"audit://object/default/pods/web/extra",
+		"audit://object/default/pods/web//",
+		"audit://object/default/pods/%2F%2F/extra",
+		"http://events/default",
+		"events/default",
+	}
+
+	for _, uri := range uris {
+		t.Run(uri, func(t *testing.T) {
+			_, err := parseURIPath(uri)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), "unsupported resource URI") ||
+				!strings.Contains(err.Error(), namespaceEventsPattern.Template) {
+				t.Errorf("expected error listing valid patterns, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseURIPathInvalidEscape(t *testing.T) {
+	if _, err := parseURIPath("audit://object/default/pods/web%zz"); err == nil || !strings.Contains(err.Error(), "invalid resource URI") {
+		t.Errorf("expected invalid escape error, got %v", err)
+	}
+}
+
+func TestExpectPatternMismatch(t *testing.T) {
+	if _, err := expectPattern("audit://events/default/pods", namespaceEventsPattern); err == nil {
+		t.Error("expected error for URI matching a different pattern")
+	}
+}
@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// businessHours defines the local working window. When StartHour > EndHour the
+// window spans midnight (e.g. 22 to 6 for a night shift).
+type businessHours struct {
+	Location  *time.Location
+	StartHour int
+	EndHour   int
+	Weekends  bool // treat Saturday and Sunday as business days
+}
+
+// contains reports whether t falls inside business hours in the configured timezone
+func (b businessHours) contains(t time.Time) bool {
+	local := t.In(b.Location)
+	hour := local.Hour()
+
+	var inHours bool
+	if b.StartHour <= b.EndHour {
+		inHours = hour >= b.StartHour && hour < b.EndHour
+	} else {
+		inHours = hour >= b.StartHour || hour < b.EndHour
+	}
+	if !inHours {
+		return false
+	}
+	if b.Weekends {
+		return true
+	}
+
+	// An overnight shift belongs to the day it started on
+	day := local
+	if b.StartHour > b.EndHour && hour < b.EndHour {
+		day = local.AddDate(0, 0, -1)
+	}
+	return day.Weekday() != time.Saturday && day.Weekday() != time.Sunday
+}
+
+// parseBusinessHours reads the business-hours definition from a tool request
+func parseBusinessHours(request mcp.CallToolRequest) (businessHours, error) {
+	tzName := request.GetString("timezone", "UTC")
+	location, err := time.LoadLocation(tzName)
+	if err != nil {
+		return businessHours{}, fmt.Errorf("invalid timezone %q: %w", tzName, err)
+	}
+
+	hours := businessHours{
+		Location:  location,
+		StartHour: request.GetInt("business_start_hour", 9),
+		EndHour:   request.GetInt("business_end_hour", 17),
+		Weekends:  request.GetBool("include_weekends", false),
+	}
+	if hours.StartHour < 0 || hours.StartHour > 23 || hours.EndHour < 0 || hours.EndHour > 23 {
+		return businessHours{}, fmt.Errorf("business hours must be between 0 and 23")
+	}
+	if hours.StartHour == hours.EndHour {
+		return businessHours{}, fmt.Errorf("business_start_hour and business_end_hour must differ")
+	}
+
+	return hours, nil
+}
+
+// unattributedChanges groups the changes recorded without the requesting user
+const unattributedChanges = "unattributed"
+
+// changeActor returns who a change is grouped under. Events recorded by the
+// watcher carry its own user rather than the requesting one.
+func changeActor(event audit.AuditEvent) string {
+	if event.User == "" || event.User == watcherUser {
+		return unattributedChanges
+	}
+	return event.User
+}
+
+// AfterHoursChanges reports mutating changes made outside business hours, grouped by user
+func (h *ToolHandlers) AfterHoursChanges(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	hours, err := parseBusinessHours(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Verbs:     mutatingVerbs,
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+	}
+
+	if len(events) == 0 {
+		return mcp.NewToolResultText("No resource changes found in the specified time range."), nil
+	}
+
+	byUser := make(map[string][]audit.AuditEvent)
+	offHours := 0
+	for _, event := range events {
+		if hours.contains(event.Timestamp) {
+			continue
+		}
+		actor := changeActor(event)
+		byUser[actor] = append(byUser[actor], event)
+		offHours++
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("After-Hours Changes (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	weekdays := "weekdays"
+	if hours.Weekends {
+		weekdays = "every day"
+	}
+	results.WriteString(fmt.Sprintf("Business Hours: %02d:00-%02d:00 %s, %s\n", hours.StartHour, hours.EndHour, hours.Location, weekdays))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if offHours == 0 {
+		results.WriteString("✅ All changes were made during business hours.\n")
+	} else {
+		results.WriteString(fmt.Sprintf("🌙 Off-hours changes: %d\n\n", offHours))
+
+		users := make([]string, 0, len(byUser))
+		for user := range byUser {
+			users = append(users, user)
+		}
+		sort.Slice(users, func(i, j int) bool {
+			if len(byUser[users[i]]) != len(byUser[users[j]]) {
+				return len(byUser[users[i]]) > len(byUser[users[j]])
+			}
+			return users[i] < users[j]
+		})
+
+		for _, user := range users {
+			userEvents := byUser[user]
+			sort.Slice(userEvents, func(i, j int) bool {
+				return userEvents[i].Timestamp.Before(userEvents[j].Timestamp)
+			})
+
+			results.WriteString(fmt.Sprintf("👤 %s: %d changes\n", user, len(userEvents)))
+			for _, event := range userEvents {
+				results.WriteString(fmt.Sprintf("  - %s: %s %s %s/%s\n",
+					event.Timestamp.In(hours.Location).Format("Mon 2006-01-02 15:04:05 MST"),
+					event.Verb, event.ResourceType, event.Namespace, event.ResourceName))
+			}
+			results.WriteString("\n")
+		}
+		if len(byUser[unattributedChanges]) > 0 {
+			results.WriteString("Unattributed changes were recorded by a watcher that doesn't see the requesting user.\n")
+		}
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal change events analyzed: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestBusinessHoursContains(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	day := businessHours{Location: berlin, StartHour: 9, EndHour: 17}
+	night := businessHours{Location: time.UTC, StartHour: 22, EndHour: 6}
+
+	tests := []struct {
+		name  string
+		hours businessHours
+		at    string
+		want  bool
+	}{
+		// 2024-03-05 is a Tuesday; Berlin is UTC+1 in March
+		{name: "weekday in hours", hours: day, at: "2024-03-05T09:30:00Z", want: true},
+		{name: "weekday before hours in local time", hours: day, at: "2024-03-05T07:30:00Z", want: false},
+		{name: "end hour is exclusive", hours: day, at: "2024-03-05T16:00:00Z", want: false},
+		{name: "saturday", hours: day, at: "2024-03-09T10:00:00Z", want: false},
+		{name: "overnight before midnight", hours: night, at: "2024-03-05T23:00:00Z", want: true},
+		{name: "overnight after midnight", hours: night, at: "2024-03-06T03:00:00Z", want: true},
+		{name: "overnight daytime", hours: night, at: "2024-03-06T12:00:00Z", want: false},
+		// Friday night shift running into Saturday belongs to Friday
+		{name: "overnight from friday", hours: night, at: "2024-03-09T02:00:00Z", want: true},
+		{name: "overnight from saturday", hours: night, at: "2024-03-09T23:00:00Z", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.hours.contains(at); got != tt.want {
+				t.Errorf("contains(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAfterHoursChangesAttribution(t *testing.T) {
+	// 2024-03-05 is a Tuesday; all changes are at night
+	night := time.Date(2024, 3, 5, 2, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/events" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("cursor") == "" {
+			json.NewEncoder(w).Encode(audit.EventPage{
+				Items: []audit.AuditEvent{
+					{Timestamp: night, Verb: "update", User: watcherUser, Namespace: "default", ResourceType: "deployments", ResourceName: "web"},
+					{Timestamp: night.Add(time.Minute), Verb: "delete", User: "alice", Namespace: "default", ResourceType: "pods", ResourceName: "web-0"},
+				},
+				HasMore:    true,
+				NextCursor: "next",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(audit.EventPage{
+			Items: []audit.AuditEvent{{Timestamp: night.Add(2 * time.Minute), Verb: "update", User: watcherUser}},
+		})
+	}))
+	defer server.Close()
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 2, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{
+		"start_time": night.Add(-time.Hour).Format(time.RFC3339),
+		"end_time":   night.Add(time.Hour).Format(time.RFC3339),
+	}
+
+	result, err := h.AfterHoursChanges(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		"👤 alice: 1 changes",
+		"👤 unattributed: 1 changes",
+		"Showing analysis of the first 2 of 3 events (all types); narrow the window for full coverage.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, watcherUser) {
+		t.Errorf("expected the watcher not to be reported as a user, got:\n%s", text)
+	}
+}
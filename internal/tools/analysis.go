@@ -172,6 +172,11 @@ func (h *ToolHandlers) InvestigatePodStartup(ctx context.Context, request mcp.Ca
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	order, err := parseSortOrder(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	podName, err := request.RequireString("pod_name")
 	if err != nil {
 		return mcp.NewToolResultError("pod_name is required"), nil
@@ -183,7 +188,8 @@ func (h *ToolHandlers) InvestigatePodStartup(ctx context.Context, request mcp.Ca
 	}
 
 	// Query pod-specific events
-	events, err := h.auditClient.QueryEvents(ctx, audit.QueryOptions{
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
 		StartTime:    startTime,
 		EndTime:      endTime,
 		Namespace:    namespace,
@@ -204,75 +210,60 @@ func (h *ToolHandlers) InvestigatePodStartup(ctx context.Context, request mcp.Ca
 	results.WriteString(strings.Repeat("=", 60) + "\n\n")
 
 	// Analyze different aspects
-	imageIssues := []string{}
-	secretIssues := []string{}
-	volumeIssues := []string{}
-	initContainerIssues := []string{}
-	probeIssues := []string{}
+	imageIssues := []audit.AuditEvent{}
+	secretIssues := []audit.AuditEvent{}
+	volumeIssues := []audit.AuditEvent{}
+	initContainerIssues := []audit.AuditEvent{}
+	probeIssues := []audit.AuditEvent{}
 
 	for _, event := range events {
 		msg := strings.ToLower(event.Message)
 
-		if strings.Contains(msg, "image") {
-			if strings.Contains(msg, "pull") || strings.Contains(msg, "not found") ||
-				strings.Contains(msg, "unauthorized") {
-				imageIssues = append(imageIssues, fmt.Sprintf("[%s] %s", event.Timestamp.Format("15:04:05"), event.Message))
-			}
+		if isImageIssue(msg) {
+			imageIssues = append(imageIssues, event)
 		}
-		if strings.Contains(msg, "secret") && strings.Contains(msg, "not found") {
-			secretIssues = append(secretIssues, fmt.Sprintf("[%s] %s", event.Timestamp.Format("15:04:05"), event.Message))
+		if isSecretNotFound(msg) {
+			secretIssues = append(secretIssues, event)
 		}
-		if strings.Contains(msg, "volume") || strings.Contains(msg, "mount") {
-			if strings.Contains(msg, "fail") || strings.Contains(msg, "error") {
-				volumeIssues = append(volumeIssues, fmt.Sprintf("[%s] %s", event.Timestamp.Format("15:04:05"), event.Message))
-			}
+		if isVolumeError(msg) {
+			volumeIssues = append(volumeIssues, event)
 		}
-		if strings.Contains(msg, "init") && strings.Contains(msg, "container") {
-			initContainerIssues = append(initContainerIssues, fmt.Sprintf("[%s] %s", event.Timestamp.Format("15:04:05"), event.Message))
+		if isInitContainerEvent(msg) {
+			initContainerIssues = append(initContainerIssues, event)
 		}
-		if strings.Contains(msg, "readiness") || strings.Contains(msg, "liveness") {
-			probeIssues = append(probeIssues, fmt.Sprintf("[%s] %s", event.Timestamp.Format("15:04:05"), event.Message))
+		if isProbeEvent(msg) {
+			probeIssues = append(probeIssues, event)
 		}
 	}
 
 	// Report findings
 	if len(imageIssues) > 0 {
 		results.WriteString("🔍 Image Issues:\n")
-		for _, issue := range imageIssues[:min(5, len(imageIssues))] {
-			results.WriteString(fmt.Sprintf("  %s\n", issue))
-		}
+		writeFindings(&results, imageIssues, 5, order, nil)
 		results.WriteString("\n")
 	}
 
 	if len(secretIssues) > 0 {
 		results.WriteString("🔍 Secret/Pull Secret Issues:\n")
-		for _, issue := range secretIssues[:min(5, len(secretIssues))] {
-			results.WriteString(fmt.Sprintf("  %s\n", issue))
-		}
+		writeFindings(&results, secretIssues, 5, order, nil)
 		results.WriteString("\n")
 	}
 
 	if len(volumeIssues) > 0 {
 		results.WriteString("🔍 Volume Mount Issues:\n")
-		for _, issue := range volumeIssues[:min(5, len(volumeIssues))] {
-			results.WriteString(fmt.Sprintf("  %s\n", issue))
-		}
+		writeFindings(&results, volumeIssues, 5, order, nil)
 		results.WriteString("\n")
 	}
 
 	if len(initContainerIssues) > 0 {
 		results.WriteString("🔍 Init Container Issues:\n")
-		for _, issue := range initContainerIssues[:min(5, len(initContainerIssues))] {
-			results.WriteString(fmt.Sprintf("  %s\n", issue))
-		}
+		writeFindings(&results, initContainerIssues, 5, order, nil)
 		results.WriteString("\n")
 	}
 
 	if len(probeIssues) > 0 {
 		results.WriteString("🔍 Probe Configuration:\n")
-		for _, issue := range probeIssues[:min(3, len(probeIssues))] {
-			results.WriteString(fmt.Sprintf("  %s\n", issue))
-		}
+		writeFindings(&results, probeIssues, 3, order, nil)
 		results.WriteString("\n")
 	}
 
@@ -280,12 +271,13 @@ func (h *ToolHandlers) InvestigatePodStartup(ctx context.Context, request mcp.Ca
 		len(initContainerIssues) == 0 && len(probeIssues) == 0 {
 		results.WriteString("ℹ️  No obvious startup issues detected in audit logs.\n")
 		results.WriteString("Recent events:\n")
-		for _, event := range events[:min(5, len(events))] {
-			results.WriteString(fmt.Sprintf("  [%s] %s: %s\n",
-				event.Timestamp.Format("15:04:05"), event.Verb, event.Message))
-		}
+		writeFindings(&results, events, 5, order, func(event audit.AuditEvent) string {
+			return event.Verb
+		})
 	}
 
+	budget.writeNotices(&results)
+
 	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", len(events)))
 
 	return mcp.NewToolResultText(results.String()), nil
@@ -298,16 +290,31 @@ func (h *ToolHandlers) CheckResourceLimits(ctx context.Context, request mcp.Call
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	order, err := parseSortOrder(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	namespace := request.GetString("namespace", "")
 
 	// Query pod events for resource issues
-	events, err := h.auditClient.GetResourceTypeEvents(ctx, namespace, "pods", startTime, endTime)
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "pods",
+	})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
 	}
 
 	// Also query node events for resource exhaustion
-	nodeEvents, err := h.auditClient.GetResourceTypeEvents(ctx, "", "nodes", startTime, endTime)
+	nodeEvents, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		ResourceType: "nodes",
+	})
 	if err == nil {
 		events = append(events, nodeEvents...)
 	}
@@ -324,30 +331,25 @@ func (h *ToolHandlers) CheckResourceLimits(ctx context.Context, request mcp.Call
 	results.WriteString(strings.Repeat("=", 60) + "\n\n")
 
 	// Categorize resource issues
-	cpuThrottling := []string{}
-	oomKills := []string{}
-	misconfigured := []string{}
-	nodeExhaustion := []string{}
+	cpuThrottling := []audit.AuditEvent{}
+	oomKills := []audit.AuditEvent{}
+	misconfigured := []audit.AuditEvent{}
+	nodeExhaustion := []audit.AuditEvent{}
 
 	for _, event := range events {
 		msg := strings.ToLower(event.Message)
 
-		if strings.Contains(msg, "cpu") && (strings.Contains(msg, "throttl") || strings.Contains(msg, "limit")) {
-			cpuThrottling = append(cpuThrottling, fmt.Sprintf("[%s] %s/%s: %s",
-				event.Timestamp.Format("15:04:05"), event.Namespace, event.ResourceName, event.Message))
+		if isCPUThrottling(msg) {
+			cpuThrottling = append(cpuThrottling, event)
 		}
-		if strings.Contains(msg, "oom") || strings.Contains(msg, "out of memory") {
-			oomKills = append(oomKills, fmt.Sprintf("[%s] %s/%s: %s",
-				event.Timestamp.Format("15:04:05"), event.Namespace, event.ResourceName, event.Message))
+		if isOOM(msg) {
+			oomKills = append(oomKills, event)
 		}
-		if strings.Contains(msg, "limit") && (strings.Contains(msg, "exceed") || strings.Contains(msg, "invalid")) {
-			misconfigured = append(misconfigured, fmt.Sprintf("[%s] %s",
-				event.Timestamp.Format("15:04:05"), event.Message))
+		if isLimitMisconfigured(msg) {
+			misconfigured = append(misconfigured, event)
 		}
-		if event.ResourceType == "nodes" &&
-			(strings.Contains(msg, "insufficient") || strings.Contains(msg, "exhausted")) {
-			nodeExhaustion = append(nodeExhaustion, fmt.Sprintf("[%s] Node %s: %s",
-				event.Timestamp.Format("15:04:05"), event.ResourceName, event.Message))
+		if event.ResourceType == "nodes" && isResourceExhausted(msg) {
+			nodeExhaustion = append(nodeExhaustion, event)
 		}
 	}
 
@@ -357,36 +359,28 @@ func (h *ToolHandlers) CheckResourceLimits(ctx context.Context, request mcp.Call
 	if len(cpuThrottling) > 0 {
 		issueFound = true
 		results.WriteString(fmt.Sprintf("⚠️  CPU Throttling: %d events\n", len(cpuThrottling)))
-		for _, issue := range cpuThrottling[:min(5, len(cpuThrottling))] {
-			results.WriteString(fmt.Sprintf("  %s\n", issue))
-		}
+		writeFindings(&results, cpuThrottling, 5, order, podSubject)
 		results.WriteString("\n")
 	}
 
 	if len(oomKills) > 0 {
 		issueFound = true
 		results.WriteString(fmt.Sprintf("🔴 OOM Kills: %d events\n", len(oomKills)))
-		for _, issue := range oomKills[:min(5, len(oomKills))] {
-			results.WriteString(fmt.Sprintf("  %s\n", issue))
-		}
+		writeFindings(&results, oomKills, 5, order, podSubject)
 		results.WriteString("\n")
 	}
 
 	if len(misconfigured) > 0 {
 		issueFound = true
 		results.WriteString(fmt.Sprintf("⚠️  Misconfigured Limits: %d events\n", len(misconfigured)))
-		for _, issue := range misconfigured[:min(5, len(misconfigured))] {
-			results.WriteString(fmt.Sprintf("  %s\n", issue))
-		}
+		writeFindings(&results, misconfigured, 5, order, nil)
 		results.WriteString("\n")
 	}
 
 	if len(nodeExhaustion) > 0 {
 		issueFound = true
 		results.WriteString(fmt.Sprintf("🔴 Node Resource Exhaustion: %d events\n", len(nodeExhaustion)))
-		for _, issue := range nodeExhaustion[:min(5, len(nodeExhaustion))] {
-			results.WriteString(fmt.Sprintf("  %s\n", issue))
-		}
+		writeFindings(&results, nodeExhaustion, 5, order, nodeSubject)
 		results.WriteString("\n")
 	}
 
@@ -394,6 +388,8 @@ func (h *ToolHandlers) CheckResourceLimits(ctx context.Context, request mcp.Call
 		results.WriteString("✅ No resource limit issues detected.\n")
 	}
 
+	budget.writeNotices(&results)
+
 	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", len(events)))
 
 	return mcp.NewToolResultText(results.String()), nil
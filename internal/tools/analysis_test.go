@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestInvestigatePodStartupTruncatesFindings(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	var events []audit.AuditEvent
+	for i := 0; i < 7; i++ {
+		events = append(events, audit.AuditEvent{
+			Timestamp:    start.Add(time.Duration(i) * time.Minute),
+			Verb:         "update",
+			Namespace:    "default",
+			ResourceType: "pods",
+			ResourceName: "web",
+			Message:      fmt.Sprintf("Failed to pull image web:%d", i),
+		})
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(audit.EventPage{Items: events, Count: len(events)})
+	}))
+	defer server.Close()
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{
+		"pod_name":   "web",
+		"namespace":  "default",
+		"start_time": start.Format(time.RFC3339),
+		"end_time":   start.Add(time.Hour).Format(time.RFC3339),
+	}
+
+	result, err := h.InvestigatePodStartup(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+
+	// The section shows 5 of the 7 distinct findings, the rest is counted
+	// in the note and all of them in the total
+	if n := strings.Count(text, "Failed to pull image"); n != 5 {
+		t.Errorf("expected 5 image findings rendered, got %d:\n%s", n, text)
+	}
+	for _, want := range []string{"... and 2 more distinct findings\n", "Total events analyzed: 7\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// DefaultEventBudget is the default cap on the events a tool analyzes per query
+const DefaultEventBudget = 2000
+
+// budgetCountPages caps the extra pages fetched to count the events a
+// truncated query left out when the summary endpoint can't be used
+const budgetCountPages = 10
+
+// queryBudget caps the events each query of one tool call pulls and collects
+// a notice for every query that was truncated
+type queryBudget struct {
+	client  *audit.Client
+	limit   int
+	notices []string
+}
+
+// newQueryBudget starts the budget of a single tool call
+func (h *ToolHandlers) newQueryBudget() *queryBudget {
+	return &queryBudget{client: h.auditClient, limit: h.eventBudget}
+}
+
+// query returns the first events matching opts, at most the budget's limit.
+// When more events match, it records a notice with how many were left out.
+func (b *queryBudget) query(ctx context.Context, opts audit.QueryOptions) ([]audit.AuditEvent, error) {
+	opts.Limit = b.limit
+	page, err := b.client.QueryEventsPage(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !page.HasMore {
+		return page.Items, nil
+	}
+
+	label := opts.ResourceType
+	if label == "" {
+		label = "all types"
+	}
+	if page.Truncated {
+		label += ", response size limit reached"
+	}
+	total := b.countTotal(ctx, opts, page)
+	b.notices = append(b.notices, fmt.Sprintf("Showing analysis of the first %d of %s events (%s); narrow the window for full coverage.",
+		len(page.Items), total, label))
+	return page.Items, nil
+}
+
+// countTotal counts every event matching opts. A single namespace and
+// resource type filter are answered by the summary endpoint; other filters
+// page through the remaining events, giving up after budgetCountPages pages.
+func (b *queryBudget) countTotal(ctx context.Context, opts audit.QueryOptions, first *audit.EventPage) string {
+	if opts.Verb == "" && opts.ResourceName == "" && opts.User == "" && len(opts.Verbs) == 0 && len(opts.Namespaces) == 0 {
+		if summary, err := b.client.GetEventSummary(ctx, opts.StartTime, opts.EndTime); err == nil {
+			total := 0
+			for namespace, counts := range summary.Counts {
+				if opts.Namespace != "" && namespace != opts.Namespace {
+					continue
+				}
+				for resourceType, count := range counts {
+					if opts.ResourceType == "" || resourceType == opts.ResourceType {
+						total += count
+					}
+				}
+			}
+			if total > len(first.Items) {
+				return fmt.Sprintf("%d", total)
+			}
+		}
+	}
+
+	counted := len(first.Items)
+	page := first
+	for i := 0; i < budgetCountPages && page.HasMore; i++ {
+		opts.Cursor = page.NextCursor
+		next, err := b.client.QueryEventsPage(ctx, opts)
+		if err != nil {
+			break
+		}
+		page = next
+		counted += len(page.Items)
+	}
+	if page.HasMore {
+		return fmt.Sprintf("more than %d", counted)
+	}
+	return fmt.Sprintf("%d", counted)
+}
+
+// writeNotices appends the truncation notices of the tool call, if any
+func (b *queryBudget) writeNotices(results *strings.Builder) {
+	for _, notice := range b.notices {
+		results.WriteString(fmt.Sprintf("⚠️  %s\n", notice))
+	}
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestQueryBudgetTruncationNotice(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/events/summary":
+			json.NewEncoder(w).Encode(audit.EventSummary{Counts: map[string]map[string]int{"": {"nodes": 5}}})
+		case "/api/v1/events":
+			if got := r.URL.Query().Get("limit"); got != "2" {
+				t.Errorf("expected limit 2, got %q", got)
+			}
+			json.NewEncoder(w).Encode(audit.EventPage{
+				Items: []audit.AuditEvent{
+					{Timestamp: start, Verb: "update", ResourceType: "nodes", ResourceName: "node-1"},
+					{Timestamp: start.Add(time.Minute), Verb: "update", ResourceType: "nodes", ResourceName: "node-2"},
+				},
+				HasMore:    true,
+				NextCursor: "next",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 2, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{
+		"start_time": start.Format(time.RFC3339),
+		"end_time":   start.Add(time.Hour).Format(time.RFC3339),
+	}
+
+	result, err := h.CheckNodeHealth(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Showing analysis of the first 2 of 5 events (nodes); narrow the window for full coverage.") {
+		t.Errorf("expected truncation notice, got:\n%s", text)
+	}
+}
+
+func TestQueryBudgetNoNoticeWhenComplete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(audit.EventPage{Items: []audit.AuditEvent{{Verb: "update", ResourceType: "nodes"}}})
+	}))
+	defer server.Close()
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 2, nil)
+	budget := h.newQueryBudget()
+	events, err := budget.query(context.Background(), audit.QueryOptions{ResourceType: "nodes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || len(budget.notices) != 0 {
+		t.Errorf("expected 1 event and no notice, got %d events, notices %v", len(events), budget.notices)
+	}
+}
+
+func TestQueryBudgetCountsMultiValueFilters(t *testing.T) {
+	for _, opts := range []audit.QueryOptions{
+		{Verbs: []string{"create", "delete"}},
+		{Namespaces: []string{"a", "b"}},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/api/v1/events/summary":
+				// Counts every verb and namespace, far more than match
+				json.NewEncoder(w).Encode(audit.EventSummary{Counts: map[string]map[string]int{"a": {"pods": 50}, "c": {"pods": 50}}})
+			case r.URL.Query().Get("cursor") == "":
+				json.NewEncoder(w).Encode(audit.EventPage{
+					Items:      []audit.AuditEvent{{Verb: "create"}, {Verb: "delete"}},
+					HasMore:    true,
+					NextCursor: "next",
+				})
+			default:
+				json.NewEncoder(w).Encode(audit.EventPage{Items: []audit.AuditEvent{{Verb: "create"}}})
+			}
+		}))
+
+		h := NewToolHandlers(audit.NewClient(server.URL), 2, nil)
+		budget := h.newQueryBudget()
+		if _, err := budget.query(context.Background(), opts); err != nil {
+			t.Fatal(err)
+		}
+		server.Close()
+		if len(budget.notices) != 1 || !strings.Contains(budget.notices[0], "first 2 of 3 events") {
+			t.Errorf("%+v: expected the counted total in the notice, got %v", opts, budget.notices)
+		}
+	}
+}
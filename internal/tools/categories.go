@@ -0,0 +1,137 @@
+package tools
+
+import "strings"
+
+// Keyword heuristics the diagnostic tools sort events into categories with.
+// Each takes lowercased text; which text (message, annotations or the whole
+// event) is up to the calling tool. ShowUncategorizedEvents runs all of them,
+// so a new category must be added to knownCategories as well.
+
+// Node health
+func isNodeNotReady(text string) bool {
+	return strings.Contains(text, "notready")
+}
+
+func isResourcePressure(text string) bool {
+	return strings.Contains(text, "pressure") || strings.Contains(text, "memorypressure") ||
+		strings.Contains(text, "diskpressure")
+}
+
+func isNetworkUnavailable(text string) bool {
+	return strings.Contains(text, "network") && strings.Contains(text, "unavailable")
+}
+
+func isKubeletEvent(text string) bool {
+	return strings.Contains(text, "kubelet")
+}
+
+// Pod issues
+func isCrashLoop(text string) bool {
+	return strings.Contains(text, "crashloopbackoff")
+}
+
+func isImagePullBackOff(text string) bool {
+	return strings.Contains(text, "imagepullbackoff") || strings.Contains(text, "errimagepull")
+}
+
+func isOOMKilled(text string) bool {
+	return strings.Contains(text, "oomkilled") || strings.Contains(text, "out of memory")
+}
+
+func isProbeFailure(text string) bool {
+	return strings.Contains(text, "liveness") || strings.Contains(text, "readiness") ||
+		strings.Contains(text, "probe failed")
+}
+
+func isConfigMissing(text string) bool {
+	return strings.Contains(text, "configmap") || strings.Contains(text, "secret") &&
+		strings.Contains(text, "not found")
+}
+
+func isReplicaFailure(text string) bool {
+	return strings.Contains(text, "replica") &&
+		(strings.Contains(text, "insufficient") || strings.Contains(text, "failed"))
+}
+
+// Volume issues
+func isPending(text string) bool {
+	return strings.Contains(text, "pending")
+}
+
+func isBindingIssue(text string) bool {
+	return strings.Contains(text, "binding") || strings.Contains(text, "not bound")
+}
+
+func isStorageClassError(text string) bool {
+	return strings.Contains(text, "storageclass") &&
+		(strings.Contains(text, "error") || strings.Contains(text, "failed"))
+}
+
+func isMountFailure(text string) bool {
+	return strings.Contains(text, "mount") && strings.Contains(text, "fail")
+}
+
+func isDiskFull(text string) bool {
+	return strings.Contains(text, "disk full") || strings.Contains(text, "no space left")
+}
+
+// Pod startup
+func isImageIssue(text string) bool {
+	return strings.Contains(text, "image") &&
+		(strings.Contains(text, "pull") || strings.Contains(text, "not found") ||
+			strings.Contains(text, "unauthorized"))
+}
+
+func isSecretNotFound(text string) bool {
+	return strings.Contains(text, "secret") && strings.Contains(text, "not found")
+}
+
+func isVolumeError(text string) bool {
+	return (strings.Contains(text, "volume") || strings.Contains(text, "mount")) &&
+		(strings.Contains(text, "fail") || strings.Contains(text, "error"))
+}
+
+func isInitContainerEvent(text string) bool {
+	return strings.Contains(text, "init") && strings.Contains(text, "container")
+}
+
+func isProbeEvent(text string) bool {
+	return strings.Contains(text, "readiness") || strings.Contains(text, "liveness")
+}
+
+// Resource limits
+func isCPUThrottling(text string) bool {
+	return strings.Contains(text, "cpu") && (strings.Contains(text, "throttl") || strings.Contains(text, "limit"))
+}
+
+func isOOM(text string) bool {
+	return strings.Contains(text, "oom") || strings.Contains(text, "out of memory")
+}
+
+func isLimitMisconfigured(text string) bool {
+	return strings.Contains(text, "limit") && (strings.Contains(text, "exceed") || strings.Contains(text, "invalid"))
+}
+
+func isResourceExhausted(text string) bool {
+	return strings.Contains(text, "insufficient") || strings.Contains(text, "exhausted")
+}
+
+// knownCategories lists every heuristic above. Resource type conditions the
+// tools add on top (e.g. pending only for PVCs) are ignored here.
+var knownCategories = []func(text string) bool{
+	isNodeNotReady, isResourcePressure, isNetworkUnavailable, isKubeletEvent,
+	isCrashLoop, isImagePullBackOff, isOOMKilled, isProbeFailure, isConfigMissing, isReplicaFailure,
+	isPending, isBindingIssue, isStorageClassError, isMountFailure, isDiskFull,
+	isImageIssue, isSecretNotFound, isVolumeError, isInitContainerEvent, isProbeEvent,
+	isCPUThrottling, isOOM, isLimitMisconfigured, isResourceExhausted,
+}
+
+// matchesKnownCategory reports whether any diagnostic tool heuristic matches text
+func matchesKnownCategory(text string) bool {
+	for _, matches := range knownCategories {
+		if matches(text) {
+			return true
+		}
+	}
+	return false
+}
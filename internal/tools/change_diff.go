@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// changeDiffSearchWindow is how far around the requested timestamp
+// ShowChangeDiff looks for update events
+const changeDiffSearchWindow = time.Hour
+
+// changeDiffQueryLimit caps the update events fetched for one object
+const changeDiffQueryLimit = 500
+
+// ShowChangeDiff renders the field-level diff of the update event closest to a timestamp
+func (h *ToolHandlers) ShowChangeDiff(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resourceType, err := request.RequireString("resource_type")
+	if err != nil {
+		return mcp.NewToolResultError("resource_type is required"), nil
+	}
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError("name is required"), nil
+	}
+
+	timestampStr, err := request.RequireString("timestamp")
+	if err != nil {
+		return mcp.NewToolResultError("timestamp is required (RFC3339 format)"), nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, timestampStr)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid timestamp format: %v", err)), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	events, err := h.auditClient.QueryEvents(ctx, audit.QueryOptions{
+		StartTime:    timestamp.Add(-changeDiffSearchWindow),
+		EndTime:      timestamp.Add(changeDiffSearchWindow),
+		Namespace:    namespace,
+		ResourceType: resourceType,
+		ResourceName: name,
+		Verb:         "update",
+		Limit:        changeDiffQueryLimit,
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+	}
+
+	object := name
+	if namespace != "" {
+		object = namespace + "/" + name
+	}
+
+	event, ok := closestEvent(events, timestamp)
+	if !ok {
+		return mcp.NewToolResultText(fmt.Sprintf("No update events found for %s %s within %s of %s.",
+			resourceType, object, changeDiffSearchWindow, timestamp.Format(time.RFC3339))), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Change Diff: %s %s\n", resourceType, object))
+	results.WriteString(fmt.Sprintf("Update at: %s (requested %s)\n",
+		event.Timestamp.Format(time.RFC3339), timestamp.Format(time.RFC3339)))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	switch {
+	case len(event.ChangedFields) > 0:
+		results.WriteString(fmt.Sprintf("📝 Changed Fields: %d\n", len(event.ChangedFields)))
+		for _, change := range event.ChangedFields {
+			results.WriteString(fmt.Sprintf("  %s\n", formatFieldChange(change)))
+		}
+	case len(event.ObjectChanges) > 0:
+		results.WriteString("ℹ️  No field-level diff is stored for this event.\n")
+		results.WriteString("It predates diff storage and only carries a full-object snapshot of the updated object.\n")
+	default:
+		results.WriteString("ℹ️  No field-level diff or object snapshot is stored for this event.\n")
+	}
+
+	results.WriteString(fmt.Sprintf("\nUpdate events in window: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// closestEvent returns the event whose timestamp is nearest to t
+func closestEvent(events []audit.AuditEvent, t time.Time) (audit.AuditEvent, bool) {
+	var closest audit.AuditEvent
+	found := false
+	var best time.Duration
+	for _, event := range events {
+		d := event.Timestamp.Sub(t)
+		if d < 0 {
+			d = -d
+		}
+		if !found || d < best {
+			closest, best, found = event, d, true
+		}
+	}
+	return closest, found
+}
+
+// formatFieldChange renders a change as "path: old → new"
+func formatFieldChange(change audit.FieldChange) string {
+	return fmt.Sprintf("%s: %s → %s", change.Path, formatDiffValue(change.Old), formatDiffValue(change.New))
+}
+
+// formatDiffValue renders a diff value compactly, with <unset> for an added or removed field
+func formatDiffValue(v any) string {
+	if v == nil {
+		return "<unset>"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestClosestEvent(t *testing.T) {
+	at := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	events := []audit.AuditEvent{
+		{Timestamp: at.Add(-10 * time.Minute), Message: "before"},
+		{Timestamp: at.Add(2 * time.Minute), Message: "closest"},
+		{Timestamp: at.Add(30 * time.Minute), Message: "after"},
+	}
+
+	event, ok := closestEvent(events, at)
+	if !ok || event.Message != "closest" {
+		t.Errorf("closestEvent = %q, %v; want closest", event.Message, ok)
+	}
+
+	if _, ok := closestEvent(nil, at); ok {
+		t.Error("closestEvent on no events should report not found")
+	}
+}
+
+func TestFormatFieldChange(t *testing.T) {
+	tests := []struct {
+		change audit.FieldChange
+		want   string
+	}{
+		{audit.FieldChange{Path: "spec.replicas", Old: 2.0, New: 3.0}, "spec.replicas: 2 → 3"},
+		{audit.FieldChange{Path: "spec.template.spec.containers[0].image", Old: "app:v1", New: "app:v2"},
+			`spec.template.spec.containers[0].image: "app:v1" → "app:v2"`},
+		{audit.FieldChange{Path: "metadata.labels.tier", New: "web"}, `metadata.labels.tier: <unset> → "web"`},
+		{audit.FieldChange{Path: "spec.paused", Old: true}, "spec.paused: true → <unset>"},
+	}
+
+	for _, tt := range tests {
+		if got := formatFieldChange(tt.change); got != tt.want {
+			t.Errorf("formatFieldChange(%+v) = %q, want %q", tt.change, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// summaryDefaultLimit is the number of issues listed when no limit is given
+const summaryDefaultLimit = 10
+
+// issueCategory is one category of a diagnostic tool with its events
+type issueCategory struct {
+	name    string
+	events  []audit.AuditEvent
+	subject func(audit.AuditEvent) string
+}
+
+// scoredFinding is a finding with the highest severity of its events
+type scoredFinding struct {
+	Finding
+	severity Severity
+	category string
+	signal   string
+}
+
+// SummarizeClusterIssues runs the node, pod and volume analyses and lists the top findings across all of them by severity
+func (h *ToolHandlers) SummarizeClusterIssues(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+	limit := request.GetInt("limit", summaryDefaultLimit)
+	if limit <= 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid limit %d: must be positive", limit)), nil
+	}
+
+	budget := h.newQueryBudget()
+	queried := make(map[string][]audit.AuditEvent)
+	for _, q := range []struct {
+		resourceType string
+		namespace    string
+	}{
+		{"nodes", ""},
+		{"pods", namespace},
+		{"persistentvolumeclaims", namespace},
+		{"persistentvolumes", ""},
+	} {
+		events, err := budget.query(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Namespace:    q.namespace,
+			ResourceType: q.resourceType,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query %s events: %v", q.resourceType, err)), nil
+		}
+		queried[q.resourceType] = events
+	}
+
+	nodes := categorizeNodeIssues(queried["nodes"])
+	pods := categorizePodIssues(queried["pods"])
+	volumes := categorizeVolumeIssues(append(queried["persistentvolumeclaims"], queried["persistentvolumes"]...))
+	findings := scoreFindings([]issueCategory{
+		{"NotReady Nodes", nodes.notReady, nodeSubject},
+		{"Node Resource Pressure", nodes.pressure, nodeSubject},
+		{"Node Network Issues", nodes.network, nodeSubject},
+		{"Kubelet Events", nodes.kubelet, nil},
+		{"CrashLoopBackOff", pods.crashLoop, podSubject},
+		{"Image Pull Errors", pods.imagePull, podSubject},
+		{"OOMKilled", pods.oom, podSubject},
+		{"Probe Failures", pods.probeFailures, podSubject},
+		{"Config Issues", pods.config, podSubject},
+		{"Replica Issues", pods.replica, nil},
+		{"Pending PVCs", volumes.pendingPVC, pvcSubject},
+		{"PV Binding Issues", volumes.binding, volumeSubject},
+		{"StorageClass Errors", volumes.storageClass, nil},
+		{"Volume Mount Failures", volumes.mountFailures, nil},
+		{"Disk Full Events", volumes.diskFull, nil},
+	})
+
+	analyzed := 0
+	for _, events := range queried {
+		analyzed += len(events)
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Cluster Issues Summary (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s (node and PV issues included)\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(findings) == 0 {
+		results.WriteString("✅ No node, pod or volume issues detected.\n")
+	} else {
+		counts := make(map[Severity]int)
+		for _, f := range findings {
+			counts[f.severity]++
+		}
+		results.WriteString(fmt.Sprintf("📋 %d issues: %d critical, %d high, %d medium, %d low\n\n",
+			len(findings), counts[SeverityCritical], counts[SeverityHigh], counts[SeverityMedium], counts[SeverityLow]))
+
+		for i, f := range findings[:min(limit, len(findings))] {
+			category := f.category
+			if f.signal != "" && f.signal != f.category {
+				category += ", " + f.signal
+			}
+			results.WriteString(fmt.Sprintf("  %d. [%s] %s (%s)\n", i+1, f.severity, f.Finding, category))
+		}
+		if len(findings) > limit {
+			results.WriteString(fmt.Sprintf("  ... and %d more issues\n", len(findings)-limit))
+		}
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", analyzed))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// scoreFindings deduplicates the events of each category into findings and
+// scores every finding with the highest severity of its events. A finding in
+// several categories is listed once, under the category it scored highest in
+// or, on a tie, the one with the most events. Findings are sorted by
+// severity, then by count and recency.
+func scoreFindings(categories []issueCategory) []scoredFinding {
+	type findingKey struct {
+		subject string
+		message string
+	}
+
+	var scored []scoredFinding
+	index := make(map[findingKey]int)
+	for _, category := range categories {
+		severities := make(map[findingKey]Severity)
+		signals := make(map[findingKey]string)
+		for _, event := range category.events {
+			key := findingKey{message: event.Message}
+			if category.subject != nil {
+				key.subject = category.subject(event)
+			}
+			severity, signal := scoreEvent(event)
+			if current, ok := severities[key]; !ok || severity > current {
+				severities[key] = severity
+				signals[key] = signal
+			}
+		}
+
+		for _, finding := range DeduplicateFindings(category.events, category.subject) {
+			key := findingKey{subject: finding.Subject, message: finding.Message}
+			candidate := scoredFinding{Finding: finding, severity: severities[key], category: category.name, signal: signals[key]}
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(scored)
+				scored = append(scored, candidate)
+				continue
+			}
+			if candidate.severity > scored[i].severity || candidate.severity == scored[i].severity && candidate.Count > scored[i].Count {
+				scored[i] = candidate
+			}
+		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].severity != scored[j].severity {
+			return scored[i].severity > scored[j].severity
+		}
+		if scored[i].Count != scored[j].Count {
+			return scored[i].Count > scored[j].Count
+		}
+		return scored[i].LastSeen.After(scored[j].LastSeen)
+	})
+	return scored
+}
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+const (
+	// defaultChurnThreshold is the default number of updates per hour above
+	// which a config object is reported
+	defaultChurnThreshold = 6
+	// configChurnQueryLimit caps the update events fetched per config resource type
+	configChurnQueryLimit = 5000
+	// watcherUser is the user the watch server records for every event; it
+	// says nothing about who made the change
+	watcherUser = "system:k8s-watcher"
+)
+
+// churningConfig is a ConfigMap or Secret updated above the threshold
+type churningConfig struct {
+	key            string
+	updates        int
+	perHour        float64
+	medianInterval time.Duration
+	first          time.Time
+	last           time.Time
+	updaters       map[string]bool
+}
+
+// DetectConfigChurn finds ConfigMaps and Secrets updated far more often than expected in a window (rotation storms)
+func (h *ToolHandlers) DetectConfigChurn(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	threshold := request.GetInt("min_updates_per_hour", defaultChurnThreshold)
+	if threshold < 1 {
+		return mcp.NewToolResultError("min_updates_per_hour must be at least 1"), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	updates := make(map[string][]audit.AuditEvent)
+	var truncated []string
+	for _, resourceType := range []string{"configmaps", "secrets"} {
+		events, err := h.auditClient.QueryEvents(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Namespace:    namespace,
+			ResourceType: resourceType,
+			Verb:         "update",
+			Limit:        configChurnQueryLimit,
+		})
+		if errors.Is(err, audit.ErrNoData) {
+			continue
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+		}
+		if len(events) >= configChurnQueryLimit {
+			truncated = append(truncated, resourceType)
+		}
+
+		for _, event := range events {
+			key := configKey(resourceType, event.Namespace, event.ResourceName)
+			updates[key] = append(updates[key], event)
+		}
+	}
+
+	if len(updates) == 0 {
+		return mcp.NewToolResultText("No ConfigMap or Secret updates found in the specified time range."), nil
+	}
+
+	churning := detectConfigChurn(updates, endTime.Sub(startTime), threshold)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Config Churn Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(fmt.Sprintf("Threshold: %d updates/hour\n", threshold))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(churning) == 0 {
+		results.WriteString("✅ No ConfigMap or Secret was updated above the threshold.\n")
+	} else {
+		results.WriteString(fmt.Sprintf("🔴 Update Storms: %d\n", len(churning)))
+		results.WriteString("  (an operator rewriting the same object in a loop can keep restarting the pods that consume it)\n")
+		for _, c := range churning[:min(20, len(churning))] {
+			results.WriteString(fmt.Sprintf("  - %s: %d updates (%.1f/hour), median interval %s, %s to %s\n",
+				formatConfigKey(c.key), c.updates, c.perHour, formatLifetime(c.medianInterval),
+				c.first.Format(time.RFC3339), c.last.Format(time.RFC3339)))
+			results.WriteString(fmt.Sprintf("      Updated by: %s\n", strings.Join(sortedKeys(c.updaters), ", ")))
+		}
+		if len(churning) > 20 {
+			results.WriteString(fmt.Sprintf("  ... and %d more\n", len(churning)-20))
+		}
+	}
+	results.WriteString("\n")
+
+	if len(truncated) > 0 {
+		results.WriteString(fmt.Sprintf("⚠️  Results truncated at %d update events for: %s (narrow the time range or namespace)\n",
+			configChurnQueryLimit, strings.Join(truncated, ", ")))
+	}
+
+	results.WriteString(fmt.Sprintf("\nTotal updated configs analyzed: %d\n", len(updates)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// detectConfigChurn returns the configs whose update rate exceeds threshold
+// per hour, busiest first. Windows shorter than an hour are rated as a full
+// hour so a handful of updates in a few minutes is not extrapolated.
+func detectConfigChurn(updates map[string][]audit.AuditEvent, window time.Duration, threshold int) []churningConfig {
+	hours := max(window.Hours(), 1)
+
+	var churning []churningConfig
+	for key, events := range updates {
+		perHour := float64(len(events)) / hours
+		if perHour <= float64(threshold) {
+			continue
+		}
+
+		sorted := make([]audit.AuditEvent, len(events))
+		copy(sorted, events)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+		})
+
+		intervals := make([]time.Duration, 0, len(sorted)-1)
+		updaters := make(map[string]bool)
+		for i, event := range sorted {
+			if i > 0 {
+				intervals = append(intervals, event.Timestamp.Sub(sorted[i-1].Timestamp))
+			}
+			updaters[configUpdater(event)] = true
+		}
+		sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+
+		var median time.Duration
+		if len(intervals) > 0 {
+			median = intervals[len(intervals)/2]
+		}
+
+		churning = append(churning, churningConfig{
+			key:            key,
+			updates:        len(sorted),
+			perHour:        perHour,
+			medianInterval: median,
+			first:          sorted[0].Timestamp,
+			last:           sorted[len(sorted)-1].Timestamp,
+			updaters:       updaters,
+		})
+	}
+
+	sort.Slice(churning, func(i, j int) bool {
+		if churning[i].updates != churning[j].updates {
+			return churning[i].updates > churning[j].updates
+		}
+		return churning[i].key < churning[j].key
+	})
+	return churning
+}
+
+// configUpdater names who updated a config. Watch events carry no real user,
+// so the owning controller from ownerReferences is used instead when present.
+func configUpdater(event audit.AuditEvent) string {
+	if event.User != "" && event.User != watcherUser {
+		return event.User
+	}
+
+	metadata, _ := event.ObjectChanges["metadata"].(map[string]any)
+	refs, _ := metadata["ownerReferences"].([]any)
+	for _, r := range refs {
+		ref, _ := r.(map[string]any)
+		kind, _ := ref["kind"].(string)
+		name, _ := ref["name"].(string)
+		if kind != "" && name != "" {
+			return fmt.Sprintf("owner %s %s", kind, name)
+		}
+	}
+	return "unknown (no user or owner recorded)"
+}
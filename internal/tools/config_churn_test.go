@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestDetectConfigChurn(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	owned := map[string]any{"metadata": map[string]any{
+		"ownerReferences": []any{map[string]any{"kind": "Certificate", "name": "web-tls"}},
+	}}
+
+	var storm []audit.AuditEvent
+	for i := 0; i < 30; i++ {
+		storm = append(storm, audit.AuditEvent{
+			Timestamp:     start.Add(time.Duration(i) * 2 * time.Minute),
+			User:          watcherUser,
+			ObjectChanges: owned,
+		})
+	}
+	updates := map[string][]audit.AuditEvent{
+		"secrets/default/web-tls": storm,
+		"configmaps/default/app": {
+			{Timestamp: start, User: "alice"},
+			{Timestamp: start.Add(time.Hour), User: "alice"},
+		},
+	}
+
+	churning := detectConfigChurn(updates, 2*time.Hour, 6)
+	if len(churning) != 1 {
+		t.Fatalf("expected only the storming secret, got %+v", churning)
+	}
+
+	c := churning[0]
+	if c.key != "secrets/default/web-tls" || c.updates != 30 || c.perHour != 15 {
+		t.Errorf("unexpected churn %+v", c)
+	}
+	if c.medianInterval != 2*time.Minute {
+		t.Errorf("expected a 2m median interval, got %s", c.medianInterval)
+	}
+	if !c.updaters["owner Certificate web-tls"] || len(c.updaters) != 1 {
+		t.Errorf("expected the owning Certificate as updater, got %v", c.updaters)
+	}
+}
+
+func TestDetectConfigChurnShortWindow(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	updates := map[string][]audit.AuditEvent{
+		"configmaps/default/app": {
+			{Timestamp: start, User: "alice"},
+			{Timestamp: start.Add(time.Minute), User: "alice"},
+		},
+	}
+
+	// Two updates in a 5 minute window are not extrapolated to 24/hour
+	if churning := detectConfigChurn(updates, 5*time.Minute, 6); len(churning) != 0 {
+		t.Errorf("expected no churn, got %+v", churning)
+	}
+}
@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// credentialKeyHints are substrings of ConfigMap keys that suggest a credential
+var credentialKeyHints = []string{"password", "passwd", "token", "apikey", "api_key", "api-key", "secret", "credential", "private_key"}
+
+// referenceKeySuffixes mark keys that name or configure a credential rather
+// than hold one (e.g. secretName, tls_secret_ref, token_ttl)
+var referenceKeySuffixes = []string{"name", "ref", "ttl", "path", "file"}
+
+const (
+	// minEntropyLength is the shortest value considered for the entropy check
+	minEntropyLength = 20
+	// entropyThreshold is the Shannon entropy (bits per char) above which a value looks random
+	entropyThreshold = 4.0
+)
+
+// configMapFinding is a suspicious ConfigMap key
+type configMapFinding struct {
+	event  audit.AuditEvent
+	key    string
+	reason string
+	value  string
+}
+
+// DetectSecretsInConfigMaps flags ConfigMap values that look like credentials
+func (h *ToolHandlers) DetectSecretsInConfigMaps(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "configmaps",
+	})
+	if errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultText("No ConfigMap events found in the specified time range."), nil
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+	}
+
+	var findings []configMapFinding
+	seen := make(map[string]bool)
+
+	for _, event := range events {
+		if event.Verb != "create" && event.Verb != "update" {
+			continue
+		}
+
+		data, ok := event.ObjectChanges["data"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for key, raw := range data {
+			value, ok := raw.(string)
+			if !ok {
+				continue
+			}
+
+			reason := credentialReason(key, value)
+			if reason == "" {
+				continue
+			}
+
+			// Report each ConfigMap key once, at its first occurrence
+			id := fmt.Sprintf("%s/%s/%s", event.Namespace, event.ResourceName, key)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			findings = append(findings, configMapFinding{event: event, key: key, reason: reason, value: value})
+		}
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("ConfigMap Credential Scan (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(findings) == 0 {
+		results.WriteString("✅ No credential-like values found in ConfigMaps.\n")
+	} else {
+		results.WriteString(fmt.Sprintf("🔴 Possible credentials in ConfigMaps: %d keys\n", len(findings)))
+		results.WriteString("  (move these to Secrets; the writing user is not available from watch events)\n")
+		for _, f := range findings {
+			results.WriteString(fmt.Sprintf("  - %s: ConfigMap %s/%s key %q (%s, value %s)\n",
+				f.event.Timestamp.Format(time.RFC3339), f.event.Namespace, f.event.ResourceName,
+				f.key, f.reason, maskValue(f.value)))
+		}
+		results.WriteString("\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal ConfigMap events analyzed: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// credentialReason returns why a ConfigMap entry looks like a credential, or "" if it doesn't
+func credentialReason(key, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	lowerKey := strings.ToLower(key)
+	if !isReferenceKey(lowerKey) {
+		for _, hint := range credentialKeyHints {
+			if strings.Contains(lowerKey, hint) {
+				return fmt.Sprintf("key contains %q", hint)
+			}
+		}
+	}
+
+	// Multi-line values are usually config files, not tokens
+	if len(value) >= minEntropyLength && !strings.ContainsAny(value, " \n") &&
+		shannonEntropy(value) >= entropyThreshold {
+		return "high-entropy value"
+	}
+
+	return ""
+}
+
+// isReferenceKey reports whether a lowercase key names a credential instead of holding one
+func isReferenceKey(lowerKey string) bool {
+	for _, suffix := range referenceKeySuffixes {
+		if strings.HasSuffix(lowerKey, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy computes the Shannon entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// maskValue describes a value by its length only. A hash would let short or
+// guessable credentials be recovered by brute force.
+func maskValue(value string) string {
+	return fmt.Sprintf("<%d chars>", len([]rune(value)))
+}
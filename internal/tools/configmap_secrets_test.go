@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCredentialReason(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		flagged bool
+	}{
+		{key: "DB_PASSWORD", value: "hunter2", flagged: true},
+		{key: "api_key", value: "abc", flagged: true},
+		{key: "auth-token", value: "abc", flagged: true},
+		{key: "secretName", value: "db-credentials", flagged: false},
+		{key: "tls_secret_name", value: "ingress-tls", flagged: false},
+		{key: "token_ttl", value: "3600", flagged: false},
+		{key: "password_file", value: "/etc/app/password", flagged: false},
+		{key: "log_level", value: "debug", flagged: false},
+		{key: "signing", value: "q8Zr3VtX1mN7pL0aK5wE9yB2cH6jD4fG", flagged: true},
+		{key: "config.yaml", value: "server:\n  port: 8080\n  host: example.internal", flagged: false},
+		{key: "password", value: "", flagged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			reason := credentialReason(tt.key, tt.value)
+			if (reason != "") != tt.flagged {
+				t.Errorf("credentialReason(%q) = %q, want flagged=%v", tt.key, reason, tt.flagged)
+			}
+		})
+	}
+}
+
+func TestMaskValue(t *testing.T) {
+	masked := maskValue("hunter2-пароль")
+
+	if strings.Contains(masked, "hu") || strings.Contains(masked, "па") {
+		t.Errorf("masked value leaks content: %s", masked)
+	}
+	if masked != "<14 chars>" {
+		t.Errorf("expected only the rune length, got %s", masked)
+	}
+}
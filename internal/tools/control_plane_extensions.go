@@ -0,0 +1,297 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// webhookConfigurationTypes are the admission webhook configuration types,
+// by resource type
+var webhookConfigurationTypes = map[string]string{
+	"mutatingwebhookconfigurations":   "MutatingWebhookConfiguration",
+	"validatingwebhookconfigurations": "ValidatingWebhookConfiguration",
+}
+
+// extensionChange collects the changes to one webhook configuration or
+// APIService in a window
+type extensionChange struct {
+	kind    string
+	name    string
+	created bool
+	deleted bool
+	updates int
+	users   map[string]bool
+	first   time.Time
+	last    time.Time
+	// object is the latest recorded state
+	object map[string]any
+}
+
+// DetectControlPlaneExtensions reports admission webhook configurations and APIServices created, modified or removed in a window, with the resources and namespaces each webhook intercepts
+func (h *ToolHandlers) DetectControlPlaneExtensions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	budget := h.newQueryBudget()
+	var events []audit.AuditEvent
+	for _, resourceType := range []string{"mutatingwebhookconfigurations", "validatingwebhookconfigurations", "apiservices"} {
+		typeEvents, err := budget.query(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			ResourceType: resourceType,
+		})
+		if err != nil && !errors.Is(err, audit.ErrNoData) {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query %s events: %v", resourceType, err)), nil
+		}
+		events = append(events, typeEvents...)
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Control Plane Extensions (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	var webhooks, apiServices []*extensionChange
+	for _, change := range extensionChanges(events) {
+		if change.kind == "APIService" {
+			apiServices = append(apiServices, change)
+		} else {
+			webhooks = append(webhooks, change)
+		}
+	}
+
+	if len(webhooks) == 0 && len(apiServices) == 0 {
+		results.WriteString("✅ No webhook configurations or APIServices were created, modified or removed.\n")
+		if len(events) == 0 {
+			results.WriteString("  (no events at all; check that mutatingwebhookconfigurations, validatingwebhookconfigurations and apiservices are watched)\n")
+		}
+	}
+
+	if len(webhooks) > 0 {
+		results.WriteString(fmt.Sprintf("🪝 Admission Webhook Configurations Changed: %d\n", len(webhooks)))
+		results.WriteString("  (webhooks intercept API requests; a broken one can block or silently alter every matching request)\n")
+		for _, change := range webhooks {
+			writeExtensionChange(&results, change)
+			if change.deleted {
+				continue
+			}
+			webhookList, _ := change.object["webhooks"].([]any)
+			for _, w := range webhookList {
+				webhook, _ := w.(map[string]any)
+				writeWebhook(&results, webhook)
+			}
+		}
+		results.WriteString("\n")
+	}
+
+	if len(apiServices) > 0 {
+		results.WriteString(fmt.Sprintf("🔌 APIServices Changed: %d\n", len(apiServices)))
+		results.WriteString("  (an unavailable aggregated API breaks discovery, e.g. namespace deletion and kubectl)\n")
+		for _, change := range apiServices {
+			writeExtensionChange(&results, change)
+			if !change.deleted {
+				results.WriteString(fmt.Sprintf("      %s\n", apiServiceDescription(change.object)))
+			}
+		}
+		results.WriteString("\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// extensionChanges groups the events of webhook configurations and
+// APIServices per object, ordered by their first change
+func extensionChanges(events []audit.AuditEvent) []*extensionChange {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	changes := make(map[string]*extensionChange)
+	var ordered []*extensionChange
+	for _, event := range sorted {
+		kind := webhookConfigurationTypes[event.ResourceType]
+		if event.ResourceType == "apiservices" {
+			kind = "APIService"
+		}
+		if kind == "" {
+			continue
+		}
+
+		key := event.ResourceType + "/" + event.ResourceName
+		change := changes[key]
+		if change == nil {
+			change = &extensionChange{kind: kind, name: event.ResourceName, users: make(map[string]bool), first: event.Timestamp}
+			changes[key] = change
+			ordered = append(ordered, change)
+		}
+		change.last = event.Timestamp
+		change.users[configUpdater(event)] = true
+		switch event.Verb {
+		case "create":
+			change.created = true
+			change.deleted = false
+		case "delete":
+			change.deleted = true
+		default:
+			change.updates++
+		}
+		if event.ObjectChanges != nil {
+			change.object = event.ObjectChanges
+		}
+	}
+	return ordered
+}
+
+// writeExtensionChange writes the header line of a changed object
+func writeExtensionChange(results *strings.Builder, change *extensionChange) {
+	var actions []string
+	if change.created {
+		actions = append(actions, "created")
+	}
+	if change.updates > 0 {
+		actions = append(actions, fmt.Sprintf("modified %dx", change.updates))
+	}
+	if change.deleted {
+		actions = append(actions, "removed")
+	}
+	results.WriteString(fmt.Sprintf("  - %s %s: %s between %s and %s by %s\n", change.kind, change.name,
+		strings.Join(actions, ", "), change.first.Format(time.RFC3339), change.last.Format("15:04:05"),
+		strings.Join(sortedKeys(change.users), ", ")))
+}
+
+// writeWebhook describes what one webhook intercepts and how it fails
+func writeWebhook(results *strings.Builder, webhook map[string]any) {
+	name, _ := webhook["name"].(string)
+	// failurePolicy defaults to Fail in admissionregistration.k8s.io/v1
+	failurePolicy, _ := webhook["failurePolicy"].(string)
+	if failurePolicy == "" {
+		failurePolicy = "Fail"
+	}
+	namespaces := selectorDescription(webhook["namespaceSelector"], "all namespaces")
+
+	results.WriteString(fmt.Sprintf("      Webhook %s: %s\n", name, webhookRules(webhook)))
+	results.WriteString(fmt.Sprintf("        Scope: %s; backend: %s; failurePolicy: %s\n", namespaces, webhookBackend(webhook), failurePolicy))
+	if failurePolicy == "Fail" && namespaces == "all namespaces" {
+		results.WriteString("        ⚠️  Fails closed in every namespace, kube-system included: an unreachable backend rejects these requests cluster-wide\n")
+	}
+}
+
+// webhookRules formats the operations and resources a webhook's rules
+// match, e.g. "CREATE,UPDATE pods, deployments.apps"
+func webhookRules(webhook map[string]any) string {
+	rules, _ := webhook["rules"].([]any)
+	var formatted []string
+	for _, r := range rules {
+		rule, _ := r.(map[string]any)
+		var resources []string
+		for _, group := range stringList(rule["apiGroups"]) {
+			for _, resource := range stringList(rule["resources"]) {
+				if group != "" {
+					resource += "." + group
+				}
+				resources = append(resources, resource)
+			}
+		}
+		formatted = append(formatted, fmt.Sprintf("%s %s", strings.Join(stringList(rule["operations"]), ","), strings.Join(resources, ", ")))
+	}
+	if len(formatted) == 0 {
+		return "no rules"
+	}
+	return strings.Join(formatted, "; ")
+}
+
+// webhookBackend names the service or URL a webhook calls
+func webhookBackend(webhook map[string]any) string {
+	clientConfig, _ := webhook["clientConfig"].(map[string]any)
+	if service, ok := clientConfig["service"].(map[string]any); ok {
+		namespace, _ := service["namespace"].(string)
+		name, _ := service["name"].(string)
+		return fmt.Sprintf("service %s/%s", namespace, name)
+	}
+	if url, ok := clientConfig["url"].(string); ok {
+		return url
+	}
+	return "unknown"
+}
+
+// selectorDescription formats a label selector, or returns all when it
+// selects everything
+func selectorDescription(value any, all string) string {
+	selector, _ := value.(map[string]any)
+	var terms []string
+	matchLabels, _ := selector["matchLabels"].(map[string]any)
+	for key, value := range matchLabels {
+		terms = append(terms, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(terms)
+	expressions, _ := selector["matchExpressions"].([]any)
+	for _, e := range expressions {
+		expression, _ := e.(map[string]any)
+		key, _ := expression["key"].(string)
+		operator, _ := expression["operator"].(string)
+		term := fmt.Sprintf("%s %s", key, operator)
+		if values := stringList(expression["values"]); len(values) > 0 {
+			term += fmt.Sprintf(" (%s)", strings.Join(values, ","))
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return all
+	}
+	return "namespaces matching " + strings.Join(terms, ", ")
+}
+
+// apiServiceDescription formats where an APIService is served from and
+// whether it is available
+func apiServiceDescription(object map[string]any) string {
+	spec, _ := object["spec"].(map[string]any)
+	backend := "local (served by kube-apiserver)"
+	if service, ok := spec["service"].(map[string]any); ok {
+		namespace, _ := service["namespace"].(string)
+		name, _ := service["name"].(string)
+		backend = fmt.Sprintf("service %s/%s", namespace, name)
+	}
+
+	availability := "unknown"
+	status, _ := object["status"].(map[string]any)
+	conditions, _ := status["conditions"].([]any)
+	for _, c := range conditions {
+		condition, _ := c.(map[string]any)
+		if condition["type"] != "Available" {
+			continue
+		}
+		availability, _ = condition["status"].(string)
+		if availability != "True" {
+			if reason, _ := condition["reason"].(string); reason != "" {
+				availability = fmt.Sprintf("⚠️  %s (%s)", availability, reason)
+			}
+		}
+	}
+	return fmt.Sprintf("Backend: %s; Available: %s", backend, availability)
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	var values []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
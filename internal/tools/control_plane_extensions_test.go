@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestExtensionChanges(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	webhookConfig := map[string]any{
+		"webhooks": []any{map[string]any{
+			"name": "inject.sidecar.example.com",
+			"rules": []any{map[string]any{
+				"operations": []any{"CREATE"},
+				"apiGroups":  []any{"", "apps"},
+				"resources":  []any{"pods"},
+			}},
+			"clientConfig": map[string]any{"service": map[string]any{"namespace": "mesh", "name": "injector"}},
+		}},
+	}
+
+	changes := extensionChanges([]audit.AuditEvent{
+		{Timestamp: start.Add(time.Minute), Verb: "update", User: "bob", ResourceType: "mutatingwebhookconfigurations", ResourceName: "sidecar", ObjectChanges: webhookConfig},
+		{Timestamp: start, Verb: "create", User: "alice", ResourceType: "mutatingwebhookconfigurations", ResourceName: "sidecar", ObjectChanges: webhookConfig},
+		{Timestamp: start.Add(2 * time.Minute), Verb: "delete", User: "alice", ResourceType: "apiservices", ResourceName: "v1beta1.metrics.k8s.io"},
+		{Timestamp: start, Verb: "update", ResourceType: "configmaps", ResourceName: "ignored"},
+	})
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changed objects, got %+v", changes)
+	}
+	webhook := changes[0]
+	if webhook.kind != "MutatingWebhookConfiguration" || !webhook.created || webhook.updates != 1 || webhook.deleted {
+		t.Errorf("unexpected webhook change %+v", webhook)
+	}
+	if users := strings.Join(sortedKeys(webhook.users), ","); users != "alice,bob" {
+		t.Errorf("expected both users, got %s", users)
+	}
+	if apiService := changes[1]; apiService.kind != "APIService" || !apiService.deleted {
+		t.Errorf("unexpected APIService change %+v", apiService)
+	}
+
+	var results strings.Builder
+	writeWebhook(&results, webhookConfig["webhooks"].([]any)[0].(map[string]any))
+	for _, want := range []string{"CREATE pods, pods.apps", "all namespaces", "service mesh/injector", "failurePolicy: Fail", "Fails closed"} {
+		if !strings.Contains(results.String(), want) {
+			t.Errorf("expected %q in %q", want, results.String())
+		}
+	}
+}
+
+func TestWebhookScope(t *testing.T) {
+	selector := map[string]any{
+		"matchLabels":      map[string]any{"mesh": "enabled"},
+		"matchExpressions": []any{map[string]any{"key": "kubernetes.io/metadata.name", "operator": "NotIn", "values": []any{"kube-system"}}},
+	}
+	if got := selectorDescription(selector, "all"); got != "namespaces matching mesh=enabled, kubernetes.io/metadata.name NotIn (kube-system)" {
+		t.Errorf("unexpected selector description %q", got)
+	}
+
+	var results strings.Builder
+	writeWebhook(&results, map[string]any{"name": "policy", "failurePolicy": "Ignore", "namespaceSelector": selector})
+	if strings.Contains(results.String(), "Fails closed") {
+		t.Errorf("expected no warning for a scoped webhook that fails open, got %q", results.String())
+	}
+
+	object := map[string]any{
+		"spec": map[string]any{"service": map[string]any{"namespace": "kube-system", "name": "metrics-server"}},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Available", "status": "False", "reason": "MissingEndpoints"},
+		}},
+	}
+	if got := apiServiceDescription(object); got != "Backend: service kube-system/metrics-server; Available: ⚠️  False (MissingEndpoints)" {
+		t.Errorf("unexpected APIService description %q", got)
+	}
+}
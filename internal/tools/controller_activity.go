@@ -0,0 +1,193 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// mutatingVerbs are the verbs that change cluster state
+var mutatingVerbs = []string{"create", "update", "patch", "delete"}
+
+const (
+	// serviceAccountUserPrefix starts the username of every service account
+	serviceAccountUserPrefix = "system:serviceaccount:"
+	// controllerNamespace holds the service accounts of the system controllers
+	controllerNamespace = "kube-system"
+)
+
+// Actor kinds behind a mutation
+const (
+	actorController     = "controller"
+	actorServiceAccount = "serviceaccount"
+	actorHuman          = "human"
+	actorUnattributed   = "unattributed"
+)
+
+// controllerActivity is the mutations one system controller made in a window
+type controllerActivity struct {
+	name          string
+	mutations     int
+	verbs         map[string]int
+	resourceTypes map[string]int
+	namespaces    map[string]int
+}
+
+// ControllerActivity ranks the system controllers by the mutations they made in a window, separating controller-driven churn from human changes
+func (h *ToolHandlers) ControllerActivity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+	top := request.GetInt("top", 10)
+	if top < 1 {
+		return mcp.NewToolResultError("top must be at least 1"), nil
+	}
+
+	budget := h.newQueryBudget()
+	var events []audit.AuditEvent
+	for _, verb := range mutatingVerbs {
+		verbEvents, err := budget.query(ctx, audit.QueryOptions{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Namespace: namespace,
+			Verb:      verb,
+		})
+		if err != nil && !errors.Is(err, audit.ErrNoData) {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query events: %v", err)), nil
+		}
+		events = append(events, verbEvents...)
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Controller Activity (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	controllers, actors := controllerActivities(events)
+	if len(events) == 0 {
+		results.WriteString("No mutations found in the specified time range.\n")
+	} else {
+		results.WriteString("👥 Mutations by actor:\n")
+		for _, kind := range []string{actorController, actorServiceAccount, actorHuman, actorUnattributed} {
+			results.WriteString(fmt.Sprintf("  - %s: %d (%.0f%%)\n", actorLabel(kind), actors[kind],
+				100*float64(actors[kind])/float64(len(events))))
+		}
+		if actors[actorUnattributed] > 0 {
+			results.WriteString("  Unattributed mutations were recorded by a watcher that doesn't see the requesting user.\n")
+		}
+		results.WriteString("\n")
+
+		if len(controllers) == 0 {
+			results.WriteString("✅ No mutations by system controllers.\n")
+		} else {
+			results.WriteString(fmt.Sprintf("🤖 Most Active Controllers: %d of %d\n", min(top, len(controllers)), len(controllers)))
+			for i, controller := range controllers[:min(top, len(controllers))] {
+				results.WriteString(fmt.Sprintf("  %d. %s: %d mutations (%.0f%% of controller activity)\n", i+1,
+					controller.name, controller.mutations, 100*float64(controller.mutations)/float64(actors[actorController])))
+				results.WriteString(fmt.Sprintf("     Verbs: %s\n", topCounts(controller.verbs, 4, nil)))
+				results.WriteString(fmt.Sprintf("     Resource types: %s\n", topCounts(controller.resourceTypes, 3, nil)))
+				if namespace == "" {
+					results.WriteString(fmt.Sprintf("     Namespaces: %s\n", topCounts(controller.namespaces, 3, namespaceLabel)))
+				}
+			}
+		}
+		results.WriteString("\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal mutations analyzed: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// controllerActivities groups mutations by the system controller that made
+// them, ordered by mutation count and then name, and counts the mutations
+// per actor kind
+func controllerActivities(events []audit.AuditEvent) ([]controllerActivity, map[string]int) {
+	actors := make(map[string]int)
+	byName := make(map[string]*controllerActivity)
+	for _, event := range events {
+		kind, name := mutationActor(event.User)
+		actors[kind]++
+		if kind != actorController {
+			continue
+		}
+
+		controller := byName[name]
+		if controller == nil {
+			controller = &controllerActivity{
+				name:          name,
+				verbs:         make(map[string]int),
+				resourceTypes: make(map[string]int),
+				namespaces:    make(map[string]int),
+			}
+			byName[name] = controller
+		}
+		controller.mutations++
+		controller.verbs[event.Verb]++
+		controller.resourceTypes[event.ResourceType]++
+		controller.namespaces[event.Namespace]++
+	}
+
+	controllers := make([]controllerActivity, 0, len(byName))
+	for _, controller := range byName {
+		controllers = append(controllers, *controller)
+	}
+	sort.Slice(controllers, func(i, j int) bool {
+		if controllers[i].mutations != controllers[j].mutations {
+			return controllers[i].mutations > controllers[j].mutations
+		}
+		return controllers[i].name < controllers[j].name
+	})
+	return controllers, actors
+}
+
+// mutationActor classifies the user of a mutation and, for system
+// controllers, returns the controller name. kube-controller-manager runs
+// each controller under its own kube-system service account (e.g.
+// system:serviceaccount:kube-system:replicaset-controller); the controller
+// manager, scheduler and kubelets also act under their own system users.
+func mutationActor(user string) (string, string) {
+	switch {
+	case user == "" || user == watcherUser:
+		return actorUnattributed, ""
+	case strings.HasPrefix(user, serviceAccountUserPrefix):
+		namespace, name, _ := strings.Cut(strings.TrimPrefix(user, serviceAccountUserPrefix), ":")
+		if namespace == controllerNamespace && name != "" {
+			return actorController, name
+		}
+		return actorServiceAccount, ""
+	case strings.HasPrefix(user, "system:node:"):
+		return actorController, "kubelet"
+	case user == "system:kube-controller-manager" || user == "system:kube-scheduler" || user == "system:apiserver":
+		return actorController, strings.TrimPrefix(user, "system:")
+	default:
+		return actorHuman, ""
+	}
+}
+
+// actorLabel describes an actor kind
+func actorLabel(kind string) string {
+	switch kind {
+	case actorController:
+		return "System controllers"
+	case actorServiceAccount:
+		return "Other service accounts"
+	case actorHuman:
+		return "Users"
+	default:
+		return "Unattributed"
+	}
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestMutationActor(t *testing.T) {
+	tests := []struct {
+		user     string
+		wantKind string
+		wantName string
+	}{
+		{"system:serviceaccount:kube-system:replicaset-controller", actorController, "replicaset-controller"},
+		{"system:kube-scheduler", actorController, "kube-scheduler"},
+		{"system:node:worker-1", actorController, "kubelet"},
+		{"system:serviceaccount:argocd:argocd-application-controller", actorServiceAccount, ""},
+		{"alice@example.com", actorHuman, ""},
+		{watcherUser, actorUnattributed, ""},
+		{"", actorUnattributed, ""},
+	}
+	for _, tt := range tests {
+		kind, name := mutationActor(tt.user)
+		if kind != tt.wantKind || name != tt.wantName {
+			t.Errorf("%q: expected %s %q, got %s %q", tt.user, tt.wantKind, tt.wantName, kind, name)
+		}
+	}
+}
+
+func TestControllerActivities(t *testing.T) {
+	rsController := "system:serviceaccount:kube-system:replicaset-controller"
+	events := []audit.AuditEvent{
+		{Verb: "create", User: rsController, Namespace: "shop", ResourceType: "pods"},
+		{Verb: "delete", User: rsController, Namespace: "shop", ResourceType: "pods"},
+		{Verb: "create", User: rsController, Namespace: "web", ResourceType: "pods"},
+		{Verb: "update", User: "system:serviceaccount:kube-system:deployment-controller", Namespace: "shop", ResourceType: "replicasets"},
+		{Verb: "update", User: "system:serviceaccount:kube-system:endpoint-controller", Namespace: "shop", ResourceType: "endpoints"},
+		{Verb: "patch", User: "alice@example.com", Namespace: "shop", ResourceType: "deployments"},
+		{Verb: "update", User: watcherUser, Namespace: "shop", ResourceType: "configmaps"},
+	}
+
+	controllers, actors := controllerActivities(events)
+	if actors[actorController] != 5 || actors[actorHuman] != 1 || actors[actorUnattributed] != 1 {
+		t.Errorf("unexpected actor counts %v", actors)
+	}
+	if len(controllers) != 3 {
+		t.Fatalf("expected 3 controllers, got %+v", controllers)
+	}
+	top := controllers[0]
+	if top.name != "replicaset-controller" || top.mutations != 3 || top.verbs["create"] != 2 || top.namespaces["web"] != 1 {
+		t.Errorf("unexpected top controller %+v", top)
+	}
+	// Ties are ordered by name
+	if controllers[1].name != "deployment-controller" || controllers[2].name != "endpoint-controller" {
+		t.Errorf("unexpected order %s, %s", controllers[1].name, controllers[2].name)
+	}
+}
@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+const (
+	// correlateDefaultWindow is the time on each side of the center timestamp
+	// when no window is given
+	correlateDefaultWindow = 5 * time.Minute
+	// correlateMaxLines caps the lines of the timeline
+	correlateMaxLines = 200
+)
+
+// correlatedEntry is one line of the correlated timeline
+type correlatedEntry struct {
+	event audit.AuditEvent
+	// cause is the latest earlier change of the object a Kubernetes event is
+	// about, nil for other events or when the object did not change
+	cause *audit.AuditEvent
+}
+
+// CorrelateEvents lists every event of a namespace in a window around a timestamp in time order, across all resource types, with Kubernetes events placed next to the changes of the objects they are about
+func (h *ToolHandlers) CorrelateEvents(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := request.GetString("namespace", "")
+	if namespace == "" {
+		return mcp.NewToolResultError("namespace is required"), nil
+	}
+
+	timestampStr := request.GetString("timestamp", "")
+	if timestampStr == "" {
+		return mcp.NewToolResultError("timestamp is required (RFC3339 format)"), nil
+	}
+	center, err := time.Parse(time.RFC3339, timestampStr)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid timestamp format: %v", err)), nil
+	}
+
+	window := correlateDefaultWindow
+	if windowStr := request.GetString("window", ""); windowStr != "" {
+		window, err = parseLookback(windowStr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid window: %v", err)), nil
+		}
+	}
+
+	startTime, endTime := center.Add(-window), center.Add(window)
+
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Namespace: namespace,
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query events: %v", err)), nil
+	}
+
+	entries := correlateEvents(events, startTime, endTime)
+	if len(entries) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No events found in namespace %s within %s of %s.", namespace, window, center.Format(time.RFC3339))), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Correlated Events (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(fmt.Sprintf("Namespace: %s, centered on %s ± %s\n", namespace, center.Format(time.RFC3339), window))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	centerMarked := false
+	for _, entry := range entries[:min(correlateMaxLines, len(entries))] {
+		if !centerMarked && !entry.event.Timestamp.Before(center) {
+			results.WriteString(fmt.Sprintf("  ---- %s ----\n", center.Format(time.RFC3339)))
+			centerMarked = true
+		}
+		results.WriteString(fmt.Sprintf("  %s (%s) %s\n", entry.event.Timestamp.Format(time.RFC3339), correlateOffset(entry.event.Timestamp.Sub(center)), correlatedLine(entry.event)))
+		if entry.cause != nil {
+			results.WriteString(fmt.Sprintf("      ↳ after %s %s at %s\n", entry.cause.Verb, onsetObject(*entry.cause), entry.cause.Timestamp.Format(time.RFC3339)))
+		}
+	}
+	if len(entries) > correlateMaxLines {
+		results.WriteString(fmt.Sprintf("  ... and %d more events\n", len(entries)-correlateMaxLines))
+	} else if !centerMarked {
+		results.WriteString(fmt.Sprintf("  ---- %s ----\n", center.Format(time.RFC3339)))
+	}
+	results.WriteString("\n")
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", len(entries)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// correlateEvents sorts the events within [startTime, endTime] by time and
+// links each Kubernetes event to the latest earlier change of its object
+func correlateEvents(events []audit.AuditEvent, startTime, endTime time.Time) []correlatedEntry {
+	var sorted []audit.AuditEvent
+	for _, event := range events {
+		if !event.Timestamp.Before(startTime) && !event.Timestamp.After(endTime) {
+			sorted = append(sorted, event)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	entries := make([]correlatedEntry, len(sorted))
+	// Changes so far by namespace/name, oldest first
+	changes := make(map[string][]*audit.AuditEvent)
+	for i := range sorted {
+		event := &sorted[i]
+		entries[i].event = *event
+		if event.ResourceType != "events" {
+			key := event.Namespace + "/" + event.ResourceName
+			changes[key] = append(changes[key], event)
+			continue
+		}
+
+		involved, _ := event.ObjectChanges["involvedObject"].(map[string]any)
+		kind, _ := involved["kind"].(string)
+		namespace, _ := involved["namespace"].(string)
+		name, _ := involved["name"].(string)
+		objectChanges := changes[namespace+"/"+name]
+		for j := len(objectChanges) - 1; j >= 0; j-- {
+			if kindMatchesResourceType(kind, objectChanges[j].ResourceType) {
+				entries[i].cause = objectChanges[j]
+				break
+			}
+		}
+	}
+	return entries
+}
+
+// kindMatchesResourceType reports whether resourceType is the plural of kind,
+// e.g. Pod and pods or NetworkPolicy and networkpolicies
+func kindMatchesResourceType(kind, resourceType string) bool {
+	kind = strings.ToLower(kind)
+	if kind == "" {
+		return false
+	}
+	switch resourceType {
+	case kind, kind + "s", kind + "es", strings.TrimSuffix(kind, "y") + "ies":
+		return true
+	}
+	return false
+}
+
+// correlatedLine describes an event of the timeline: Kubernetes events by
+// their type, reason and message, other events by resource type and verb
+func correlatedLine(event audit.AuditEvent) string {
+	if event.ResourceType == "events" {
+		eventType, _ := event.ObjectChanges["type"].(string)
+		reason, _ := event.ObjectChanges["reason"].(string)
+		return fmt.Sprintf("[event %s] %s: %s — %s", eventType, involvedObject(event), reason, onsetMessage(event))
+	}
+
+	line := fmt.Sprintf("[%s %s] %s", event.ResourceType, event.Verb, event.ResourceName)
+	if event.User != "" && event.User != watcherUser {
+		line += " by " + event.User
+	}
+	return line
+}
+
+// correlateOffset renders the distance of an event to the center timestamp,
+// e.g. -2m13s or +45s
+func correlateOffset(offset time.Duration) string {
+	offset = offset.Round(time.Second)
+	if offset > 0 {
+		return "+" + offset.String()
+	}
+	return offset.String()
+}
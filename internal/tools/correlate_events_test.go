@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestCorrelateEvents(t *testing.T) {
+	center := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	start, end := center.Add(-5*time.Minute), center.Add(5*time.Minute)
+
+	change := func(resourceType, name string, at time.Time) audit.AuditEvent {
+		return audit.AuditEvent{Timestamp: at, Verb: "update", Namespace: "default", ResourceType: resourceType, ResourceName: name}
+	}
+	events := []audit.AuditEvent{
+		k8sEvent("Pod", "web-1", "BackOff", "Back-off restarting failed container", center.Add(2*time.Minute)),
+		change("pods", "web-1", center.Add(-time.Minute)),
+		// The configmap of the same name is not what the event is about
+		change("configmaps", "web-1", center.Add(-30*time.Second)),
+		// Boundaries are inclusive
+		change("deployments", "web", start),
+		change("deployments", "web", end),
+		change("deployments", "web", start.Add(-time.Second)),
+		change("deployments", "web", end.Add(time.Second)),
+	}
+
+	entries := correlateEvents(events, start, end)
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 events within the window, got %+v", entries)
+	}
+	want := []time.Time{start, center.Add(-time.Minute), center.Add(-30 * time.Second), center.Add(2 * time.Minute), end}
+	for i, at := range want {
+		if !entries[i].event.Timestamp.Equal(at) {
+			t.Errorf("entry %d: expected %s, got %s", i, at, entries[i].event.Timestamp)
+		}
+	}
+
+	backOff := entries[3]
+	if backOff.event.ResourceType != "events" || backOff.cause == nil || backOff.cause.ResourceType != "pods" {
+		t.Errorf("expected the BackOff event to follow the pod update, got %+v", backOff)
+	}
+	if entries[1].cause != nil {
+		t.Errorf("only Kubernetes events have a cause, got %+v", entries[1].cause)
+	}
+}
+
+func TestKindMatchesResourceType(t *testing.T) {
+	for _, tt := range []struct {
+		kind, resourceType string
+		want               bool
+	}{
+		{"Pod", "pods", true},
+		{"Ingress", "ingresses", true},
+		{"NetworkPolicy", "networkpolicies", true},
+		{"Endpoints", "endpoints", true},
+		{"Pod", "podtemplates", false},
+		{"", "pods", false},
+	} {
+		if got := kindMatchesResourceType(tt.kind, tt.resourceType); got != tt.want {
+			t.Errorf("kindMatchesResourceType(%q, %q) = %v, want %v", tt.kind, tt.resourceType, got, tt.want)
+		}
+	}
+}
+
+func TestCorrelateEventsTool(t *testing.T) {
+	center := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	server := newEventsServer(t, []audit.AuditEvent{
+		k8sEvent("Pod", "web-1", "BackOff", "Back-off restarting failed container", center.Add(30*time.Second)),
+		{Timestamp: center.Add(-90 * time.Second), Verb: "update", Namespace: "default", ResourceType: "pods", ResourceName: "web-1", User: "alice"},
+	})
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{
+		"namespace": "default",
+		"timestamp": center.Format(time.RFC3339),
+		"window":    "2m",
+	}
+	result, err := h.CorrelateEvents(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %+v", err, result)
+	}
+
+	text := result.Content[0].(mcp.TextContent).Text
+	update := strings.Index(text, "(-1m30s) [pods update] web-1 by alice")
+	marker := strings.Index(text, "---- 2025-03-04T11:00:00Z ----")
+	backOff := strings.Index(text, "(+30s) [event Warning] Pod default/web-1: BackOff")
+	if update < 0 || marker < update || backOff < marker {
+		t.Errorf("expected the update, the center marker and the event in order:\n%s", text)
+	}
+	if !strings.Contains(text, "↳ after update pods default/web-1 at 2025-03-04T10:58:30Z") {
+		t.Errorf("expected the event to be linked to the pod update:\n%s", text)
+	}
+
+	request.Params.Arguments = map[string]any{"namespace": "default"}
+	if result, _ := h.CorrelateEvents(context.Background(), request); !result.IsError {
+		t.Error("expected an error without a timestamp")
+	}
+}
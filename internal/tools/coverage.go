@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// CheckResourceCoverage reports whether a resource type is watched and how many events are stored for it
+func (h *ToolHandlers) CheckResourceCoverage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resourceType, err := request.RequireString("resource_type")
+	if err != nil {
+		return mcp.NewToolResultError("resource_type is required"), nil
+	}
+	resourceType = strings.TrimSpace(resourceType)
+
+	watched, err := h.auditClient.GetWatchedResources(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query watched resources: %v", err)), nil
+	}
+
+	// Accept either the plural resource type or the Kind
+	var matches []audit.WatchedResource
+	for _, resource := range watched {
+		if strings.EqualFold(resource.ResourceType, resourceType) || strings.EqualFold(resource.Kind, resourceType) {
+			matches = append(matches, resource)
+		}
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Audit Coverage: %s\n", resourceType))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(matches) == 0 {
+		results.WriteString(fmt.Sprintf("❌ %s is NOT watched.\n", resourceType))
+		results.WriteString("  Empty results for this type do not mean nothing happened - changes are simply not recorded.\n")
+		results.WriteString(fmt.Sprintf("\nTotal watched resource types: %d\n", len(watched)))
+		return mcp.NewToolResultText(results.String()), nil
+	}
+
+	for _, resource := range matches {
+		gv := resource.Version
+		if resource.Group != "" {
+			gv = resource.Group + "/" + resource.Version
+		}
+
+		if resource.EventCount == 0 {
+			results.WriteString(fmt.Sprintf("⚠️  %s (%s) is watched but quiet\n", resource.Kind, gv))
+		} else {
+			results.WriteString(fmt.Sprintf("✅ %s (%s) is watched\n", resource.Kind, gv))
+		}
+		results.WriteString(fmt.Sprintf("  Watched since: %s\n", resource.Since.Format(time.RFC3339)))
+		results.WriteString(fmt.Sprintf("  Stored events: %d\n", resource.EventCount))
+		results.WriteString("\n")
+	}
+
+	return mcp.NewToolResultText(results.String()), nil
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+const (
+	// histogramMaxBuckets is the number of buckets above which the default
+	// bucket width is widened
+	histogramMaxBuckets = 48
+	// histogramBarWidth is the length of the bar of the fullest bucket
+	histogramBarWidth = 40
+)
+
+// histogramBucketWidths are the default bucket widths, from narrowest
+var histogramBucketWidths = []time.Duration{
+	time.Minute, 5 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// EventHistogram charts event counts per time bucket, optionally filtered by namespace, resource type and verb
+func (h *ToolHandlers) EventHistogram(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	bucket := histogramBucketWidth(endTime.Sub(startTime))
+	if bucketStr := request.GetString("bucket", ""); bucketStr != "" {
+		bucket, err = time.ParseDuration(bucketStr)
+		if err != nil || bucket <= 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid bucket %q: expected a duration such as 5m or 1h", bucketStr)), nil
+		}
+	}
+
+	opts := audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    request.GetString("namespace", ""),
+		ResourceType: request.GetString("resource_type", ""),
+		Verb:         request.GetString("verb", ""),
+	}
+	histogram, err := h.auditClient.GetEventHistogram(ctx, opts, bucket)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query event histogram: %v", err)), nil
+	}
+
+	if histogram.Total == 0 {
+		return mcp.NewToolResultText("No events found in the specified time range."), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Event Histogram (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	var filters []string
+	for _, filter := range [][2]string{{"Namespace", opts.Namespace}, {"Resource type", opts.ResourceType}, {"Verb", opts.Verb}} {
+		if filter[1] != "" {
+			filters = append(filters, fmt.Sprintf("%s: %s", filter[0], filter[1]))
+		}
+	}
+	if len(filters) > 0 {
+		results.WriteString(strings.Join(filters, ", ") + "\n")
+	}
+	results.WriteString(fmt.Sprintf("Bucket: %s\n", bucket))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	writeHistogram(&results, histogram.Buckets, bucket)
+
+	if histogram.Truncated {
+		results.WriteString("\n⚠️  The watch server stopped scanning early; later buckets are undercounted.\n")
+	}
+
+	results.WriteString(fmt.Sprintf("\nTotal events: %d\n", histogram.Total))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// histogramBucketWidth returns the narrowest default bucket width that
+// splits window into at most histogramMaxBuckets buckets
+func histogramBucketWidth(window time.Duration) time.Duration {
+	for _, width := range histogramBucketWidths {
+		if window <= width*histogramMaxBuckets {
+			return width
+		}
+	}
+	return histogramBucketWidths[len(histogramBucketWidths)-1]
+}
+
+// writeHistogram writes one bar per bucket, scaled to the fullest one, and
+// marks the peak
+func writeHistogram(results *strings.Builder, buckets []audit.HistogramBucket, width time.Duration) {
+	peak := 0
+	for _, bucket := range buckets {
+		peak = max(peak, bucket.Count)
+	}
+
+	layout := "15:04"
+	if width < time.Minute {
+		layout = "15:04:05"
+	}
+	if len(buckets) > 0 && buckets[len(buckets)-1].Start.Sub(buckets[0].Start) >= 24*time.Hour {
+		layout = "01-02 " + layout
+	}
+
+	for _, bucket := range buckets {
+		length := bucket.Count * histogramBarWidth / peak
+		bar := strings.Repeat("█", length)
+		if length == 0 && bucket.Count > 0 {
+			bar, length = "▏", 1
+		}
+		line := fmt.Sprintf("  %s %s%s %d", bucket.Start.Format(layout), bar, strings.Repeat(" ", histogramBarWidth-length), bucket.Count)
+		if bucket.Count == peak {
+			line += " ← peak"
+		}
+		results.WriteString(line + "\n")
+	}
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestHistogramBucketWidth(t *testing.T) {
+	tests := []struct {
+		window time.Duration
+		want   time.Duration
+	}{
+		{30 * time.Minute, time.Minute},
+		{48 * time.Minute, time.Minute},
+		{2 * time.Hour, 5 * time.Minute},
+		{24 * time.Hour, 30 * time.Minute},
+		{7 * 24 * time.Hour, 6 * time.Hour},
+		{365 * 24 * time.Hour, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := histogramBucketWidth(tt.window); got != tt.want {
+			t.Errorf("histogramBucketWidth(%s) = %s, want %s", tt.window, got, tt.want)
+		}
+	}
+}
+
+func TestEventHistogram(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/histogram" || r.URL.Query().Get("bucket") != "15m0s" || r.URL.Query().Get("verb") != "delete" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		json.NewEncoder(w).Encode(audit.EventHistogram{
+			Bucket: "15m0s",
+			Total:  51,
+			Buckets: []audit.HistogramBucket{
+				{Start: start, Count: 1},
+				{Start: start.Add(15 * time.Minute), Count: 50},
+				{Start: start.Add(30 * time.Minute), Count: 0},
+			},
+		})
+	}))
+	defer server.Close()
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{
+		"start_time": start.Format(time.RFC3339),
+		"end_time":   start.Add(45 * time.Minute).Format(time.RFC3339),
+		"bucket":     "15m",
+		"verb":       "delete",
+	}
+
+	result, err := h.EventHistogram(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		"Verb: delete\nBucket: 15m0s\n",
+		"  11:00 ▏" + strings.Repeat(" ", histogramBarWidth-1) + " 1\n",
+		"  11:15 " + strings.Repeat("█", histogramBarWidth) + " 50 ← peak\n",
+		"  11:30 " + strings.Repeat(" ", histogramBarWidth) + " 0\n",
+		"Total events: 51\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output:\n%s", want, text)
+		}
+	}
+
+	request.Params.Arguments.(map[string]any)["bucket"] = "-5m"
+	if result, _ := h.EventHistogram(context.Background(), request); !result.IsError {
+		t.Error("expected an error for a negative bucket")
+	}
+}
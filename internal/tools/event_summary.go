@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// EventSummary reports event counts per namespace and resource type for a time range
+func (h *ToolHandlers) EventSummary(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	groupByTeam := request.GetBool("group_by_team", false)
+	if groupByTeam && len(h.teams) == 0 {
+		return mcp.NewToolResultError("group_by_team requires a team mapping (MCP_TEAM_MAPPING)"), nil
+	}
+
+	summary, err := h.auditClient.GetEventSummary(ctx, startTime, endTime)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query event summary: %v", err)), nil
+	}
+
+	if summary.Total == 0 {
+		return mcp.NewToolResultText("No events found in the specified time range."), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Event Summary (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	typeTotals := make(map[string]int)
+	namespaceTotals := make(map[string]int)
+	for namespace, counts := range summary.Counts {
+		for resourceType, count := range counts {
+			typeTotals[resourceType] += count
+			namespaceTotals[namespace] += count
+		}
+	}
+
+	if groupByTeam {
+		teamTotals, teamNamespaces := teamSummary(h.teams, namespaceTotals)
+		results.WriteString(fmt.Sprintf("👥 By Team: %d\n", len(teamTotals)))
+		for _, team := range sortedByCount(teamTotals) {
+			var cells []string
+			namespaces := teamNamespaces[team]
+			for _, namespace := range sortedByCount(namespaces) {
+				cells = append(cells, fmt.Sprintf("%s=%d", namespaceLabel(namespace), namespaces[namespace]))
+			}
+			results.WriteString(fmt.Sprintf("  - %s (%d): %s\n", team, teamTotals[team], strings.Join(cells, ", ")))
+		}
+	} else {
+		results.WriteString(fmt.Sprintf("📁 By Namespace: %d\n", len(namespaceTotals)))
+		for _, namespace := range sortedByCount(namespaceTotals) {
+			var cells []string
+			counts := summary.Counts[namespace]
+			for _, resourceType := range sortedByCount(counts) {
+				cells = append(cells, fmt.Sprintf("%s=%d", resourceType, counts[resourceType]))
+			}
+			results.WriteString(fmt.Sprintf("  - %s (%d): %s\n", namespaceLabel(namespace), namespaceTotals[namespace], strings.Join(cells, ", ")))
+		}
+	}
+	results.WriteString("\n")
+
+	results.WriteString(fmt.Sprintf("📦 By Resource Type: %d\n", len(typeTotals)))
+	for _, resourceType := range sortedByCount(typeTotals) {
+		results.WriteString(fmt.Sprintf("  - %s: %d\n", resourceType, typeTotals[resourceType]))
+	}
+
+	results.WriteString(fmt.Sprintf("\nTotal events: %d\n", summary.Total))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// teamSummary aggregates per-namespace event counts by owning team. It returns
+// the total per team and the namespace counts making up each total.
+func teamSummary(teams TeamMapping, namespaceTotals map[string]int) (map[string]int, map[string]map[string]int) {
+	totals := make(map[string]int)
+	namespaces := make(map[string]map[string]int)
+	for namespace, count := range namespaceTotals {
+		team := teams.Team(namespace)
+		totals[team] += count
+		if namespaces[team] == nil {
+			namespaces[team] = make(map[string]int)
+		}
+		namespaces[team][namespace] = count
+	}
+	return totals, namespaces
+}
+
+// namespaceLabel names a namespace for display, including the empty namespace
+// of cluster-scoped resources
+func namespaceLabel(namespace string) string {
+	if namespace == "" {
+		return "(cluster-scoped)"
+	}
+	return namespace
+}
+
+// sortedByCount returns the keys of counts ordered by count descending, then name
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
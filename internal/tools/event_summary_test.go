@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestEventSummaryGroupByTeam(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(audit.EventSummary{
+			Total: 18,
+			Counts: map[string]map[string]int{
+				"payments":         {"pods": 5, "deployments": 1},
+				"payments-staging": {"pods": 4},
+				"monitoring":       {"pods": 6},
+				"":                 {"nodes": 2},
+			},
+		})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "teams.yaml")
+	mapping := "teams:\n  payments: [payments, payments-staging]\n  platform: [monitoring]\n"
+	if err := os.WriteFile(path, []byte(mapping), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	teams, err := LoadTeamMapping(path)
+	if err != nil {
+		t.Fatalf("LoadTeamMapping failed: %v", err)
+	}
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, teams)
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{
+		"start_time":    start.Format(time.RFC3339),
+		"end_time":      start.Add(time.Hour).Format(time.RFC3339),
+		"group_by_team": true,
+	}
+
+	result, err := h.EventSummary(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		"👥 By Team: 3\n",
+		"  - payments (10): payments=6, payments-staging=4\n",
+		"  - platform (6): monitoring=6\n",
+		"  - (unassigned) (2): (cluster-scoped)=2\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output:\n%s", want, text)
+		}
+	}
+}
+
+func TestLoadTeamMappingConflict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "teams.yaml")
+	mapping := "teams:\n  payments: [shared]\n  platform: [shared]\n"
+	if err := os.WriteFile(path, []byte(mapping), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTeamMapping(path); err == nil {
+		t.Error("expected an error for a namespace mapped to two teams")
+	}
+}
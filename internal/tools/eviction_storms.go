@@ -0,0 +1,407 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+const (
+	// evictionBurstGap is the longest pause between two evictions on a node
+	// that still belong to the same burst
+	evictionBurstGap = 5 * time.Minute
+
+	// evictionFollowUp is how long after the window evicted pods are followed
+	// to see whether they were replaced
+	evictionFollowUp = 10 * time.Minute
+)
+
+// pressureConditions are the node conditions under which the kubelet evicts pods
+var pressureConditions = []string{"MemoryPressure", "DiskPressure", "PIDPressure"}
+
+// pressurePeriod is a span in which a node reported a pressure condition. An
+// open period has a zero end.
+type pressurePeriod struct {
+	node      string
+	condition string
+	start     time.Time
+	end       time.Time
+}
+
+// podEviction is a pod evicted from a node
+type podEviction struct {
+	key      string // namespace/name
+	workload string
+	node     string
+	message  string
+	at       time.Time
+}
+
+// evictionStorm is a burst of evictions on one node with the pressure
+// periods overlapping it
+type evictionStorm struct {
+	node      string
+	evictions []podEviction
+	pressure  []pressurePeriod
+}
+
+// DetectEvictionStorms correlates node pressure conditions with bursts of pod evictions and whether the evicted pods were replaced
+func (h *ToolHandlers) DetectEvictionStorms(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	minEvictions := request.GetInt("min_evictions", 3)
+	if minEvictions < 1 {
+		return mcp.NewToolResultError("min_evictions must be at least 1"), nil
+	}
+
+	budget := h.newQueryBudget()
+	query := func(resourceType string, end time.Time) ([]audit.AuditEvent, error) {
+		events, err := budget.query(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      end,
+			ResourceType: resourceType,
+		})
+		if err != nil && !errors.Is(err, audit.ErrNoData) {
+			return nil, fmt.Errorf("failed to query %s events: %w", resourceType, err)
+		}
+		return events, nil
+	}
+
+	nodeEvents, err := query("nodes", endTime)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	// Replacement pods can trail the last eviction
+	podEvents, err := query("pods", endTime.Add(evictionFollowUp))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	k8sEvents, err := query("events", endTime)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var inWindow []podEviction
+	for _, eviction := range podEvictions(podEvents, k8sEvents) {
+		if !eviction.at.After(endTime) {
+			inWindow = append(inWindow, eviction)
+		}
+	}
+	periods := nodePressurePeriods(nodeEvents)
+	storms := evictionStorms(inWindow, periods, minEvictions)
+	outcomes := evictionOutcomes(inWindow, podEvents)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Eviction Storm Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(storms) == 0 {
+		results.WriteString(fmt.Sprintf("✅ No node evicted %d or more pods in a burst.\n", minEvictions))
+	} else {
+		results.WriteString(fmt.Sprintf("🌪️  Eviction Storms: %d\n", len(storms)))
+		for _, storm := range storms {
+			first, last := storm.evictions[0].at, storm.evictions[len(storm.evictions)-1].at
+			results.WriteString(fmt.Sprintf("  - Node %s shed %d pods (%s to %s)\n",
+				storm.node, len(storm.evictions), first.Format(time.RFC3339), last.Format(time.RFC3339)))
+
+			if message := storm.evictions[0].message; message != "" {
+				results.WriteString(fmt.Sprintf("      Kubelet: %s\n", message))
+			}
+			if len(storm.pressure) == 0 {
+				results.WriteString("      Pressure: none recorded (check that nodes are watched)\n")
+			}
+			for _, period := range storm.pressure {
+				cleared := "still active"
+				if !period.end.IsZero() {
+					cleared = "cleared " + period.end.Format(time.RFC3339)
+				}
+				results.WriteString(fmt.Sprintf("      Pressure: %s since %s (%s)\n", period.condition, period.start.Format(time.RFC3339), cleared))
+			}
+
+			rescheduled := 0
+			for _, eviction := range storm.evictions {
+				outcome := outcomes[eviction.key]
+				marker := "❌"
+				if outcome.rescheduled {
+					marker = "✅"
+					rescheduled++
+				}
+				workload := eviction.workload
+				if workload == "" {
+					workload = "workload unknown"
+				}
+				results.WriteString(fmt.Sprintf("      %s Pod %s (%s): %s\n", marker, eviction.key, workload, outcome.description))
+			}
+			results.WriteString(fmt.Sprintf("      Rescheduled successfully: %d of %d\n", rescheduled, len(storm.evictions)))
+		}
+		results.WriteString("\n")
+	}
+
+	if stormed := stormEvictionCount(storms); len(inWindow) > stormed {
+		results.WriteString(fmt.Sprintf("ℹ️  Isolated evictions outside storms: %d\n\n", len(inWindow)-stormed))
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", len(nodeEvents)+len(podEvents)+len(k8sEvents)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// nodePressurePeriods follows the pressure conditions of each node through
+// its observations. A condition already true on the first observation
+// starts a period there.
+func nodePressurePeriods(events []audit.AuditEvent) []pressurePeriod {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	open := make(map[[2]string]*pressurePeriod)
+	var periods []*pressurePeriod
+	for _, event := range sorted {
+		status, _ := event.ObjectChanges["status"].(map[string]any)
+		conditions, _ := status["conditions"].([]any)
+		active := make(map[string]bool)
+		for _, c := range conditions {
+			condition, _ := c.(map[string]any)
+			conditionType, _ := condition["type"].(string)
+			active[conditionType] = condition["status"] == "True"
+		}
+
+		for _, conditionType := range pressureConditions {
+			key := [2]string{event.ResourceName, conditionType}
+			period := open[key]
+			switch {
+			case active[conditionType] && period == nil && event.Verb != "delete":
+				period = &pressurePeriod{node: event.ResourceName, condition: conditionType, start: event.Timestamp}
+				open[key] = period
+				periods = append(periods, period)
+			case (!active[conditionType] || event.Verb == "delete") && period != nil:
+				period.end = event.Timestamp
+				delete(open, key)
+			}
+		}
+	}
+
+	result := make([]pressurePeriod, 0, len(periods))
+	for _, period := range periods {
+		result = append(result, *period)
+	}
+	return result
+}
+
+// podEvictions collects evicted pods from pod status (reason Evicted) and
+// from Evicted Kubernetes events, in time order. Each pod is reported once,
+// at the first sign of its eviction. The workload is empty for pods only
+// known from Kubernetes events.
+func podEvictions(podEvents, k8sEvents []audit.AuditEvent) []podEviction {
+	evictions := make(map[string]*podEviction)
+	workloads := make(map[string]string)
+	nodes := make(map[string]string)
+
+	record := func(key, node, message string, at time.Time) {
+		eviction := evictions[key]
+		if eviction == nil {
+			evictions[key] = &podEviction{key: key, node: node, message: message, at: at}
+			return
+		}
+		if at.Before(eviction.at) {
+			eviction.at = at
+		}
+		if eviction.node == "" {
+			eviction.node = node
+		}
+		if eviction.message == "" {
+			eviction.message = message
+		}
+	}
+
+	for _, event := range podEvents {
+		key := event.Namespace + "/" + event.ResourceName
+		workloads[key] = podWorkload(event)
+		spec, _ := event.ObjectChanges["spec"].(map[string]any)
+		if node, _ := spec["nodeName"].(string); node != "" {
+			nodes[key] = node
+		}
+
+		status, _ := event.ObjectChanges["status"].(map[string]any)
+		if reason, _ := status["reason"].(string); reason == "Evicted" {
+			message, _ := status["message"].(string)
+			record(key, nodes[key], message, event.Timestamp)
+		}
+	}
+
+	for _, event := range k8sEvents {
+		if reason, _ := event.ObjectChanges["reason"].(string); reason != "Evicted" {
+			continue
+		}
+		involved, _ := event.ObjectChanges["involvedObject"].(map[string]any)
+		if kind, _ := involved["kind"].(string); kind != "Pod" {
+			continue
+		}
+		namespace, _ := involved["namespace"].(string)
+		name, _ := involved["name"].(string)
+		message, _ := event.ObjectChanges["message"].(string)
+		record(namespace+"/"+name, nestedName(event.ObjectChanges, "source", "host"), message, event.Timestamp)
+	}
+
+	sorted := make([]podEviction, 0, len(evictions))
+	for key, eviction := range evictions {
+		if eviction.node == "" {
+			eviction.node = nodes[key]
+		}
+		eviction.workload = workloads[key]
+		sorted = append(sorted, *eviction)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].at.Equal(sorted[j].at) {
+			return sorted[i].at.Before(sorted[j].at)
+		}
+		return sorted[i].key < sorted[j].key
+	})
+	return sorted
+}
+
+// evictionStorms groups each node's evictions into bursts separated by more
+// than evictionBurstGap and returns the bursts of at least minEvictions pods,
+// with the node's pressure periods overlapping them
+func evictionStorms(evictions []podEviction, periods []pressurePeriod, minEvictions int) []evictionStorm {
+	byNode := make(map[string][]podEviction)
+	for _, eviction := range evictions {
+		node := eviction.node
+		if node == "" {
+			node = "(unknown node)"
+		}
+		byNode[node] = append(byNode[node], eviction)
+	}
+
+	var storms []evictionStorm
+	flush := func(node string, burst []podEviction) {
+		if len(burst) < minEvictions {
+			return
+		}
+		first, last := burst[0].at, burst[len(burst)-1].at
+		storm := evictionStorm{node: node, evictions: burst}
+		for _, period := range periods {
+			if period.node != node || period.start.After(last) {
+				continue
+			}
+			// Evictions can trail the condition clearing by a little
+			if !period.end.IsZero() && period.end.Add(evictionBurstGap).Before(first) {
+				continue
+			}
+			storm.pressure = append(storm.pressure, period)
+		}
+		storms = append(storms, storm)
+	}
+
+	for node, nodeEvictions := range byNode {
+		var burst []podEviction
+		for _, eviction := range nodeEvictions {
+			if len(burst) > 0 && eviction.at.Sub(burst[len(burst)-1].at) > evictionBurstGap {
+				flush(node, burst)
+				burst = nil
+			}
+			burst = append(burst, eviction)
+		}
+		flush(node, burst)
+	}
+
+	sort.Slice(storms, func(i, j int) bool {
+		if !storms[i].evictions[0].at.Equal(storms[j].evictions[0].at) {
+			return storms[i].evictions[0].at.Before(storms[j].evictions[0].at)
+		}
+		return storms[i].node < storms[j].node
+	})
+	return storms
+}
+
+// evictionOutcome is what became of an evicted pod
+type evictionOutcome struct {
+	description string
+	rescheduled bool
+}
+
+// evictionOutcomes follows each evicted pod, keyed by namespace/name, to the
+// first pod of the same workload created after its eviction. Each
+// replacement is matched to one eviction, in eviction order. A pod counts as
+// rescheduled once its replacement reached Running. Bare pods have no
+// controller to replace them.
+func evictionOutcomes(evictions []podEviction, podEvents []audit.AuditEvent) map[string]evictionOutcome {
+	outcomes := make(map[string]evictionOutcome)
+	used := make(map[string]bool) // namespace/name@creation of matched replacements
+	for _, eviction := range evictions {
+		switch {
+		case eviction.workload == "":
+			outcomes[eviction.key] = evictionOutcome{description: "unknown (pod not watched)"}
+			continue
+		case strings.HasPrefix(eviction.workload, "Pod "):
+			outcomes[eviction.key] = evictionOutcome{description: "not replaced (no controller)"}
+			continue
+		}
+
+		var replacement string
+		var created time.Time
+		for _, event := range podEvents {
+			key := event.Namespace + "/" + event.ResourceName
+			if event.Verb != "create" || !event.Timestamp.After(eviction.at) || used[key+"@"+event.Timestamp.String()] ||
+				podWorkload(event) != eviction.workload {
+				continue
+			}
+			if created.IsZero() || event.Timestamp.Before(created) {
+				replacement, created = key, event.Timestamp
+			}
+		}
+		if replacement == "" {
+			outcomes[eviction.key] = evictionOutcome{description: "no replacement pod created"}
+			continue
+		}
+		used[replacement+"@"+created.String()] = true
+		outcomes[eviction.key] = replacementOutcome(replacement, created, podEvents)
+	}
+	return outcomes
+}
+
+// replacementOutcome reports where the replacement pod was scheduled and
+// whether it reached Running. StatefulSet replacements reuse the evicted
+// pod's name, so only observations from the replacement's creation on count.
+func replacementOutcome(replacement string, created time.Time, podEvents []audit.AuditEvent) evictionOutcome {
+	var node string
+	for _, event := range podEvents {
+		if event.Namespace+"/"+event.ResourceName != replacement || event.Timestamp.Before(created) {
+			continue
+		}
+		spec, _ := event.ObjectChanges["spec"].(map[string]any)
+		if nodeName, _ := spec["nodeName"].(string); nodeName != "" {
+			node = nodeName
+		}
+		status, _ := event.ObjectChanges["status"].(map[string]any)
+		if phase, _ := status["phase"].(string); phase == "Running" {
+			return evictionOutcome{description: fmt.Sprintf("replaced by %s, Running on %s", replacement, node), rescheduled: true}
+		}
+	}
+	if node != "" {
+		return evictionOutcome{description: fmt.Sprintf("replaced by %s on %s, not Running yet", replacement, node)}
+	}
+	return evictionOutcome{description: fmt.Sprintf("replaced by %s, not scheduled", replacement)}
+}
+
+// stormEvictionCount returns the number of evictions across storms
+func stormEvictionCount(storms []evictionStorm) int {
+	count := 0
+	for _, storm := range storms {
+		count += len(storm.evictions)
+	}
+	return count
+}
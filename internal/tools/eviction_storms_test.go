@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// nodeConditionEvent returns a stored node reporting the given conditions as True
+func nodeConditionEvent(node string, at time.Time, trueConditions ...string) audit.AuditEvent {
+	var conditions []any
+	for _, conditionType := range append([]string{"Ready"}, pressureConditions...) {
+		status := "False"
+		for _, c := range trueConditions {
+			if c == conditionType {
+				status = "True"
+			}
+		}
+		conditions = append(conditions, map[string]any{"type": conditionType, "status": status})
+	}
+	return audit.AuditEvent{
+		Timestamp: at, Verb: "update", ResourceType: "nodes", ResourceName: node,
+		ObjectChanges: map[string]any{"status": map[string]any{"conditions": conditions}},
+	}
+}
+
+// storedPod returns a stored pod of ReplicaSet web-5d8f on node in the given phase
+func storedPod(name, verb, node, phase, reason string, at time.Time) audit.AuditEvent {
+	return audit.AuditEvent{
+		Timestamp: at, Verb: verb, Namespace: "shop", ResourceType: "pods", ResourceName: name,
+		ObjectChanges: map[string]any{
+			"metadata": map[string]any{
+				"labels":          map[string]any{"pod-template-hash": "5d8f"},
+				"ownerReferences": []any{map[string]any{"kind": "ReplicaSet", "name": "web-5d8f"}},
+			},
+			"spec":   map[string]any{"nodeName": node},
+			"status": map[string]any{"phase": phase, "reason": reason, "message": "The node was low on resource: memory."},
+		},
+	}
+}
+
+func TestNodePressurePeriods(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	periods := nodePressurePeriods([]audit.AuditEvent{
+		nodeConditionEvent("node-1", start.Add(4*time.Minute), "Ready"),
+		nodeConditionEvent("node-1", start, "Ready"),
+		nodeConditionEvent("node-1", start.Add(time.Minute), "Ready", "MemoryPressure"),
+		nodeConditionEvent("node-1", start.Add(2*time.Minute), "Ready", "MemoryPressure", "DiskPressure"),
+		// Already under pressure on its first observation
+		nodeConditionEvent("node-2", start, "DiskPressure"),
+	})
+
+	if len(periods) != 3 {
+		t.Fatalf("expected 3 pressure periods, got %+v", periods)
+	}
+	// Ordered by start
+	memory := periods[1]
+	if memory.node != "node-1" || memory.condition != "MemoryPressure" ||
+		!memory.start.Equal(start.Add(time.Minute)) || !memory.end.Equal(start.Add(4*time.Minute)) {
+		t.Errorf("unexpected memory pressure period %+v", memory)
+	}
+	if open := periods[0]; open.node != "node-2" || !open.end.IsZero() {
+		t.Errorf("expected an open disk pressure period on node-2, got %+v", open)
+	}
+}
+
+func TestEvictionStorms(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	podEvents := []audit.AuditEvent{
+		storedPod("web-a", "update", "node-1", "Failed", "Evicted", start.Add(2*time.Minute)),
+		storedPod("web-b", "update", "node-1", "Failed", "Evicted", start.Add(3*time.Minute)),
+		// Replacement of web-a, Running elsewhere
+		storedPod("web-d", "create", "", "Pending", "", start.Add(3*time.Minute+10*time.Second)),
+		storedPod("web-d", "update", "node-2", "Running", "", start.Add(4*time.Minute)),
+		// Isolated eviction much later
+		storedPod("web-late", "update", "node-1", "Failed", "Evicted", start.Add(time.Hour)),
+	}
+	k8sEvents := []audit.AuditEvent{{
+		Timestamp: start.Add(5 * time.Minute), Namespace: "shop", ResourceType: "events", ResourceName: "web-c.1",
+		ObjectChanges: map[string]any{
+			"reason":         "Evicted",
+			"message":        "The node was low on resource: memory.",
+			"involvedObject": map[string]any{"kind": "Pod", "namespace": "shop", "name": "web-c"},
+			"source":         map[string]any{"host": "node-1"},
+		},
+	}}
+	periods := []pressurePeriod{
+		{node: "node-1", condition: "MemoryPressure", start: start.Add(time.Minute), end: start.Add(6 * time.Minute)},
+		{node: "node-2", condition: "DiskPressure", start: start},
+	}
+
+	evictions := podEvictions(podEvents, k8sEvents)
+	if len(evictions) != 4 {
+		t.Fatalf("expected 4 evictions, got %+v", evictions)
+	}
+
+	storms := evictionStorms(evictions, periods, 3)
+	if len(storms) != 1 {
+		t.Fatalf("expected 1 storm, got %+v", storms)
+	}
+	storm := storms[0]
+	if storm.node != "node-1" || len(storm.evictions) != 3 || storm.evictions[2].key != "shop/web-c" {
+		t.Errorf("unexpected storm %+v", storm)
+	}
+	if len(storm.pressure) != 1 || storm.pressure[0].condition != "MemoryPressure" {
+		t.Errorf("expected node-1's memory pressure, got %+v", storm.pressure)
+	}
+
+	outcomes := evictionOutcomes(evictions, podEvents)
+	if outcome := outcomes["shop/web-a"]; !outcome.rescheduled || !strings.Contains(outcome.description, "shop/web-d, Running on node-2") {
+		t.Errorf("expected web-a to be replaced by a Running pod, got %+v", outcome)
+	}
+	// The only replacement already went to web-a
+	if outcome := outcomes["shop/web-b"]; outcome.rescheduled || outcome.description != "no replacement pod created" {
+		t.Errorf("expected no replacement for web-b, got %+v", outcome)
+	}
+	// web-c is only known from its Kubernetes event
+	if outcome := outcomes["shop/web-c"]; outcome.rescheduled || outcome.description != "unknown (pod not watched)" {
+		t.Errorf("expected an unknown outcome for web-c, got %+v", outcome)
+	}
+
+	if storms := evictionStorms(evictions, periods, 5); len(storms) != 0 {
+		t.Errorf("expected no storm of 5 evictions, got %+v", storms)
+	}
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// sortOrder controls which findings of a category are shown when the
+// category is truncated.
+type sortOrder string
+
+const (
+	// sortNewestFirst shows the most recently seen findings first.
+	sortNewestFirst sortOrder = "newest"
+	// sortOldestFirst shows findings in the order they first appeared.
+	sortOldestFirst sortOrder = "oldest"
+)
+
+// parseSortOrder reads the optional sort_order parameter, defaulting to
+// newest first so responders see the latest occurrences.
+func parseSortOrder(request mcp.CallToolRequest) (sortOrder, error) {
+	switch order := sortOrder(request.GetString("sort_order", string(sortNewestFirst))); order {
+	case sortNewestFirst, sortOldestFirst:
+		return order, nil
+	default:
+		return "", fmt.Errorf("invalid sort_order %q: must be %q or %q", order, sortNewestFirst, sortOldestFirst)
+	}
+}
+
+// Finding is a group of events that share the same subject and message.
+type Finding struct {
+	Subject   string    `json:"subject,omitempty"`
+	Message   string    `json:"message"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// DeduplicateFindings collapses events with an identical subject and message
+// into a single Finding carrying the number of occurrences and the first and
+// last time they were seen. Findings keep the order in which they first
+// appear in events. subject describes the object an event is about, e.g.
+// "Pod default/web-0"; a nil subject groups by message alone.
+func DeduplicateFindings(events []audit.AuditEvent, subject func(audit.AuditEvent) string) []Finding {
+	type findingKey struct {
+		subject string
+		message string
+	}
+
+	findings := []Finding{}
+	index := make(map[findingKey]int)
+
+	for _, event := range events {
+		key := findingKey{message: event.Message}
+		if subject != nil {
+			key.subject = subject(event)
+		}
+
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(findings)
+			findings = append(findings, Finding{
+				Subject:   key.subject,
+				Message:   key.message,
+				Count:     1,
+				FirstSeen: event.Timestamp,
+				LastSeen:  event.Timestamp,
+			})
+			continue
+		}
+
+		f := &findings[i]
+		f.Count++
+		if event.Timestamp.Before(f.FirstSeen) {
+			f.FirstSeen = event.Timestamp
+		}
+		if event.Timestamp.After(f.LastSeen) {
+			f.LastSeen = event.Timestamp
+		}
+	}
+
+	return findings
+}
+
+// String renders the finding as a single report line without indentation.
+func (f Finding) String() string {
+	when := f.FirstSeen.Format(time.RFC3339)
+	if f.Count > 1 {
+		layout := "15:04:05"
+		if f.FirstSeen.Format("2006-01-02") != f.LastSeen.Format("2006-01-02") {
+			layout = time.RFC3339
+		}
+		when = fmt.Sprintf("×%d between %s and %s", f.Count, f.FirstSeen.Format(layout), f.LastSeen.Format(layout))
+	}
+
+	if f.Subject == "" {
+		return fmt.Sprintf("%s: %s", when, f.Message)
+	}
+	return fmt.Sprintf("%s: %s - %s", when, f.Subject, f.Message)
+}
+
+// sortFindings orders findings by when they were last seen (newest first) or
+// first seen (oldest first). Ties keep their original order.
+func sortFindings(findings []Finding, order sortOrder) {
+	if order == sortOldestFirst {
+		sort.SliceStable(findings, func(i, j int) bool {
+			return findings[i].FirstSeen.Before(findings[j].FirstSeen)
+		})
+		return
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].LastSeen.After(findings[j].LastSeen)
+	})
+}
+
+// writeFindings deduplicates and sorts events, then writes at most limit
+// findings to results followed by a note on how many distinct findings were
+// left out.
+func writeFindings(results *strings.Builder, events []audit.AuditEvent, limit int, order sortOrder, subject func(audit.AuditEvent) string) {
+	findings := DeduplicateFindings(events, subject)
+	sortFindings(findings, order)
+	for _, f := range findings[:min(limit, len(findings))] {
+		results.WriteString(fmt.Sprintf("  - %s\n", f))
+	}
+	if len(findings) > limit {
+		results.WriteString(fmt.Sprintf("  ... and %d more distinct findings\n", len(findings)-limit))
+	}
+}
+
+// podSubject describes a pod event as "Pod <namespace>/<name>".
+func podSubject(event audit.AuditEvent) string {
+	return fmt.Sprintf("Pod %s/%s", event.Namespace, event.ResourceName)
+}
+
+// nodeSubject describes a node event as "Node <name>".
+func nodeSubject(event audit.AuditEvent) string {
+	return fmt.Sprintf("Node %s", event.ResourceName)
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestDeduplicateFindingsCollapsesBurst(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 1, 0, 0, time.UTC)
+
+	var events []audit.AuditEvent
+	for i := 0; i < 47; i++ {
+		events = append(events, audit.AuditEvent{
+			Timestamp:    start.Add(time.Duration(i) * time.Minute),
+			Namespace:    "default",
+			ResourceName: "web-0",
+			Message:      "Back-off restarting failed container",
+		})
+	}
+	// Interleave a different pod with the same message and a distinct message.
+	events = append(events,
+		audit.AuditEvent{Timestamp: start, Namespace: "default", ResourceName: "web-1", Message: "Back-off restarting failed container"},
+		audit.AuditEvent{Timestamp: start, Namespace: "default", ResourceName: "web-0", Message: "Container image pulled"},
+	)
+
+	findings := DeduplicateFindings(events, podSubject)
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d: %+v", len(findings), findings)
+	}
+
+	burst := findings[0]
+	if burst.Count != 47 {
+		t.Errorf("burst count = %d, want 47", burst.Count)
+	}
+	if !burst.FirstSeen.Equal(start) {
+		t.Errorf("first seen = %s, want %s", burst.FirstSeen, start)
+	}
+	if want := start.Add(46 * time.Minute); !burst.LastSeen.Equal(want) {
+		t.Errorf("last seen = %s, want %s", burst.LastSeen, want)
+	}
+
+	line := burst.String()
+	if want := "×47 between 10:01:00 and 10:47:00: Pod default/web-0 - Back-off restarting failed container"; line != want {
+		t.Errorf("rendered %q, want %q", line, want)
+	}
+
+	if findings[1].Subject != "Pod default/web-1" || findings[1].Count != 1 {
+		t.Errorf("unexpected second finding: %+v", findings[1])
+	}
+	if findings[2].Message != "Container image pulled" || findings[2].Count != 1 {
+		t.Errorf("unexpected third finding: %+v", findings[2])
+	}
+}
+
+func TestDeduplicateFindingsWithoutSubject(t *testing.T) {
+	ts := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	events := []audit.AuditEvent{
+		{Timestamp: ts, ResourceName: "a", Message: "same"},
+		{Timestamp: ts.Add(time.Second), ResourceName: "b", Message: "same"},
+	}
+
+	findings := DeduplicateFindings(events, nil)
+	if len(findings) != 1 || findings[0].Count != 2 {
+		t.Fatalf("expected one finding with count 2, got %+v", findings)
+	}
+	if got := findings[0].String(); strings.Contains(got, " - ") {
+		t.Errorf("finding without subject should not render one: %q", got)
+	}
+}
+
+func TestWriteFindingsLimit(t *testing.T) {
+	ts := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	events := []audit.AuditEvent{
+		{Timestamp: ts, Message: "a"},
+		{Timestamp: ts, Message: "b"},
+		{Timestamp: ts, Message: "a"},
+		{Timestamp: ts, Message: "c"},
+	}
+
+	var results strings.Builder
+	writeFindings(&results, events, 2, sortOldestFirst, nil)
+
+	out := results.String()
+	if strings.Count(out, "  - ") != 2 {
+		t.Errorf("expected 2 findings rendered, got:\n%s", out)
+	}
+	if !strings.Contains(out, "... and 1 more distinct findings") {
+		t.Errorf("expected omitted note, got:\n%s", out)
+	}
+}
+
+func TestWriteFindingsNewestFirst(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	var events []audit.AuditEvent
+	for i := 0; i < 8; i++ {
+		events = append(events, audit.AuditEvent{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Message:   fmt.Sprintf("event %d", i),
+		})
+	}
+
+	var newest strings.Builder
+	writeFindings(&newest, events, 3, sortNewestFirst, nil)
+	want := "  - 2025-03-04T10:07:00Z: event 7\n" +
+		"  - 2025-03-04T10:06:00Z: event 6\n" +
+		"  - 2025-03-04T10:05:00Z: event 5\n" +
+		"  ... and 5 more distinct findings\n"
+	if got := newest.String(); got != want {
+		t.Errorf("newest first rendered:\n%s\nwant:\n%s", got, want)
+	}
+
+	var oldest strings.Builder
+	writeFindings(&oldest, events, 1, sortOldestFirst, nil)
+	if got := oldest.String(); !strings.HasPrefix(got, "  - 2025-03-04T10:00:00Z: event 0\n") {
+		t.Errorf("oldest first rendered:\n%s", got)
+	}
+}
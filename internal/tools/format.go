@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// outputFormat selects how a tool renders its report
+type outputFormat string
+
+const (
+	// formatText is the human-readable report
+	formatText outputFormat = "text"
+	// formatJSON is a structured report for agents that parse the result
+	formatJSON outputFormat = "json"
+)
+
+// parseOutputFormat reads the optional format parameter, defaulting to text
+func parseOutputFormat(request mcp.CallToolRequest) (outputFormat, error) {
+	switch format := outputFormat(request.GetString("format", string(formatText))); format {
+	case formatText, formatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid format %q: must be %q or %q", format, formatText, formatJSON)
+	}
+}
+
+// categoryReport is the JSON form of one category of a report: the number of
+// events in it and all of their deduplicated findings
+type categoryReport struct {
+	Events   int       `json:"events"`
+	Findings []Finding `json:"findings"`
+}
+
+// newCategoryReport deduplicates and sorts the events of a category like
+// writeFindings, without truncating them
+func newCategoryReport(events []audit.AuditEvent, order sortOrder, subject func(audit.AuditEvent) string) categoryReport {
+	findings := DeduplicateFindings(events, subject)
+	sortFindings(findings, order)
+	return categoryReport{Events: len(events), Findings: findings}
+}
+
+// reportNotices returns the truncation notices of the tool call, never nil so
+// the JSON shape is the same with and without notices
+func (b *queryBudget) reportNotices() []string {
+	if b.notices == nil {
+		return []string{}
+	}
+	return b.notices
+}
+
+// newJSONResult returns report as indented JSON text
+func newJSONResult(report any) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to encode report: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// newEventsServer serves events as the only page of every query
+func newEventsServer(t *testing.T, events []audit.AuditEvent) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(audit.EventPage{Items: events})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// jsonKeys returns the sorted top-level keys of a JSON object
+func jsonKeys(t *testing.T, text string) []string {
+	t.Helper()
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(text), &object); err != nil {
+		t.Fatalf("invalid JSON %q: %v", text, err)
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestCheckPodIssuesJSON(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	server := newEventsServer(t, []audit.AuditEvent{
+		{Timestamp: start, Namespace: "shop", ResourceType: "pods", ResourceName: "web-0", Message: "Back-off restarting: CrashLoopBackOff"},
+		{Timestamp: start.Add(time.Minute), Namespace: "shop", ResourceType: "pods", ResourceName: "web-0", Message: "Back-off restarting: CrashLoopBackOff"},
+		{Timestamp: start.Add(2 * time.Minute), Namespace: "shop", ResourceType: "pods", ResourceName: "api-0", Message: "Container OOMKilled"},
+	})
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{
+		"start_time": start.Format(time.RFC3339),
+		"end_time":   start.Add(time.Hour).Format(time.RFC3339),
+		"namespace":  "shop",
+		"format":     "json",
+	}
+
+	result, err := h.CheckPodIssues(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+
+	wantKeys := []string{"configIssues", "crashLoopBackOff", "endTime", "imagePull", "namespace", "notices", "oomKilled", "probeFailures", "replicaIssues", "startTime", "totalEvents"}
+	if got := jsonKeys(t, text); !slices.Equal(got, wantKeys) {
+		t.Errorf("expected keys %v, got %v", wantKeys, got)
+	}
+
+	var report podIssuesReport
+	if err := json.Unmarshal([]byte(text), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if report.TotalEvents != 3 || report.Namespace != "shop" || !report.StartTime.Equal(start) {
+		t.Errorf("unexpected report header %+v", report)
+	}
+	wantCrashLoop := categoryReport{Events: 2, Findings: []Finding{{
+		Subject:   "Pod shop/web-0",
+		Message:   "Back-off restarting: CrashLoopBackOff",
+		Count:     2,
+		FirstSeen: start,
+		LastSeen:  start.Add(time.Minute),
+	}}}
+	if !reflect.DeepEqual(report.CrashLoopBackOff, wantCrashLoop) {
+		t.Errorf("expected crashLoopBackOff %+v, got %+v", wantCrashLoop, report.CrashLoopBackOff)
+	}
+	if report.OOMKilled.Events != 1 || report.ImagePull.Events != 0 || report.ImagePull.Findings == nil {
+		t.Errorf("expected one OOMKilled event and an empty imagePull category, got %+v and %+v", report.OOMKilled, report.ImagePull)
+	}
+
+	// The report round-trips unchanged
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != text {
+		t.Errorf("report changed in a round trip:\n%s\nvs\n%s", data, text)
+	}
+}
+
+func TestCheckNodeHealthJSONWithoutEvents(t *testing.T) {
+	server := newEventsServer(t, nil)
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{"duration": "1h", "format": "json"}
+
+	result, err := h.CheckNodeHealth(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+
+	wantKeys := []string{"endTime", "kubelet", "networkIssues", "notReady", "notices", "resourcePressure", "startTime", "totalEvents"}
+	if got := jsonKeys(t, text); !slices.Equal(got, wantKeys) {
+		t.Errorf("expected keys %v, got %v", wantKeys, got)
+	}
+
+	var report nodeHealthReport
+	if err := json.Unmarshal([]byte(text), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if report.TotalEvents != 0 || report.NotReady.Events != 0 || report.NotReady.Findings == nil || report.Notices == nil {
+		t.Errorf("expected empty categories and notices, got %+v", report)
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{}
+	if format, err := parseOutputFormat(request); err != nil || format != formatText {
+		t.Errorf("expected text by default, got %q (err %v)", format, err)
+	}
+
+	request.Params.Arguments = map[string]any{"format": "yaml"}
+	if _, err := parseOutputFormat(request); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
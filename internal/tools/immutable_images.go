@@ -0,0 +1,281 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// workloadKinds maps the workload resource types checked for image references to their Kind for display
+var workloadKinds = map[string]string{
+	"deployments":  "Deployment",
+	"statefulsets": "StatefulSet",
+	"daemonsets":   "DaemonSet",
+}
+
+// floatingTags are tags conventionally moved to every new build
+var floatingTags = map[string]bool{
+	"latest":  true,
+	"main":    true,
+	"master":  true,
+	"dev":     true,
+	"develop": true,
+	"edge":    true,
+	"nightly": true,
+	"stable":  true,
+}
+
+// imageRef is a parsed container image reference
+type imageRef struct {
+	repository string
+	tag        string
+	digest     string
+}
+
+// pinned reports whether the reference resolves to a fixed digest
+func (r imageRef) pinned() bool {
+	return r.digest != ""
+}
+
+// floating reports whether the tag is conventionally moved to new builds. A
+// missing tag means :latest.
+func (r imageRef) floating() bool {
+	return !r.pinned() && (r.tag == "" || floatingTags[r.tag])
+}
+
+// imageUsage is one container image of a workload
+type imageUsage struct {
+	workload     string
+	container    string
+	image        string
+	ref          imageRef
+	redeploys    int
+	lastRedeploy time.Time
+}
+
+// CheckImmutableImages flags workloads that reference images by mutable tags and tags that were redeployed without changing
+func (h *ToolHandlers) CheckImmutableImages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	budget := h.newQueryBudget()
+	var usages []imageUsage
+	workloads := 0
+	for _, resourceType := range []string{"deployments", "statefulsets", "daemonsets"} {
+		events, err := budget.query(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Namespace:    namespace,
+			ResourceType: resourceType,
+		})
+		if err != nil && !errors.Is(err, audit.ErrNoData) {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query %s events: %v", resourceType, err)), nil
+		}
+		found, count := collectImageUsages(workloadKinds[resourceType], events)
+		usages = append(usages, found...)
+		workloads += count
+	}
+
+	if workloads == 0 {
+		msg := "No Deployment, StatefulSet or DaemonSet events found in the specified time range"
+		if namespace != "" {
+			msg += fmt.Sprintf(" for namespace '%s'", namespace)
+		}
+		return mcp.NewToolResultText(msg + "."), nil
+	}
+
+	var floating, unpinned, redeployed []imageUsage
+	pinned := 0
+	for _, usage := range usages {
+		switch {
+		case usage.ref.pinned():
+			pinned++
+		case usage.ref.floating():
+			floating = append(floating, usage)
+		default:
+			unpinned = append(unpinned, usage)
+		}
+		if usage.redeploys > 0 {
+			redeployed = append(redeployed, usage)
+		}
+	}
+	sort.SliceStable(redeployed, func(i, j int) bool {
+		return redeployed[i].redeploys > redeployed[j].redeploys
+	})
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Image Immutability Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(redeployed) > 0 {
+		results.WriteString(fmt.Sprintf("🔄 Same-Tag Redeploys: %d\n", len(redeployed)))
+		results.WriteString("  (the pod template changed but the image tag did not, so new pods may run a different digest)\n")
+		for _, usage := range redeployed[:min(10, len(redeployed))] {
+			results.WriteString(fmt.Sprintf("  - %s container %s: %s redeployed %d times, last at %s\n",
+				usage.workload, usage.container, usage.image, usage.redeploys, usage.lastRedeploy.Format(time.RFC3339)))
+		}
+		if len(redeployed) > 10 {
+			results.WriteString(fmt.Sprintf("  ... and %d more\n", len(redeployed)-10))
+		}
+		results.WriteString("\n")
+	}
+
+	if len(floating) > 0 {
+		results.WriteString(fmt.Sprintf("🔴 Floating Tags: %d\n", len(floating)))
+		results.WriteString("  (tags like :latest point to a new build whenever one is pushed)\n")
+		writeImageUsages(&results, floating)
+		results.WriteString("\n")
+	}
+
+	if len(unpinned) > 0 {
+		results.WriteString(fmt.Sprintf("⚠️  Tags Without Digest: %d\n", len(unpinned)))
+		results.WriteString("  (version tags can still be overwritten in the registry; pin with @sha256:...)\n")
+		writeImageUsages(&results, unpinned)
+		results.WriteString("\n")
+	}
+
+	if len(floating) == 0 && len(unpinned) == 0 {
+		results.WriteString("✅ Every observed container image is pinned by digest.\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal workloads analyzed: %d (%d of %d container images pinned by digest)\n", workloads, pinned, len(usages)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// collectImageUsages returns the container images of each workload at its last
+// observation, counting the pod template changes that kept a mutable image
+// unchanged. It also returns the number of workloads still present.
+func collectImageUsages(kind string, events []audit.AuditEvent) ([]imageUsage, int) {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	type workloadState struct {
+		template map[string]any
+		usages   map[string]*imageUsage
+	}
+
+	states := make(map[string]*workloadState)
+	var order []string
+	for _, event := range sorted {
+		name := fmt.Sprintf("%s %s/%s", kind, event.Namespace, event.ResourceName)
+		if event.Verb == "delete" {
+			delete(states, name)
+			continue
+		}
+
+		spec, _ := event.ObjectChanges["spec"].(map[string]any)
+		template, ok := spec["template"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		state := states[name]
+		if state == nil {
+			state = &workloadState{usages: make(map[string]*imageUsage)}
+			states[name] = state
+			order = append(order, name)
+		}
+		templateChanged := state.template != nil && !reflect.DeepEqual(state.template, template)
+
+		current := make(map[string]*imageUsage)
+		for container, image := range templateImages(template) {
+			usage := &imageUsage{workload: name, container: container, image: image, ref: parseImageRef(image)}
+			if previous, ok := state.usages[container]; ok && previous.image == image {
+				usage.redeploys, usage.lastRedeploy = previous.redeploys, previous.lastRedeploy
+				if templateChanged && !usage.ref.pinned() {
+					usage.redeploys++
+					usage.lastRedeploy = event.Timestamp
+				}
+			}
+			current[container] = usage
+		}
+		state.template = template
+		state.usages = current
+	}
+
+	var usages []imageUsage
+	workloads := 0
+	for _, name := range order {
+		state, ok := states[name]
+		if !ok {
+			continue
+		}
+		// A workload deleted and recreated appears in order twice
+		delete(states, name)
+		workloads++
+
+		containers := make([]string, 0, len(state.usages))
+		for container := range state.usages {
+			containers = append(containers, container)
+		}
+		sort.Strings(containers)
+		for _, container := range containers {
+			usages = append(usages, *state.usages[container])
+		}
+	}
+	return usages, workloads
+}
+
+// templateImages returns the image of each (init) container in a pod template
+func templateImages(template map[string]any) map[string]string {
+	spec, _ := template["spec"].(map[string]any)
+
+	images := make(map[string]string)
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := spec[field].([]any)
+		for _, c := range containers {
+			container, _ := c.(map[string]any)
+			name, _ := container["name"].(string)
+			image, _ := container["image"].(string)
+			if name != "" && image != "" {
+				images[name] = image
+			}
+		}
+	}
+	return images
+}
+
+// parseImageRef splits an image reference into repository, tag and digest.
+// The registry port in "host:5000/app" is not mistaken for a tag.
+func parseImageRef(image string) imageRef {
+	var ref imageRef
+	name, digest, _ := strings.Cut(image, "@")
+	ref.digest = digest
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.repository, ref.tag = name[:i], name[i+1:]
+	} else {
+		ref.repository = name
+	}
+	return ref
+}
+
+// writeImageUsages lists container images, capped at 10 entries
+func writeImageUsages(results *strings.Builder, usages []imageUsage) {
+	for _, usage := range usages[:min(10, len(usages))] {
+		results.WriteString(fmt.Sprintf("  - %s container %s: %s\n", usage.workload, usage.container, usage.image))
+	}
+	if len(usages) > 10 {
+		results.WriteString(fmt.Sprintf("  ... and %d more\n", len(usages)-10))
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestParseImageRef(t *testing.T) {
+	tests := []struct {
+		image    string
+		want     imageRef
+		pinned   bool
+		floating bool
+	}{
+		{"nginx", imageRef{repository: "nginx"}, false, true},
+		{"nginx:latest", imageRef{repository: "nginx", tag: "latest"}, false, true},
+		{"ghcr.io/acme/api:main", imageRef{repository: "ghcr.io/acme/api", tag: "main"}, false, true},
+		{"registry:5000/app:v1.2.3", imageRef{repository: "registry:5000/app", tag: "v1.2.3"}, false, false},
+		{"registry:5000/app", imageRef{repository: "registry:5000/app"}, false, true},
+		{"nginx:latest@sha256:abc", imageRef{repository: "nginx", tag: "latest", digest: "sha256:abc"}, true, false},
+		{"nginx@sha256:abc", imageRef{repository: "nginx", digest: "sha256:abc"}, true, false},
+	}
+
+	for _, tt := range tests {
+		got := parseImageRef(tt.image)
+		if got != tt.want {
+			t.Errorf("parseImageRef(%q) = %+v, want %+v", tt.image, got, tt.want)
+		}
+		if got.pinned() != tt.pinned || got.floating() != tt.floating {
+			t.Errorf("%q: pinned=%v floating=%v, want %v/%v", tt.image, got.pinned(), got.floating(), tt.pinned, tt.floating)
+		}
+	}
+}
+
+func TestCollectImageUsagesCountsSameTagRedeploys(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	deployment := func(minutes int, restartedAt, image string) audit.AuditEvent {
+		return audit.AuditEvent{
+			Timestamp:    start.Add(time.Duration(minutes) * time.Minute),
+			Verb:         "update",
+			Namespace:    "default",
+			ResourceName: "web",
+			ObjectChanges: map[string]any{"spec": map[string]any{
+				"replicas": float64(minutes),
+				"template": map[string]any{
+					"metadata": map[string]any{"annotations": map[string]any{"kubectl.kubernetes.io/restartedAt": restartedAt}},
+					"spec": map[string]any{"containers": []any{
+						map[string]any{"name": "app", "image": image},
+					}},
+				},
+			}},
+		}
+	}
+
+	events := []audit.AuditEvent{
+		deployment(20, "b", "web:latest"),
+		deployment(0, "a", "web:latest"),
+		// Scaling alone keeps the template and is not a redeploy
+		deployment(10, "a", "web:latest"),
+		deployment(30, "b", "web:latest"),
+	}
+
+	usages, workloads := collectImageUsages("Deployment", events)
+	if workloads != 1 || len(usages) != 1 {
+		t.Fatalf("expected 1 workload with 1 image, got %d workloads and %+v", workloads, usages)
+	}
+	usage := usages[0]
+	if usage.workload != "Deployment default/web" || usage.container != "app" {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	if usage.redeploys != 1 || !usage.lastRedeploy.Equal(start.Add(20*time.Minute)) {
+		t.Errorf("expected 1 redeploy at +20m, got %d at %s", usage.redeploys, usage.lastRedeploy)
+	}
+
+	// Changing the tag is a regular rollout
+	usages, _ = collectImageUsages("Deployment", []audit.AuditEvent{
+		deployment(0, "a", "web:v1"),
+		deployment(10, "b", "web:v2"),
+	})
+	if usages[0].redeploys != 0 || usages[0].image != "web:v2" {
+		t.Errorf("expected the latest image without redeploys, got %+v", usages[0])
+	}
+}
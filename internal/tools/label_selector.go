@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// labelQueryPageSize is the page size used while scanning for label matches
+	labelQueryPageSize = 1000
+	// labelQueryMaxEvents caps the events scanned for one label selector query
+	labelQueryMaxEvents = 10000
+)
+
+// labeledObject collects the matching events of one object
+type labeledObject struct {
+	name   string
+	events []audit.AuditEvent
+}
+
+// QueryByLabelSelector lists events of objects whose labels match a selector, cluster-wide
+func (h *ToolHandlers) QueryByLabelSelector(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	selector, err := parseLabelSelector(request.GetString("label_selector", ""))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resourceType := request.GetString("resource_type", "")
+
+	objects := make(map[string]*labeledObject)
+	scanned := 0
+	matched := 0
+	truncated := false
+	cursor := ""
+	for {
+		page, err := h.auditClient.QueryEventsPage(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			ResourceType: resourceType,
+			Limit:        labelQueryPageSize,
+			Cursor:       cursor,
+		})
+		if errors.Is(err, audit.ErrNoData) {
+			break
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+		}
+
+		for _, event := range page.Items {
+			scanned++
+			if !selector.Matches(labels.Set(objectLabels(event))) {
+				continue
+			}
+			matched++
+
+			key := fmt.Sprintf("%s %s", event.ResourceType, event.ResourceName)
+			if event.Namespace != "" {
+				key = fmt.Sprintf("%s %s/%s", event.ResourceType, event.Namespace, event.ResourceName)
+			}
+			if objects[key] == nil {
+				objects[key] = &labeledObject{name: key}
+			}
+			objects[key].events = append(objects[key].events, event)
+		}
+
+		if !page.HasMore {
+			break
+		}
+		if scanned >= labelQueryMaxEvents {
+			truncated = true
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if matched == 0 {
+		msg := fmt.Sprintf("No events for objects matching '%s' found in the specified time range (%d events scanned).", selector, scanned)
+		if truncated {
+			msg += fmt.Sprintf(" The scan stopped after %d events; narrow the time range or set resource_type.", labelQueryMaxEvents)
+		}
+		return mcp.NewToolResultText(msg), nil
+	}
+
+	sorted := make([]*labeledObject, 0, len(objects))
+	for _, object := range objects {
+		sorted = append(sorted, object)
+	}
+	// Most recently active objects first
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i].events[len(sorted[i].events)-1], sorted[j].events[len(sorted[j].events)-1]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.After(b.Timestamp)
+		}
+		return sorted[i].name < sorted[j].name
+	})
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Label Selector Query (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(fmt.Sprintf("Selector: %s\n", selector))
+	if resourceType != "" {
+		results.WriteString(fmt.Sprintf("Resource Type: %s\n", resourceType))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	results.WriteString(fmt.Sprintf("🏷️  Matching Objects: %d\n", len(sorted)))
+	for _, object := range sorted {
+		results.WriteString(fmt.Sprintf("  %s: %d events\n", object.name, len(object.events)))
+		// Show the latest events of each object
+		recent := object.events[max(0, len(object.events)-5):]
+		for i := len(recent) - 1; i >= 0; i-- {
+			results.WriteString(fmt.Sprintf("    - %s: %s\n", recent[i].Timestamp.Format(time.RFC3339), recent[i].Message))
+		}
+	}
+	results.WriteString("\n")
+
+	if truncated {
+		results.WriteString(fmt.Sprintf("⚠️  Scan stopped after %d events (narrow the time range or set resource_type)\n", labelQueryMaxEvents))
+	}
+
+	results.WriteString(fmt.Sprintf("\nTotal events scanned: %d, matching: %d\n", scanned, matched))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// parseLabelSelector parses a standard label selector, rejecting empty
+// selectors that would match every object
+func parseLabelSelector(raw string) (labels.Selector, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("label_selector is required (e.g. 'app=web,tier!=cache')")
+	}
+
+	selector, err := labels.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label_selector %q: %v", raw, err)
+	}
+	if selector.Empty() {
+		return nil, fmt.Errorf("label_selector %q matches every object; add at least one requirement", raw)
+	}
+	return selector, nil
+}
+
+// objectLabels returns metadata.labels from the stored object
+func objectLabels(event audit.AuditEvent) map[string]string {
+	metadata, _ := event.ObjectChanges["metadata"].(map[string]any)
+	raw, _ := metadata["labels"].(map[string]any)
+
+	result := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if s, ok := value.(string); ok {
+			result[key] = s
+		}
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	for _, raw := range []string{"", "   ", "app in (", "app=web=x"} {
+		if _, err := parseLabelSelector(raw); err == nil {
+			t.Errorf("parseLabelSelector(%q) should fail", raw)
+		}
+	}
+
+	selector, err := parseLabelSelector("app=web,tier!=cache")
+	if err != nil {
+		t.Fatalf("parseLabelSelector failed: %v", err)
+	}
+
+	event := func(lbls map[string]any) audit.AuditEvent {
+		return audit.AuditEvent{ObjectChanges: map[string]any{"metadata": map[string]any{"labels": lbls}}}
+	}
+	if !selector.Matches(labels.Set(objectLabels(event(map[string]any{"app": "web", "tier": "frontend"})))) {
+		t.Error("expected app=web,tier=frontend to match")
+	}
+	if selector.Matches(labels.Set(objectLabels(event(map[string]any{"app": "web", "tier": "cache"})))) {
+		t.Error("expected tier=cache not to match")
+	}
+	if selector.Matches(labels.Set(objectLabels(audit.AuditEvent{}))) {
+		t.Error("expected an object without labels not to match")
+	}
+}
@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// namespaceQueryLimit caps the namespace events fetched per verb
+const namespaceQueryLimit = 1000
+
+// namespaceRecord tracks a namespace seen during the window
+type namespaceRecord struct {
+	name    string
+	created time.Time
+	deleted time.Time
+}
+
+// NamespaceLifecycle lists namespaces created and deleted in a window and how long short-lived ones existed
+func (h *ToolHandlers) NamespaceLifecycle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	records := make(map[string]*namespaceRecord)
+	record := func(name string) *namespaceRecord {
+		if records[name] == nil {
+			records[name] = &namespaceRecord{name: name}
+		}
+		return records[name]
+	}
+
+	var truncated []string
+	analyzed := 0
+	for _, verb := range []string{"create", "delete"} {
+		events, err := h.auditClient.QueryEvents(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			ResourceType: "namespaces",
+			Verb:         verb,
+			Limit:        namespaceQueryLimit,
+		})
+		if errors.Is(err, audit.ErrNoData) {
+			continue
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+		}
+		if len(events) >= namespaceQueryLimit {
+			truncated = append(truncated, verb)
+		}
+
+		for _, event := range events {
+			created, hasCreated := creationTimestamp(event)
+			switch verb {
+			case "create":
+				// The informer's initial list replays existing namespaces as creates
+				if !createdWithin(event, startTime, endTime) {
+					continue
+				}
+				r := record(event.ResourceName)
+				r.created = event.Timestamp
+				if hasCreated {
+					r.created = created
+				}
+			case "delete":
+				r := record(event.ResourceName)
+				r.deleted = event.Timestamp
+				// The deleted object still carries its creation time, even if it
+				// was created before the window
+				if hasCreated && r.created.IsZero() {
+					r.created = created
+				}
+			}
+			analyzed++
+		}
+	}
+
+	if analyzed == 0 {
+		return mcp.NewToolResultText("No namespace creations or deletions found in the specified time range."), nil
+	}
+
+	var created, deleted, shortLived []*namespaceRecord
+	for _, r := range records {
+		createdInWindow := !r.created.IsZero() && !r.created.Before(startTime)
+		if createdInWindow {
+			created = append(created, r)
+		}
+		if !r.deleted.IsZero() {
+			deleted = append(deleted, r)
+			if createdInWindow {
+				shortLived = append(shortLived, r)
+			}
+		}
+	}
+	sortNamespaceRecords(created, func(r *namespaceRecord) time.Time { return r.created })
+	sortNamespaceRecords(deleted, func(r *namespaceRecord) time.Time { return r.deleted })
+	sortNamespaceRecords(shortLived, func(r *namespaceRecord) time.Time { return r.created })
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Namespace Lifecycle (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(created) > 0 {
+		results.WriteString(fmt.Sprintf("🆕 Created: %d namespaces\n", len(created)))
+		for _, r := range created {
+			results.WriteString(fmt.Sprintf("  - %s: %s\n", r.created.Format(time.RFC3339), r.name))
+		}
+		results.WriteString("\n")
+	}
+
+	if len(deleted) > 0 {
+		results.WriteString(fmt.Sprintf("🗑️  Deleted: %d namespaces\n", len(deleted)))
+		for _, r := range deleted {
+			age := "unknown age"
+			if !r.created.IsZero() {
+				age = fmt.Sprintf("existed %s", formatLifetime(r.deleted.Sub(r.created)))
+			}
+			results.WriteString(fmt.Sprintf("  - %s: %s (%s)\n", r.deleted.Format(time.RFC3339), r.name, age))
+		}
+		results.WriteString("\n")
+	}
+
+	if len(shortLived) > 0 {
+		results.WriteString(fmt.Sprintf("⏱️  Short-lived (created and deleted in window): %d namespaces\n", len(shortLived)))
+		for _, r := range shortLived {
+			results.WriteString(fmt.Sprintf("  - %s: lived %s\n", r.name, formatLifetime(r.deleted.Sub(r.created))))
+		}
+		results.WriteString("\n")
+	}
+
+	results.WriteString("ℹ️  The user performing each action is not available from watch events.\n")
+	if len(truncated) > 0 {
+		results.WriteString(fmt.Sprintf("⚠️  Results truncated at %d events for: %s (narrow the time range)\n",
+			namespaceQueryLimit, strings.Join(truncated, ", ")))
+	}
+
+	results.WriteString(fmt.Sprintf("\nTotal namespace events analyzed: %d\n", analyzed))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// creationTimestamp returns metadata.creationTimestamp from the stored object
+func creationTimestamp(event audit.AuditEvent) (time.Time, bool) {
+	metadata, ok := event.ObjectChanges["metadata"].(map[string]any)
+	if !ok {
+		return time.Time{}, false
+	}
+	raw, ok := metadata["creationTimestamp"].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	created, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created, true
+}
+
+// sortNamespaceRecords orders records chronologically by the given timestamp
+func sortNamespaceRecords(records []*namespaceRecord, at func(*namespaceRecord) time.Time) {
+	sort.Slice(records, func(i, j int) bool {
+		return at(records[i]).Before(at(records[j]))
+	})
+}
+
+// formatLifetime renders a duration rounded to a readable precision
+func formatLifetime(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(time.Minute).String()
+}
@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,35 +14,64 @@ import (
 // ToolHandlers contains all MCP tool handlers
 type ToolHandlers struct {
 	auditClient *audit.Client
+	eventBudget int
+	teams       TeamMapping
 }
 
-// NewToolHandlers creates a new ToolHandlers instance
-func NewToolHandlers(auditClient *audit.Client) *ToolHandlers {
+// NewToolHandlers creates a new ToolHandlers instance. eventBudget caps the
+// events a tool analyzes per query; zero or less uses DefaultEventBudget.
+// teams maps namespaces to owning teams for the group_by_team option and may
+// be nil.
+func NewToolHandlers(auditClient *audit.Client, eventBudget int, teams TeamMapping) *ToolHandlers {
+	if eventBudget <= 0 {
+		eventBudget = DefaultEventBudget
+	}
 	return &ToolHandlers{
 		auditClient: auditClient,
+		eventBudget: eventBudget,
+		teams:       teams,
 	}
 }
 
-// parseTimeRange extracts start and end time from tool request
+// parseTimeRange extracts start and end time from tool request. A duration
+// such as 2h or 7d selects the window ending now; start_time and end_time
+// take precedence over it when given.
 func parseTimeRange(request mcp.CallToolRequest) (time.Time, time.Time, error) {
-	startStr, err := request.RequireString("start_time")
-	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("start_time is required (RFC3339 format)")
-	}
+	startStr := request.GetString("start_time", "")
+	endStr := request.GetString("end_time", "")
+	durationStr := request.GetString("duration", "")
 
-	endStr, err := request.RequireString("end_time")
-	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("end_time is required (RFC3339 format)")
+	if durationStr == "" {
+		if startStr == "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("start_time is required (RFC3339 format), or a duration such as 2h")
+		}
+		if endStr == "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("end_time is required (RFC3339 format), or a duration such as 2h")
+		}
 	}
 
-	startTime, err := time.Parse(time.RFC3339, startStr)
-	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid start_time format: %w", err)
+	endTime := time.Now()
+	if endStr != "" {
+		var err error
+		endTime, err = time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end_time format: %w", err)
+		}
 	}
 
-	endTime, err := time.Parse(time.RFC3339, endStr)
-	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid end_time format: %w", err)
+	var startTime time.Time
+	if startStr != "" {
+		var err error
+		startTime, err = time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start_time format: %w", err)
+		}
+	} else {
+		duration, err := parseLookback(durationStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		startTime = endTime.Add(-duration)
 	}
 
 	if endTime.Before(startTime) {
@@ -51,6 +81,24 @@ func parseTimeRange(request mcp.CallToolRequest) (time.Time, time.Time, error) {
 	return startTime, endTime, nil
 }
 
+// parseLookback parses a positive duration such as 90m or 2h, or a number
+// of days such as 7d
+func parseLookback(s string) (time.Duration, error) {
+	var duration time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		duration = time.Duration(n) * 24 * time.Hour
+	} else {
+		duration, err = time.ParseDuration(s)
+	}
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %q: expected a duration such as 90m, 2h or 7d", s)
+	}
+	return duration, nil
+}
+
 // CheckNodeHealth checks for node-related issues in audit logs
 func (h *ToolHandlers) CheckNodeHealth(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	startTime, endTime, err := parseTimeRange(request)
@@ -58,12 +106,42 @@ func (h *ToolHandlers) CheckNodeHealth(ctx context.Context, request mcp.CallTool
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	order, err := parseSortOrder(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	format, err := parseOutputFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Query node-related events
-	events, err := h.auditClient.GetResourceTypeEvents(ctx, "", "nodes", startTime, endTime)
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		ResourceType: "nodes",
+	})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
 	}
 
+	issues := categorizeNodeIssues(events)
+
+	if format == formatJSON {
+		return newJSONResult(nodeHealthReport{
+			StartTime:        startTime,
+			EndTime:          endTime,
+			TotalEvents:      len(events),
+			NotReady:         newCategoryReport(issues.notReady, order, nodeSubject),
+			ResourcePressure: newCategoryReport(issues.pressure, order, nodeSubject),
+			NetworkIssues:    newCategoryReport(issues.network, order, nodeSubject),
+			Kubelet:          newCategoryReport(issues.kubelet, order, nil),
+			Notices:          budget.reportNotices(),
+		})
+	}
+
 	if len(events) == 0 {
 		return mcp.NewToolResultText("No node events found in the specified time range."), nil
 	}
@@ -72,81 +150,85 @@ func (h *ToolHandlers) CheckNodeHealth(ctx context.Context, request mcp.CallTool
 	results.WriteString(fmt.Sprintf("Node Health Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
 	results.WriteString(strings.Repeat("=", 60) + "\n\n")
 
-	// Categorize node issues
-	notReadyEvents := []audit.AuditEvent{}
-	pressureEvents := []audit.AuditEvent{}
-	networkEvents := []audit.AuditEvent{}
-	kubeletEvents := []audit.AuditEvent{}
-
-	for _, event := range events {
-		msg := strings.ToLower(event.Message)
-		annotations := strings.ToLower(fmt.Sprintf("%v", event.Annotations))
-
-		if strings.Contains(msg, "notready") || strings.Contains(annotations, "notready") {
-			notReadyEvents = append(notReadyEvents, event)
-		}
-		if strings.Contains(msg, "pressure") || strings.Contains(msg, "memorypressure") ||
-			strings.Contains(msg, "diskpressure") {
-			pressureEvents = append(pressureEvents, event)
-		}
-		if strings.Contains(msg, "network") && strings.Contains(msg, "unavailable") {
-			networkEvents = append(networkEvents, event)
-		}
-		if strings.Contains(msg, "kubelet") {
-			kubeletEvents = append(kubeletEvents, event)
-		}
-	}
-
 	// Report findings
-	if len(notReadyEvents) > 0 {
-		results.WriteString(fmt.Sprintf("⚠️  NotReady Nodes: %d events\n", len(notReadyEvents)))
-		for _, event := range notReadyEvents[:min(5, len(notReadyEvents))] {
-			results.WriteString(fmt.Sprintf("  - %s: %s (Node: %s)\n",
-				event.Timestamp.Format(time.RFC3339), event.Message, event.ResourceName))
-		}
+	if len(issues.notReady) > 0 {
+		results.WriteString(fmt.Sprintf("⚠️  NotReady Nodes: %d events\n", len(issues.notReady)))
+		writeFindings(&results, issues.notReady, 5, order, nodeSubject)
 		results.WriteString("\n")
 	}
 
-	if len(pressureEvents) > 0 {
-		results.WriteString(fmt.Sprintf("⚠️  Resource Pressure: %d events\n", len(pressureEvents)))
-		for _, event := range pressureEvents[:min(5, len(pressureEvents))] {
-			results.WriteString(fmt.Sprintf("  - %s: %s (Node: %s)\n",
-				event.Timestamp.Format(time.RFC3339), event.Message, event.ResourceName))
-		}
+	if len(issues.pressure) > 0 {
+		results.WriteString(fmt.Sprintf("⚠️  Resource Pressure: %d events\n", len(issues.pressure)))
+		writeFindings(&results, issues.pressure, 5, order, nodeSubject)
 		results.WriteString("\n")
 	}
 
-	if len(networkEvents) > 0 {
-		results.WriteString(fmt.Sprintf("⚠️  Network Issues: %d events\n", len(networkEvents)))
-		for _, event := range networkEvents[:min(5, len(networkEvents))] {
-			results.WriteString(fmt.Sprintf("  - %s: %s (Node: %s)\n",
-				event.Timestamp.Format(time.RFC3339), event.Message, event.ResourceName))
-		}
+	if len(issues.network) > 0 {
+		results.WriteString(fmt.Sprintf("⚠️  Network Issues: %d events\n", len(issues.network)))
+		writeFindings(&results, issues.network, 5, order, nodeSubject)
 		results.WriteString("\n")
 	}
 
-	if len(kubeletEvents) > 0 {
-		results.WriteString(fmt.Sprintf("ℹ️  Kubelet Events: %d events\n", len(kubeletEvents)))
-		results.WriteString(fmt.Sprintf("  (Showing first 3 of %d)\n", len(kubeletEvents)))
-		for _, event := range kubeletEvents[:min(3, len(kubeletEvents))] {
-			results.WriteString(fmt.Sprintf("  - %s: %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Message))
-		}
+	if len(issues.kubelet) > 0 {
+		results.WriteString(fmt.Sprintf("ℹ️  Kubelet Events: %d events\n", len(issues.kubelet)))
+		writeFindings(&results, issues.kubelet, 3, order, nil)
 		results.WriteString("\n")
 	}
 
-	if len(notReadyEvents) == 0 && len(pressureEvents) == 0 && len(networkEvents) == 0 {
+	if len(issues.notReady) == 0 && len(issues.pressure) == 0 && len(issues.network) == 0 {
 		results.WriteString("✅ No critical node health issues detected.\n")
 	}
 
+	budget.writeNotices(&results)
+
 	results.WriteString(fmt.Sprintf("\nTotal node events analyzed: %d\n", len(events)))
 
 	return mcp.NewToolResultText(results.String()), nil
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// nodeIssues are node events sorted into the categories of
+// check_node_health. An event may fall into several categories.
+type nodeIssues struct {
+	notReady []audit.AuditEvent
+	pressure []audit.AuditEvent
+	network  []audit.AuditEvent
+	kubelet  []audit.AuditEvent
+}
+
+// categorizeNodeIssues matches the message, and for NotReady the
+// annotations, of each event against the node health categories
+func categorizeNodeIssues(events []audit.AuditEvent) nodeIssues {
+	var issues nodeIssues
+	for _, event := range events {
+		msg := strings.ToLower(event.Message)
+		annotations := strings.ToLower(fmt.Sprintf("%v", event.Annotations))
+
+		if isNodeNotReady(msg) || isNodeNotReady(annotations) {
+			issues.notReady = append(issues.notReady, event)
+		}
+		if isResourcePressure(msg) {
+			issues.pressure = append(issues.pressure, event)
+		}
+		if isNetworkUnavailable(msg) {
+			issues.network = append(issues.network, event)
+		}
+		if isKubeletEvent(msg) {
+			issues.kubelet = append(issues.kubelet, event)
+		}
 	}
-	return b
+	return issues
+}
+
+// nodeHealthReport is the format=json result of check_node_health. Every
+// category is present, with zero events when nothing matched.
+type nodeHealthReport struct {
+	StartTime        time.Time      `json:"startTime"`
+	EndTime          time.Time      `json:"endTime"`
+	TotalEvents      int            `json:"totalEvents"`
+	NotReady         categoryReport `json:"notReady"`
+	ResourcePressure categoryReport `json:"resourcePressure"`
+	NetworkIssues    categoryReport `json:"networkIssues"`
+	Kubelet          categoryReport `json:"kubelet"`
+	// Notices are set when the event budget truncated the analysis
+	Notices []string `json:"notices"`
 }
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		start     time.Time
+		end       time.Time
+		window    time.Duration
+		wantError string
+	}{
+		{name: "absolute", args: map[string]any{"start_time": "2024-01-01T00:00:00Z", "end_time": "2024-01-01T06:00:00Z"}, start: start, end: end},
+		{name: "absolute wins over duration", args: map[string]any{"start_time": "2024-01-01T00:00:00Z", "end_time": "2024-01-01T06:00:00Z", "duration": "2h"}, start: start, end: end},
+		{name: "duration before end_time", args: map[string]any{"end_time": "2024-01-01T06:00:00Z", "duration": "90m"}, start: end.Add(-90 * time.Minute), end: end},
+		{name: "duration", args: map[string]any{"duration": "2h"}, window: 2 * time.Hour},
+		{name: "days", args: map[string]any{"duration": "7d"}, window: 7 * 24 * time.Hour},
+		{name: "invalid duration", args: map[string]any{"duration": "soon"}, wantError: "invalid duration"},
+		{name: "negative duration", args: map[string]any{"duration": "-2h"}, wantError: "invalid duration"},
+		{name: "missing start", args: map[string]any{"end_time": "2024-01-01T06:00:00Z"}, wantError: "start_time is required"},
+		{name: "missing end", args: map[string]any{"start_time": "2024-01-01T00:00:00Z"}, wantError: "end_time is required"},
+		{name: "reversed", args: map[string]any{"start_time": "2024-01-01T06:00:00Z", "end_time": "2024-01-01T00:00:00Z"}, wantError: "end_time must be after start_time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request mcp.CallToolRequest
+			request.Params.Arguments = tt.args
+
+			gotStart, gotEnd, err := parseTimeRange(request)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.window > 0 {
+				// The window ends now
+				if since := time.Since(gotEnd); since < 0 || since > time.Minute {
+					t.Errorf("expected the range to end now, got %s", gotEnd)
+				}
+				if got := gotEnd.Sub(gotStart); got != tt.window {
+					t.Errorf("expected a %s window, got %s", tt.window, got)
+				}
+				return
+			}
+			if !gotStart.Equal(tt.start) || !gotEnd.Equal(tt.end) {
+				t.Errorf("expected %s to %s, got %s to %s", tt.start, tt.end, gotStart, gotEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// upgradeCorrelationWindow is how far around a node version change pod
+// deletions and node warnings are attributed to the upgrade. Nodes are
+// usually drained before the new kubelet reports in, so the window reaches
+// back as well.
+const upgradeCorrelationWindow = 15 * time.Minute
+
+// nodeVersions are the component versions a node reports in status.nodeInfo
+type nodeVersions struct {
+	kubelet string
+	runtime string
+}
+
+// nodeVersionChange is a component version change of one node
+type nodeVersionChange struct {
+	node      string
+	component string
+	from      string
+	to        string
+	timestamp time.Time
+}
+
+// TrackNodeVersionChanges reports kubelet and container runtime version changes (node upgrades) and the pod disruptions around them
+func (h *ToolHandlers) TrackNodeVersionChanges(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	budget := h.newQueryBudget()
+	nodeEvents, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		ResourceType: "nodes",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query node events: %v", err)), nil
+	}
+
+	changes, latest := detectNodeVersionChanges(nodeEvents)
+	if len(changes) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No node version changes found in the specified time range (%d node events analyzed).", len(nodeEvents))), nil
+	}
+
+	correlationStart := startTime.Add(-upgradeCorrelationWindow)
+	correlationEnd := endTime.Add(upgradeCorrelationWindow)
+
+	podDeletes, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    correlationStart,
+		EndTime:      correlationEnd,
+		ResourceType: "pods",
+		Verb:         "delete",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query pod events: %v", err)), nil
+	}
+
+	k8sEvents, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    correlationStart,
+		EndTime:      correlationEnd,
+		ResourceType: "events",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query Kubernetes events: %v", err)), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Node Version Changes (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	results.WriteString(fmt.Sprintf("🔄 Version Changes: %d\n", len(changes)))
+	disrupted := 0
+	for _, change := range changes {
+		results.WriteString(fmt.Sprintf("  - %s: node %s %s %s → %s\n",
+			change.timestamp.Format(time.RFC3339), change.node, change.component, change.from, change.to))
+
+		pods := upgradePodDeletes(change, podDeletes)
+		if len(pods) > 0 {
+			disrupted++
+			results.WriteString(fmt.Sprintf("      🔴 Pods deleted from %s within %s: %d (%s)\n",
+				change.node, upgradeCorrelationWindow, len(pods), strings.Join(pods[:min(5, len(pods))], ", ")))
+			if len(pods) > 5 {
+				results.WriteString(fmt.Sprintf("        ... and %d more\n", len(pods)-5))
+			}
+		}
+
+		warnings := upgradeNodeWarnings(change, k8sEvents)
+		if len(warnings) > 0 {
+			disrupted++
+			results.WriteString(fmt.Sprintf("      ⚠️  Node warnings within %s: %s\n", upgradeCorrelationWindow, strings.Join(warnings, ", ")))
+		}
+	}
+	results.WriteString("\n")
+
+	if disrupted == 0 {
+		results.WriteString("✅ No pod deletions or node warnings around the version changes.\n\n")
+	}
+
+	results.WriteString("📊 Kubelet Versions at End of Window:\n")
+	for _, line := range kubeletVersionSkew(latest) {
+		results.WriteString(fmt.Sprintf("  - %s\n", line))
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal node events analyzed: %d\n", len(nodeEvents)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// nodeInfoVersions returns the kubelet and container runtime versions of a stored node
+func nodeInfoVersions(event audit.AuditEvent) nodeVersions {
+	status, _ := event.ObjectChanges["status"].(map[string]any)
+	return nodeVersions{
+		kubelet: nestedName(status, "nodeInfo", "kubeletVersion"),
+		runtime: nestedName(status, "nodeInfo", "containerRuntimeVersion"),
+	}
+}
+
+// detectNodeVersionChanges compares consecutive observations of each node
+// and returns the version changes in time order, along with the latest
+// versions of every node still present. The first observation of a node is
+// its baseline.
+func detectNodeVersionChanges(events []audit.AuditEvent) ([]nodeVersionChange, map[string]nodeVersions) {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	latest := make(map[string]nodeVersions)
+	var changes []nodeVersionChange
+	for _, event := range sorted {
+		if event.Verb == "delete" {
+			delete(latest, event.ResourceName)
+			continue
+		}
+
+		current := nodeInfoVersions(event)
+		before, seen := latest[event.ResourceName]
+		latest[event.ResourceName] = current
+		if !seen {
+			continue
+		}
+
+		if before.kubelet != "" && current.kubelet != "" && before.kubelet != current.kubelet {
+			changes = append(changes, nodeVersionChange{node: event.ResourceName, component: "kubelet",
+				from: before.kubelet, to: current.kubelet, timestamp: event.Timestamp})
+		}
+		if before.runtime != "" && current.runtime != "" && before.runtime != current.runtime {
+			changes = append(changes, nodeVersionChange{node: event.ResourceName, component: "container runtime",
+				from: before.runtime, to: current.runtime, timestamp: event.Timestamp})
+		}
+	}
+	return changes, latest
+}
+
+// aroundUpgrade reports whether t falls in the correlation window around a change
+func aroundUpgrade(change nodeVersionChange, t time.Time) bool {
+	return !t.Before(change.timestamp.Add(-upgradeCorrelationWindow)) &&
+		!t.After(change.timestamp.Add(upgradeCorrelationWindow))
+}
+
+// upgradePodDeletes lists pods deleted from the upgraded node around the change
+func upgradePodDeletes(change nodeVersionChange, podDeletes []audit.AuditEvent) []string {
+	var pods []string
+	for _, pod := range podDeletes {
+		spec, _ := pod.ObjectChanges["spec"].(map[string]any)
+		if nodeName, _ := spec["nodeName"].(string); nodeName != change.node {
+			continue
+		}
+		if aroundUpgrade(change, pod.Timestamp) {
+			pods = append(pods, pod.Namespace+"/"+pod.ResourceName)
+		}
+	}
+	sort.Strings(pods)
+	return pods
+}
+
+// upgradeNodeWarnings counts the Warning events about the upgraded node
+// around the change by reason, e.g. "NodeNotReady ×2"
+func upgradeNodeWarnings(change nodeVersionChange, k8sEvents []audit.AuditEvent) []string {
+	counts := make(map[string]int)
+	reasons := make(map[string]bool)
+	for _, event := range k8sEvents {
+		if eventType, _ := event.ObjectChanges["type"].(string); eventType != "Warning" {
+			continue
+		}
+		involved, _ := event.ObjectChanges["involvedObject"].(map[string]any)
+		kind, _ := involved["kind"].(string)
+		name, _ := involved["name"].(string)
+		if kind != "Node" || name != change.node || !aroundUpgrade(change, event.Timestamp) {
+			continue
+		}
+		reason, _ := event.ObjectChanges["reason"].(string)
+		counts[reason]++
+		reasons[reason] = true
+	}
+
+	var warnings []string
+	for _, reason := range sortedKeys(reasons) {
+		warnings = append(warnings, fmt.Sprintf("%s ×%d", reason, counts[reason]))
+	}
+	return warnings
+}
+
+// kubeletVersionSkew summarizes how many nodes run each kubelet version, most common first
+func kubeletVersionSkew(latest map[string]nodeVersions) []string {
+	counts := make(map[string]int)
+	for _, versions := range latest {
+		version := versions.kubelet
+		if version == "" {
+			version = "unknown"
+		}
+		counts[version]++
+	}
+
+	versions := make([]string, 0, len(counts))
+	for version := range counts {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if counts[versions[i]] != counts[versions[j]] {
+			return counts[versions[i]] > counts[versions[j]]
+		}
+		return versions[i] < versions[j]
+	})
+
+	lines := make([]string, 0, len(versions))
+	for _, version := range versions {
+		lines = append(lines, fmt.Sprintf("%s: %d nodes", version, counts[version]))
+	}
+	return lines
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// versionedNode builds a stored node update reporting the given versions
+func versionedNode(name string, at time.Time, kubelet, runtime string) audit.AuditEvent {
+	return audit.AuditEvent{
+		Timestamp:    at,
+		Verb:         "update",
+		ResourceType: "nodes",
+		ResourceName: name,
+		ObjectChanges: map[string]any{"status": map[string]any{"nodeInfo": map[string]any{
+			"kubeletVersion":          kubelet,
+			"containerRuntimeVersion": runtime,
+		}}},
+	}
+}
+
+func TestDetectNodeVersionChanges(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	events := []audit.AuditEvent{
+		versionedNode("node-1", start.Add(10*time.Minute), "v1.30.1", "containerd://1.7.2"),
+		versionedNode("node-1", start, "v1.29.3", "containerd://1.7.2"),
+		versionedNode("node-2", start, "v1.29.3", "containerd://1.6.0"),
+		versionedNode("node-2", start.Add(5*time.Minute), "v1.29.3", "containerd://1.7.2"),
+		// A node first seen after its upgrade is only a baseline
+		versionedNode("node-3", start.Add(time.Minute), "v1.30.1", "containerd://1.7.2"),
+	}
+
+	changes, latest := detectNodeVersionChanges(events)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].node != "node-2" || changes[0].component != "container runtime" {
+		t.Errorf("unexpected runtime change %+v", changes[0])
+	}
+	upgrade := changes[1]
+	if upgrade.node != "node-1" || upgrade.component != "kubelet" || upgrade.from != "v1.29.3" || upgrade.to != "v1.30.1" {
+		t.Errorf("unexpected kubelet change %+v", upgrade)
+	}
+
+	skew := kubeletVersionSkew(latest)
+	if len(skew) != 2 || skew[0] != "v1.30.1: 2 nodes" || skew[1] != "v1.29.3: 1 nodes" {
+		t.Errorf("unexpected version skew %v", skew)
+	}
+
+	podDeletes := []audit.AuditEvent{
+		{Timestamp: start.Add(-5 * time.Minute), Namespace: "default", ResourceName: "drained",
+			ObjectChanges: map[string]any{"spec": map[string]any{"nodeName": "node-1"}}},
+		{Timestamp: start.Add(time.Hour), Namespace: "default", ResourceName: "later",
+			ObjectChanges: map[string]any{"spec": map[string]any{"nodeName": "node-1"}}},
+		{Timestamp: start.Add(10 * time.Minute), Namespace: "default", ResourceName: "elsewhere",
+			ObjectChanges: map[string]any{"spec": map[string]any{"nodeName": "node-2"}}},
+	}
+	if pods := upgradePodDeletes(upgrade, podDeletes); len(pods) != 1 || pods[0] != "default/drained" {
+		t.Errorf("expected only the drained pod, got %v", pods)
+	}
+
+	warnings := upgradeNodeWarnings(upgrade, []audit.AuditEvent{
+		k8sEvent("Node", "node-1", "NodeNotReady", "Node is not ready", start.Add(9*time.Minute)),
+		k8sEvent("Node", "node-1", "NodeNotReady", "Node is not ready", start.Add(11*time.Minute)),
+		k8sEvent("Node", "node-2", "NodeNotReady", "Node is not ready", start.Add(11*time.Minute)),
+	})
+	if len(warnings) != 1 || warnings[0] != "NodeNotReady ×2" {
+		t.Errorf("unexpected node warnings %v", warnings)
+	}
+}
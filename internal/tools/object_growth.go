@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// defaultMinNetGrowth is the default net number of created objects
+	// above which a namespace's growth is reported
+	defaultMinNetGrowth = 10
+	// growthIntervals is the number of equal intervals a window is split
+	// into to tell steady growth from a single burst
+	growthIntervals = 6
+)
+
+// defaultGrowthTypes are the resource types checked when none are given;
+// they pile up when controllers or pipelines create them without cleanup
+var defaultGrowthTypes = []string{"configmaps", "secrets", "jobs"}
+
+// objectGrowth is the net growth of one resource type in one namespace
+type objectGrowth struct {
+	namespace        string
+	resourceType     string
+	creates          int
+	deletes          int
+	perHour          float64
+	growingIntervals int
+}
+
+// quotaUsage is one limit of a ResourceQuota
+type quotaUsage struct {
+	quota    string
+	resource string
+	used     int64
+	hard     int64
+}
+
+// CheckObjectGrowth finds namespaces whose object counts grow steadily, created without matching deletes, trending toward etcd and quota limits
+func (h *ToolHandlers) CheckObjectGrowth(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+	minNet := request.GetInt("min_net_growth", defaultMinNetGrowth)
+	if minNet < 1 {
+		return mcp.NewToolResultError("min_net_growth must be at least 1"), nil
+	}
+	resourceTypes := defaultGrowthTypes
+	if types := request.GetString("resource_types", ""); types != "" {
+		resourceTypes = nil
+		for _, resourceType := range strings.Split(types, ",") {
+			if resourceType = strings.TrimSpace(resourceType); resourceType != "" {
+				resourceTypes = append(resourceTypes, resourceType)
+			}
+		}
+	}
+
+	budget := h.newQueryBudget()
+	var creates, deletes []audit.AuditEvent
+	for _, resourceType := range resourceTypes {
+		for _, verb := range []string{"create", "delete"} {
+			events, err := budget.query(ctx, audit.QueryOptions{
+				StartTime:    startTime,
+				EndTime:      endTime,
+				Namespace:    namespace,
+				ResourceType: resourceType,
+				Verb:         verb,
+			})
+			if err != nil && !errors.Is(err, audit.ErrNoData) {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to query events: %v", err)), nil
+			}
+			if verb == "create" {
+				creates = append(creates, events...)
+			} else {
+				deletes = append(deletes, events...)
+			}
+		}
+	}
+
+	quotaEvents, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "resourcequotas",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query events: %v", err)), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Object Growth (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(fmt.Sprintf("Resource types: %s\n", strings.Join(resourceTypes, ", ")))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	growing := objectGrowthTrends(creates, deletes, startTime, endTime, minNet)
+	if len(growing) == 0 {
+		results.WriteString(fmt.Sprintf("✅ No namespace grew steadily by %d or more objects of one type.\n", minNet))
+	} else {
+		quotas := namespaceQuotas(quotaEvents)
+		results.WriteString(fmt.Sprintf("📈 Steadily Growing Object Counts: %d\n", len(growing)))
+		for i, growth := range growing {
+			results.WriteString(fmt.Sprintf("  %d. %s %s: +%d net (%d created, %d deleted), %.1f/hour, grew in %d of %d intervals\n",
+				i+1, namespaceLabel(growth.namespace), growth.resourceType, growth.creates-growth.deletes,
+				growth.creates, growth.deletes, growth.perHour, growth.growingIntervals, growthIntervals))
+			if quota := quotas.tightest(growth.namespace, growth.resourceType); quota != nil {
+				results.WriteString(fmt.Sprintf("     Quota %s: %d of %d used", quota.quota, quota.used, quota.hard))
+				switch remaining := quota.hard - quota.used; {
+				case remaining <= 0:
+					results.WriteString(", limit reached\n")
+				case growth.perHour > 0:
+					eta := time.Duration(float64(remaining) / growth.perHour * float64(time.Hour))
+					results.WriteString(fmt.Sprintf(", limit reached in ~%s at this rate\n", eta.Round(time.Minute)))
+				default:
+					results.WriteString("\n")
+				}
+			}
+		}
+		results.WriteString("\n")
+		results.WriteString("💡 Objects that are only ever created usually lack cleanup (e.g. a Job ttlSecondsAfterFinished, or ConfigMaps and Secrets generated per rollout). Every object adds to etcd size.\n\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", len(creates)+len(deletes)+len(quotaEvents)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// objectGrowthTrends computes the net growth per namespace and resource
+// type and returns those that grew by at least minNet and did so in most
+// intervals of the window, ordered by net growth
+func objectGrowthTrends(creates, deletes []audit.AuditEvent, start, end time.Time, minNet int) []objectGrowth {
+	type counts struct {
+		creates, deletes int
+		net              [growthIntervals]int
+	}
+	window := end.Sub(start)
+	interval := func(at time.Time) int {
+		if window <= 0 {
+			return 0
+		}
+		i := int(at.Sub(start) * growthIntervals / window)
+		return max(0, min(i, growthIntervals-1))
+	}
+
+	byKey := make(map[[2]string]*counts)
+	count := func(event audit.AuditEvent) *counts {
+		key := [2]string{event.Namespace, event.ResourceType}
+		if byKey[key] == nil {
+			byKey[key] = &counts{}
+		}
+		return byKey[key]
+	}
+	for _, event := range creates {
+		c := count(event)
+		c.creates++
+		c.net[interval(event.Timestamp)]++
+	}
+	for _, event := range deletes {
+		c := count(event)
+		c.deletes++
+		c.net[interval(event.Timestamp)]--
+	}
+
+	hours := window.Hours()
+	var growing []objectGrowth
+	for key, c := range byKey {
+		if c.creates-c.deletes < minNet {
+			continue
+		}
+		growth := objectGrowth{namespace: key[0], resourceType: key[1], creates: c.creates, deletes: c.deletes}
+		for _, net := range c.net {
+			if net > 0 {
+				growth.growingIntervals++
+			}
+		}
+		// A single burst is not a trend
+		if growth.growingIntervals <= growthIntervals/2 {
+			continue
+		}
+		if hours > 0 {
+			growth.perHour = float64(c.creates-c.deletes) / hours
+		}
+		growing = append(growing, growth)
+	}
+
+	sort.Slice(growing, func(i, j int) bool {
+		ni, nj := growing[i].creates-growing[i].deletes, growing[j].creates-growing[j].deletes
+		if ni != nj {
+			return ni > nj
+		}
+		if growing[i].namespace != growing[j].namespace {
+			return growing[i].namespace < growing[j].namespace
+		}
+		return growing[i].resourceType < growing[j].resourceType
+	})
+	return growing
+}
+
+// resourceQuotas holds the count limits of the latest state of each
+// ResourceQuota, by namespace and quota name
+type resourceQuotas map[string]map[string][]quotaUsage
+
+// namespaceQuotas reads the used and hard counts of the latest state of each
+// ResourceQuota
+func namespaceQuotas(events []audit.AuditEvent) resourceQuotas {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	quotas := make(resourceQuotas)
+	for _, event := range sorted {
+		if quotas[event.Namespace] == nil {
+			quotas[event.Namespace] = make(map[string][]quotaUsage)
+		}
+		if event.Verb == "delete" {
+			delete(quotas[event.Namespace], event.ResourceName)
+			continue
+		}
+
+		status, _ := event.ObjectChanges["status"].(map[string]any)
+		hard, _ := status["hard"].(map[string]any)
+		used, _ := status["used"].(map[string]any)
+		var usages []quotaUsage
+		for name, value := range hard {
+			hardCount, ok := quantityValue(value)
+			if !ok {
+				continue
+			}
+			usedCount, _ := quantityValue(used[name])
+			usages = append(usages, quotaUsage{quota: event.ResourceName, resource: name, used: usedCount, hard: hardCount})
+		}
+		quotas[event.Namespace][event.ResourceName] = usages
+	}
+	return quotas
+}
+
+// tightest returns the ResourceQuota count limit of resourceType in
+// namespace with the fewest objects left, or nil if none limits it
+func (q resourceQuotas) tightest(namespace, resourceType string) *quotaUsage {
+	var tightest *quotaUsage
+	for _, usages := range q[namespace] {
+		for _, usage := range usages {
+			// Counts are "count/<resource>[.<group>]", or just the resource
+			// for the core types that predate object count quotas
+			if usage.resource != resourceType && usage.resource != "count/"+resourceType &&
+				!strings.HasPrefix(usage.resource, "count/"+resourceType+".") {
+				continue
+			}
+			if tightest == nil || usage.hard-usage.used < tightest.hard-tightest.used {
+				tightest = &usage
+			}
+		}
+	}
+	return tightest
+}
+
+// quantityValue parses a quota quantity such as "100" or "1k"
+func quantityValue(value any) (int64, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	quantity, err := resource.ParseQuantity(s)
+	if err != nil {
+		return 0, false
+	}
+	return quantity.Value(), true
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestObjectGrowthTrends(t *testing.T) {
+	start := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := start.Add(6 * time.Hour)
+
+	var creates, deletes []audit.AuditEvent
+	event := func(namespace, resourceType string, at time.Time) audit.AuditEvent {
+		return audit.AuditEvent{Timestamp: at, Namespace: namespace, ResourceType: resourceType}
+	}
+	for i := 0; i < 24; i++ {
+		at := start.Add(time.Duration(i) * 15 * time.Minute)
+		// ci: three ConfigMaps every 15 minutes, one cleaned up
+		creates = append(creates, event("ci", "configmaps", at), event("ci", "configmaps", at), event("ci", "configmaps", at))
+		deletes = append(deletes, event("ci", "configmaps", at))
+		// web: Jobs created and cleaned up in step
+		creates = append(creates, event("web", "jobs", at))
+		deletes = append(deletes, event("web", "jobs", at.Add(time.Minute)))
+	}
+	// batch: one burst of Secrets in the first hour
+	for i := 0; i < 30; i++ {
+		creates = append(creates, event("batch", "secrets", start.Add(time.Duration(i)*time.Minute)))
+	}
+
+	growing := objectGrowthTrends(creates, deletes, start, end, 10)
+	if len(growing) != 1 {
+		t.Fatalf("expected only ci's ConfigMaps to grow steadily, got %+v", growing)
+	}
+	growth := growing[0]
+	if growth.namespace != "ci" || growth.resourceType != "configmaps" || growth.creates != 72 || growth.deletes != 24 {
+		t.Errorf("unexpected growth %+v", growth)
+	}
+	if growth.perHour != 8 || growth.growingIntervals != growthIntervals {
+		t.Errorf("expected 8/hour in every interval, got %.1f in %d", growth.perHour, growth.growingIntervals)
+	}
+
+	if growing := objectGrowthTrends(creates, deletes, start, end, 100); len(growing) != 0 {
+		t.Errorf("expected no growth of 100, got %+v", growing)
+	}
+}
+
+func TestNamespaceQuotas(t *testing.T) {
+	start := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+	quotaEvent := func(name string, at time.Time, hard, used map[string]any) audit.AuditEvent {
+		return audit.AuditEvent{
+			Timestamp: at, Verb: "update", Namespace: "ci", ResourceType: "resourcequotas", ResourceName: name,
+			ObjectChanges: map[string]any{"status": map[string]any{"hard": hard, "used": used}},
+		}
+	}
+
+	quotas := namespaceQuotas([]audit.AuditEvent{
+		quotaEvent("objects", start.Add(time.Hour), map[string]any{"count/configmaps": "100"}, map[string]any{"count/configmaps": "80"}),
+		quotaEvent("objects", start, map[string]any{"count/configmaps": "100"}, map[string]any{"count/configmaps": "10"}),
+		quotaEvent("legacy", start, map[string]any{"configmaps": "500", "count/jobs.batch": "1k"}, map[string]any{"configmaps": "80"}),
+	})
+
+	quota := quotas.tightest("ci", "configmaps")
+	if quota == nil || quota.quota != "objects" || quota.used != 80 || quota.hard != 100 {
+		t.Errorf("expected the latest state of the tighter quota, got %+v", quota)
+	}
+	if quota := quotas.tightest("ci", "jobs"); quota == nil || quota.hard != 1000 || quota.used != 0 {
+		t.Errorf("expected the group-qualified Job count, got %+v", quota)
+	}
+	if quota := quotas.tightest("ci", "secrets"); quota != nil {
+		t.Errorf("expected no Secret quota, got %+v", quota)
+	}
+}
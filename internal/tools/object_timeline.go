@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// objectTimelineEntry is a watch event or related Kubernetes Event of the
+// object, placed on its timeline
+type objectTimelineEntry struct {
+	event   audit.AuditEvent
+	related bool
+}
+
+// GetObjectTimeline renders the watch events of one object interleaved with
+// the Kubernetes Events that refer to it, oldest first
+func (h *ToolHandlers) GetObjectTimeline(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return mcp.NewToolResultError("namespace is required"), nil
+	}
+
+	resourceType, err := request.RequireString("resource_type")
+	if err != nil {
+		return mcp.NewToolResultError("resource_type is required"), nil
+	}
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError("name is required"), nil
+	}
+
+	history, err := h.auditClient.GetObjectHistory(ctx, namespace, resourceType, name)
+	if errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultText(fmt.Sprintf("No events found for %s %s/%s.", resourceType, namespace, name)), nil
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query object history: %v", err)), nil
+	}
+
+	entries := objectTimeline(history)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Object Timeline: %s %s/%s\n", resourceType, namespace, name))
+	results.WriteString(fmt.Sprintf("Watch events: %d | Related Events: %d\n",
+		len(history.WatchEvents), len(history.RelatedEvents)))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+	for _, entry := range entries {
+		results.WriteString(formatObjectTimelineEntry(entry) + "\n")
+	}
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// objectTimeline merges the watch events and related Events of history by
+// timestamp. On ties a watch event comes first, as the Events it causes are
+// recorded after it.
+func objectTimeline(history *audit.ObjectHistory) []objectTimelineEntry {
+	entries := make([]objectTimelineEntry, 0, len(history.WatchEvents)+len(history.RelatedEvents))
+	for _, event := range history.WatchEvents {
+		entries = append(entries, objectTimelineEntry{event: event})
+	}
+	for _, event := range history.RelatedEvents {
+		entries = append(entries, objectTimelineEntry{event: event, related: true})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].event.Timestamp.Before(entries[j].event.Timestamp)
+	})
+	return entries
+}
+
+// formatObjectTimelineEntry renders an entry as a single line: the verb and
+// changed fields of a watch event, or the type, reason and note of an Event
+func formatObjectTimelineEntry(entry objectTimelineEntry) string {
+	event := entry.event
+	at := event.Timestamp.Format(time.RFC3339)
+
+	if !entry.related {
+		line := fmt.Sprintf("%s  📝 %s", at, event.Verb)
+		if event.User != "" {
+			line += " by " + event.User
+		}
+		if len(event.ChangedFields) > 0 {
+			paths := make([]string, 0, len(event.ChangedFields))
+			for _, change := range event.ChangedFields {
+				paths = append(paths, change.Path)
+			}
+			line += " (" + strings.Join(paths, ", ") + ")"
+		}
+		return line
+	}
+
+	info := event.EventInfo
+	if info == nil {
+		return fmt.Sprintf("%s  ℹ️  %s", at, event.Message)
+	}
+	icon := "ℹ️ "
+	if info.Type == "Warning" {
+		icon = "⚠️ "
+	}
+	line := fmt.Sprintf("%s  %s %s %s: %s", at, icon, info.Type, info.Reason, info.Note)
+	if info.Count > 1 {
+		line += fmt.Sprintf(" (x%d)", info.Count)
+	}
+	return line
+}
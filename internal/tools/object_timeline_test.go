@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// sampleObjectHistory is an /api/v1/events/{namespace}/{resourceType}/{name}
+// response as sent by the watch server
+const sampleObjectHistory = `{
+  "namespace": "default",
+  "resourceType": "pods",
+  "resourceName": "web",
+  "watchEvents": [
+    {"timestamp": "2025-03-04T10:00:00Z", "verb": "create", "resourceType": "pods", "resourceName": "web"},
+    {"timestamp": "2025-03-04T10:05:00Z", "verb": "update", "user": "kubelet", "resourceType": "pods", "resourceName": "web",
+     "changedFields": [{"path": "status.phase", "old": "Pending", "new": "Running"}]}
+  ],
+  "relatedEvents": [
+    {"timestamp": "2025-03-04T10:02:00Z", "verb": "create", "resourceType": "events", "resourceName": "web.1",
+     "message": "BackOff: Back-off pulling image",
+     "eventInfo": {"reason": "BackOff", "type": "Warning", "note": "Back-off pulling image", "count": 4}},
+    {"timestamp": "2025-03-04T10:05:00Z", "verb": "create", "resourceType": "events", "resourceName": "web.2",
+     "message": "Started: Started container web",
+     "eventInfo": {"reason": "Started", "type": "Normal", "note": "Started container web"}}
+  ]
+}`
+
+func TestDecodeObjectHistory(t *testing.T) {
+	var history audit.ObjectHistory
+	if err := json.Unmarshal([]byte(sampleObjectHistory), &history); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if history.ResourceName != "web" || len(history.WatchEvents) != 2 || len(history.RelatedEvents) != 2 {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+	info := history.RelatedEvents[0].EventInfo
+	if info == nil || info.Reason != "BackOff" || info.Type != "Warning" || info.Count != 4 {
+		t.Errorf("expected the Event fields to be decoded, got %+v", info)
+	}
+}
+
+func TestGetObjectTimeline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/events/default/pods/web" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(sampleObjectHistory))
+	}))
+	defer server.Close()
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{"namespace": "default", "resource_type": "pods", "name": "web"}
+
+	result, err := h.GetObjectTimeline(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	want := "2025-03-04T10:00:00Z  📝 create\n" +
+		"2025-03-04T10:02:00Z  ⚠️  Warning BackOff: Back-off pulling image (x4)\n" +
+		"2025-03-04T10:05:00Z  📝 update by kubelet (status.phase)\n" +
+		"2025-03-04T10:05:00Z  ℹ️  Normal Started: Started container web\n"
+	if !strings.Contains(text, want) {
+		t.Errorf("expected the events interleaved by time:\n%s\ngot:\n%s", want, text)
+	}
+	if !strings.Contains(text, "Watch events: 2 | Related Events: 2\n") {
+		t.Errorf("expected the event counts in output:\n%s", text)
+	}
+}
+
+func TestGetObjectTimelineNoEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"no events found for this object","code":"no_data"}`))
+	}))
+	defer server.Close()
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{"namespace": "default", "resource_type": "pods", "name": "gone"}
+
+	result, err := h.GetObjectTimeline(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "No events found for pods default/gone") {
+		t.Errorf("expected a no-events message, got %+v", result.Content)
+	}
+}
@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// activityPeak is one minute of a window with its event breakdown
+type activityPeak struct {
+	minute        time.Time
+	count         int
+	resourceTypes map[string]int
+	verbs         map[string]int
+	namespaces    map[string]int
+}
+
+// FindPeakActivity reports the minutes with the highest event volume in a window and what happened in them
+func (h *ToolHandlers) FindPeakActivity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+	top := request.GetInt("top", 3)
+	if top < 1 {
+		return mcp.NewToolResultError("top must be at least 1"), nil
+	}
+
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Namespace: namespace,
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query events: %v", err)), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Peak Activity (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	peaks, active := activityPeaks(events, top)
+	if len(peaks) == 0 {
+		results.WriteString("No events found in the specified time range.\n")
+	} else {
+		minutes := max(1, int(endTime.Sub(startTime)/time.Minute))
+		results.WriteString(fmt.Sprintf("📊 Baseline: %.1f events/minute on average, %d of %d minutes with activity\n\n",
+			float64(len(events))/float64(minutes), active, minutes))
+
+		results.WriteString(fmt.Sprintf("🔥 Busiest Minutes: %d\n", len(peaks)))
+		for i, peak := range peaks {
+			results.WriteString(fmt.Sprintf("  %d. %s - %s: %d events\n", i+1,
+				peak.minute.Format(time.RFC3339), peak.minute.Add(time.Minute).Format("15:04:05"), peak.count))
+			results.WriteString(fmt.Sprintf("     Resource types: %s\n", topCounts(peak.resourceTypes, 3, nil)))
+			results.WriteString(fmt.Sprintf("     Verbs: %s\n", topCounts(peak.verbs, 3, nil)))
+			if namespace == "" {
+				results.WriteString(fmt.Sprintf("     Namespaces: %s\n", topCounts(peak.namespaces, 3, namespaceLabel)))
+			}
+		}
+		results.WriteString("\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// activityPeaks buckets events per minute and returns the top busiest
+// minutes, ordered by event count and then time, along with the number of
+// minutes that had any events
+func activityPeaks(events []audit.AuditEvent, top int) ([]activityPeak, int) {
+	buckets := make(map[time.Time]*activityPeak)
+	for _, event := range events {
+		minute := event.Timestamp.UTC().Truncate(time.Minute)
+		peak := buckets[minute]
+		if peak == nil {
+			peak = &activityPeak{
+				minute:        minute,
+				resourceTypes: make(map[string]int),
+				verbs:         make(map[string]int),
+				namespaces:    make(map[string]int),
+			}
+			buckets[minute] = peak
+		}
+		peak.count++
+		peak.resourceTypes[event.ResourceType]++
+		peak.verbs[event.Verb]++
+		peak.namespaces[event.Namespace]++
+	}
+
+	peaks := make([]activityPeak, 0, len(buckets))
+	for _, peak := range buckets {
+		peaks = append(peaks, *peak)
+	}
+	sort.Slice(peaks, func(i, j int) bool {
+		if peaks[i].count != peaks[j].count {
+			return peaks[i].count > peaks[j].count
+		}
+		return peaks[i].minute.Before(peaks[j].minute)
+	})
+	return peaks[:min(top, len(peaks))], len(buckets)
+}
+
+// topCounts formats the n largest counts as "key=count", labelling keys with
+// label when set
+func topCounts(counts map[string]int, n int, label func(string) string) string {
+	keys := sortedByCount(counts)
+	var cells []string
+	for _, key := range keys[:min(n, len(keys))] {
+		name := key
+		if label != nil {
+			name = label(key)
+		}
+		cells = append(cells, fmt.Sprintf("%s=%d", name, counts[key]))
+	}
+	if len(keys) > n {
+		cells = append(cells, fmt.Sprintf("+%d more", len(keys)-n))
+	}
+	return strings.Join(cells, ", ")
+}
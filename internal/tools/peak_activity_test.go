@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestActivityPeaks(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	var events []audit.AuditEvent
+	add := func(at time.Time, n int, verb, resourceType string) {
+		for i := 0; i < n; i++ {
+			events = append(events, audit.AuditEvent{Timestamp: at, Verb: verb, Namespace: "web", ResourceType: resourceType})
+		}
+	}
+	add(start.Add(10*time.Second), 2, "update", "deployments")
+	// The incident minute: pods churning
+	add(start.Add(5*time.Minute+3*time.Second), 5, "delete", "pods")
+	add(start.Add(5*time.Minute+50*time.Second), 4, "create", "pods")
+	add(start.Add(5*time.Minute+59*time.Second), 1, "update", "replicasets")
+	// Ties with the first minute but comes later
+	add(start.Add(9*time.Minute), 2, "update", "configmaps")
+
+	peaks, active := activityPeaks(events, 2)
+	if active != 3 {
+		t.Errorf("expected 3 active minutes, got %d", active)
+	}
+	if len(peaks) != 2 {
+		t.Fatalf("expected 2 peaks, got %+v", peaks)
+	}
+
+	peak := peaks[0]
+	if !peak.minute.Equal(start.Add(5*time.Minute)) || peak.count != 10 {
+		t.Errorf("expected 10 events at 10:05, got %d at %s", peak.count, peak.minute)
+	}
+	if peak.resourceTypes["pods"] != 9 || peak.verbs["delete"] != 5 {
+		t.Errorf("unexpected breakdown %v %v", peak.resourceTypes, peak.verbs)
+	}
+	if !peaks[1].minute.Equal(start) {
+		t.Errorf("expected the earlier minute to win the tie, got %s", peaks[1].minute)
+	}
+
+	if got := topCounts(peak.verbs, 2, nil); got != "delete=5, create=4, +1 more" {
+		t.Errorf("unexpected top verbs %q", got)
+	}
+}
@@ -18,14 +18,48 @@ func (h *ToolHandlers) CheckPodIssues(ctx context.Context, request mcp.CallToolR
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	order, err := parseSortOrder(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	format, err := parseOutputFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	namespace := request.GetString("namespace", "")
 
 	// Query pod-related events
-	events, err := h.auditClient.GetResourceTypeEvents(ctx, namespace, "pods", startTime, endTime)
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "pods",
+	})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
 	}
 
+	issues := categorizePodIssues(events)
+
+	if format == formatJSON {
+		return newJSONResult(podIssuesReport{
+			StartTime:        startTime,
+			EndTime:          endTime,
+			Namespace:        namespace,
+			TotalEvents:      len(events),
+			CrashLoopBackOff: newCategoryReport(issues.crashLoop, order, podSubject),
+			ImagePull:        newCategoryReport(issues.imagePull, order, podSubject),
+			OOMKilled:        newCategoryReport(issues.oom, order, podSubject),
+			ProbeFailures:    newCategoryReport(issues.probeFailures, order, podSubject),
+			ConfigIssues:     newCategoryReport(issues.config, order, podSubject),
+			ReplicaIssues:    newCategoryReport(issues.replica, order, nil),
+			Notices:          budget.reportNotices(),
+		})
+	}
+
 	if len(events) == 0 {
 		msg := "No pod events found in the specified time range"
 		if namespace != "" {
@@ -41,107 +75,48 @@ func (h *ToolHandlers) CheckPodIssues(ctx context.Context, request mcp.CallToolR
 	}
 	results.WriteString(strings.Repeat("=", 60) + "\n\n")
 
-	// Categorize pod issues
-	crashLoopEvents := []audit.AuditEvent{}
-	imagePullEvents := []audit.AuditEvent{}
-	oomEvents := []audit.AuditEvent{}
-	probeFailures := []audit.AuditEvent{}
-	configIssues := []audit.AuditEvent{}
-	replicaIssues := []audit.AuditEvent{}
-
-	for _, event := range events {
-		eventData, err := json.Marshal(event)
-		if err != nil {
-			continue
-		}
-
-		// 1: we have resource changes
-		// 2: we have resource events
-
-		combined := strings.ToLower(string(eventData))
-		if strings.Contains(combined, "crashloopbackoff") {
-			crashLoopEvents = append(crashLoopEvents, event)
-		}
-		if strings.Contains(combined, "imagepullbackoff") || strings.Contains(combined, "errimagepull") {
-			imagePullEvents = append(imagePullEvents, event)
-		}
-		if strings.Contains(combined, "oomkilled") || strings.Contains(combined, "out of memory") {
-			oomEvents = append(oomEvents, event)
-		}
-		if strings.Contains(combined, "liveness") || strings.Contains(combined, "readiness") ||
-			strings.Contains(combined, "probe failed") {
-			probeFailures = append(probeFailures, event)
-		}
-		if strings.Contains(combined, "configmap") || strings.Contains(combined, "secret") &&
-			strings.Contains(combined, "not found") {
-			configIssues = append(configIssues, event)
-		}
-		if strings.Contains(combined, "replica") &&
-			(strings.Contains(combined, "insufficient") || strings.Contains(combined, "failed")) {
-			replicaIssues = append(replicaIssues, event)
-		}
-	}
-
 	// Report findings
 	issueFound := false
 
-	if len(crashLoopEvents) > 0 {
+	if len(issues.crashLoop) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("🔴 CrashLoopBackOff: %d events\n", len(crashLoopEvents)))
-		for _, event := range crashLoopEvents[:min(5, len(crashLoopEvents))] {
-			results.WriteString(fmt.Sprintf("  - %s: Pod %s/%s - %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Namespace, event.ResourceName, event.Message))
-		}
+		results.WriteString(fmt.Sprintf("🔴 CrashLoopBackOff: %d events\n", len(issues.crashLoop)))
+		writeFindings(&results, issues.crashLoop, 5, order, podSubject)
 		results.WriteString("\n")
 	}
 
-	if len(imagePullEvents) > 0 {
+	if len(issues.imagePull) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("🔴 Image Pull Issues: %d events\n", len(imagePullEvents)))
-		for _, event := range imagePullEvents[:min(5, len(imagePullEvents))] {
-			results.WriteString(fmt.Sprintf("  - %s: Pod %s/%s - %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Namespace, event.ResourceName, event.Message))
-		}
+		results.WriteString(fmt.Sprintf("🔴 Image Pull Issues: %d events\n", len(issues.imagePull)))
+		writeFindings(&results, issues.imagePull, 5, order, podSubject)
 		results.WriteString("\n")
 	}
 
-	if len(oomEvents) > 0 {
+	if len(issues.oom) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("🔴 OOMKilled: %d events\n", len(oomEvents)))
-		for _, event := range oomEvents[:min(5, len(oomEvents))] {
-			results.WriteString(fmt.Sprintf("  - %s: Pod %s/%s - %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Namespace, event.ResourceName, event.Message))
-		}
+		results.WriteString(fmt.Sprintf("🔴 OOMKilled: %d events\n", len(issues.oom)))
+		writeFindings(&results, issues.oom, 5, order, podSubject)
 		results.WriteString("\n")
 	}
 
-	if len(probeFailures) > 0 {
+	if len(issues.probeFailures) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("⚠️  Probe Failures: %d events\n", len(probeFailures)))
-		for _, event := range probeFailures[:min(5, len(probeFailures))] {
-			results.WriteString(fmt.Sprintf("  - %s: Pod %s/%s - %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Namespace, event.ResourceName, event.Message))
-		}
+		results.WriteString(fmt.Sprintf("⚠️  Probe Failures: %d events\n", len(issues.probeFailures)))
+		writeFindings(&results, issues.probeFailures, 5, order, podSubject)
 		results.WriteString("\n")
 	}
 
-	if len(configIssues) > 0 {
+	if len(issues.config) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("⚠️  Config/Secret Issues: %d events\n", len(configIssues)))
-		for _, event := range configIssues[:min(5, len(configIssues))] {
-			results.WriteString(fmt.Sprintf("  - %s: Pod %s/%s - %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Namespace, event.ResourceName, event.Message))
-		}
+		results.WriteString(fmt.Sprintf("⚠️  Config/Secret Issues: %d events\n", len(issues.config)))
+		writeFindings(&results, issues.config, 5, order, podSubject)
 		results.WriteString("\n")
 	}
 
-	if len(replicaIssues) > 0 {
+	if len(issues.replica) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("⚠️  Replica Scheduling Issues: %d events\n", len(replicaIssues)))
-		for _, event := range replicaIssues[:min(3, len(replicaIssues))] {
-			results.WriteString(fmt.Sprintf("  - %s: %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Message))
-		}
+		results.WriteString(fmt.Sprintf("⚠️  Replica Scheduling Issues: %d events\n", len(issues.replica)))
+		writeFindings(&results, issues.replica, 3, order, nil)
 		results.WriteString("\n")
 	}
 
@@ -149,11 +124,77 @@ func (h *ToolHandlers) CheckPodIssues(ctx context.Context, request mcp.CallToolR
 		results.WriteString("✅ No critical pod issues detected.\n")
 	}
 
+	budget.writeNotices(&results)
+
 	results.WriteString(fmt.Sprintf("\nTotal pod events analyzed: %d\n", len(events)))
 
 	return mcp.NewToolResultText(results.String()), nil
 }
 
+// podIssues are pod events sorted into the categories of check_pod_issues.
+// An event may fall into several categories.
+type podIssues struct {
+	crashLoop     []audit.AuditEvent
+	imagePull     []audit.AuditEvent
+	oom           []audit.AuditEvent
+	probeFailures []audit.AuditEvent
+	config        []audit.AuditEvent
+	replica       []audit.AuditEvent
+}
+
+// categorizePodIssues matches the whole of each event, including its object,
+// against the pod issue categories
+func categorizePodIssues(events []audit.AuditEvent) podIssues {
+	var issues podIssues
+	for _, event := range events {
+		eventData, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+
+		// 1: we have resource changes
+		// 2: we have resource events
+
+		combined := strings.ToLower(string(eventData))
+		if isCrashLoop(combined) {
+			issues.crashLoop = append(issues.crashLoop, event)
+		}
+		if isImagePullBackOff(combined) {
+			issues.imagePull = append(issues.imagePull, event)
+		}
+		if isOOMKilled(combined) {
+			issues.oom = append(issues.oom, event)
+		}
+		if isProbeFailure(combined) {
+			issues.probeFailures = append(issues.probeFailures, event)
+		}
+		if isConfigMissing(combined) {
+			issues.config = append(issues.config, event)
+		}
+		if isReplicaFailure(combined) {
+			issues.replica = append(issues.replica, event)
+		}
+	}
+	return issues
+}
+
+// podIssuesReport is the format=json result of check_pod_issues. Every
+// category is present, with zero events when nothing matched.
+type podIssuesReport struct {
+	StartTime        time.Time      `json:"startTime"`
+	EndTime          time.Time      `json:"endTime"`
+	Namespace        string         `json:"namespace,omitempty"`
+	TotalEvents      int            `json:"totalEvents"`
+	CrashLoopBackOff categoryReport `json:"crashLoopBackOff"`
+	ImagePull        categoryReport `json:"imagePull"`
+	OOMKilled        categoryReport `json:"oomKilled"`
+	ProbeFailures    categoryReport `json:"probeFailures"`
+	ConfigIssues     categoryReport `json:"configIssues"`
+	ReplicaIssues    categoryReport `json:"replicaIssues"`
+	// Notices are set when the event budget truncated the analysis
+	Notices []string `json:"notices"`
+}
+
 // CheckVolumeIssues analyzes volume and storage-related problems
 func (h *ToolHandlers) CheckVolumeIssues(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	startTime, endTime, err := parseTimeRange(request)
@@ -161,6 +202,11 @@ func (h *ToolHandlers) CheckVolumeIssues(ctx context.Context, request mcp.CallTo
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	order, err := parseSortOrder(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	namespace := request.GetString("namespace", "")
 
 	var results strings.Builder
@@ -171,13 +217,23 @@ func (h *ToolHandlers) CheckVolumeIssues(ctx context.Context, request mcp.CallTo
 	results.WriteString(strings.Repeat("=", 60) + "\n\n")
 
 	// Query PVC events
-	pvcEvents, err := h.auditClient.GetResourceTypeEvents(ctx, namespace, "persistentvolumeclaims", startTime, endTime)
+	budget := h.newQueryBudget()
+	pvcEvents, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "persistentvolumeclaims",
+	})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to query PVC events: %v", err)), nil
 	}
 
 	// Query PV events
-	pvEvents, err := h.auditClient.GetResourceTypeEvents(ctx, "", "persistentvolumes", startTime, endTime)
+	pvEvents, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		ResourceType: "persistentvolumes",
+	})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to query PV events: %v", err)), nil
 	}
@@ -188,86 +244,43 @@ func (h *ToolHandlers) CheckVolumeIssues(ctx context.Context, request mcp.CallTo
 		return mcp.NewToolResultText("No volume events found in the specified time range."), nil
 	}
 
-	// Categorize volume issues
-	pendingPVC := []audit.AuditEvent{}
-	bindingIssues := []audit.AuditEvent{}
-	storageClassIssues := []audit.AuditEvent{}
-	mountFailures := []audit.AuditEvent{}
-	diskFullEvents := []audit.AuditEvent{}
-
-	for _, event := range allEvents {
-		msg := strings.ToLower(event.Message)
-		annotations := strings.ToLower(fmt.Sprintf("%v", event.Annotations))
-		combined := msg + " " + annotations
-
-		if strings.Contains(combined, "pending") && event.ResourceType == "persistentvolumeclaims" {
-			pendingPVC = append(pendingPVC, event)
-		}
-		if strings.Contains(combined, "binding") || strings.Contains(combined, "not bound") {
-			bindingIssues = append(bindingIssues, event)
-		}
-		if strings.Contains(combined, "storageclass") &&
-			(strings.Contains(combined, "error") || strings.Contains(combined, "failed")) {
-			storageClassIssues = append(storageClassIssues, event)
-		}
-		if strings.Contains(combined, "mount") && strings.Contains(combined, "fail") {
-			mountFailures = append(mountFailures, event)
-		}
-		if strings.Contains(combined, "disk full") || strings.Contains(combined, "no space left") {
-			diskFullEvents = append(diskFullEvents, event)
-		}
-	}
+	issues := categorizeVolumeIssues(allEvents)
 
 	// Report findings
 	issueFound := false
 
-	if len(pendingPVC) > 0 {
+	if len(issues.pendingPVC) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("⚠️  Pending PVCs: %d events\n", len(pendingPVC)))
-		for _, event := range pendingPVC[:min(5, len(pendingPVC))] {
-			results.WriteString(fmt.Sprintf("  - %s: PVC %s/%s - %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Namespace, event.ResourceName, event.Message))
-		}
+		results.WriteString(fmt.Sprintf("⚠️  Pending PVCs: %d events\n", len(issues.pendingPVC)))
+		writeFindings(&results, issues.pendingPVC, 5, order, pvcSubject)
 		results.WriteString("\n")
 	}
 
-	if len(bindingIssues) > 0 {
+	if len(issues.binding) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("🔴 PV Binding Issues: %d events\n", len(bindingIssues)))
-		for _, event := range bindingIssues[:min(5, len(bindingIssues))] {
-			results.WriteString(fmt.Sprintf("  - %s: %s %s - %s\n",
-				event.Timestamp.Format(time.RFC3339), event.ResourceType, event.ResourceName, event.Message))
-		}
+		results.WriteString(fmt.Sprintf("🔴 PV Binding Issues: %d events\n", len(issues.binding)))
+		writeFindings(&results, issues.binding, 5, order, volumeSubject)
 		results.WriteString("\n")
 	}
 
-	if len(storageClassIssues) > 0 {
+	if len(issues.storageClass) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("🔴 StorageClass Errors: %d events\n", len(storageClassIssues)))
-		for _, event := range storageClassIssues[:min(5, len(storageClassIssues))] {
-			results.WriteString(fmt.Sprintf("  - %s: %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Message))
-		}
+		results.WriteString(fmt.Sprintf("🔴 StorageClass Errors: %d events\n", len(issues.storageClass)))
+		writeFindings(&results, issues.storageClass, 5, order, nil)
 		results.WriteString("\n")
 	}
 
-	if len(mountFailures) > 0 {
+	if len(issues.mountFailures) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("🔴 Volume Mount Failures: %d events\n", len(mountFailures)))
-		for _, event := range mountFailures[:min(5, len(mountFailures))] {
-			results.WriteString(fmt.Sprintf("  - %s: %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Message))
-		}
+		results.WriteString(fmt.Sprintf("🔴 Volume Mount Failures: %d events\n", len(issues.mountFailures)))
+		writeFindings(&results, issues.mountFailures, 5, order, nil)
 		results.WriteString("\n")
 	}
 
-	if len(diskFullEvents) > 0 {
+	if len(issues.diskFull) > 0 {
 		issueFound = true
-		results.WriteString(fmt.Sprintf("🔴 Disk Full Events: %d events\n", len(diskFullEvents)))
-		for _, event := range diskFullEvents[:min(3, len(diskFullEvents))] {
-			results.WriteString(fmt.Sprintf("  - %s: %s\n",
-				event.Timestamp.Format(time.RFC3339), event.Message))
-		}
+		results.WriteString(fmt.Sprintf("🔴 Disk Full Events: %d events\n", len(issues.diskFull)))
+		writeFindings(&results, issues.diskFull, 3, order, nil)
 		results.WriteString("\n")
 	}
 
@@ -275,7 +288,57 @@ func (h *ToolHandlers) CheckVolumeIssues(ctx context.Context, request mcp.CallTo
 		results.WriteString("✅ No volume issues detected.\n")
 	}
 
+	budget.writeNotices(&results)
+
 	results.WriteString(fmt.Sprintf("\nTotal volume events analyzed: %d\n", len(allEvents)))
 
 	return mcp.NewToolResultText(results.String()), nil
 }
+
+// volumeIssues are PVC and PV events sorted into the categories of
+// check_volume_issues. An event may fall into several categories.
+type volumeIssues struct {
+	pendingPVC    []audit.AuditEvent
+	binding       []audit.AuditEvent
+	storageClass  []audit.AuditEvent
+	mountFailures []audit.AuditEvent
+	diskFull      []audit.AuditEvent
+}
+
+// categorizeVolumeIssues matches the message and annotations of each event
+// against the volume issue categories
+func categorizeVolumeIssues(events []audit.AuditEvent) volumeIssues {
+	var issues volumeIssues
+	for _, event := range events {
+		msg := strings.ToLower(event.Message)
+		annotations := strings.ToLower(fmt.Sprintf("%v", event.Annotations))
+		combined := msg + " " + annotations
+
+		if isPending(combined) && event.ResourceType == "persistentvolumeclaims" {
+			issues.pendingPVC = append(issues.pendingPVC, event)
+		}
+		if isBindingIssue(combined) {
+			issues.binding = append(issues.binding, event)
+		}
+		if isStorageClassError(combined) {
+			issues.storageClass = append(issues.storageClass, event)
+		}
+		if isMountFailure(combined) {
+			issues.mountFailures = append(issues.mountFailures, event)
+		}
+		if isDiskFull(combined) {
+			issues.diskFull = append(issues.diskFull, event)
+		}
+	}
+	return issues
+}
+
+// pvcSubject describes a PVC event as "PVC <namespace>/<name>"
+func pvcSubject(event audit.AuditEvent) string {
+	return fmt.Sprintf("PVC %s/%s", event.Namespace, event.ResourceName)
+}
+
+// volumeSubject describes a PVC or PV event as "<resource type> <name>"
+func volumeSubject(event audit.AuditEvent) string {
+	return fmt.Sprintf("%s %s", event.ResourceType, event.ResourceName)
+}
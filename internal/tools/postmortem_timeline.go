@@ -0,0 +1,284 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+const (
+	// timelineRunLength is the number of consecutive similar entries from
+	// which a stretch is summarized in a single line
+	timelineRunLength = 3
+	// timelinePhaseLines caps the lines rendered per phase
+	timelinePhaseLines = 15
+)
+
+// timelineChangeTypes are the resource types whose changes are significant
+// for a postmortem. Pods and nodes are left out, their status churns constantly.
+var timelineChangeTypes = []string{
+	"deployments", "statefulsets", "daemonsets", "configmaps", "secrets",
+	"services", "ingresses", "networkpolicies", "horizontalpodautoscalers",
+}
+
+// timelineEntry is one significant event of the timeline
+type timelineEntry struct {
+	at     time.Time
+	kind   string // change, failure or recovery
+	group  string // entries of the same group can be summarized together
+	label  string // describes a summarized stretch of the group
+	object string
+	text   string
+}
+
+// timelinePhase is a titled section of the timeline
+type timelinePhase struct {
+	title   string
+	entries []timelineEntry
+}
+
+// GeneratePostmortemTimeline emits a neutral, chronological markdown timeline of changes, failures and recoveries grouped by incident phase
+func (h *ToolHandlers) GeneratePostmortemTimeline(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	budget := h.newQueryBudget()
+	var entries []timelineEntry
+	analyzed := 0
+	for _, resourceType := range timelineChangeTypes {
+		events, err := budget.query(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Namespace:    namespace,
+			ResourceType: resourceType,
+		})
+		if err != nil && !errors.Is(err, audit.ErrNoData) {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query %s events: %v", resourceType, err)), nil
+		}
+		analyzed += len(events)
+		entries = append(entries, timelineChanges(events)...)
+	}
+
+	k8sEvents, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "events",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query Kubernetes events: %v", err)), nil
+	}
+	analyzed += len(k8sEvents)
+	entries = append(entries, timelineIncidents(k8sEvents)...)
+
+	if len(entries) == 0 {
+		return mcp.NewToolResultText("No significant changes or warning events found in the specified time range."), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Postmortem Timeline (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n")
+
+	phases := timelinePhases(entries)
+	if phases[0].title == "Changes" {
+		results.WriteString("\nNo warning events in the window; the timeline lists changes only.\n")
+	}
+	for _, phase := range phases {
+		results.WriteString(fmt.Sprintf("\n### %s\n\n", phase.title))
+		writeTimelinePhase(&results, phase.entries)
+	}
+	results.WriteString("\n")
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", analyzed))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// timelineChanges returns the creates, deletes and spec or data updates of a
+// resource type. Updates that only touch status are not significant; an
+// object's first update in the window is its baseline.
+func timelineChanges(events []audit.AuditEvent) []timelineEntry {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var entries []timelineEntry
+	previous := make(map[string][2]any)
+	for _, event := range sorted {
+		key := event.Namespace + "/" + event.ResourceName
+		state := [2]any{event.ObjectChanges["spec"], event.ObjectChanges["data"]}
+		before, seen := previous[key]
+		previous[key] = state
+
+		var action string
+		switch event.Verb {
+		case "create":
+			action = "created"
+		case "delete":
+			action = "deleted"
+		case "update":
+			if !seen || reflect.DeepEqual(before, state) {
+				continue
+			}
+			action = "updated"
+		default:
+			continue
+		}
+
+		object := onsetObject(event)
+		text := fmt.Sprintf("%s %s", object, action)
+		if updater := configUpdater(event); !strings.HasPrefix(updater, "unknown") {
+			text += fmt.Sprintf(" (by %s)", updater)
+		}
+		entries = append(entries, timelineEntry{
+			at:     event.Timestamp,
+			kind:   "change",
+			group:  "change/" + event.ResourceType + "/" + action,
+			label:  fmt.Sprintf("%s %s", event.ResourceType, action),
+			object: object,
+			text:   text,
+		})
+	}
+	return entries
+}
+
+// timelineIncidents returns the Warning events as failures and, for every
+// object that had a warning, the first Normal event after its latest warning
+// as a recovery
+func timelineIncidents(events []audit.AuditEvent) []timelineEntry {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var entries []timelineEntry
+	failing := make(map[string]bool)
+	for _, event := range sorted {
+		eventType, _ := event.ObjectChanges["type"].(string)
+		reason, _ := event.ObjectChanges["reason"].(string)
+		message, _ := event.ObjectChanges["message"].(string)
+		object := involvedObject(event)
+
+		switch {
+		case eventType == "Warning":
+			failing[object] = true
+			entries = append(entries, timelineEntry{
+				at:     event.Timestamp,
+				kind:   "failure",
+				group:  "failure/" + reason,
+				label:  fmt.Sprintf("%s warnings", reason),
+				object: object,
+				text:   fmt.Sprintf("%s: %s — %s", object, reason, message),
+			})
+		case eventType == "Normal" && failing[object]:
+			delete(failing, object)
+			entries = append(entries, timelineEntry{
+				at:     event.Timestamp,
+				kind:   "recovery",
+				group:  "recovery/" + reason,
+				label:  fmt.Sprintf("%s after warnings", reason),
+				object: object,
+				text:   fmt.Sprintf("%s: %s — %s", object, reason, message),
+			})
+		}
+	}
+	return entries
+}
+
+// timelinePhases splits the entries chronologically: detection runs up to and
+// including the first failure, impact until the first change after it, and
+// mitigation from that change on. Without failures there is a single phase.
+func timelinePhases(entries []timelineEntry) []timelinePhase {
+	sorted := make([]timelineEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].at.Before(sorted[j].at)
+	})
+
+	firstFailure := -1
+	for i, entry := range sorted {
+		if entry.kind == "failure" {
+			firstFailure = i
+			break
+		}
+	}
+	if firstFailure < 0 {
+		return []timelinePhase{{title: "Changes", entries: sorted}}
+	}
+
+	mitigation := len(sorted)
+	for i := firstFailure + 1; i < len(sorted); i++ {
+		if sorted[i].kind == "change" {
+			mitigation = i
+			break
+		}
+	}
+
+	phases := []timelinePhase{
+		{title: "Detection", entries: sorted[:firstFailure+1]},
+		{title: "Impact", entries: sorted[firstFailure+1 : mitigation]},
+		{title: "Mitigation", entries: sorted[mitigation:]},
+	}
+	var nonEmpty []timelinePhase
+	for _, phase := range phases {
+		if len(phase.entries) > 0 {
+			nonEmpty = append(nonEmpty, phase)
+		}
+	}
+	return nonEmpty
+}
+
+// writeTimelinePhase renders the entries of a phase as markdown list items.
+// Stretches of similar consecutive entries are summarized in one line and
+// the phase is capped at timelinePhaseLines lines.
+func writeTimelinePhase(results *strings.Builder, entries []timelineEntry) {
+	var lines []string
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && entries[end].group == entries[start].group {
+			end++
+		}
+
+		run := entries[start:end]
+		if len(run) >= timelineRunLength {
+			objects := make(map[string]bool)
+			for _, entry := range run {
+				objects[entry.object] = true
+			}
+			lines = append(lines, fmt.Sprintf("- **%s – %s** — %d × %s on %d objects (first: %s)",
+				run[0].at.Format(time.RFC3339), run[len(run)-1].at.Format(time.RFC3339),
+				len(run), run[0].label, len(objects), run[0].text))
+		} else {
+			for _, entry := range run {
+				lines = append(lines, fmt.Sprintf("- **%s** — %s", entry.at.Format(time.RFC3339), entry.text))
+			}
+		}
+		start = end
+	}
+
+	for _, line := range lines[:min(timelinePhaseLines, len(lines))] {
+		results.WriteString(line + "\n")
+	}
+	if len(lines) > timelinePhaseLines {
+		results.WriteString(fmt.Sprintf("- … %d more entries omitted\n", len(lines)-timelinePhaseLines))
+	}
+}
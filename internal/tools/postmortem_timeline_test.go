@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func deploymentChange(verb string, at time.Time, image string) audit.AuditEvent {
+	return audit.AuditEvent{
+		Timestamp:     at,
+		Verb:          verb,
+		Namespace:     "default",
+		ResourceType:  "deployments",
+		ResourceName:  "web",
+		ObjectChanges: map[string]any{"spec": map[string]any{"image": image}},
+	}
+}
+
+func TestTimelineChanges(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	entries := timelineChanges([]audit.AuditEvent{
+		deploymentChange("update", start, "web:1"),
+		// Status-only updates keep the spec
+		deploymentChange("update", start.Add(time.Minute), "web:1"),
+		deploymentChange("update", start.Add(2*time.Minute), "web:2"),
+		deploymentChange("delete", start.Add(3*time.Minute), "web:2"),
+	})
+
+	if len(entries) != 2 {
+		t.Fatalf("expected an update and a delete, got %+v", entries)
+	}
+	if entries[0].text != "deployments default/web updated" || entries[1].text != "deployments default/web deleted" {
+		t.Errorf("unexpected entries: %q, %q", entries[0].text, entries[1].text)
+	}
+}
+
+func TestTimelinePhases(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	started := k8sEvent("Pod", "web-1", "Started", "Started container app", start.Add(7*time.Minute))
+	started.ObjectChanges["type"] = "Normal"
+	// Normal events of objects without earlier warnings are not recoveries
+	scheduled := k8sEvent("Pod", "web-3", "Scheduled", "Successfully assigned", start.Add(time.Minute))
+	scheduled.ObjectChanges["type"] = "Normal"
+
+	entries := timelineIncidents([]audit.AuditEvent{
+		k8sEvent("Pod", "web-1", "BackOff", "Back-off restarting failed container", start.Add(2*time.Minute)),
+		k8sEvent("Pod", "web-2", "BackOff", "Back-off restarting failed container", start.Add(3*time.Minute)),
+		k8sEvent("Pod", "web-1", "BackOff", "Back-off restarting failed container", start.Add(4*time.Minute)),
+		started,
+		scheduled,
+	})
+	if len(entries) != 4 || entries[3].kind != "recovery" {
+		t.Fatalf("expected 3 failures and a recovery, got %+v", entries)
+	}
+	entries = append(entries, timelineChanges([]audit.AuditEvent{
+		deploymentChange("create", start, "web:2"),
+		deploymentChange("update", start.Add(6*time.Minute), "web:1"),
+	})...)
+
+	phases := timelinePhases(entries)
+	var titles []string
+	for _, phase := range phases {
+		titles = append(titles, phase.title)
+	}
+	if strings.Join(titles, ",") != "Detection,Impact,Mitigation" {
+		t.Fatalf("unexpected phases %v", titles)
+	}
+	if len(phases[0].entries) != 2 || len(phases[1].entries) != 2 || len(phases[2].entries) != 2 {
+		t.Errorf("unexpected phase sizes: %d, %d, %d", len(phases[0].entries), len(phases[1].entries), len(phases[2].entries))
+	}
+
+	var results strings.Builder
+	backOffs := []timelineEntry{phases[0].entries[1], phases[1].entries[0], phases[1].entries[1]}
+	writeTimelinePhase(&results, backOffs)
+	if !strings.Contains(results.String(), "3 × BackOff warnings on 2 objects") {
+		t.Errorf("expected the BackOff stretch to be summarized, got:\n%s", results.String())
+	}
+}
@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+const (
+	// onsetPageSize is the number of events fetched per page while scanning
+	onsetPageSize = 500
+	// onsetScanLimit caps the events scanned while looking for the first match
+	onsetScanLimit = 100000
+)
+
+// problemOnset tracks the first occurrence of a problem keyword and how often
+// it matched afterwards
+type problemOnset struct {
+	keyword     string
+	first       *audit.AuditEvent
+	occurrences int
+	objects     map[string]bool
+	last        time.Time
+}
+
+func newProblemOnset(keyword string) *problemOnset {
+	return &problemOnset{keyword: strings.ToLower(keyword), objects: make(map[string]bool)}
+}
+
+// observe records event if it mentions the keyword. Events must be observed
+// in chronological order.
+func (p *problemOnset) observe(event audit.AuditEvent) {
+	text := strings.ToLower(event.Message + " " + fmt.Sprint(event.ObjectChanges))
+	if !strings.Contains(text, p.keyword) {
+		return
+	}
+
+	if p.first == nil {
+		p.first = &event
+	}
+	p.occurrences++
+	p.objects[onsetObject(event)] = true
+	p.last = event.Timestamp
+}
+
+// FindProblemOnset finds the earliest retained event mentioning a problem keyword and how often it occurred since
+func (h *ToolHandlers) FindProblemOnset(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	keyword := strings.TrimSpace(request.GetString("keyword", ""))
+	if keyword == "" {
+		return mcp.NewToolResultError("keyword is required"), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+	resourceType := request.GetString("resource_type", "")
+
+	// The time index is scanned oldest first, so the first match is the onset
+	onset := newProblemOnset(keyword)
+	opts := audit.QueryOptions{
+		EndTime:      time.Now(),
+		Namespace:    namespace,
+		ResourceType: resourceType,
+		Limit:        onsetPageSize,
+	}
+	scanned, sinceOnset := 0, 0
+	complete := false
+	for {
+		page, err := h.auditClient.QueryEventsPage(ctx, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+		}
+		for _, event := range page.Items {
+			onset.observe(event)
+			scanned++
+			if onset.first != nil {
+				sinceOnset++
+			}
+		}
+
+		if !page.HasMore {
+			complete = true
+			break
+		}
+		if onset.first == nil && scanned >= onsetScanLimit {
+			break
+		}
+		// Counting occurrences after the onset is capped like any other query
+		if onset.first != nil && sinceOnset >= h.eventBudget {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	if onset.first == nil {
+		msg := fmt.Sprintf("No event mentioning %q found in the retained history", keyword)
+		if !complete {
+			msg = fmt.Sprintf("No event mentioning %q found in the oldest %d retained events; narrow it down with namespace or resource_type", keyword, scanned)
+		}
+		return mcp.NewToolResultText(msg + "."), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Problem Onset: %q\n", keyword))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	if resourceType != "" {
+		results.WriteString(fmt.Sprintf("Resource type: %s\n", resourceType))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	first := onset.first
+	results.WriteString(fmt.Sprintf("🕐 First seen: %s (%s ago)\n",
+		first.Timestamp.Format(time.RFC3339), formatLifetime(time.Since(first.Timestamp))))
+	results.WriteString(fmt.Sprintf("  Object: %s (%s)\n", onsetObject(*first), first.Verb))
+	if message := onsetMessage(*first); message != "" {
+		results.WriteString(fmt.Sprintf("  Message: %s\n", message))
+	}
+	results.WriteString("\n")
+
+	occurrences := fmt.Sprintf("%d", onset.occurrences)
+	if !complete {
+		occurrences = "at least " + occurrences
+	}
+	results.WriteString(fmt.Sprintf("📈 Since then: %s occurrences across %d objects, last at %s\n",
+		occurrences, len(onset.objects), onset.last.Format(time.RFC3339)))
+	if days := onset.last.Sub(first.Timestamp).Hours() / 24; days >= 1 {
+		results.WriteString(fmt.Sprintf("  (%.1f per day)\n", float64(onset.occurrences)/days))
+	}
+
+	if !complete {
+		results.WriteString(fmt.Sprintf("\n⚠️  Stopped counting after %d events following the onset; narrow it down with namespace or resource_type for a full count.\n", sinceOnset))
+	}
+
+	results.WriteString(fmt.Sprintf("\nTotal events scanned: %d\n", scanned))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// onsetObject names the object an event is about. Kubernetes events are
+// attributed to their involved object rather than the Event itself.
+func onsetObject(event audit.AuditEvent) string {
+	if event.ResourceType == "events" {
+		if object := involvedObject(event); object != "" {
+			return object
+		}
+	}
+	if event.Namespace == "" {
+		return fmt.Sprintf("%s %s", event.ResourceType, event.ResourceName)
+	}
+	return fmt.Sprintf("%s %s/%s", event.ResourceType, event.Namespace, event.ResourceName)
+}
+
+// onsetMessage returns the most descriptive message of an event
+func onsetMessage(event audit.AuditEvent) string {
+	if message, _ := event.ObjectChanges["message"].(string); message != "" {
+		return message
+	}
+	return event.Message
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestProblemOnsetObserve(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	onset := newProblemOnset("CrashLoopBackOff")
+
+	for _, event := range []audit.AuditEvent{
+		{Timestamp: start, ResourceType: "pods", Namespace: "default", ResourceName: "web-1", Message: "pod added"},
+		k8sEvent("Pod", "web-1", "BackOff", "Back-off restarting failed container (crashloopbackoff)", start.Add(time.Minute)),
+		{Timestamp: start.Add(2 * time.Minute), ResourceType: "pods", Namespace: "default", ResourceName: "web-2",
+			ObjectChanges: map[string]any{"status": map[string]any{"reason": "CrashLoopBackOff"}}},
+	} {
+		onset.observe(event)
+	}
+
+	if onset.first == nil || !onset.first.Timestamp.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected onset at the Kubernetes event, got %+v", onset.first)
+	}
+	if onset.occurrences != 2 || len(onset.objects) != 2 {
+		t.Errorf("expected 2 occurrences on 2 objects, got %d on %v", onset.occurrences, onset.objects)
+	}
+	if got := onsetObject(*onset.first); got != "Pod default/web-1" {
+		t.Errorf("expected involved object, got %q", got)
+	}
+}
+
+func TestFindProblemOnsetPagesOldestFirst(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	pages := map[string]audit.EventPage{
+		"": {
+			Items:      []audit.AuditEvent{{Timestamp: start, ResourceType: "pods", ResourceName: "ok", Message: "pod added"}},
+			HasMore:    true,
+			NextCursor: "page-2",
+		},
+		"page-2": {
+			Items: []audit.AuditEvent{
+				{Timestamp: start.Add(time.Hour), ResourceType: "pods", ResourceName: "web", Message: "OOMKilled"},
+				{Timestamp: start.Add(2 * time.Hour), ResourceType: "pods", ResourceName: "web", Message: "OOMKilled"},
+			},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+	}))
+	defer server.Close()
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{"keyword": "oomkilled"}
+
+	result, err := h.FindProblemOnset(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		"First seen: " + start.Add(time.Hour).Format(time.RFC3339),
+		"Since then: 2 occurrences across 1 objects",
+		"Total events scanned: 3",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
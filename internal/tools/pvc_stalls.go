@@ -0,0 +1,277 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// podVolumeStall is a pod stuck in ContainerCreating that mounts PVCs
+type podVolumeStall struct {
+	pod    string
+	since  time.Time
+	claims []claimBlock
+}
+
+// claimBlock explains why a PVC blocks a stalled pod
+type claimBlock struct {
+	claim  string
+	reason string
+	detail string
+}
+
+// CorrelatePVCWithPodStalls joins PVC binding and mount failures with the pods stuck in ContainerCreating they block
+func (h *ToolHandlers) CorrelatePVCWithPodStalls(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	budget := h.newQueryBudget()
+	var byType [3][]audit.AuditEvent
+	for i, resourceType := range []string{"pods", "persistentvolumeclaims", "events"} {
+		events, err := budget.query(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Namespace:    namespace,
+			ResourceType: resourceType,
+		})
+		if err != nil && !errors.Is(err, audit.ErrNoData) {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query %s events: %v", resourceType, err)), nil
+		}
+		byType[i] = events
+	}
+	pods, pvcs, k8sEvents := byType[0], byType[1], byType[2]
+
+	if len(pods) == 0 {
+		return mcp.NewToolResultText("No pod events found in the specified time range."), nil
+	}
+
+	stalls, unrelated := correlatePVCStalls(pods, pvcs, k8sEvents)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("PVC / Pod Stall Correlation (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(stalls) == 0 {
+		results.WriteString("✅ No pod mounting a PVC is stuck in ContainerCreating.\n")
+	} else {
+		results.WriteString(fmt.Sprintf("🔴 Pods Stalled on Volumes: %d\n", len(stalls)))
+		for _, stall := range stalls[:min(20, len(stalls))] {
+			results.WriteString(fmt.Sprintf("  - Pod %s in ContainerCreating since %s\n", stall.pod, stall.since.Format(time.RFC3339)))
+			for _, block := range stall.claims {
+				results.WriteString(fmt.Sprintf("      PVC %s: %s\n", block.claim, block.reason))
+				if block.detail != "" {
+					results.WriteString(fmt.Sprintf("        %s\n", block.detail))
+				}
+			}
+		}
+		if len(stalls) > 20 {
+			results.WriteString(fmt.Sprintf("  ... and %d more\n", len(stalls)-20))
+		}
+	}
+	results.WriteString("\n")
+
+	if unrelated > 0 {
+		results.WriteString(fmt.Sprintf("ℹ️  %d more pods are stuck in ContainerCreating without mounting a PVC (check_pod_issues covers those)\n", unrelated))
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal pod events analyzed: %d\n", len(pods)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// correlatePVCStalls finds the pods whose latest recorded state is stuck in
+// ContainerCreating and explains each PVC they mount from the PVC's latest
+// phase and the FailedMount/FailedAttachVolume events of the pod. It also
+// returns how many stalled pods mount no PVC at all.
+func correlatePVCStalls(pods, pvcs, k8sEvents []audit.AuditEvent) ([]podVolumeStall, int) {
+	sorted := make([]audit.AuditEvent, len(pods))
+	copy(sorted, pods)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	// Track when each pod entered its current ContainerCreating stall
+	since := make(map[string]time.Time)
+	latest := make(map[string]audit.AuditEvent)
+	for _, event := range sorted {
+		key := event.Namespace + "/" + event.ResourceName
+		latest[key] = event
+		if event.Verb == "delete" || !containerCreating(event) {
+			delete(since, key)
+			continue
+		}
+		if _, ok := since[key]; !ok {
+			since[key] = event.Timestamp
+		}
+	}
+
+	phases := latestPVCPhases(pvcs)
+
+	claimEvents := make(map[string]audit.AuditEvent)
+	podEvents := make(map[string][]audit.AuditEvent)
+	for _, event := range k8sEvents {
+		involved, _ := event.ObjectChanges["involvedObject"].(map[string]any)
+		kind, _ := involved["kind"].(string)
+		ns, _ := involved["namespace"].(string)
+		name, _ := involved["name"].(string)
+		key := ns + "/" + name
+		switch kind {
+		case "PersistentVolumeClaim":
+			if previous, ok := claimEvents[key]; !ok || event.Timestamp.After(previous.Timestamp) {
+				claimEvents[key] = event
+			}
+		case "Pod":
+			reason, _ := event.ObjectChanges["reason"].(string)
+			if reason == "FailedMount" || reason == "FailedAttachVolume" {
+				podEvents[key] = append(podEvents[key], event)
+			}
+		}
+	}
+
+	var stalls []podVolumeStall
+	unrelated := 0
+	for key, start := range since {
+		claims := podClaims(latest[key])
+		if len(claims) == 0 {
+			unrelated++
+			continue
+		}
+
+		namespace := latest[key].Namespace
+		stall := podVolumeStall{pod: key, since: start}
+		for _, c := range claims {
+			claimKey := namespace + "/" + c.claim
+			stall.claims = append(stall.claims, explainClaim(claimKey, c, phases[claimKey], claimEvents[claimKey], podEvents[key]))
+		}
+		stalls = append(stalls, stall)
+	}
+
+	sort.Slice(stalls, func(i, j int) bool {
+		if !stalls[i].since.Equal(stalls[j].since) {
+			return stalls[i].since.Before(stalls[j].since)
+		}
+		return stalls[i].pod < stalls[j].pod
+	})
+	return stalls, unrelated
+}
+
+// latestPVCPhases returns the most recent phase recorded for every PVC
+func latestPVCPhases(pvcs []audit.AuditEvent) map[string]string {
+	sorted := make([]audit.AuditEvent, len(pvcs))
+	copy(sorted, pvcs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	phases := make(map[string]string)
+	for _, event := range sorted {
+		key := event.Namespace + "/" + event.ResourceName
+		if event.Verb == "delete" {
+			phases[key] = "deleted"
+			continue
+		}
+		status, _ := event.ObjectChanges["status"].(map[string]any)
+		if phase, _ := status["phase"].(string); phase != "" {
+			phases[key] = phase
+		}
+	}
+	return phases
+}
+
+// explainClaim says why a PVC blocks a pod: the claim is unbound, the pod
+// fails to mount it, or nothing was recorded that explains the stall
+func explainClaim(claimKey string, c podClaim, phase string, claimEvent audit.AuditEvent, podEvents []audit.AuditEvent) claimBlock {
+	block := claimBlock{claim: claimKey}
+
+	switch phase {
+	case "":
+		block.reason = "no PVC state recorded in the window"
+	case "deleted":
+		block.reason = "PVC was deleted while the pod still references it"
+	case "Bound":
+	default:
+		block.reason = fmt.Sprintf("unbound (phase %s)", phase)
+		if message, _ := claimEvent.ObjectChanges["message"].(string); message != "" {
+			reason, _ := claimEvent.ObjectChanges["reason"].(string)
+			block.detail = fmt.Sprintf("%s: %s", reason, message)
+		}
+		return block
+	}
+
+	// Mount failures name the pod volume, not the claim, in their message
+	var failure *audit.AuditEvent
+	for i, event := range podEvents {
+		message, _ := event.ObjectChanges["message"].(string)
+		if !strings.Contains(message, `"`+c.volume+`"`) && !strings.Contains(message, c.claim) {
+			continue
+		}
+		if failure == nil || event.Timestamp.After(failure.Timestamp) {
+			failure = &podEvents[i]
+		}
+	}
+	if failure != nil {
+		reason, _ := failure.ObjectChanges["reason"].(string)
+		message, _ := failure.ObjectChanges["message"].(string)
+		block.reason = "mount failing"
+		block.detail = fmt.Sprintf("%s: %s", reason, message)
+		return block
+	}
+
+	if block.reason == "" {
+		block.reason = "bound, but no mount failure recorded (check the node and CSI driver)"
+	}
+	return block
+}
+
+// podClaim is a PVC a pod mounts through one of its volumes
+type podClaim struct {
+	volume string
+	claim  string
+}
+
+// podClaims returns the PVCs referenced by a pod's volumes
+func podClaims(event audit.AuditEvent) []podClaim {
+	spec, _ := event.ObjectChanges["spec"].(map[string]any)
+	volumes, _ := spec["volumes"].([]any)
+
+	var claims []podClaim
+	for _, v := range volumes {
+		volume, _ := v.(map[string]any)
+		name, _ := volume["name"].(string)
+		if claim := nestedName(volume, "persistentVolumeClaim", "claimName"); claim != "" {
+			claims = append(claims, podClaim{volume: name, claim: claim})
+		}
+	}
+	return claims
+}
+
+// containerCreating reports whether any container of the pod is waiting in ContainerCreating
+func containerCreating(event audit.AuditEvent) bool {
+	status, _ := event.ObjectChanges["status"].(map[string]any)
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _ := status[field].([]any)
+		for _, s := range statuses {
+			containerStatus, _ := s.(map[string]any)
+			state, _ := containerStatus["state"].(map[string]any)
+			if nestedName(state, "waiting", "reason") == "ContainerCreating" {
+				return true
+			}
+		}
+	}
+	return false
+}
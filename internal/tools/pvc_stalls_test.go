@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// stalledPod builds a stored pod mounting the given claims, in ContainerCreating when creating is set
+func stalledPod(name string, at time.Time, creating bool, claims ...string) audit.AuditEvent {
+	volumes := make([]any, 0, len(claims))
+	for _, claim := range claims {
+		volumes = append(volumes, map[string]any{
+			"name":                  claim + "-vol",
+			"persistentVolumeClaim": map[string]any{"claimName": claim},
+		})
+	}
+	state := map[string]any{"running": map[string]any{}}
+	if creating {
+		state = map[string]any{"waiting": map[string]any{"reason": "ContainerCreating"}}
+	}
+	return audit.AuditEvent{
+		Timestamp:    at,
+		Verb:         "update",
+		Namespace:    "default",
+		ResourceType: "pods",
+		ResourceName: name,
+		ObjectChanges: map[string]any{
+			"spec":   map[string]any{"volumes": volumes},
+			"status": map[string]any{"containerStatuses": []any{map[string]any{"name": "app", "state": state}}},
+		},
+	}
+}
+
+func pvcPhase(name, phase string, at time.Time) audit.AuditEvent {
+	return audit.AuditEvent{
+		Timestamp:     at,
+		Verb:          "update",
+		Namespace:     "default",
+		ResourceType:  "persistentvolumeclaims",
+		ResourceName:  name,
+		ObjectChanges: map[string]any{"status": map[string]any{"phase": phase}},
+	}
+}
+
+func k8sEvent(kind, name, reason, message string, at time.Time) audit.AuditEvent {
+	return audit.AuditEvent{
+		Timestamp:    at,
+		Verb:         "create",
+		Namespace:    "default",
+		ResourceType: "events",
+		ObjectChanges: map[string]any{
+			"type":           "Warning",
+			"reason":         reason,
+			"message":        message,
+			"involvedObject": map[string]any{"kind": kind, "namespace": "default", "name": name},
+		},
+	}
+}
+
+func TestCorrelatePVCStalls(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+
+	pods := []audit.AuditEvent{
+		stalledPod("db-0", start.Add(2*time.Minute), true, "data-db-0"),
+		stalledPod("db-0", start, true, "data-db-0"),
+		stalledPod("web-1", start.Add(time.Minute), true, "uploads"),
+		// Recovered pods are not stalled
+		stalledPod("cache-0", start, true, "cache"),
+		stalledPod("cache-0", start.Add(time.Minute), false, "cache"),
+		// Stalled pods without a PVC are only counted
+		stalledPod("api-2", start, true),
+	}
+	pvcs := []audit.AuditEvent{
+		pvcPhase("data-db-0", "Pending", start),
+		pvcPhase("uploads", "Pending", start),
+		pvcPhase("uploads", "Bound", start.Add(30*time.Second)),
+	}
+	events := []audit.AuditEvent{
+		k8sEvent("PersistentVolumeClaim", "data-db-0", "ProvisioningFailed", "storageclass \"fast\" not found", start.Add(time.Minute)),
+		k8sEvent("Pod", "web-1", "FailedMount", `MountVolume.SetUp failed for volume "uploads-vol" : rpc error`, start.Add(3*time.Minute)),
+		k8sEvent("Pod", "web-1", "Scheduled", "Successfully assigned", start),
+	}
+
+	stalls, unrelated := correlatePVCStalls(pods, pvcs, events)
+	if unrelated != 1 {
+		t.Errorf("expected 1 stalled pod without PVC, got %d", unrelated)
+	}
+	if len(stalls) != 2 {
+		t.Fatalf("expected 2 stalls, got %+v", stalls)
+	}
+
+	db := stalls[0]
+	if db.pod != "default/db-0" || !db.since.Equal(start) {
+		t.Errorf("expected db-0 stalled since start, got %+v", db)
+	}
+	if db.claims[0].reason != "unbound (phase Pending)" || !strings.Contains(db.claims[0].detail, "ProvisioningFailed") {
+		t.Errorf("expected unbound claim with provisioning failure, got %+v", db.claims[0])
+	}
+
+	web := stalls[1]
+	if web.pod != "default/web-1" || web.claims[0].reason != "mount failing" || !strings.Contains(web.claims[0].detail, "FailedMount") {
+		t.Errorf("expected web-1 blocked by a failing mount, got %+v", web)
+	}
+}
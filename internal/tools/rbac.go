@@ -0,0 +1,240 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// rbacEscalation is a create or update of an RBAC object that widens access
+type rbacEscalation struct {
+	at     time.Time
+	verb   string
+	object string // e.g. "ClusterRoleBinding ci-admin"
+	user   string
+	reason string // e.g. "binds ClusterRole cluster-admin to ServiceAccount ci/deployer"
+}
+
+// CheckRBACChanges reports created, updated and deleted Roles, ClusterRoles and their bindings in a window, flagging grants of cluster-admin or wildcard verbs
+func (h *ToolHandlers) CheckRBACChanges(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	budget := h.newQueryBudget()
+	query := func(resourceType, namespace string) ([]audit.AuditEvent, error) {
+		events, err := budget.query(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Namespace:    namespace,
+			ResourceType: resourceType,
+		})
+		if err != nil && !errors.Is(err, audit.ErrNoData) {
+			return nil, fmt.Errorf("failed to query %s events: %w", resourceType, err)
+		}
+		return events, nil
+	}
+
+	// ClusterRoles and ClusterRoleBindings are cluster-scoped and apply to
+	// every namespace, so they are included with a namespace filter too
+	var roles, bindings []audit.AuditEvent
+	for _, q := range []struct {
+		resourceType string
+		namespace    string
+		into         *[]audit.AuditEvent
+	}{
+		{"roles", namespace, &roles},
+		{"clusterroles", "", &roles},
+		{"rolebindings", namespace, &bindings},
+		{"clusterrolebindings", "", &bindings},
+	} {
+		events, err := query(q.resourceType, q.namespace)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		*q.into = append(*q.into, events...)
+	}
+
+	escalations := rbacEscalations(roles, bindings)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("RBAC Changes (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s (cluster-scoped changes included)\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(roles) == 0 && len(bindings) == 0 {
+		results.WriteString("✅ No RBAC changes in this window.\n")
+		results.WriteString("  (check that roles, clusterroles, rolebindings and clusterrolebindings are watched)\n")
+	}
+
+	if len(escalations) > 0 {
+		results.WriteString(fmt.Sprintf("🔴 Privilege Escalations: %d\n", len(escalations)))
+		results.WriteString("  (grants of cluster-admin or wildcard verbs give broad control over the cluster)\n")
+		for _, e := range escalations[:min(20, len(escalations))] {
+			by := ""
+			if e.user != "" && e.user != watcherUser {
+				by = " by " + e.user
+			}
+			results.WriteString(fmt.Sprintf("  - %s: %s %s%s %s\n", e.at.Format(time.RFC3339), e.verb, e.object, by, e.reason))
+		}
+		if len(escalations) > 20 {
+			results.WriteString(fmt.Sprintf("  ... and %d more\n", len(escalations)-20))
+		}
+		results.WriteString("\n")
+	}
+
+	if len(roles) > 0 {
+		results.WriteString(fmt.Sprintf("📋 Roles and ClusterRoles: %d events\n", len(roles)))
+		writeFindings(&results, roles, 10, sortNewestFirst, nil)
+		results.WriteString("\n")
+	}
+
+	if len(bindings) > 0 {
+		results.WriteString(fmt.Sprintf("📋 RoleBindings and ClusterRoleBindings: %d events\n", len(bindings)))
+		writeFindings(&results, bindings, 10, sortNewestFirst, nil)
+		results.WriteString("\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal RBAC events analyzed: %d\n", len(roles)+len(bindings)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// rbacEscalations returns the role and binding changes that grant wildcard
+// verbs or cluster-admin, in time order. A binding is flagged when it
+// references cluster-admin or a role seen with wildcard verbs in the window.
+func rbacEscalations(roles, bindings []audit.AuditEvent) []rbacEscalation {
+	var escalations []rbacEscalation
+
+	// Roles with wildcard verbs, keyed like the roleRef of a binding
+	wildcardRoles := make(map[string]bool)
+	for _, event := range roles {
+		if event.Verb == "delete" {
+			continue
+		}
+		object := rbacObject(event)
+		rules, _ := event.ObjectChanges["rules"].([]any)
+		if resources := wildcardVerbResources(rules); len(resources) > 0 {
+			wildcardRoles[object] = true
+			escalations = append(escalations, rbacEscalation{
+				at:     event.Timestamp,
+				verb:   event.Verb,
+				object: object,
+				user:   event.User,
+				reason: "grants all verbs on " + strings.Join(resources, ", "),
+			})
+		}
+	}
+
+	for _, event := range bindings {
+		if event.Verb == "delete" {
+			continue
+		}
+		roleRef, _ := event.ObjectChanges["roleRef"].(map[string]any)
+		roleKind, _ := roleRef["kind"].(string)
+		roleName, _ := roleRef["name"].(string)
+		role := roleKind + " " + roleName
+		if roleKind == "Role" {
+			role = roleKind + " " + event.Namespace + "/" + roleName
+		}
+
+		var grant string
+		switch {
+		case roleKind == "ClusterRole" && roleName == "cluster-admin":
+			grant = "ClusterRole cluster-admin"
+		case wildcardRoles[role]:
+			grant = role + " (wildcard verbs)"
+		default:
+			continue
+		}
+
+		subjects, _ := event.ObjectChanges["subjects"].([]any)
+		escalations = append(escalations, rbacEscalation{
+			at:     event.Timestamp,
+			verb:   event.Verb,
+			object: rbacObject(event),
+			user:   event.User,
+			reason: fmt.Sprintf("binds %s to %s", grant, bindingSubjects(subjects, event.Namespace)),
+		})
+	}
+
+	sort.SliceStable(escalations, func(i, j int) bool {
+		return escalations[i].at.Before(escalations[j].at)
+	})
+	return escalations
+}
+
+// rbacObject names an RBAC object by kind, e.g. "Role shop/reader" or
+// "ClusterRoleBinding ci-admin"
+func rbacObject(event audit.AuditEvent) string {
+	kinds := map[string]string{
+		"roles":               "Role",
+		"clusterroles":        "ClusterRole",
+		"rolebindings":        "RoleBinding",
+		"clusterrolebindings": "ClusterRoleBinding",
+	}
+	if event.Namespace == "" {
+		return kinds[event.ResourceType] + " " + event.ResourceName
+	}
+	return kinds[event.ResourceType] + " " + event.Namespace + "/" + event.ResourceName
+}
+
+// wildcardVerbResources returns the resources of the policy rules that allow
+// every verb, sorted; "*" stands for all resources
+func wildcardVerbResources(rules []any) []string {
+	resources := make(map[string]bool)
+	for _, r := range rules {
+		rule, _ := r.(map[string]any)
+		verbs, _ := rule["verbs"].([]any)
+		if !slices.Contains(verbs, any("*")) {
+			continue
+		}
+		ruleResources, _ := rule["resources"].([]any)
+		for _, resource := range ruleResources {
+			if name, ok := resource.(string); ok {
+				resources[name] = true
+			}
+		}
+		if nonResourceURLs, _ := rule["nonResourceURLs"].([]any); len(ruleResources) == 0 && len(nonResourceURLs) > 0 {
+			resources["non-resource URLs"] = true
+		}
+	}
+	return sortedKeys(resources)
+}
+
+// bindingSubjects describes binding subjects, e.g. "ServiceAccount ci/deployer,
+// User alice". Service accounts without a namespace default to the binding's.
+func bindingSubjects(subjects []any, bindingNamespace string) string {
+	if len(subjects) == 0 {
+		return "no subjects"
+	}
+	var names []string
+	for _, s := range subjects {
+		subject, _ := s.(map[string]any)
+		kind, _ := subject["kind"].(string)
+		name, _ := subject["name"].(string)
+		if kind == "ServiceAccount" {
+			namespace, _ := subject["namespace"].(string)
+			if namespace == "" {
+				namespace = bindingNamespace
+			}
+			name = namespace + "/" + name
+		}
+		names = append(names, kind+" "+name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// roleEvent returns a stored role with one rule of the given verbs on pods
+func roleEvent(resourceType, namespace, name, verb string, at time.Time, verbs ...any) audit.AuditEvent {
+	return audit.AuditEvent{
+		Timestamp:    at,
+		Verb:         verb,
+		Namespace:    namespace,
+		ResourceType: resourceType,
+		ResourceName: name,
+		ObjectChanges: map[string]any{
+			"rules": []any{map[string]any{"apiGroups": []any{""}, "resources": []any{"pods"}, "verbs": verbs}},
+		},
+	}
+}
+
+func TestRBACEscalations(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	roles := []audit.AuditEvent{
+		roleEvent("roles", "web", "reader", "create", start, "get", "list"),
+		roleEvent("roles", "web", "pod-admin", "update", start.Add(time.Minute), "*"),
+		// Deleting a wildcard role removes access
+		roleEvent("clusterroles", "", "old-admin", "delete", start.Add(2*time.Minute), "*"),
+	}
+	bindings := []audit.AuditEvent{
+		bindingEvent("clusterrolebindings", "", "ci-admin", "create", "ClusterRole", "cluster-admin", start.Add(3*time.Minute), [2]string{"ci", "deployer"}),
+		bindingEvent("rolebindings", "web", "pod-admins", "create", "Role", "pod-admin", start.Add(4*time.Minute), [2]string{"", "app"}),
+		bindingEvent("rolebindings", "web", "readers", "create", "Role", "reader", start.Add(5*time.Minute), [2]string{"web", "app"}),
+		// A Role of the same name in another namespace has no wildcard verbs
+		bindingEvent("rolebindings", "shop", "pod-admins", "create", "Role", "pod-admin", start.Add(6*time.Minute), [2]string{"shop", "app"}),
+		bindingEvent("clusterrolebindings", "", "gone", "delete", "ClusterRole", "cluster-admin", start.Add(7*time.Minute)),
+	}
+
+	escalations := rbacEscalations(roles, bindings)
+	if len(escalations) != 3 {
+		t.Fatalf("expected 3 escalations, got %+v", escalations)
+	}
+
+	want := []struct {
+		object string
+		reason string
+	}{
+		{"Role web/pod-admin", "grants all verbs on pods"},
+		{"ClusterRoleBinding ci-admin", "binds ClusterRole cluster-admin to ServiceAccount ci/deployer"},
+		{"RoleBinding web/pod-admins", "binds Role web/pod-admin (wildcard verbs) to ServiceAccount web/app"},
+	}
+	for i, w := range want {
+		if escalations[i].object != w.object || escalations[i].reason != w.reason {
+			t.Errorf("escalation %d: expected %s %q, got %s %q", i, w.object, w.reason, escalations[i].object, escalations[i].reason)
+		}
+	}
+}
+
+func TestCheckRBACChanges(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	admin := bindingEvent("clusterrolebindings", "", "ci-admin", "create", "ClusterRole", "cluster-admin", start, [2]string{"ci", "deployer"})
+	admin.User = "alice"
+	events := map[string][]audit.AuditEvent{
+		"roles":               {roleEvent("roles", "web", "reader", "update", start, "get")},
+		"clusterrolebindings": {admin},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(audit.EventPage{Items: events[r.URL.Query().Get("resourceType")]})
+	}))
+	defer server.Close()
+
+	h := NewToolHandlers(audit.NewClient(server.URL), 0, nil)
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{
+		"start_time": start.Format(time.RFC3339),
+		"end_time":   start.Add(time.Hour).Format(time.RFC3339),
+		"namespace":  "web",
+	}
+	result, err := h.CheckRBACChanges(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %+v", err, result)
+	}
+
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		"🔴 Privilege Escalations: 1",
+		"create ClusterRoleBinding ci-admin by alice binds ClusterRole cluster-admin to ServiceAccount ci/deployer",
+		"📋 Roles and ClusterRoles: 1 events",
+		"📋 RoleBindings and ClusterRoleBindings: 1 events",
+		"Total RBAC events analyzed: 2",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultRecentEvents is the number of events GetRecentEvents shows without a limit
+const defaultRecentEvents = 20
+
+// GetRecentEvents lists the most recent events across the whole cluster, newest first
+func (h *ToolHandlers) GetRecentEvents(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	limit := request.GetInt("limit", defaultRecentEvents)
+	if limit <= 0 {
+		return mcp.NewToolResultError("limit must be a positive number"), nil
+	}
+
+	events, err := h.auditClient.GetRecentEvents(ctx, limit)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query recent events: %v", err)), nil
+	}
+
+	if len(events) == 0 {
+		return mcp.NewToolResultText("No events stored yet."), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Most Recent Events (newest first, limit %d)\n", limit))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	for _, event := range events {
+		results.WriteString(fmt.Sprintf("  - %s: %s\n", event.Timestamp.Format(time.RFC3339), event.Message))
+	}
+
+	if len(events) < limit {
+		results.WriteString(fmt.Sprintf("\n(only %d events are stored)\n", len(events)))
+	}
+
+	results.WriteString(fmt.Sprintf("\nTotal events shown: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+const (
+	// defaultRecreateCycles is the default number of recreations from which
+	// an object is reported
+	defaultRecreateCycles = 3
+	// recreateStepLines caps the steps listed per object
+	recreateStepLines = 10
+)
+
+// recreateLoop is an object deleted and recreated under the same name
+// repeatedly
+type recreateLoop struct {
+	key    string
+	cycles int
+	steps  []audit.AuditEvent
+}
+
+// DetectRecreateLoops finds objects deleted and recreated with the same name repeatedly (a controller fighting with something, or a CI loop)
+func (h *ToolHandlers) DetectRecreateLoops(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	minCycles := request.GetInt("min_cycles", defaultRecreateCycles)
+	if minCycles < 1 {
+		return mcp.NewToolResultError("min_cycles must be at least 1"), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	budget := h.newQueryBudget()
+	var events []audit.AuditEvent
+	for _, verb := range []string{"create", "delete"} {
+		verbEvents, err := budget.query(ctx, audit.QueryOptions{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Namespace: namespace,
+			Verb:      verb,
+		})
+		if err != nil && !errors.Is(err, audit.ErrNoData) {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query %s events: %v", verb, err)), nil
+		}
+		events = append(events, verbEvents...)
+	}
+
+	if len(events) == 0 {
+		return mcp.NewToolResultText("No create or delete events found in the specified time range."), nil
+	}
+
+	loops := detectRecreateLoops(events, minCycles)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Recreate Loop Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(fmt.Sprintf("Threshold: %d recreations\n", minCycles))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(loops) == 0 {
+		results.WriteString("✅ No object was recreated after deletion above the threshold.\n")
+	} else {
+		results.WriteString(fmt.Sprintf("🔁 Recreate Loops: %d\n", len(loops)))
+		results.WriteString("  (a controller fighting with another actor, or a CI job re-applying what something else deletes)\n")
+		for _, loop := range loops[:min(20, len(loops))] {
+			results.WriteString(fmt.Sprintf("  - %s: %d recreations, %s to %s\n",
+				formatObjectKey(loop.key), loop.cycles,
+				loop.steps[0].Timestamp.Format(time.RFC3339), loop.steps[len(loop.steps)-1].Timestamp.Format(time.RFC3339)))
+			for _, step := range loop.steps[:min(recreateStepLines, len(loop.steps))] {
+				results.WriteString(fmt.Sprintf("      %s %s by %s\n",
+					step.Timestamp.Format(time.RFC3339), step.Verb, configUpdater(step)))
+			}
+			if len(loop.steps) > recreateStepLines {
+				results.WriteString(fmt.Sprintf("      ... and %d more steps\n", len(loop.steps)-recreateStepLines))
+			}
+		}
+		if len(loops) > 20 {
+			results.WriteString(fmt.Sprintf("  ... and %d more\n", len(loops)-20))
+		}
+	}
+	results.WriteString("\n")
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal create/delete events analyzed: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// detectRecreateLoops returns the objects created again after a deletion at
+// least minCycles times, most recreations first. Kubernetes events are left
+// out: they are short-lived by design.
+func detectRecreateLoops(events []audit.AuditEvent, minCycles int) []recreateLoop {
+	byObject := make(map[string][]audit.AuditEvent)
+	for _, event := range events {
+		if event.ResourceType == "events" {
+			continue
+		}
+		key := configKey(event.ResourceType, event.Namespace, event.ResourceName)
+		byObject[key] = append(byObject[key], event)
+	}
+
+	var loops []recreateLoop
+	for key, steps := range byObject {
+		sort.SliceStable(steps, func(i, j int) bool {
+			return steps[i].Timestamp.Before(steps[j].Timestamp)
+		})
+
+		cycles := 0
+		for i := 1; i < len(steps); i++ {
+			if steps[i].Verb == "create" && steps[i-1].Verb == "delete" {
+				cycles++
+			}
+		}
+		if cycles >= minCycles {
+			loops = append(loops, recreateLoop{key: key, cycles: cycles, steps: steps})
+		}
+	}
+
+	sort.Slice(loops, func(i, j int) bool {
+		if loops[i].cycles != loops[j].cycles {
+			return loops[i].cycles > loops[j].cycles
+		}
+		return loops[i].key < loops[j].key
+	})
+	return loops
+}
+
+// formatObjectKey renders an object key as "resourceType namespace/name", or
+// "resourceType name" for cluster-scoped objects
+func formatObjectKey(key string) string {
+	resourceType, rest, _ := strings.Cut(key, "/")
+	namespace, name, _ := strings.Cut(rest, "/")
+	if namespace == "" {
+		return fmt.Sprintf("%s %s", resourceType, name)
+	}
+	return fmt.Sprintf("%s %s/%s", resourceType, namespace, name)
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestDetectRecreateLoops(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	owned := map[string]any{"metadata": map[string]any{
+		"ownerReferences": []any{map[string]any{"kind": "Application", "name": "web"}},
+	}}
+
+	var events []audit.AuditEvent
+	for i := 0; i < 4; i++ {
+		at := start.Add(time.Duration(i) * 10 * time.Minute)
+		events = append(events,
+			audit.AuditEvent{Timestamp: at, Verb: "create", ResourceType: "configmaps", Namespace: "default", ResourceName: "app", ObjectChanges: owned},
+			audit.AuditEvent{Timestamp: at.Add(5 * time.Minute), Verb: "delete", ResourceType: "configmaps", Namespace: "default", ResourceName: "app", User: "ci-bot"},
+		)
+	}
+	events = append(events,
+		// Recreated once only
+		audit.AuditEvent{Timestamp: start, Verb: "create", ResourceType: "pods", Namespace: "default", ResourceName: "db-0"},
+		audit.AuditEvent{Timestamp: start.Add(time.Minute), Verb: "delete", ResourceType: "pods", Namespace: "default", ResourceName: "db-0"},
+		audit.AuditEvent{Timestamp: start.Add(2 * time.Minute), Verb: "create", ResourceType: "pods", Namespace: "default", ResourceName: "db-0"},
+	)
+
+	loops := detectRecreateLoops(events, 3)
+	if len(loops) != 1 {
+		t.Fatalf("expected only the configmap loop, got %+v", loops)
+	}
+
+	loop := loops[0]
+	if loop.key != "configmaps/default/app" || loop.cycles != 3 || len(loop.steps) != 8 {
+		t.Errorf("unexpected loop %s: %d cycles, %d steps", loop.key, loop.cycles, len(loop.steps))
+	}
+	if got := configUpdater(loop.steps[0]); got != "owner Application web" {
+		t.Errorf("expected the owner as creator, got %q", got)
+	}
+	if got := configUpdater(loop.steps[1]); got != "ci-bot" {
+		t.Errorf("expected ci-bot as deleter, got %q", got)
+	}
+	if got := formatObjectKey(loop.key); got != "configmaps default/app" {
+		t.Errorf("unexpected object name %q", got)
+	}
+}
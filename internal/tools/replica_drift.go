@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+const (
+	// driftSparklineWidth is the number of time buckets of the sparklines
+	driftSparklineWidth = 48
+	// driftTableRows caps the replica changes listed
+	driftTableRows = 30
+)
+
+// driftWorkloadTypes maps the workload kinds with replicas to their resource type
+var driftWorkloadTypes = map[string]string{
+	"Deployment":  "deployments",
+	"StatefulSet": "statefulsets",
+	"ReplicaSet":  "replicasets",
+}
+
+// sparkBlocks are the sparkline levels from zero to the maximum
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// replicaSample is the desired and ready replica count of a workload from a
+// point in time on
+type replicaSample struct {
+	at      time.Time
+	desired int
+	ready   int
+	deleted bool
+}
+
+// degradedPeriod is a stretch in which fewer replicas were ready than desired
+type degradedPeriod struct {
+	start   time.Time
+	end     time.Time
+	ongoing bool
+	// worst is the largest shortfall of ready replicas during the period
+	worst int
+}
+
+// ReplicaDriftTimeline shows desired vs. ready replicas of a workload over time and when it ran degraded
+func (h *ToolHandlers) ReplicaDriftTimeline(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError("name is required"), nil
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return mcp.NewToolResultError("namespace is required"), nil
+	}
+
+	kind := request.GetString("kind", "Deployment")
+	resourceType, ok := driftWorkloadTypes[kind]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("kind must be one of Deployment, StatefulSet or ReplicaSet, got %q", kind)), nil
+	}
+
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: resourceType,
+		ResourceName: name,
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+	}
+
+	samples := replicaSamples(events)
+	if len(samples) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No events with replica counts found for %s %s/%s in the specified time range.", kind, namespace, name)), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Replica Drift Timeline: %s %s/%s (%s to %s)\n",
+		kind, namespace, name, startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	scale := 0
+	for _, sample := range samples {
+		scale = max(scale, sample.desired, sample.ready)
+	}
+	results.WriteString(fmt.Sprintf("📈 Replicas over time (max %d, %s per column):\n",
+		scale, formatLifetime(endTime.Sub(startTime)/driftSparklineWidth)))
+	results.WriteString(fmt.Sprintf("  Desired  %s\n", sparkline(samples, startTime, endTime, scale, func(s replicaSample) int { return s.desired })))
+	results.WriteString(fmt.Sprintf("  Ready    %s\n\n", sparkline(samples, startTime, endTime, scale, func(s replicaSample) int { return s.ready })))
+
+	periods := degradedPeriods(samples, endTime)
+	if len(periods) == 0 {
+		results.WriteString("✅ Ready replicas matched the desired count whenever observed.\n\n")
+	} else {
+		var total time.Duration
+		for _, period := range periods {
+			total += period.end.Sub(period.start)
+		}
+		results.WriteString(fmt.Sprintf("🔴 Degraded Periods: %d (%s in total)\n", len(periods), formatLifetime(total)))
+		for _, period := range periods {
+			end := period.end.Format(time.RFC3339)
+			if period.ongoing {
+				end = "ongoing"
+			}
+			results.WriteString(fmt.Sprintf("  - %s to %s (%s), up to %d replicas short\n",
+				period.start.Format(time.RFC3339), end, formatLifetime(period.end.Sub(period.start)), period.worst))
+		}
+		results.WriteString("\n")
+	}
+
+	results.WriteString(fmt.Sprintf("📋 Replica Changes: %d\n", len(samples)))
+	results.WriteString("  Time                  Desired  Ready\n")
+	for _, sample := range samples[:min(driftTableRows, len(samples))] {
+		if sample.deleted {
+			results.WriteString(fmt.Sprintf("  %s  (deleted)\n", sample.at.Format(time.RFC3339)))
+			continue
+		}
+		marker := ""
+		if sample.ready < sample.desired {
+			marker = fmt.Sprintf("  ⚠️ -%d", sample.desired-sample.ready)
+		}
+		results.WriteString(fmt.Sprintf("  %s  %7d  %5d%s\n", sample.at.Format(time.RFC3339), sample.desired, sample.ready, marker))
+	}
+	if len(samples) > driftTableRows {
+		results.WriteString(fmt.Sprintf("  ... and %d more\n", len(samples)-driftTableRows))
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// replicaSamples returns the desired and ready replica counts of a workload
+// in time order, one sample per change. Events without status carry the last
+// observed ready count forward; a status without readyReplicas means none
+// are ready.
+func replicaSamples(events []audit.AuditEvent) []replicaSample {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var samples []replicaSample
+	current := replicaSample{desired: -1, ready: -1}
+	for _, event := range sorted {
+		if event.Verb == "delete" {
+			samples = append(samples, replicaSample{at: event.Timestamp, deleted: true})
+			current = replicaSample{desired: -1, ready: -1}
+			continue
+		}
+
+		spec, _ := event.ObjectChanges["spec"].(map[string]any)
+		if replicas, ok := spec["replicas"].(float64); ok {
+			current.desired = int(replicas)
+		} else if spec != nil && current.desired < 0 {
+			// spec.replicas defaults to 1 when unset
+			current.desired = 1
+		}
+		if status, ok := event.ObjectChanges["status"].(map[string]any); ok {
+			ready, _ := status["readyReplicas"].(float64)
+			current.ready = int(ready)
+		}
+		if current.desired < 0 || current.ready < 0 {
+			continue
+		}
+
+		if n := len(samples); n > 0 && !samples[n-1].deleted &&
+			samples[n-1].desired == current.desired && samples[n-1].ready == current.ready {
+			continue
+		}
+		current.at = event.Timestamp
+		samples = append(samples, current)
+	}
+	return samples
+}
+
+// degradedPeriods returns the periods in which fewer replicas were ready than
+// desired. A period still open at the last sample lasts until end.
+func degradedPeriods(samples []replicaSample, end time.Time) []degradedPeriod {
+	var periods []degradedPeriod
+	var open *degradedPeriod
+	for _, sample := range samples {
+		short := sample.desired - sample.ready
+		if sample.deleted || short <= 0 {
+			if open != nil {
+				open.end = sample.at
+				periods = append(periods, *open)
+				open = nil
+			}
+			continue
+		}
+		if open == nil {
+			open = &degradedPeriod{start: sample.at}
+		}
+		open.worst = max(open.worst, short)
+	}
+	if open != nil {
+		open.end = end
+		open.ongoing = true
+		periods = append(periods, *open)
+	}
+	return periods
+}
+
+// sparkline renders the value of the samples over the window in
+// driftSparklineWidth columns, each showing the value in effect at its end.
+// Columns before the first sample or after a deletion are blank.
+func sparkline(samples []replicaSample, start, end time.Time, scale int, value func(replicaSample) int) string {
+	step := end.Sub(start) / driftSparklineWidth
+	var line strings.Builder
+	next := 0
+	var current *replicaSample
+	for column := 1; column <= driftSparklineWidth; column++ {
+		at := start.Add(step * time.Duration(column))
+		for next < len(samples) && !samples[next].at.After(at) {
+			current = &samples[next]
+			next++
+		}
+
+		if current == nil || current.deleted {
+			line.WriteRune(' ')
+			continue
+		}
+		level := 0
+		if scale > 0 {
+			level = value(*current) * (len(sparkBlocks) - 1) / scale
+		}
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// replicaEvent returns a stored Deployment with the given replica counts; a
+// negative ready count leaves out the status
+func replicaEvent(at time.Time, desired, ready int) audit.AuditEvent {
+	object := map[string]any{"spec": map[string]any{"replicas": float64(desired)}}
+	if ready >= 0 {
+		status := map[string]any{}
+		if ready > 0 {
+			status["readyReplicas"] = float64(ready)
+		}
+		object["status"] = status
+	}
+	return audit.AuditEvent{Timestamp: at, Verb: "update", ResourceType: "deployments", ObjectChanges: object}
+}
+
+func TestReplicaSamples(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	events := []audit.AuditEvent{
+		replicaEvent(start, 3, 3),
+		replicaEvent(start.Add(time.Minute), 3, 3),
+		// Scale up without a status yet: ready carries forward
+		replicaEvent(start.Add(2*time.Minute), 5, -1),
+		// readyReplicas is omitted when zero
+		replicaEvent(start.Add(3*time.Minute), 5, 0),
+		replicaEvent(start.Add(10*time.Minute), 5, 5),
+	}
+
+	samples := replicaSamples(events)
+	want := []replicaSample{
+		{at: start, desired: 3, ready: 3},
+		{at: start.Add(2 * time.Minute), desired: 5, ready: 3},
+		{at: start.Add(3 * time.Minute), desired: 5, ready: 0},
+		{at: start.Add(10 * time.Minute), desired: 5, ready: 5},
+	}
+	if len(samples) != len(want) {
+		t.Fatalf("expected %d samples, got %+v", len(want), samples)
+	}
+	for i := range want {
+		if samples[i] != want[i] {
+			t.Errorf("sample %d: expected %+v, got %+v", i, want[i], samples[i])
+		}
+	}
+
+	periods := degradedPeriods(samples, start.Add(time.Hour))
+	if len(periods) != 1 {
+		t.Fatalf("expected one degraded period, got %+v", periods)
+	}
+	p := periods[0]
+	if !p.start.Equal(start.Add(2*time.Minute)) || !p.end.Equal(start.Add(10*time.Minute)) || p.worst != 5 || p.ongoing {
+		t.Errorf("unexpected period %+v", p)
+	}
+}
+
+func TestDegradedPeriodOngoing(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	samples := replicaSamples([]audit.AuditEvent{replicaEvent(start, 2, 1)})
+
+	periods := degradedPeriods(samples, start.Add(time.Hour))
+	if len(periods) != 1 || !periods[0].ongoing || !periods[0].end.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected an ongoing period until the end of the window, got %+v", periods)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	end := start.Add(driftSparklineWidth * time.Minute)
+	samples := []replicaSample{
+		{at: start.Add(10 * time.Minute), desired: 4, ready: 0},
+		{at: start.Add(20 * time.Minute), desired: 4, ready: 4},
+	}
+
+	line := []rune(sparkline(samples, start, end, 4, func(s replicaSample) int { return s.ready }))
+	if len(line) != driftSparklineWidth {
+		t.Fatalf("expected %d columns, got %d", driftSparklineWidth, len(line))
+	}
+	if line[0] != ' ' || line[9] != '▁' || line[19] != '█' || line[len(line)-1] != '█' {
+		t.Errorf("unexpected sparkline %q", string(line))
+	}
+}
@@ -0,0 +1,244 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// replicaBound is the HPA limit a workload is pinned at
+type replicaBound string
+
+const (
+	boundMax replicaBound = "max"
+	boundMin replicaBound = "min"
+)
+
+// hpaObservation is the replica state of an HPA at one event
+type hpaObservation struct {
+	timestamp   time.Time
+	current     int
+	min         int
+	max         int
+	target      string
+	limitReason string
+}
+
+// pinnedWorkload is an HPA that stayed at one bound for every observation in the window
+type pinnedWorkload struct {
+	hpa          string
+	target       string
+	bound        replicaBound
+	replicas     int
+	since        time.Time
+	observations int
+	limitReason  string
+}
+
+// DetectReplicaPinning finds autoscaled workloads that stayed at their min or max replica bound for the whole window
+func (h *ToolHandlers) DetectReplicaPinning(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "horizontalpodautoscalers",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+	}
+
+	observations := make(map[string][]hpaObservation)
+	for _, event := range events {
+		if event.Verb == "delete" {
+			continue
+		}
+		obs, ok := parseHPAObservation(event)
+		if !ok {
+			continue
+		}
+		key := event.Namespace + "/" + event.ResourceName
+		observations[key] = append(observations[key], obs)
+	}
+
+	if len(observations) == 0 {
+		msg := "No HorizontalPodAutoscaler events found in the specified time range"
+		if namespace != "" {
+			msg += fmt.Sprintf(" for namespace '%s'", namespace)
+		}
+		return mcp.NewToolResultText(msg + "."), nil
+	}
+
+	var atMax, atMin []pinnedWorkload
+	for hpa, obs := range observations {
+		pinned, ok := detectPinning(hpa, obs)
+		if !ok {
+			continue
+		}
+		if pinned.bound == boundMax {
+			atMax = append(atMax, pinned)
+		} else {
+			atMin = append(atMin, pinned)
+		}
+	}
+	sortPinned(atMax)
+	sortPinned(atMin)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Replica Pinning Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(atMax) > 0 {
+		results.WriteString(fmt.Sprintf("🔴 Pinned at Max Replicas: %d\n", len(atMax)))
+		results.WriteString("  (likely under-provisioned: the autoscaler cannot scale beyond maxReplicas)\n")
+		writePinned(&results, atMax, endTime)
+		results.WriteString("\n")
+	}
+
+	if len(atMin) > 0 {
+		results.WriteString(fmt.Sprintf("⚠️  Pinned at Min Replicas: %d\n", len(atMin)))
+		results.WriteString("  (possibly over-provisioned: minReplicas keeps more replicas than the load needs)\n")
+		writePinned(&results, atMin, endTime)
+		results.WriteString("\n")
+	}
+
+	if len(atMax) == 0 && len(atMin) == 0 {
+		results.WriteString("✅ No autoscaled workloads stayed at a replica bound for the whole window.\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal HPAs analyzed: %d\n", len(observations)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// parseHPAObservation reads replica bounds and the current count from a stored HPA
+func parseHPAObservation(event audit.AuditEvent) (hpaObservation, bool) {
+	spec, _ := event.ObjectChanges["spec"].(map[string]any)
+	status, _ := event.ObjectChanges["status"].(map[string]any)
+
+	maxReplicas, ok := spec["maxReplicas"].(float64)
+	if !ok {
+		return hpaObservation{}, false
+	}
+	current, ok := status["currentReplicas"].(float64)
+	if !ok {
+		return hpaObservation{}, false
+	}
+	// minReplicas defaults to 1 when unset
+	minReplicas := 1.0
+	if v, ok := spec["minReplicas"].(float64); ok {
+		minReplicas = v
+	}
+
+	obs := hpaObservation{
+		timestamp: event.Timestamp,
+		current:   int(current),
+		min:       int(minReplicas),
+		max:       int(maxReplicas),
+	}
+
+	if ref, ok := spec["scaleTargetRef"].(map[string]any); ok {
+		kind, _ := ref["kind"].(string)
+		name, _ := ref["name"].(string)
+		obs.target = strings.TrimSpace(kind + " " + name)
+	}
+
+	conditions, _ := status["conditions"].([]any)
+	for _, c := range conditions {
+		condition, _ := c.(map[string]any)
+		if condition["type"] == "ScalingLimited" && condition["status"] == "True" {
+			obs.limitReason, _ = condition["reason"].(string)
+		}
+	}
+
+	return obs, true
+}
+
+// detectPinning reports whether every observation of an HPA sits at the same
+// bound. HPAs with minReplicas equal to maxReplicas are fixed, not pinned.
+func detectPinning(hpa string, observations []hpaObservation) (pinnedWorkload, bool) {
+	if len(observations) == 0 {
+		return pinnedWorkload{}, false
+	}
+
+	sort.Slice(observations, func(i, j int) bool {
+		return observations[i].timestamp.Before(observations[j].timestamp)
+	})
+
+	var bound replicaBound
+	for i, obs := range observations {
+		if obs.min >= obs.max {
+			return pinnedWorkload{}, false
+		}
+
+		var at replicaBound
+		switch obs.current {
+		case obs.max:
+			at = boundMax
+		case obs.min:
+			at = boundMin
+		default:
+			return pinnedWorkload{}, false
+		}
+
+		if i == 0 {
+			bound = at
+		} else if at != bound {
+			return pinnedWorkload{}, false
+		}
+	}
+
+	last := observations[len(observations)-1]
+	return pinnedWorkload{
+		hpa:          hpa,
+		target:       last.target,
+		bound:        bound,
+		replicas:     last.current,
+		since:        observations[0].timestamp,
+		observations: len(observations),
+		limitReason:  last.limitReason,
+	}, true
+}
+
+// sortPinned orders pinned workloads by how long they have been pinned, longest first
+func sortPinned(pinned []pinnedWorkload) {
+	sort.Slice(pinned, func(i, j int) bool {
+		if !pinned[i].since.Equal(pinned[j].since) {
+			return pinned[i].since.Before(pinned[j].since)
+		}
+		return pinned[i].hpa < pinned[j].hpa
+	})
+}
+
+// writePinned renders pinned workloads with how long they stayed at their bound
+func writePinned(results *strings.Builder, pinned []pinnedWorkload, endTime time.Time) {
+	for _, p := range pinned {
+		name := "HPA " + p.hpa
+		if p.target != "" {
+			name += " → " + p.target
+		}
+		results.WriteString(fmt.Sprintf("  - %s: %d replicas (%s) for at least %s across %d observations\n",
+			name, p.replicas, p.bound, formatLifetime(endTime.Sub(p.since)), p.observations))
+		if p.limitReason != "" {
+			results.WriteString(fmt.Sprintf("      ScalingLimited: %s\n", p.limitReason))
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectPinning(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	obs := func(minutes, current, min, max int) hpaObservation {
+		return hpaObservation{timestamp: start.Add(time.Duration(minutes) * time.Minute), current: current, min: min, max: max}
+	}
+
+	tests := []struct {
+		name         string
+		observations []hpaObservation
+		wantPinned   bool
+		wantBound    replicaBound
+	}{
+		{"at max throughout", []hpaObservation{obs(30, 10, 2, 10), obs(0, 10, 2, 10)}, true, boundMax},
+		{"at min throughout", []hpaObservation{obs(0, 2, 2, 10), obs(10, 2, 2, 10)}, true, boundMin},
+		{"scaled in between", []hpaObservation{obs(0, 10, 2, 10), obs(5, 6, 2, 10)}, false, ""},
+		{"flipped bounds", []hpaObservation{obs(0, 10, 2, 10), obs(5, 2, 2, 10)}, false, ""},
+		{"fixed size", []hpaObservation{obs(0, 3, 3, 3)}, false, ""},
+	}
+
+	for _, tt := range tests {
+		pinned, ok := detectPinning("default/web", tt.observations)
+		if ok != tt.wantPinned {
+			t.Errorf("%s: pinned = %v, want %v", tt.name, ok, tt.wantPinned)
+			continue
+		}
+		if ok && pinned.bound != tt.wantBound {
+			t.Errorf("%s: bound = %s, want %s", tt.name, pinned.bound, tt.wantBound)
+		}
+		if ok && !pinned.since.Equal(start) {
+			t.Errorf("%s: since = %s, want earliest observation %s", tt.name, pinned.since, start)
+		}
+	}
+}
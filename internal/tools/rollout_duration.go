@@ -0,0 +1,262 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// rolloutTrendThreshold is the relative change in rollout duration reported as a trend
+const rolloutTrendThreshold = 0.2
+
+// rolloutState is the outcome of a rollout
+type rolloutState string
+
+const (
+	rolloutCompleted  rolloutState = "completed"
+	rolloutSuperseded rolloutState = "superseded"
+	rolloutInProgress rolloutState = "in progress"
+)
+
+// rollout is one pod template change of a Deployment and how it progressed
+type rollout struct {
+	started  time.Time
+	revision string
+	state    rolloutState
+	// finished is when the rollout completed or was superseded
+	finished  time.Time
+	desired   int
+	updated   int
+	available int
+}
+
+// deploymentStatus is the replica state of a stored Deployment
+type deploymentStatus struct {
+	generation         int
+	observedGeneration int
+	desired            int
+	replicas           int
+	updated            int
+	available          int
+}
+
+// complete reports whether the controller has observed the current spec and
+// every replica runs the new template, with no old replicas left
+func (s deploymentStatus) complete() bool {
+	return s.observedGeneration >= s.generation &&
+		s.updated == s.desired && s.available == s.desired && s.replicas == s.desired
+}
+
+// MeasureRolloutDuration reports how long the rollouts of a Deployment took and whether they are getting slower
+func (h *ToolHandlers) MeasureRolloutDuration(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	name, err := request.RequireString("deployment_name")
+	if err != nil {
+		return mcp.NewToolResultError("deployment_name is required"), nil
+	}
+
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return mcp.NewToolResultError("namespace is required"), nil
+	}
+
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "deployments",
+		ResourceName: name,
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+	}
+
+	if len(events) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No events found for Deployment %s/%s in the specified time range.", namespace, name)), nil
+	}
+
+	rollouts := detectRollouts(events)
+	if len(rollouts) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No rollouts of Deployment %s/%s found in the specified time range (%d events analyzed).", namespace, name, len(events))), nil
+	}
+
+	// Stalled rollouts have been running until the end of the window
+	observedUntil := endTime
+	if now := time.Now(); now.Before(observedUntil) {
+		observedUntil = now
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Rollout Duration Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(fmt.Sprintf("Deployment: %s/%s\n", namespace, name))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	var durations []time.Duration
+	results.WriteString(fmt.Sprintf("🚀 Rollouts: %d\n", len(rollouts)))
+	for _, r := range rollouts {
+		label := r.started.Format(time.RFC3339)
+		if r.revision != "" {
+			label += fmt.Sprintf(" (revision %s)", r.revision)
+		}
+
+		switch r.state {
+		case rolloutCompleted:
+			duration := r.finished.Sub(r.started)
+			durations = append(durations, duration)
+			results.WriteString(fmt.Sprintf("  - %s: ✅ completed in %s\n", label, formatLifetime(duration)))
+		case rolloutSuperseded:
+			results.WriteString(fmt.Sprintf("  - %s: ⏭️  superseded by the next rollout after %s\n", label, formatLifetime(r.finished.Sub(r.started))))
+		default:
+			results.WriteString(fmt.Sprintf("  - %s: ⏳ in progress / stalled for %s (updated %d/%d, available %d/%d)\n",
+				label, formatLifetime(observedUntil.Sub(r.started)), r.updated, r.desired, r.available, r.desired))
+		}
+	}
+	results.WriteString("\n")
+
+	results.WriteString(fmt.Sprintf("📈 Trend: %s\n", rolloutTrend(durations)))
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal deployment events analyzed: %d\n", len(events)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// detectRollouts walks a Deployment's events in time order. A rollout starts
+// when the pod template changes and ends at the first observation where the
+// new template is fully rolled out, or when the next rollout starts. The first
+// observation is the baseline; it starts no rollout of its own.
+func detectRollouts(events []audit.AuditEvent) []rollout {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var rollouts []rollout
+	var template map[string]any
+	// current indexes the rollout still in progress, or is -1
+	current := -1
+	for _, event := range sorted {
+		if event.Verb == "delete" {
+			template, current = nil, -1
+			continue
+		}
+
+		spec, _ := event.ObjectChanges["spec"].(map[string]any)
+		next, ok := spec["template"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if template != nil && !reflect.DeepEqual(template, next) {
+			if current >= 0 {
+				rollouts[current].state = rolloutSuperseded
+				rollouts[current].finished = event.Timestamp
+			}
+			rollouts = append(rollouts, rollout{
+				started:  event.Timestamp,
+				revision: deploymentRevision(event),
+				state:    rolloutInProgress,
+			})
+			current = len(rollouts) - 1
+		}
+		template = next
+
+		if current < 0 {
+			continue
+		}
+		r := &rollouts[current]
+		// The controller bumps the revision annotation after the template change
+		if revision := deploymentRevision(event); revision != "" {
+			r.revision = revision
+		}
+		status := parseDeploymentStatus(event)
+		r.desired, r.updated, r.available = status.desired, status.updated, status.available
+		if status.complete() {
+			r.state = rolloutCompleted
+			r.finished = event.Timestamp
+			current = -1
+		}
+	}
+	return rollouts
+}
+
+// parseDeploymentStatus reads the generation and replica counts of a stored Deployment
+func parseDeploymentStatus(event audit.AuditEvent) deploymentStatus {
+	metadata, _ := event.ObjectChanges["metadata"].(map[string]any)
+	spec, _ := event.ObjectChanges["spec"].(map[string]any)
+	status, _ := event.ObjectChanges["status"].(map[string]any)
+
+	number := func(obj map[string]any, key string) int {
+		v, _ := obj[key].(float64)
+		return int(v)
+	}
+
+	// spec.replicas defaults to 1 when unset
+	desired := 1
+	if _, ok := spec["replicas"].(float64); ok {
+		desired = number(spec, "replicas")
+	}
+
+	return deploymentStatus{
+		generation:         number(metadata, "generation"),
+		observedGeneration: number(status, "observedGeneration"),
+		desired:            desired,
+		replicas:           number(status, "replicas"),
+		updated:            number(status, "updatedReplicas"),
+		available:          number(status, "availableReplicas"),
+	}
+}
+
+// deploymentRevision returns the revision annotation the Deployment controller maintains
+func deploymentRevision(event audit.AuditEvent) string {
+	metadata, _ := event.ObjectChanges["metadata"].(map[string]any)
+	annotations, _ := metadata["annotations"].(map[string]any)
+	revision, _ := annotations["deployment.kubernetes.io/revision"].(string)
+	return revision
+}
+
+// rolloutTrend compares the average duration of the older and newer half of
+// completed rollouts
+func rolloutTrend(durations []time.Duration) string {
+	if len(durations) < 2 {
+		return "not enough completed rollouts to compare (need at least 2)"
+	}
+
+	average := func(ds []time.Duration) time.Duration {
+		var total time.Duration
+		for _, d := range ds {
+			total += d
+		}
+		return total / time.Duration(len(ds))
+	}
+
+	half := len(durations) / 2
+	older := average(durations[:half])
+	newer := average(durations[len(durations)-half:])
+
+	if older == 0 {
+		older = time.Second
+	}
+	change := float64(newer-older) / float64(older)
+	switch {
+	case change > rolloutTrendThreshold:
+		return fmt.Sprintf("🔴 slower (average %s, up from %s)", formatLifetime(newer), formatLifetime(older))
+	case change < -rolloutTrendThreshold:
+		return fmt.Sprintf("✅ faster (average %s, down from %s)", formatLifetime(newer), formatLifetime(older))
+	default:
+		return fmt.Sprintf("stable (average %s)", formatLifetime(average(durations)))
+	}
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestDetectRollouts(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	deployment := func(minutes int, image string, generation, observed, updated, available, replicas int) audit.AuditEvent {
+		return audit.AuditEvent{
+			Timestamp: start.Add(time.Duration(minutes) * time.Minute),
+			Verb:      "update",
+			ObjectChanges: map[string]any{
+				"metadata": map[string]any{"generation": float64(generation)},
+				"spec": map[string]any{
+					"replicas": float64(3),
+					"template": map[string]any{"spec": map[string]any{"containers": []any{
+						map[string]any{"name": "app", "image": image},
+					}}},
+				},
+				"status": map[string]any{
+					"observedGeneration": float64(observed),
+					"updatedReplicas":    float64(updated),
+					"availableReplicas":  float64(available),
+					"replicas":           float64(replicas),
+				},
+			},
+		}
+	}
+
+	events := []audit.AuditEvent{
+		deployment(0, "web:v1", 1, 1, 3, 3, 3),
+		// v2 rolls out in 4 minutes
+		deployment(10, "web:v2", 2, 1, 0, 3, 3),
+		deployment(12, "web:v2", 2, 2, 2, 3, 4),
+		deployment(14, "web:v2", 2, 2, 3, 3, 3),
+		// v3 never finishes before v4 replaces it
+		deployment(20, "web:v3", 3, 3, 1, 3, 4),
+		// v4 stalls
+		deployment(25, "web:v4", 4, 4, 1, 2, 4),
+	}
+
+	rollouts := detectRollouts(events)
+	if len(rollouts) != 3 {
+		t.Fatalf("expected 3 rollouts, got %+v", rollouts)
+	}
+
+	if rollouts[0].state != rolloutCompleted || rollouts[0].finished.Sub(rollouts[0].started) != 4*time.Minute {
+		t.Errorf("expected first rollout to complete in 4m, got %+v", rollouts[0])
+	}
+	if rollouts[1].state != rolloutSuperseded || !rollouts[1].finished.Equal(start.Add(25*time.Minute)) {
+		t.Errorf("expected second rollout to be superseded at +25m, got %+v", rollouts[1])
+	}
+	if rollouts[2].state != rolloutInProgress || rollouts[2].updated != 1 || rollouts[2].desired != 3 {
+		t.Errorf("expected third rollout in progress with 1/3 updated, got %+v", rollouts[2])
+	}
+}
+
+func TestRolloutTrend(t *testing.T) {
+	tests := []struct {
+		durations []time.Duration
+		want      string
+	}{
+		{[]time.Duration{time.Minute}, "not enough"},
+		{[]time.Duration{time.Minute, 2 * time.Minute, 5 * time.Minute, 6 * time.Minute}, "slower"},
+		{[]time.Duration{6 * time.Minute, 5 * time.Minute, 2 * time.Minute}, "faster"},
+		{[]time.Duration{2 * time.Minute, 2 * time.Minute}, "stable"},
+	}
+
+	for _, tt := range tests {
+		if got := rolloutTrend(tt.durations); !strings.Contains(got, tt.want) {
+			t.Errorf("rolloutTrend(%v) = %q, want it to contain %q", tt.durations, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// privilegedRoles are roles that grant broad write access on their own
+var privilegedRoles = map[string]bool{"cluster-admin": true, "admin": true, "edit": true}
+
+// serviceAccountGrant is access newly granted to a service account by a binding
+type serviceAccountGrant struct {
+	at      time.Time
+	role    string // e.g. "ClusterRole cluster-admin"
+	binding string // e.g. "RoleBinding default/ci"
+	// clusterWide is set for ClusterRoleBindings
+	clusterWide bool
+	privileged  bool
+}
+
+// serviceAccountActivity collects what happened to one service account
+type serviceAccountActivity struct {
+	key     string // namespace/name
+	created time.Time
+	tokens  []string
+	grants  []serviceAccountGrant
+}
+
+// privileged reports whether any new grant is privileged or cluster-wide
+func (a *serviceAccountActivity) privileged() bool {
+	for _, grant := range a.grants {
+		if grant.privileged || grant.clusterWide {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditServiceAccountChanges reports service accounts created, given tokens or newly bound to roles in a window
+func (h *ToolHandlers) AuditServiceAccountChanges(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	budget := h.newQueryBudget()
+	query := func(resourceType, namespace string) ([]audit.AuditEvent, error) {
+		events, err := budget.query(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Namespace:    namespace,
+			ResourceType: resourceType,
+		})
+		if err != nil && !errors.Is(err, audit.ErrNoData) {
+			return nil, fmt.Errorf("failed to query %s events: %w", resourceType, err)
+		}
+		return events, nil
+	}
+
+	serviceAccounts, err := query("serviceaccounts", namespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	secrets, err := query("secrets", namespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	roleBindings, err := query("rolebindings", namespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	// ClusterRoleBindings are cluster-scoped; their subjects are filtered below
+	clusterRoleBindings, err := query("clusterrolebindings", "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	activities := serviceAccountActivities(serviceAccounts, secrets, append(roleBindings, clusterRoleBindings...), namespace)
+	analyzed := len(serviceAccounts) + len(secrets) + len(roleBindings) + len(clusterRoleBindings)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Service Account Audit (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(activities) == 0 {
+		results.WriteString("✅ No service accounts were created, given tokens or granted new access.\n")
+		if len(serviceAccounts) == 0 && len(roleBindings) == 0 && len(clusterRoleBindings) == 0 {
+			results.WriteString("  (no serviceaccount or binding events at all; check that serviceaccounts, rolebindings and clusterrolebindings are watched)\n")
+		}
+	}
+
+	var privileged, other []*serviceAccountActivity
+	for _, activity := range activities {
+		if activity.privileged() {
+			privileged = append(privileged, activity)
+		} else {
+			other = append(other, activity)
+		}
+	}
+	if len(privileged) > 0 {
+		results.WriteString(fmt.Sprintf("🔴 Newly Privileged Service Accounts: %d\n", len(privileged)))
+		results.WriteString("  (service accounts gaining broad or cluster-wide access are a common lateral-movement vector)\n")
+		writeServiceAccountActivities(&results, privileged)
+		results.WriteString("\n")
+	}
+	if len(other) > 0 {
+		results.WriteString(fmt.Sprintf("📋 Other Service Account Changes: %d\n", len(other)))
+		writeServiceAccountActivities(&results, other)
+		results.WriteString("\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal events analyzed: %d\n", analyzed))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// writeServiceAccountActivities lists each service account with what changed
+func writeServiceAccountActivities(results *strings.Builder, activities []*serviceAccountActivity) {
+	for _, activity := range activities[:min(20, len(activities))] {
+		results.WriteString(fmt.Sprintf("  - ServiceAccount %s\n", activity.key))
+		if !activity.created.IsZero() {
+			results.WriteString(fmt.Sprintf("      Created: %s\n", activity.created.Format(time.RFC3339)))
+		}
+		if len(activity.tokens) > 0 {
+			results.WriteString(fmt.Sprintf("      New token secrets: %s\n", strings.Join(activity.tokens, ", ")))
+		}
+		for _, grant := range activity.grants {
+			scope := ""
+			if grant.clusterWide {
+				scope = " (cluster-wide)"
+			}
+			results.WriteString(fmt.Sprintf("      Granted %s%s via %s at %s\n",
+				grant.role, scope, grant.binding, grant.at.Format(time.RFC3339)))
+		}
+	}
+	if len(activities) > 20 {
+		results.WriteString(fmt.Sprintf("  ... and %d more\n", len(activities)-20))
+	}
+}
+
+// serviceAccountActivities joins service account creations, new token
+// secrets and new binding subjects per service account, sorted by name. Only
+// service accounts in namespace are included when it is set.
+func serviceAccountActivities(serviceAccounts, secrets, bindings []audit.AuditEvent, namespace string) []*serviceAccountActivity {
+	activities := make(map[string]*serviceAccountActivity)
+	activity := func(key string) *serviceAccountActivity {
+		if activities[key] == nil {
+			activities[key] = &serviceAccountActivity{key: key}
+		}
+		return activities[key]
+	}
+
+	for _, event := range serviceAccounts {
+		if event.Verb == "create" {
+			activity(event.Namespace + "/" + event.ResourceName).created = event.Timestamp
+		}
+	}
+
+	for _, event := range secrets {
+		if event.Verb != "create" {
+			continue
+		}
+		if secretType, _ := event.ObjectChanges["type"].(string); secretType != "kubernetes.io/service-account-token" {
+			continue
+		}
+		metadata, _ := event.ObjectChanges["metadata"].(map[string]any)
+		if account := nestedName(metadata, "annotations", "kubernetes.io/service-account.name"); account != "" {
+			a := activity(event.Namespace + "/" + account)
+			a.tokens = append(a.tokens, event.ResourceName)
+		}
+	}
+
+	for _, grant := range newBindingSubjects(bindings) {
+		if namespace == "" || strings.HasPrefix(grant.account, namespace+"/") {
+			a := activity(grant.account)
+			a.grants = append(a.grants, grant.serviceAccountGrant)
+		}
+	}
+
+	keys := make([]string, 0, len(activities))
+	for key := range activities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sorted := make([]*serviceAccountActivity, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, activities[key])
+	}
+	return sorted
+}
+
+// accountGrant is a grant to the service account account (namespace/name)
+type accountGrant struct {
+	serviceAccountGrant
+	account string
+}
+
+// newBindingSubjects returns the service accounts added as subjects of
+// RoleBindings and ClusterRoleBindings, in time order. All subjects of a
+// created binding are new; for updates the subjects are compared with the
+// binding's previous state, taken from the recorded field changes when the
+// binding was not seen before in the window.
+func newBindingSubjects(bindings []audit.AuditEvent) []accountGrant {
+	sorted := make([]audit.AuditEvent, len(bindings))
+	copy(sorted, bindings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var grants []accountGrant
+	previous := make(map[string]map[string]bool)
+	for _, event := range sorted {
+		key := event.ResourceType + "/" + event.Namespace + "/" + event.ResourceName
+		subjects, _ := event.ObjectChanges["subjects"].([]any)
+		current := bindingServiceAccounts(subjects, event.Namespace)
+
+		if event.Verb == "delete" {
+			delete(previous, key)
+			continue
+		}
+
+		before, seen := previous[key]
+		previous[key] = current
+		if !seen && event.Verb != "create" {
+			before = current
+			for _, change := range event.ChangedFields {
+				if change.Path == "subjects" {
+					old, _ := change.Old.([]any)
+					before = bindingServiceAccounts(old, event.Namespace)
+				}
+			}
+		}
+
+		roleRef, _ := event.ObjectChanges["roleRef"].(map[string]any)
+		roleKind, _ := roleRef["kind"].(string)
+		roleName, _ := roleRef["name"].(string)
+		binding := "RoleBinding " + event.Namespace + "/" + event.ResourceName
+		if event.ResourceType == "clusterrolebindings" {
+			binding = "ClusterRoleBinding " + event.ResourceName
+		}
+
+		for _, account := range sortedKeys(current) {
+			if before[account] {
+				continue
+			}
+			grants = append(grants, accountGrant{
+				account: account,
+				serviceAccountGrant: serviceAccountGrant{
+					at:          event.Timestamp,
+					role:        roleKind + " " + roleName,
+					binding:     binding,
+					clusterWide: event.ResourceType == "clusterrolebindings",
+					privileged:  privilegedRoles[roleName],
+				},
+			})
+		}
+	}
+	return grants
+}
+
+// bindingServiceAccounts returns the service accounts (namespace/name) among
+// binding subjects. Subjects without a namespace default to the binding's.
+func bindingServiceAccounts(subjects []any, bindingNamespace string) map[string]bool {
+	accounts := make(map[string]bool)
+	for _, s := range subjects {
+		subject, _ := s.(map[string]any)
+		if kind, _ := subject["kind"].(string); kind != "ServiceAccount" {
+			continue
+		}
+		name, _ := subject["name"].(string)
+		namespace, _ := subject["namespace"].(string)
+		if namespace == "" {
+			namespace = bindingNamespace
+		}
+		accounts[namespace+"/"+name] = true
+	}
+	return accounts
+}
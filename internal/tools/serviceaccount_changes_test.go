@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// bindingEvent returns a stored binding granting roleName to the given
+// service account subjects (namespace/name)
+func bindingEvent(resourceType, namespace, name, verb, roleKind, roleName string, at time.Time, accounts ...[2]string) audit.AuditEvent {
+	var subjects []any
+	for _, account := range accounts {
+		subjects = append(subjects, map[string]any{"kind": "ServiceAccount", "namespace": account[0], "name": account[1]})
+	}
+	return audit.AuditEvent{
+		Timestamp:    at,
+		Verb:         verb,
+		Namespace:    namespace,
+		ResourceType: resourceType,
+		ResourceName: name,
+		ObjectChanges: map[string]any{
+			"roleRef":  map[string]any{"kind": roleKind, "name": roleName},
+			"subjects": subjects,
+		},
+	}
+}
+
+func TestServiceAccountActivities(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	serviceAccounts := []audit.AuditEvent{
+		{Timestamp: start, Verb: "create", Namespace: "ci", ResourceType: "serviceaccounts", ResourceName: "deployer"},
+	}
+	secrets := []audit.AuditEvent{{
+		Timestamp: start.Add(time.Minute), Verb: "create", Namespace: "ci", ResourceType: "secrets", ResourceName: "deployer-token",
+		ObjectChanges: map[string]any{
+			"type":     "kubernetes.io/service-account-token",
+			"metadata": map[string]any{"annotations": map[string]any{"kubernetes.io/service-account.name": "deployer"}},
+		},
+	}}
+
+	existing := bindingEvent("rolebindings", "web", "view", "update", "Role", "reader", start.Add(2*time.Minute), [2]string{"web", "app"})
+	existing.ChangedFields = []audit.FieldChange{{Path: "metadata.labels.team"}}
+	bindings := []audit.AuditEvent{
+		bindingEvent("clusterrolebindings", "", "ci-admin", "create", "ClusterRole", "cluster-admin", start.Add(3*time.Minute), [2]string{"ci", "deployer"}),
+		// Binding already granting app before the window: not new
+		existing,
+		// Subject added by an update
+		bindingEvent("rolebindings", "web", "view", "update", "Role", "reader", start.Add(4*time.Minute), [2]string{"web", "app"}, [2]string{"web", "metrics"}),
+	}
+
+	activities := serviceAccountActivities(serviceAccounts, secrets, bindings, "")
+	if len(activities) != 2 {
+		t.Fatalf("expected 2 service accounts, got %+v", activities)
+	}
+
+	deployer := activities[0]
+	if deployer.key != "ci/deployer" || !deployer.created.Equal(start) {
+		t.Errorf("unexpected deployer activity %+v", deployer)
+	}
+	if len(deployer.tokens) != 1 || deployer.tokens[0] != "deployer-token" {
+		t.Errorf("expected the token secret, got %v", deployer.tokens)
+	}
+	if len(deployer.grants) != 1 || deployer.grants[0].role != "ClusterRole cluster-admin" || !deployer.privileged() {
+		t.Errorf("expected a privileged cluster-admin grant, got %+v", deployer.grants)
+	}
+
+	metrics := activities[1]
+	if metrics.key != "web/metrics" || len(metrics.grants) != 1 || metrics.privileged() {
+		t.Errorf("expected an unprivileged grant for web/metrics, got %+v", metrics)
+	}
+	if metrics.grants[0].binding != "RoleBinding web/view" {
+		t.Errorf("unexpected binding %q", metrics.grants[0].binding)
+	}
+
+	// Cluster-wide grants are filtered by the service account's namespace
+	if filtered := serviceAccountActivities(nil, nil, bindings, "web"); len(filtered) != 1 || filtered[0].key != "web/metrics" {
+		t.Errorf("expected only web/metrics in namespace web, got %+v", filtered)
+	}
+}
+
+func TestNewBindingSubjectsFromChangedFields(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	// The first event in the window is an update; its recorded field
+	// changes show which subjects it added
+	update := bindingEvent("rolebindings", "web", "edit", "update", "ClusterRole", "edit", start, [2]string{"web", "app"}, [2]string{"web", "intruder"})
+	update.ChangedFields = []audit.FieldChange{{
+		Path: "subjects",
+		Old:  []any{map[string]any{"kind": "ServiceAccount", "namespace": "web", "name": "app"}},
+	}}
+
+	grants := newBindingSubjects([]audit.AuditEvent{update})
+	if len(grants) != 1 || grants[0].account != "web/intruder" || !grants[0].privileged {
+		t.Errorf("expected a privileged grant for web/intruder, got %+v", grants)
+	}
+}
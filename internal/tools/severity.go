@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// Severity ranks how urgently an issue needs attention
+type Severity int
+
+const (
+	// SeverityLow is the severity of events that match no signal
+	SeverityLow Severity = iota
+	// SeverityMedium issues degrade a workload without taking it down
+	SeverityMedium
+	// SeverityHigh issues keep a workload from running
+	SeverityHigh
+	// SeverityCritical issues take down workloads or nodes
+	SeverityCritical
+)
+
+// String returns the name of the severity, e.g. "Critical"
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "Critical"
+	case SeverityHigh:
+		return "High"
+	case SeverityMedium:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+// severityRule assigns a severity to events whose text matches a signal
+type severityRule struct {
+	signal   string
+	severity Severity
+	matches  func(text string) bool
+}
+
+// severityRules are checked in order and the first match wins, so rules of
+// higher severity come first. They reuse the category heuristics of the
+// diagnostic tools.
+var severityRules = []severityRule{
+	{"OOMKilled", SeverityCritical, isOOMKilled},
+	{"NotReady", SeverityCritical, isNodeNotReady},
+	{"DiskFull", SeverityCritical, isDiskFull},
+	{"CrashLoopBackOff", SeverityHigh, isCrashLoop},
+	{"ImagePullBackOff", SeverityHigh, isImagePullBackOff},
+	{"MountFailure", SeverityHigh, isMountFailure},
+	{"ResourcePressure", SeverityHigh, isResourcePressure},
+	{"NetworkUnavailable", SeverityHigh, isNetworkUnavailable},
+	{"ProbeFailure", SeverityMedium, isProbeFailure},
+	{"StorageClassError", SeverityMedium, isStorageClassError},
+	{"SecretNotFound", SeverityMedium, isSecretNotFound},
+	{"ReplicaFailure", SeverityMedium, isReplicaFailure},
+	{"CPUThrottling", SeverityMedium, isCPUThrottling},
+}
+
+// ScoreEvent assigns the severity of the first rule of severityRules that
+// matches the event, or SeverityLow when none does
+func ScoreEvent(event audit.AuditEvent) Severity {
+	severity, _ := scoreEvent(event)
+	return severity
+}
+
+// scoreEvent is ScoreEvent, also returning the signal of the matching rule
+func scoreEvent(event audit.AuditEvent) (Severity, string) {
+	text := severityText(event)
+	for _, rule := range severityRules {
+		if rule.matches(text) {
+			return rule.severity, rule.signal
+		}
+	}
+	return SeverityLow, ""
+}
+
+// severityText returns the lowercased parts of an event that describe a
+// problem: its message and annotations, the reason and message of a
+// Kubernetes event, and the waiting and terminated reasons of containers.
+// The rest of the object is left out, a probe definition in a pod spec is not
+// a probe failure.
+func severityText(event audit.AuditEvent) string {
+	parts := []string{event.Message, fmt.Sprintf("%v", event.Annotations)}
+	if event.ResourceType == "events" {
+		reason, _ := event.ObjectChanges["reason"].(string)
+		message, _ := event.ObjectChanges["message"].(string)
+		parts = append(parts, reason, message)
+	}
+
+	status, _ := event.ObjectChanges["status"].(map[string]any)
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _ := status[field].([]any)
+		for _, s := range statuses {
+			containerStatus, _ := s.(map[string]any)
+			for _, stateField := range []string{"state", "lastState"} {
+				state, _ := containerStatus[stateField].(map[string]any)
+				parts = append(parts, nestedName(state, "waiting", "reason"), nestedName(state, "terminated", "reason"))
+			}
+		}
+	}
+	return strings.ToLower(strings.Join(parts, " "))
+}
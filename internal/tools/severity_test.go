@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestScoreEvent(t *testing.T) {
+	tests := []struct {
+		message string
+		want    Severity
+	}{
+		{"Container app was OOMKilled", SeverityCritical},
+		{"Node worker-1 status is now: NodeNotReady", SeverityCritical},
+		{"write /data/db: no space left on device", SeverityCritical},
+		{"Back-off restarting failed container: CrashLoopBackOff", SeverityHigh},
+		{"Failed to pull image: ImagePullBackOff", SeverityHigh},
+		{"MountVolume.SetUp failed for volume data", SeverityHigh},
+		{"Node has MemoryPressure", SeverityHigh},
+		{"Readiness probe failed: connection refused", SeverityMedium},
+		{"secret \"db-password\" not found", SeverityMedium},
+		{"Scaled up replica set web-7d4 to 3", SeverityLow},
+		// The most severe signal wins
+		{"Liveness probe failed, container OOMKilled", SeverityCritical},
+	}
+	for _, tt := range tests {
+		if got := ScoreEvent(audit.AuditEvent{Message: tt.message}); got != tt.want {
+			t.Errorf("ScoreEvent(%q) = %s, want %s", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestScoreEventObject(t *testing.T) {
+	// Kubernetes events are scored by their reason and message
+	if got := ScoreEvent(k8sEvent("Pod", "web-1", "BackOff", "Back-off pulling image: ImagePullBackOff", time.Now())); got != SeverityHigh {
+		t.Errorf("expected a High image pull event, got %s", got)
+	}
+
+	// Pods by the reasons of their container states, not their spec
+	pod := audit.AuditEvent{
+		ResourceType: "pods",
+		ObjectChanges: map[string]any{
+			"spec": map[string]any{"containers": []any{map[string]any{"livenessProbe": map[string]any{}}}},
+		},
+	}
+	if got := ScoreEvent(pod); got != SeverityLow {
+		t.Errorf("expected a probe definition to score Low, got %s", got)
+	}
+	pod.ObjectChanges["status"] = map[string]any{"containerStatuses": []any{map[string]any{
+		"lastState": map[string]any{"terminated": map[string]any{"reason": "OOMKilled"}},
+	}}}
+	if got := ScoreEvent(pod); got != SeverityCritical {
+		t.Errorf("expected an OOMKilled container to score Critical, got %s", got)
+	}
+}
+
+func TestScoreFindings(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	pod := func(name, message string, at time.Time) audit.AuditEvent {
+		return audit.AuditEvent{Timestamp: at, Namespace: "default", ResourceType: "pods", ResourceName: name, Message: message}
+	}
+
+	probe := pod("web-1", "Readiness probe failed", start)
+	oom := pod("web-2", "Container OOMKilled", start.Add(time.Minute))
+	crash := pod("web-3", "CrashLoopBackOff", start.Add(2*time.Minute))
+	crashAgain := pod("web-3", "CrashLoopBackOff", start.Add(3*time.Minute))
+
+	findings := scoreFindings([]issueCategory{
+		{"Probe Failures", []audit.AuditEvent{probe, crash}, podSubject},
+		{"CrashLoopBackOff", []audit.AuditEvent{crash, crashAgain}, podSubject},
+		{"OOMKilled", []audit.AuditEvent{oom}, podSubject},
+	})
+
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %+v", findings)
+	}
+	want := []struct {
+		subject  string
+		severity Severity
+		category string
+		count    int
+	}{
+		{"Pod default/web-2", SeverityCritical, "OOMKilled", 1},
+		{"Pod default/web-3", SeverityHigh, "CrashLoopBackOff", 2},
+		{"Pod default/web-1", SeverityMedium, "Probe Failures", 1},
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.Subject != w.subject || f.severity != w.severity || f.category != w.category || f.Count != w.count {
+			t.Errorf("finding %d: expected %s %s in %s ×%d, got %s %s in %s ×%d", i, w.subject, w.severity, w.category, w.count, f.Subject, f.severity, f.category, f.Count)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// staleConfigQueryLimit caps the update events fetched per config resource type
+const staleConfigQueryLimit = 1000
+
+// configResourceKinds maps the config resource types to their Kind for display
+var configResourceKinds = map[string]string{
+	"configmaps": "ConfigMap",
+	"secrets":    "Secret",
+}
+
+// configRef is a ConfigMap or Secret referenced from a pod spec
+type configRef struct {
+	resourceType string
+	name         string
+	via          string
+}
+
+// configConsumer is a workload whose pods reference an updated config
+type configConsumer struct {
+	workload  string
+	via       map[string]bool
+	restarted bool
+}
+
+// staleConfig is an updated config with at least one consumer that did not restart
+type staleConfig struct {
+	key       string
+	updated   time.Time
+	consumers []*configConsumer
+}
+
+// DetectStaleConfig finds ConfigMaps and Secrets updated in a window whose consuming workloads did not start new pods afterward
+func (h *ToolHandlers) DetectStaleConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	// Last update per config, keyed by "<resourceType>/<namespace>/<name>"
+	lastUpdate := make(map[string]time.Time)
+	var truncated []string
+	for _, resourceType := range []string{"configmaps", "secrets"} {
+		events, err := h.auditClient.QueryEvents(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Namespace:    namespace,
+			ResourceType: resourceType,
+			Verb:         "update",
+			Limit:        staleConfigQueryLimit,
+		})
+		if errors.Is(err, audit.ErrNoData) {
+			continue
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+		}
+		if len(events) >= staleConfigQueryLimit {
+			truncated = append(truncated, resourceType)
+		}
+
+		for _, event := range events {
+			key := configKey(resourceType, event.Namespace, event.ResourceName)
+			if event.Timestamp.After(lastUpdate[key]) {
+				lastUpdate[key] = event.Timestamp
+			}
+		}
+	}
+
+	if len(lastUpdate) == 0 {
+		return mcp.NewToolResultText("No ConfigMap or Secret updates found in the specified time range."), nil
+	}
+
+	budget := h.newQueryBudget()
+	pods, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "pods",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query pod events: %v", err)), nil
+	}
+
+	// Consumers per config key, keyed by workload
+	consumers := make(map[string]map[string]*configConsumer)
+	for _, pod := range pods {
+		created, hasCreated := creationTimestamp(pod)
+		workload := podWorkload(pod)
+
+		for _, ref := range podConfigRefs(pod) {
+			key := configKey(ref.resourceType, pod.Namespace, ref.name)
+			updated, ok := lastUpdate[key]
+			if !ok {
+				continue
+			}
+
+			if consumers[key] == nil {
+				consumers[key] = make(map[string]*configConsumer)
+			}
+			consumer := consumers[key][workload]
+			if consumer == nil {
+				consumer = &configConsumer{workload: workload, via: make(map[string]bool)}
+				consumers[key][workload] = consumer
+			}
+			consumer.via[ref.via] = true
+			if hasCreated && created.After(updated) {
+				consumer.restarted = true
+			}
+		}
+	}
+
+	var stale []staleConfig
+	var unconsumed []string
+	for key, updated := range lastUpdate {
+		if len(consumers[key]) == 0 {
+			unconsumed = append(unconsumed, key)
+			continue
+		}
+
+		entry := staleConfig{key: key, updated: updated}
+		for _, consumer := range consumers[key] {
+			if !consumer.restarted {
+				entry.consumers = append(entry.consumers, consumer)
+			}
+		}
+		if len(entry.consumers) > 0 {
+			sort.Slice(entry.consumers, func(i, j int) bool {
+				return entry.consumers[i].workload < entry.consumers[j].workload
+			})
+			stale = append(stale, entry)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].updated.After(stale[j].updated)
+	})
+	sort.Strings(unconsumed)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Stale Config Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(stale) == 0 {
+		results.WriteString("✅ Every observed consumer started new pods after its config was updated.\n\n")
+	} else {
+		results.WriteString(fmt.Sprintf("🔴 Config Updated Without Rollout: %d\n", len(stale)))
+		results.WriteString("  (environment variables and subPath mounts only change when the pod restarts;\n")
+		results.WriteString("   other volume mounts are refreshed by the kubelet but the app may not reload them)\n")
+		for _, entry := range stale {
+			results.WriteString(fmt.Sprintf("  - %s updated %s\n", formatConfigKey(entry.key), entry.updated.Format(time.RFC3339)))
+			for _, consumer := range entry.consumers {
+				results.WriteString(fmt.Sprintf("      %s (via %s): no pods started since the update\n",
+					consumer.workload, strings.Join(sortedKeys(consumer.via), ", ")))
+			}
+		}
+		results.WriteString("\n")
+	}
+
+	if len(unconsumed) > 0 {
+		results.WriteString(fmt.Sprintf("ℹ️  Updated Without Observed Consumers: %d\n", len(unconsumed)))
+		results.WriteString("  (no pod events in the window reference these)\n")
+		for _, key := range unconsumed[:min(10, len(unconsumed))] {
+			results.WriteString(fmt.Sprintf("  - %s updated %s\n", formatConfigKey(key), lastUpdate[key].Format(time.RFC3339)))
+		}
+		if len(unconsumed) > 10 {
+			results.WriteString(fmt.Sprintf("  ... and %d more\n", len(unconsumed)-10))
+		}
+		results.WriteString("\n")
+	}
+
+	if len(truncated) > 0 {
+		results.WriteString(fmt.Sprintf("⚠️  Results truncated at %d update events for: %s (narrow the time range or namespace)\n",
+			staleConfigQueryLimit, strings.Join(truncated, ", ")))
+	}
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal updated configs analyzed: %d\n", len(lastUpdate)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// configKey identifies a config object as "<resourceType>/<namespace>/<name>"
+func configKey(resourceType, namespace, name string) string {
+	return resourceType + "/" + namespace + "/" + name
+}
+
+// formatConfigKey renders a config key as "ConfigMap namespace/name"
+func formatConfigKey(key string) string {
+	resourceType, rest, _ := strings.Cut(key, "/")
+	return fmt.Sprintf("%s %s", configResourceKinds[resourceType], rest)
+}
+
+// podConfigRefs lists the ConfigMaps and Secrets a stored pod references
+// through env, envFrom, volumes and projected volumes
+func podConfigRefs(event audit.AuditEvent) []configRef {
+	spec, ok := event.ObjectChanges["spec"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var refs []configRef
+	add := func(resourceType, name, via string) {
+		if name != "" {
+			refs = append(refs, configRef{resourceType: resourceType, name: name, via: via})
+		}
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := spec[field].([]any)
+		for _, c := range containers {
+			container, _ := c.(map[string]any)
+
+			envs, _ := container["env"].([]any)
+			for _, e := range envs {
+				env, _ := e.(map[string]any)
+				valueFrom, _ := env["valueFrom"].(map[string]any)
+				add("configmaps", nestedName(valueFrom, "configMapKeyRef", "name"), "env")
+				add("secrets", nestedName(valueFrom, "secretKeyRef", "name"), "env")
+			}
+
+			envFroms, _ := container["envFrom"].([]any)
+			for _, e := range envFroms {
+				envFrom, _ := e.(map[string]any)
+				add("configmaps", nestedName(envFrom, "configMapRef", "name"), "env")
+				add("secrets", nestedName(envFrom, "secretRef", "name"), "env")
+			}
+		}
+	}
+
+	volumes, _ := spec["volumes"].([]any)
+	for _, v := range volumes {
+		volume, _ := v.(map[string]any)
+		add("configmaps", nestedName(volume, "configMap", "name"), "volume")
+		add("secrets", nestedName(volume, "secret", "secretName"), "volume")
+
+		projected, _ := volume["projected"].(map[string]any)
+		sources, _ := projected["sources"].([]any)
+		for _, s := range sources {
+			source, _ := s.(map[string]any)
+			add("configmaps", nestedName(source, "configMap", "name"), "volume")
+			add("secrets", nestedName(source, "secret", "name"), "volume")
+		}
+	}
+
+	return refs
+}
+
+// nestedName returns obj[field][key] as a string, or "" when absent
+func nestedName(obj map[string]any, field, key string) string {
+	nested, _ := obj[field].(map[string]any)
+	name, _ := nested[key].(string)
+	return name
+}
+
+// podWorkload names the workload that owns a stored pod. Pods of a
+// Deployment's ReplicaSets are attributed to the Deployment, so a rollout
+// that creates a new ReplicaSet still counts for the same workload.
+func podWorkload(event audit.AuditEvent) string {
+	metadata, _ := event.ObjectChanges["metadata"].(map[string]any)
+	refs, _ := metadata["ownerReferences"].([]any)
+	if len(refs) == 0 {
+		return fmt.Sprintf("Pod %s/%s", event.Namespace, event.ResourceName)
+	}
+
+	owner, _ := refs[0].(map[string]any)
+	kind, _ := owner["kind"].(string)
+	name, _ := owner["name"].(string)
+
+	labels, _ := metadata["labels"].(map[string]any)
+	if hash, _ := labels["pod-template-hash"].(string); kind == "ReplicaSet" && hash != "" {
+		if deployment, ok := strings.CutSuffix(name, "-"+hash); ok {
+			return fmt.Sprintf("Deployment %s/%s", event.Namespace, deployment)
+		}
+	}
+
+	return fmt.Sprintf("%s %s/%s", kind, event.Namespace, name)
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestPodConfigRefs(t *testing.T) {
+	pod := audit.AuditEvent{ObjectChanges: map[string]any{
+		"spec": map[string]any{
+			"containers": []any{
+				map[string]any{
+					"env": []any{
+						map[string]any{"name": "MODE", "valueFrom": map[string]any{
+							"configMapKeyRef": map[string]any{"name": "app-config", "key": "mode"},
+						}},
+						map[string]any{"name": "PLAIN", "value": "x"},
+					},
+					"envFrom": []any{
+						map[string]any{"secretRef": map[string]any{"name": "app-creds"}},
+					},
+				},
+			},
+			"volumes": []any{
+				map[string]any{"name": "tls", "secret": map[string]any{"secretName": "app-tls"}},
+				map[string]any{"name": "all", "projected": map[string]any{"sources": []any{
+					map[string]any{"configMap": map[string]any{"name": "bundle"}},
+				}}},
+			},
+		},
+	}}
+
+	want := []configRef{
+		{resourceType: "configmaps", name: "app-config", via: "env"},
+		{resourceType: "secrets", name: "app-creds", via: "env"},
+		{resourceType: "secrets", name: "app-tls", via: "volume"},
+		{resourceType: "configmaps", name: "bundle", via: "volume"},
+	}
+	if got := podConfigRefs(pod); !reflect.DeepEqual(got, want) {
+		t.Errorf("podConfigRefs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPodWorkload(t *testing.T) {
+	owned := func(kind, name string, labels map[string]any) audit.AuditEvent {
+		return audit.AuditEvent{Namespace: "default", ResourceName: "web-5d8f-abcde", ObjectChanges: map[string]any{
+			"metadata": map[string]any{
+				"labels":          labels,
+				"ownerReferences": []any{map[string]any{"kind": kind, "name": name}},
+			},
+		}}
+	}
+
+	tests := []struct {
+		name  string
+		event audit.AuditEvent
+		want  string
+	}{
+		{"deployment", owned("ReplicaSet", "web-5d8f", map[string]any{"pod-template-hash": "5d8f"}), "Deployment default/web"},
+		{"bare replicaset", owned("ReplicaSet", "web", nil), "ReplicaSet default/web"},
+		{"statefulset", owned("StatefulSet", "db", nil), "StatefulSet default/db"},
+		{"unowned", audit.AuditEvent{Namespace: "default", ResourceName: "debug"}, "Pod default/debug"},
+	}
+
+	for _, tt := range tests {
+		if got := podWorkload(tt.event); got != tt.want {
+			t.Errorf("%s: podWorkload() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
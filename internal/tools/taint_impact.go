@@ -0,0 +1,248 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// taintCorrelationWindow is how long after a taint change pod evictions and
+// scheduling failures are attributed to it
+const taintCorrelationWindow = 10 * time.Minute
+
+// taint is a node taint as stored in spec.taints
+type taint struct {
+	Key    string
+	Value  string
+	Effect string
+}
+
+// String renders the taint as key[=value]:effect
+func (t taint) String() string {
+	if t.Value == "" {
+		return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+}
+
+// taintChange is a taint added to or removed from a node
+type taintChange struct {
+	node      string
+	taint     taint
+	added     bool
+	timestamp time.Time
+}
+
+// AnalyzeTaintImpact correlates node taint changes with pod evictions and scheduling failures that followed them
+func (h *ToolHandlers) AnalyzeTaintImpact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	budget := h.newQueryBudget()
+	nodeEvents, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		ResourceType: "nodes",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query node events: %v", err)), nil
+	}
+
+	changes := detectTaintChanges(nodeEvents)
+	if len(changes) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No node taint changes found in the specified time range (%d node events analyzed).", len(nodeEvents))), nil
+	}
+
+	// Effects can trail the last taint change by up to the correlation window
+	correlationEnd := endTime.Add(taintCorrelationWindow)
+
+	podDeletes, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      correlationEnd,
+		ResourceType: "pods",
+		Verb:         "delete",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query pod events: %v", err)), nil
+	}
+
+	k8sEvents, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      correlationEnd,
+		ResourceType: "events",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query Kubernetes events: %v", err)), nil
+	}
+	var schedulingFailures []audit.AuditEvent
+	for _, event := range k8sEvents {
+		if reason, _ := event.ObjectChanges["reason"].(string); reason == "FailedScheduling" {
+			schedulingFailures = append(schedulingFailures, event)
+		}
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Taint Impact Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	results.WriteString(fmt.Sprintf("🏷️  Taint Changes: %d\n", len(changes)))
+	impacted := 0
+	for _, change := range changes {
+		action := "tainted"
+		if !change.added {
+			action = "untainted"
+		}
+		results.WriteString(fmt.Sprintf("  - %s: node %s %s %s\n",
+			change.timestamp.Format(time.RFC3339), change.node, action, change.taint))
+
+		if !change.added {
+			continue
+		}
+
+		evicted := evictedPods(change, podDeletes)
+		if len(evicted) > 0 {
+			impacted++
+			results.WriteString(fmt.Sprintf("      🔴 Pods deleted from %s within %s: %d (%s)\n",
+				change.node, taintCorrelationWindow, len(evicted), strings.Join(evicted, ", ")))
+		}
+
+		failures := taintSchedulingFailures(change, schedulingFailures)
+		if len(failures) > 0 {
+			impacted++
+			results.WriteString(fmt.Sprintf("      ⚠️  Scheduling failures citing %s within %s: %d\n",
+				change.taint.Key, taintCorrelationWindow, len(failures)))
+			for _, f := range failures[:min(3, len(failures))] {
+				results.WriteString(fmt.Sprintf("        %s\n", f))
+			}
+		}
+	}
+	results.WriteString("\n")
+
+	if impacted == 0 {
+		results.WriteString("✅ No pod evictions or scheduling failures followed the taint changes.\n")
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal node events analyzed: %d\n", len(nodeEvents)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// nodeTaints returns spec.taints from a stored node
+func nodeTaints(event audit.AuditEvent) []taint {
+	spec, _ := event.ObjectChanges["spec"].(map[string]any)
+	raw, _ := spec["taints"].([]any)
+
+	var taints []taint
+	for _, r := range raw {
+		t, _ := r.(map[string]any)
+		key, _ := t["key"].(string)
+		value, _ := t["value"].(string)
+		effect, _ := t["effect"].(string)
+		if key != "" {
+			taints = append(taints, taint{Key: key, Value: value, Effect: effect})
+		}
+	}
+	return taints
+}
+
+// detectTaintChanges compares consecutive observations of each node and
+// returns the taints added or removed, in time order. The first observation of
+// a node is its baseline, so taints it already carried are not reported.
+func detectTaintChanges(events []audit.AuditEvent) []taintChange {
+	sorted := make([]audit.AuditEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	previous := make(map[string]map[taint]bool)
+	var changes []taintChange
+	for _, event := range sorted {
+		if event.Verb == "delete" {
+			delete(previous, event.ResourceName)
+			continue
+		}
+
+		current := make(map[taint]bool)
+		for _, t := range nodeTaints(event) {
+			current[t] = true
+		}
+
+		before, seen := previous[event.ResourceName]
+		previous[event.ResourceName] = current
+		if !seen {
+			continue
+		}
+
+		for t := range current {
+			if !before[t] {
+				changes = append(changes, taintChange{node: event.ResourceName, taint: t, added: true, timestamp: event.Timestamp})
+			}
+		}
+		for t := range before {
+			if !current[t] {
+				changes = append(changes, taintChange{node: event.ResourceName, taint: t, added: false, timestamp: event.Timestamp})
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].timestamp.Equal(changes[j].timestamp) {
+			return changes[i].timestamp.Before(changes[j].timestamp)
+		}
+		return changes[i].taint.String() < changes[j].taint.String()
+	})
+	return changes
+}
+
+// withinCorrelation reports whether t falls in the correlation window after a change
+func withinCorrelation(change taintChange, t time.Time) bool {
+	return !t.Before(change.timestamp) && !t.After(change.timestamp.Add(taintCorrelationWindow))
+}
+
+// evictedPods lists pods deleted from the tainted node shortly after a NoExecute taint was added
+func evictedPods(change taintChange, podDeletes []audit.AuditEvent) []string {
+	if change.taint.Effect != "NoExecute" {
+		return nil
+	}
+
+	var pods []string
+	for _, pod := range podDeletes {
+		spec, _ := pod.ObjectChanges["spec"].(map[string]any)
+		if nodeName, _ := spec["nodeName"].(string); nodeName != change.node {
+			continue
+		}
+		if withinCorrelation(change, pod.Timestamp) {
+			pods = append(pods, pod.Namespace+"/"+pod.ResourceName)
+		}
+	}
+	return pods
+}
+
+// taintSchedulingFailures lists FailedScheduling messages that mention the
+// added taint's key shortly after it was added
+func taintSchedulingFailures(change taintChange, failures []audit.AuditEvent) []string {
+	var matched []string
+	for _, event := range failures {
+		message, _ := event.ObjectChanges["message"].(string)
+		if !strings.Contains(message, change.taint.Key) || !withinCorrelation(change, event.Timestamp) {
+			continue
+		}
+
+		involved, _ := event.ObjectChanges["involvedObject"].(map[string]any)
+		name, _ := involved["name"].(string)
+		matched = append(matched, fmt.Sprintf("%s: Pod %s/%s - %s",
+			event.Timestamp.Format(time.RFC3339), event.Namespace, name, message))
+	}
+	return matched
+}
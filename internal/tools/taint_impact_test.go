@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// nodeEvent builds a stored node update carrying the given taints
+func nodeEvent(name string, at time.Time, taints ...map[string]any) audit.AuditEvent {
+	raw := make([]any, 0, len(taints))
+	for _, t := range taints {
+		raw = append(raw, t)
+	}
+	return audit.AuditEvent{
+		Timestamp:     at,
+		Verb:          "update",
+		ResourceType:  "nodes",
+		ResourceName:  name,
+		ObjectChanges: map[string]any{"spec": map[string]any{"taints": raw}},
+	}
+}
+
+func TestDetectTaintChanges(t *testing.T) {
+	start := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	existing := map[string]any{"key": "dedicated", "value": "gpu", "effect": "NoSchedule"}
+	unreachable := map[string]any{"key": "node.kubernetes.io/unreachable", "effect": "NoExecute"}
+
+	events := []audit.AuditEvent{
+		nodeEvent("node-1", start.Add(10*time.Minute), existing),
+		nodeEvent("node-1", start, existing),
+		nodeEvent("node-1", start.Add(5*time.Minute), existing, unreachable),
+		// A node first seen with a taint is a baseline, not a change
+		nodeEvent("node-2", start, unreachable),
+	}
+
+	changes := detectTaintChanges(events)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+
+	added, removed := changes[0], changes[1]
+	if !added.added || added.node != "node-1" || added.taint.String() != "node.kubernetes.io/unreachable:NoExecute" ||
+		!added.timestamp.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("unexpected added change: %+v", added)
+	}
+	if removed.added || removed.taint.Key != "node.kubernetes.io/unreachable" || !removed.timestamp.Equal(start.Add(10*time.Minute)) {
+		t.Errorf("unexpected removed change: %+v", removed)
+	}
+}
+
+func TestEvictedPods(t *testing.T) {
+	at := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	change := taintChange{node: "node-1", taint: taint{Key: "maintenance", Effect: "NoExecute"}, added: true, timestamp: at}
+
+	pod := func(name, node string, offset time.Duration) audit.AuditEvent {
+		return audit.AuditEvent{
+			Timestamp:     at.Add(offset),
+			Namespace:     "default",
+			ResourceName:  name,
+			ObjectChanges: map[string]any{"spec": map[string]any{"nodeName": node}},
+		}
+	}
+	deletes := []audit.AuditEvent{
+		pod("a", "node-1", time.Minute),
+		pod("b", "node-2", time.Minute),
+		pod("c", "node-1", -time.Minute),
+		pod("d", "node-1", time.Hour),
+	}
+
+	if got := evictedPods(change, deletes); len(got) != 1 || got[0] != "default/a" {
+		t.Errorf("evictedPods() = %v, want [default/a]", got)
+	}
+
+	change.taint.Effect = "NoSchedule"
+	if got := evictedPods(change, deletes); len(got) != 0 {
+		t.Errorf("NoSchedule taints should not evict, got %v", got)
+	}
+}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unassignedTeam groups the namespaces missing from the team mapping
+const unassignedTeam = "(unassigned)"
+
+// TeamMapping maps namespaces to the team owning them
+type TeamMapping map[string]string
+
+// teamMappingFile is the on-disk format of a team mapping, listing the
+// namespaces of each team:
+//
+//	teams:
+//	  payments: [payments, payments-staging]
+//	  platform: [kube-system, monitoring]
+type teamMappingFile struct {
+	Teams map[string][]string `yaml:"teams"`
+}
+
+// LoadTeamMapping reads a team mapping from a YAML file. A namespace listed
+// under more than one team is an error.
+func LoadTeamMapping(path string) (TeamMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read team mapping: %w", err)
+	}
+
+	var file teamMappingFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse team mapping YAML: %w", err)
+	}
+
+	mapping := make(TeamMapping)
+	for team, namespaces := range file.Teams {
+		for _, namespace := range namespaces {
+			if owner, ok := mapping[namespace]; ok && owner != team {
+				return nil, fmt.Errorf("namespace %q is mapped to both %q and %q", namespace, owner, team)
+			}
+			mapping[namespace] = team
+		}
+	}
+	return mapping, nil
+}
+
+// Team returns the team owning namespace, or "(unassigned)"
+func (m TeamMapping) Team(namespace string) string {
+	if team, ok := m[namespace]; ok {
+		return team
+	}
+	return unassignedTeam
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// uncategorizedReason groups the unmatched warnings sharing a reason
+type uncategorizedReason struct {
+	reason  string
+	events  int
+	objects map[string]bool
+	latest  audit.AuditEvent
+}
+
+// ShowUncategorizedEvents lists warning events that none of the diagnostic tools' category heuristics match
+func (h *ToolHandlers) ShowUncategorizedEvents(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	budget := h.newQueryBudget()
+	events, err := budget.query(ctx, audit.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespace:    namespace,
+		ResourceType: "events",
+	})
+	if err != nil && !errors.Is(err, audit.ErrNoData) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query Kubernetes events: %v", err)), nil
+	}
+
+	var warnings []audit.AuditEvent
+	for _, event := range events {
+		if eventType, _ := event.ObjectChanges["type"].(string); eventType == "Warning" {
+			warnings = append(warnings, event)
+		}
+	}
+
+	if len(warnings) == 0 {
+		msg := "No warning events found in the specified time range"
+		if namespace != "" {
+			msg += fmt.Sprintf(" for namespace '%s'", namespace)
+		}
+		return mcp.NewToolResultText(msg + "."), nil
+	}
+
+	reasons, unmatched := groupUncategorized(warnings)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Uncategorized Events (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(reasons) == 0 {
+		results.WriteString("✅ Every warning event matched a category of the diagnostic tools.\n")
+	} else {
+		results.WriteString(fmt.Sprintf("❓ Uncategorized Warnings: %d events, %d reasons\n", unmatched, len(reasons)))
+		results.WriteString("  (no diagnostic tool recognizes these messages, so they never show up in their reports)\n")
+		for _, r := range reasons[:min(20, len(reasons))] {
+			results.WriteString(fmt.Sprintf("  - %s ×%d on %d objects, last at %s\n",
+				r.reason, r.events, len(r.objects), r.latest.Timestamp.Format(time.RFC3339)))
+			message, _ := r.latest.ObjectChanges["message"].(string)
+			results.WriteString(fmt.Sprintf("      e.g. %s: %s\n", involvedObject(r.latest), message))
+		}
+		if len(reasons) > 20 {
+			results.WriteString(fmt.Sprintf("  ... and %d more reasons\n", len(reasons)-20))
+		}
+	}
+
+	budget.writeNotices(&results)
+
+	results.WriteString(fmt.Sprintf("\nTotal warning events analyzed: %d\n", len(warnings)))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// groupUncategorized groups the warnings no category heuristic matches by
+// reason, most frequent first, and returns how many warnings were unmatched
+func groupUncategorized(warnings []audit.AuditEvent) ([]*uncategorizedReason, int) {
+	byReason := make(map[string]*uncategorizedReason)
+	unmatched := 0
+	for _, event := range warnings {
+		reason, _ := event.ObjectChanges["reason"].(string)
+		message, _ := event.ObjectChanges["message"].(string)
+		if matchesKnownCategory(strings.ToLower(reason + " " + message + " " + event.Message)) {
+			continue
+		}
+		unmatched++
+
+		if reason == "" {
+			reason = "(no reason)"
+		}
+		group := byReason[reason]
+		if group == nil {
+			group = &uncategorizedReason{reason: reason, objects: make(map[string]bool)}
+			byReason[reason] = group
+		}
+		group.events++
+		group.objects[involvedObject(event)] = true
+		if !event.Timestamp.Before(group.latest.Timestamp) {
+			group.latest = event
+		}
+	}
+
+	reasons := make([]*uncategorizedReason, 0, len(byReason))
+	for _, group := range byReason {
+		reasons = append(reasons, group)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].events != reasons[j].events {
+			return reasons[i].events > reasons[j].events
+		}
+		return reasons[i].reason < reasons[j].reason
+	})
+	return reasons, unmatched
+}
+
+// involvedObject renders the object a Kubernetes event is about as "Kind namespace/name"
+func involvedObject(event audit.AuditEvent) string {
+	involved, _ := event.ObjectChanges["involvedObject"].(map[string]any)
+	kind, _ := involved["kind"].(string)
+	namespace, _ := involved["namespace"].(string)
+	name, _ := involved["name"].(string)
+	if namespace == "" {
+		return strings.TrimSpace(kind + " " + name)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s/%s", kind, namespace, name))
+}
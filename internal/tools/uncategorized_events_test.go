@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+func TestGroupUncategorized(t *testing.T) {
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	warning := func(minutes int, name, reason, message string) audit.AuditEvent {
+		return audit.AuditEvent{
+			Timestamp: start.Add(time.Duration(minutes) * time.Minute),
+			ObjectChanges: map[string]any{
+				"type":    "Warning",
+				"reason":  reason,
+				"message": message,
+				"involvedObject": map[string]any{
+					"kind": "Pod", "namespace": "default", "name": name,
+				},
+			},
+		}
+	}
+
+	warnings := []audit.AuditEvent{
+		warning(0, "web-1", "BackOff", "Back-off restarting failed container app (CrashLoopBackOff)"),
+		warning(1, "web-1", "FailedScheduling", "0/3 nodes are available: 3 Insufficient cpu"),
+		warning(2, "job-1", "DeadlineExceeded", "Job was active longer than specified deadline"),
+		warning(3, "web-2", "FailedCreatePodSandBox", "rpc error: code = Unknown desc = sandbox creation timed out"),
+		warning(4, "web-3", "FailedCreatePodSandBox", "rpc error: code = Unknown desc = sandbox creation timed out"),
+	}
+
+	reasons, unmatched := groupUncategorized(warnings)
+	if unmatched != 3 {
+		t.Errorf("expected 3 unmatched warnings, got %d", unmatched)
+	}
+	if len(reasons) != 2 {
+		t.Fatalf("expected 2 uncategorized reasons, got %d", len(reasons))
+	}
+
+	top := reasons[0]
+	if top.reason != "FailedCreatePodSandBox" || top.events != 2 || len(top.objects) != 2 {
+		t.Errorf("unexpected top reason %+v", top)
+	}
+	if !top.latest.Timestamp.Equal(start.Add(4 * time.Minute)) {
+		t.Errorf("expected the latest event to be kept, got %s", top.latest.Timestamp)
+	}
+	if reasons[1].reason != "DeadlineExceeded" {
+		t.Errorf("expected DeadlineExceeded, got %s", reasons[1].reason)
+	}
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+)
+
+// controllerManagedTypes are resource types that are normally created by a controller
+var controllerManagedTypes = []string{"pods", "replicasets"}
+
+// unmanagedQueryLimit caps the create events fetched per resource type
+const unmanagedQueryLimit = 1000
+
+// DetectUnmanagedResources finds controller-managed kinds created without owner references
+func (h *ToolHandlers) DetectUnmanagedResources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime, endTime, err := parseTimeRange(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	namespace := request.GetString("namespace", "")
+
+	var unmanaged []audit.AuditEvent
+	var truncated []string
+	analyzed := 0
+	for _, resourceType := range controllerManagedTypes {
+		events, err := h.auditClient.QueryEvents(ctx, audit.QueryOptions{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Namespace:    namespace,
+			ResourceType: resourceType,
+			Verb:         "create",
+			Limit:        unmanagedQueryLimit,
+		})
+		if errors.Is(err, audit.ErrNoData) {
+			// No data for this type is not fatal
+			continue
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query audit logs: %v", err)), nil
+		}
+		if len(events) >= unmanagedQueryLimit {
+			truncated = append(truncated, resourceType)
+		}
+
+		for _, event := range events {
+			// The informer's initial list replays existing objects as creates,
+			// so only count objects actually created inside the window
+			if !createdWithin(event, startTime, endTime) {
+				continue
+			}
+			analyzed++
+			if !hasOwnerReferences(event) {
+				unmanaged = append(unmanaged, event)
+			}
+		}
+	}
+
+	if analyzed == 0 {
+		return mcp.NewToolResultText("No create events for pods or replicasets found in the specified time range."), nil
+	}
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("Unmanaged Resources Analysis (%s to %s)\n", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	if namespace != "" {
+		results.WriteString(fmt.Sprintf("Namespace: %s\n", namespace))
+	}
+	results.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	if len(unmanaged) == 0 {
+		results.WriteString("✅ All created pods and replicasets have owner references.\n")
+	} else {
+		results.WriteString(fmt.Sprintf("⚠️  Created without owner references: %d objects\n", len(unmanaged)))
+		results.WriteString("  (likely manual kubectl changes that bypass GitOps)\n")
+		results.WriteString("  (the creating user is not available from watch events)\n")
+		for _, event := range unmanaged {
+			results.WriteString(fmt.Sprintf("  - %s: %s %s/%s\n",
+				event.Timestamp.Format(time.RFC3339), event.ResourceType, event.Namespace, event.ResourceName))
+		}
+		results.WriteString("\n")
+	}
+
+	if len(truncated) > 0 {
+		results.WriteString(fmt.Sprintf("⚠️  Results truncated at %d create events for: %s (narrow the time range or namespace)\n",
+			unmanagedQueryLimit, strings.Join(truncated, ", ")))
+	}
+
+	results.WriteString(fmt.Sprintf("\nTotal create events analyzed: %d\n", analyzed))
+
+	return mcp.NewToolResultText(results.String()), nil
+}
+
+// hasOwnerReferences reports whether the stored object carries metadata.ownerReferences
+func hasOwnerReferences(event audit.AuditEvent) bool {
+	metadata, ok := event.ObjectChanges["metadata"].(map[string]any)
+	if !ok {
+		return false
+	}
+	refs, ok := metadata["ownerReferences"].([]any)
+	return ok && len(refs) > 0
+}
+
+// createdWithin reports whether metadata.creationTimestamp falls inside the window.
+// Objects without a parseable creationTimestamp are kept.
+func createdWithin(event audit.AuditEvent, startTime, endTime time.Time) bool {
+	created, ok := creationTimestamp(event)
+	if !ok {
+		return true
+	}
+	return !created.Before(startTime) && !created.After(endTime)
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// minGzipBytes is the response size below which compressing isn't worth it
+const minGzipBytes = 1024
+
+// gzipResponses compresses responses of at least minGzipBytes for clients
+// that accept gzip. Server-sent event streams are passed through as is.
+func gzipResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		defer gw.close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.EqualFold(strings.TrimSpace(name), "gzip") && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response until it is known to
+// reach minGzipBytes, then compresses it. Smaller responses are sent as
+// they are.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buf         []byte
+	// decided is set once the response is either compressed (gz) or sent
+	// as is
+	decided bool
+	gz      *gzip.Writer
+}
+
+// WriteHeader implements http.ResponseWriter. The status is held back until
+// the encoding is decided.
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	if w.decided {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// Write implements http.ResponseWriter
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		if w.Header().Get("Content-Encoding") != "" || strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+			if err := w.passThrough(); err != nil {
+				return 0, err
+			}
+		} else {
+			w.buf = append(w.buf, p...)
+			if len(w.buf) < minGzipBytes {
+				return len(p), nil
+			}
+			if err := w.compress(); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// compress sends the headers of a compressed response and the buffered
+// start of the body
+func (w *gzipResponseWriter) compress() error {
+	w.decided = true
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Encoding", "gzip")
+	w.ResponseWriter.WriteHeader(w.status)
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	_, err := w.gz.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// passThrough sends the headers and buffered body of an uncompressed
+// response
+func (w *gzipResponseWriter) passThrough() error {
+	w.decided = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// FlushError flushes the response for http.ResponseController. A response
+// flushed before reaching minGzipBytes is sent uncompressed.
+func (w *gzipResponseWriter) FlushError() error {
+	if !w.decided {
+		if err := w.passThrough(); err != nil {
+			return err
+		}
+	}
+	if w.gz != nil {
+		if err := w.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Flush implements http.Flusher
+func (w *gzipResponseWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close completes the response once the handler returned
+func (w *gzipResponseWriter) close() {
+	if !w.decided {
+		// Nothing written and no status set: leave the default response
+		// to the server
+		if !w.wroteHeader && len(w.buf) == 0 {
+			return
+		}
+		_ = w.passThrough()
+		return
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
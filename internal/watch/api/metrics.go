@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/moritz/mcp-toolkit/internal/watch/storage"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// storageCollector exports Store.Metrics as Prometheus gauges on each scrape
+type storageCollector struct {
+	store *storage.Store
+
+	lsmSize        *prometheus.Desc
+	vlogSize       *prometheus.Desc
+	pendingGCBytes *prometheus.Desc
+}
+
+// newStorageCollector creates a collector for the given store
+func newStorageCollector(store *storage.Store) *storageCollector {
+	return &storageCollector{
+		store:          store,
+		lsmSize:        prometheus.NewDesc("watch_store_lsm_size_bytes", "On-disk size of the BadgerDB LSM tree.", nil, nil),
+		vlogSize:       prometheus.NewDesc("watch_store_vlog_size_bytes", "On-disk size of the BadgerDB value log.", nil, nil),
+		pendingGCBytes: prometheus.NewDesc("watch_store_pending_gc_bytes", "Estimated stale LSM data not yet reclaimed by compaction.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *storageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lsmSize
+	ch <- c.vlogSize
+	ch <- c.pendingGCBytes
+}
+
+// Collect implements prometheus.Collector
+func (c *storageCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics := c.store.Metrics()
+	ch <- prometheus.MustNewConstMetric(c.lsmSize, prometheus.GaugeValue, float64(metrics.LSMSize))
+	ch <- prometheus.MustNewConstMetric(c.vlogSize, prometheus.GaugeValue, float64(metrics.VLogSize))
+	ch <- prometheus.MustNewConstMetric(c.pendingGCBytes, prometheus.GaugeValue, float64(metrics.PendingGCBytes))
+}
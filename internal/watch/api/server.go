@@ -1,33 +1,85 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/moritz/mcp-toolkit/internal/watch/models"
 	"github.com/moritz/mcp-toolkit/internal/watch/storage"
+	"github.com/moritz/mcp-toolkit/internal/watch/watchers"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // Server provides the REST API for querying watch events
 type Server struct {
-	store    *storage.Store
-	maxLimit int
-	router   *chi.Mux
+	store      *storage.Store
+	watched    WatchedLister
+	events     EventSubscriber
+	kinds      KindResolver
+	dead       DeadLetterLister
+	maxLimit   int
+	maxBytes   int64
+	adminToken string
+	authTokens []string
+	router     *chi.Mux
+	registry   *prometheus.Registry
+	synced     *atomic.Bool
 }
 
-// NewServer creates a new API server
-func NewServer(store *storage.Store, maxLimit int) *Server {
+// WatchedLister reports the resource types that currently have active watchers
+type WatchedLister interface {
+	WatchedResources() []watchers.WatchedResource
+}
+
+// EventSubscriber streams events as they are stored
+type EventSubscriber interface {
+	Subscribe(buffer int, match func(*models.AuditEvent) bool) *watchers.Subscription
+}
+
+// DeadLetterLister lists the events whose store failed and that wait to be
+// retried
+type DeadLetterLister interface {
+	DeadLetters() []watchers.DeadLetter
+}
+
+// KindResolver converts resource types to Kinds, e.g. pods to Pod
+type KindResolver interface {
+	Kind(resourceType string) string
+}
+
+// NewServer creates a new API server. watched and events may be nil when no
+// watchers run, e.g. in read-only mode. When authTokens is non-empty, every
+// endpoint but the health probes requires one of them as bearer token. /metrics serves
+// registry, which the store and watchers register with as well; nil serves
+// only the storage gauges.
+func NewServer(store *storage.Store, watched WatchedLister, events EventSubscriber, maxLimit int, adminToken string, authTokens []string, registry *prometheus.Registry) *Server {
+	if registry == nil {
+		registry = prometheus.NewRegistry()
+	}
 	s := &Server{
-		store:    store,
-		maxLimit: maxLimit,
-		router:   chi.NewRouter(),
+		store:      store,
+		watched:    watched,
+		events:     events,
+		maxLimit:   maxLimit,
+		adminToken: adminToken,
+		authTokens: authTokens,
+		router:     chi.NewRouter(),
+		registry:   registry,
 	}
+	s.registry.MustRegister(newStorageCollector(store))
 
 	s.setupRoutes()
 	return s
@@ -38,10 +90,68 @@ func (s *Server) setupRoutes() {
 	s.router.Use(middleware.Logger)
 	s.router.Use(middleware.Recoverer)
 	s.router.Use(middleware.RequestID)
+	s.router.Use(gzipResponses)
+	s.router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeJSONError(w, http.StatusNotFound, errorCodeNotFound, "no such endpoint")
+	})
+	s.router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeJSONError(w, http.StatusMethodNotAllowed, errorCodeBadRequest, fmt.Sprintf("method %s not allowed", r.Method))
+	})
 
-	s.router.Get("/api/v1/events", s.handleQueryEvents)
-	s.router.Get("/api/v1/events/{namespace}/{resourceType}/{name}", s.handleObjectHistory)
+	// Probes don't carry tokens. /health predates the split into liveness
+	// and readiness and is kept as an alias of /healthz.
 	s.router.Get("/health", s.handleHealth)
+	s.router.Get("/healthz", s.handleHealth)
+	s.router.Get("/readyz", s.handleReady)
+
+	s.router.Group(func(r chi.Router) {
+		if len(s.authTokens) > 0 {
+			r.Use(s.requireToken)
+		}
+
+		r.Get("/api/v1/events", s.handleQueryEvents)
+		r.Get("/api/v1/events/summary", s.handleEventSummary)
+		r.Get("/api/v1/events/aggregate", s.handleAggregateEvents)
+		r.Get("/api/v1/events/stream", s.handleStreamEvents)
+		r.Get("/api/v1/recent", s.handleRecentEvents)
+		r.Get("/api/v1/histogram", s.handleHistogram)
+		r.Get("/api/v1/events/{namespace}/{resourceType}/{name}", s.handleObjectHistory)
+		r.Get("/api/v1/owned/{kind}/{name}", s.handleOwnedEvents)
+		r.With(s.requireAdmin).Delete("/api/v1/events", s.handleDeleteEvents)
+		r.With(s.requireAdmin).Get("/api/v1/admin/backup", s.handleBackup)
+		r.With(s.requireAdmin).Post("/api/v1/admin/restore", s.handleRestore)
+		r.With(s.requireAdmin).Get("/api/v1/admin/deadletter", s.handleDeadLetters)
+		r.With(s.requireAdmin).Get("/api/v1/admin/stats", s.handleStats)
+		r.Get("/api/v1/watched", s.handleWatched)
+		r.Get("/api/v1/storage", s.handleStorage)
+		r.Handle("/metrics", promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}))
+	})
+}
+
+// SetKindResolver sets how resource types are converted to the Kinds of
+// related events. Without one, Kinds are derived by singularization rules.
+func (s *Server) SetKindResolver(kinds KindResolver) {
+	s.kinds = kinds
+}
+
+// SetDeadLetters sets where /api/v1/admin/deadletter lists pending retries
+// from. Without one, e.g. in read-only mode, the list is empty.
+func (s *Server) SetDeadLetters(dead DeadLetterLister) {
+	s.dead = dead
+}
+
+// SetCacheSynced makes /readyz report not ready until synced is set, e.g.
+// once the watchers' informer caches have synced. Without it, readiness only
+// depends on the store.
+func (s *Server) SetCacheSynced(synced *atomic.Bool) {
+	s.synced = synced
+}
+
+// SetMaxResponseBytes caps the size of /api/v1/events responses; events
+// past the cap are left out and the response is flagged as truncated. Zero
+// or negative disables the cap.
+func (s *Server) SetMaxResponseBytes(n int64) {
+	s.maxBytes = n
 }
 
 // ServeHTTP implements http.Handler
@@ -50,77 +160,791 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // handleQueryEvents handles time-range and filtered queries
+// handleQueryEvents returns the events matching the query parameters. q
+// searches event messages and objects for case-insensitive substrings, all
+// of which must match. Searching decodes every event in the time range until
+// limit matches are found, so a search without start scans the whole store
+// and is expensive.
 func (s *Server) handleQueryEvents(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Parse query parameters
 	opts := storage.QueryOptions{
-		Namespace:    r.URL.Query().Get("namespace"),
+		Namespaces:   queryList(r, "namespace"),
 		ResourceType: r.URL.Query().Get("resourceType"),
 		ResourceName: r.URL.Query().Get("resourceName"),
-		Verb:         r.URL.Query().Get("verb"),
+		Verbs:        queryValues(r, "verb"),
 		User:         r.URL.Query().Get("user"),
+		Search:       r.URL.Query().Get("q"),
+		Cursor:       r.URL.Query().Get("cursor"),
+		Order:        r.URL.Query().Get("order"),
 	}
+	envelope := r.URL.Query().Get("envelope") == "true"
 
 	// Parse time range
-	if startStr := r.URL.Query().Get("start"); startStr != "" {
-		startTime, err := time.Parse(time.RFC3339, startStr)
+	startTime, endTime, err := parseTimeRange(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+		return
+	}
+	opts.StartTime = startTime
+	opts.EndTime = endTime
+
+	limit, clamped, err := s.parseLimit(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+		return
+	}
+	opts.Limit = limit
+	w.Header().Set("X-Limit", strconv.Itoa(limit))
+	if clamped {
+		w.Header().Set("X-Limit-Clamped", "true")
+	}
+
+	// Query the store
+	events, nextCursor, err := s.store.QueryEventsPage(ctx, opts)
+	if clientGone(w, r, err) {
+		return
+	}
+	if errors.Is(err, storage.ErrInvalidCursor) || errors.Is(err, storage.ErrInvalidOrder) {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Query failed: %v", err))
+		return
+	}
+
+	// The envelope is self-describing, so an empty page is not an error.
+	// Its metadata follows the items, so a truncated page can still point
+	// at the first event left out.
+	if envelope {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := io.WriteString(w, `{"items":`); err != nil {
+			return
+		}
+		budget := s.maxBytes
+		if budget > 0 {
+			budget = max(budget-envelopeReserve, 1)
+		}
+		written, err := writeEventArray(w, events, budget)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Invalid start time format: %v", err), http.StatusBadRequest)
 			return
 		}
-		opts.StartTime = startTime
+		page := EventsPage{Count: written, HasMore: nextCursor != "", NextCursor: nextCursor}
+		if written < len(events) {
+			page.Truncated = true
+			page.HasMore = true
+			page.NextCursor = s.truncatedCursor(ctx, opts, written)
+		}
+		writeEnvelopeTail(w, page)
+		return
+	}
+
+	// If no events found, return 404
+	if len(events) == 0 {
+		writeJSONError(w, http.StatusNotFound, errorCodeNoData, "no audit data available for the specified time range")
+		return
+	}
+
+	// Set pagination headers
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(events)))
+	if nextCursor != "" {
+		w.Header().Set("X-Has-More", "true")
+		w.Header().Set("X-Next-Cursor", nextCursor)
+	} else {
+		w.Header().Set("X-Has-More", "false")
+	}
+
+	// Return events as JSON array (matching existing client expectations).
+	// Headers are gone once the array is streamed, so truncation is
+	// reported in a trailer.
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Trailer", truncatedTrailer)
+	written, err := writeEventArray(w, events, s.maxBytes)
+	if err != nil {
+		return
+	}
+	if written < len(events) {
+		w.Header().Set(truncatedTrailer, "true")
+	}
+}
+
+// parseLimit returns the page size requested by the limit parameter. A
+// missing limit or 0 selects the server default, maxLimit; larger limits are
+// clamped to it, which is reported by clamped.
+func (s *Server) parseLimit(r *http.Request) (limit int, clamped bool, err error) {
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return s.maxLimit, false, nil
+	}
+	parsed, err := strconv.Atoi(limitStr)
+	if err != nil {
+		return 0, false, fmt.Errorf("Invalid limit: %v", err)
+	}
+	switch {
+	case parsed < 0:
+		return 0, false, fmt.Errorf("Invalid limit: %d is negative", parsed)
+	case parsed == 0:
+		return s.maxLimit, false, nil
+	case parsed > s.maxLimit:
+		return s.maxLimit, true, nil
+	}
+	return parsed, false, nil
+}
+
+// truncatedTrailer is set to "true" after a bare /api/v1/events array that
+// was cut short by the response size cap
+const truncatedTrailer = "X-Truncated"
+
+// envelopeReserve is the part of the response size cap kept for the
+// envelope around the items
+const envelopeReserve = 512
+
+// writeEventArray streams events as a JSON array, leaving out the events
+// that would take it past maxBytes (zero or negative disables the cap). It
+// returns the number of events written.
+func writeEventArray(w io.Writer, events []*models.AuditEvent, maxBytes int64) (int, error) {
+	// Opening and closing brackets
+	size := int64(2)
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+	written := 0
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return written, err
+		}
+		if written > 0 {
+			data = append([]byte{','}, data...)
+		}
+		if maxBytes > 0 && size+int64(len(data)) > maxBytes {
+			break
+		}
+		if _, err := w.Write(data); err != nil {
+			return written, err
+		}
+		size += int64(len(data))
+		written++
+	}
+	_, err := io.WriteString(w, "]")
+	return written, err
+}
+
+// writeEnvelopeTail completes an envelope whose items were streamed by
+// writeEventArray with the page metadata
+func writeEnvelopeTail(w io.Writer, page EventsPage) {
+	// Items is left nil and dropped, so the marshalled metadata spliced
+	// after the streamed items closes the envelope
+	data, err := json.Marshal(page)
+	if err != nil {
+		return
+	}
+	data = bytes.TrimPrefix(data, []byte(`{"items":null`))
+	_, _ = w.Write(data)
+}
+
+// truncatedCursor returns the cursor resuming a query after its first n
+// events, or "" if it can't be determined
+func (s *Server) truncatedCursor(ctx context.Context, opts storage.QueryOptions, n int) string {
+	if n == 0 {
+		// Nothing was returned, the query resumes where it started
+		return opts.Cursor
+	}
+	opts.Limit = n
+	_, cursor, err := s.store.QueryEventsPage(ctx, opts)
+	if err != nil {
+		return ""
+	}
+	return cursor
+}
+
+// queryValues returns the non-empty values of a query parameter that may be
+// repeated, e.g. verb=create&verb=delete
+func queryValues(r *http.Request, name string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[name] {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// queryList returns the non-empty values of a query parameter that may hold
+// a comma-separated list and may be repeated, e.g. namespace=app,app-canary
+func queryList(r *http.Request, name string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[name] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// parseTimeRange reads the optional RFC3339 start and end query parameters
+func parseTimeRange(r *http.Request) (time.Time, time.Time, error) {
+	var startTime, endTime time.Time
+
+	if startStr := r.URL.Query().Get("start"); startStr != "" {
+		parsed, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid start time format: %v", err)
+		}
+		startTime = parsed
 	}
 
 	if endStr := r.URL.Query().Get("end"); endStr != "" {
-		endTime, err := time.Parse(time.RFC3339, endStr)
+		parsed, err := time.Parse(time.RFC3339, endStr)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Invalid end time format: %v", err), http.StatusBadRequest)
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid end time format: %v", err)
+		}
+		endTime = parsed
+	}
+
+	return startTime, endTime, nil
+}
+
+// EventSummaryResponse is the response for /api/v1/events/summary
+type EventSummaryResponse struct {
+	Start  *time.Time           `json:"start,omitempty"`
+	End    *time.Time           `json:"end,omitempty"`
+	Total  int                  `json:"total"`
+	Counts storage.EventSummary `json:"counts"`
+}
+
+// handleEventSummary returns a namespace x resourceType matrix of event
+// counts for a time range. Cluster-scoped resources use the "" namespace.
+func (s *Server) handleEventSummary(w http.ResponseWriter, r *http.Request) {
+	startTime, endTime, err := parseTimeRange(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+		return
+	}
+
+	summary, err := s.store.SummarizeEvents(r.Context(), startTime, endTime)
+	if clientGone(w, r, err) {
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Summary failed: %v", err))
+		return
+	}
+
+	response := EventSummaryResponse{
+		Total:  summary.Total(),
+		Counts: summary,
+	}
+	if !startTime.IsZero() {
+		response.Start = &startTime
+	}
+	if !endTime.IsZero() {
+		response.End = &endTime
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
+		return
+	}
+}
+
+// EventAggregateResponse is the response for /api/v1/events/aggregate
+type EventAggregateResponse struct {
+	Start     *time.Time     `json:"start,omitempty"`
+	End       *time.Time     `json:"end,omitempty"`
+	GroupBy   string         `json:"groupBy"`
+	Total     int            `json:"total"`
+	Counts    map[string]int `json:"counts"`
+	Truncated bool           `json:"truncated"`
+}
+
+// handleAggregateEvents returns event counts per value of the groupBy
+// dimension (namespace, resourceType, verb or user) for a time range. The
+// namespace, resourceType, resourceName, verb and user filters of
+// /api/v1/events apply; limit does not.
+func (s *Server) handleAggregateEvents(w http.ResponseWriter, r *http.Request) {
+	startTime, endTime, err := parseTimeRange(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+		return
+	}
+
+	groupBy := r.URL.Query().Get("groupBy")
+	opts := storage.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespaces:   queryList(r, "namespace"),
+		ResourceType: r.URL.Query().Get("resourceType"),
+		ResourceName: r.URL.Query().Get("resourceName"),
+		Verbs:        queryValues(r, "verb"),
+		User:         r.URL.Query().Get("user"),
+	}
+
+	aggregate, err := s.store.AggregateEvents(r.Context(), opts, groupBy)
+	if clientGone(w, r, err) {
+		return
+	}
+	if errors.Is(err, storage.ErrInvalidGroupBy) {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Aggregation failed: %v", err))
+		return
+	}
+
+	response := EventAggregateResponse{
+		GroupBy:   groupBy,
+		Counts:    aggregate.Counts,
+		Truncated: aggregate.Truncated,
+	}
+	for _, count := range aggregate.Counts {
+		response.Total += count
+	}
+	if !startTime.IsZero() {
+		response.Start = &startTime
+	}
+	if !endTime.IsZero() {
+		response.End = &endTime
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
+		return
+	}
+}
+
+// defaultHistogramBucket is the bucket width of /api/v1/histogram without a
+// bucket parameter
+const defaultHistogramBucket = 5 * time.Minute
+
+// EventHistogramResponse is the response for /api/v1/histogram
+type EventHistogramResponse struct {
+	Start     time.Time                 `json:"start"`
+	End       time.Time                 `json:"end"`
+	Bucket    string                    `json:"bucket"`
+	Total     int                       `json:"total"`
+	Buckets   []storage.HistogramBucket `json:"buckets"`
+	Truncated bool                      `json:"truncated"`
+}
+
+// handleHistogram returns event counts per time bucket for charting, e.g.
+// bucket=5m. start is required and end defaults to now. The namespace,
+// resourceType, resourceName, verb and user filters of /api/v1/events
+// apply; limit does not.
+func (s *Server) handleHistogram(w http.ResponseWriter, r *http.Request) {
+	startTime, endTime, err := parseTimeRange(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+		return
+	}
+	if startTime.IsZero() {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, "start is required")
+		return
+	}
+	if endTime.IsZero() {
+		endTime = time.Now().UTC()
+	}
+
+	bucket := defaultHistogramBucket
+	if bucketStr := r.URL.Query().Get("bucket"); bucketStr != "" {
+		bucket, err = time.ParseDuration(bucketStr)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, fmt.Sprintf("Invalid bucket: %v", err))
+			return
+		}
+	}
+
+	opts := storage.QueryOptions{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Namespaces:   queryList(r, "namespace"),
+		ResourceType: r.URL.Query().Get("resourceType"),
+		ResourceName: r.URL.Query().Get("resourceName"),
+		Verbs:        queryValues(r, "verb"),
+		User:         r.URL.Query().Get("user"),
+	}
+
+	histogram, err := s.store.HistogramEvents(r.Context(), opts, bucket)
+	if clientGone(w, r, err) {
+		return
+	}
+	if errors.Is(err, storage.ErrInvalidHistogram) {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Histogram failed: %v", err))
+		return
+	}
+
+	response := EventHistogramResponse{
+		Start:     startTime,
+		End:       endTime,
+		Bucket:    bucket.String(),
+		Buckets:   histogram.Buckets,
+		Truncated: histogram.Truncated,
+	}
+	for _, b := range histogram.Buckets {
+		response.Total += b.Count
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
+		return
+	}
+}
+
+// defaultRecentLimit is the number of events /api/v1/recent returns without a limit
+const defaultRecentLimit = 100
+
+const (
+	// streamBuffer is the number of events buffered per stream client; a
+	// client lagging further behind misses events
+	streamBuffer = 256
+	// streamKeepalive is the interval of the comments that keep idle streams
+	// open through proxies
+	streamKeepalive = 30 * time.Second
+)
+
+// handleStreamEvents pushes newly stored events as server-sent events,
+// optionally filtered by namespace and resourceType. Events missed because
+// the client lagged behind are reported as a ": dropped N" comment.
+func (s *Server) handleStreamEvents(w http.ResponseWriter, r *http.Request) {
+	if s.events == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, errorCodeUnavailable, "event streaming requires active watchers")
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+	resourceType := r.URL.Query().Get("resourceType")
+	sub := s.events.Subscribe(streamBuffer, func(event *models.AuditEvent) bool {
+		return (namespace == "" || event.Namespace == namespace) &&
+			(resourceType == "" || event.ResourceType == resourceType)
+	})
+	defer sub.Close()
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to start stream: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepalive := time.NewTicker(streamKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if dropped := sub.TakeDropped(); dropped > 0 {
+				fmt.Fprintf(w, ": dropped %d\n\n", dropped)
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		if err := rc.Flush(); err != nil {
 			return
 		}
-		opts.EndTime = endTime
 	}
+}
 
-	// Parse limit with max enforcement
-	limit := s.maxLimit
+// handleRecentEvents returns the most recent events across the whole store,
+// newest first. limit defaults to 100 and is capped at the max query limit.
+func (s *Server) handleRecentEvents(w http.ResponseWriter, r *http.Request) {
+	limit := min(defaultRecentLimit, s.maxLimit)
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Invalid limit: %v", err), http.StatusBadRequest)
+		if err != nil || parsedLimit <= 0 {
+			writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, fmt.Sprintf("Invalid limit: %q", limitStr))
 			return
 		}
-		if parsedLimit > 0 && parsedLimit < limit {
-			limit = parsedLimit
-		}
+		limit = min(parsedLimit, s.maxLimit)
 	}
-	opts.Limit = limit
 
-	// Query the store
-	events, err := s.store.QueryEvents(ctx, opts)
+	events, err := s.store.RecentEvents(r.Context(), limit)
+	if clientGone(w, r, err) {
+		return
+	}
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Query failed: %v", err), http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Query failed: %v", err))
 		return
 	}
+	if events == nil {
+		events = []*models.AuditEvent{}
+	}
 
-	// If no events found, return 404
-	if len(events) == 0 {
-		http.Error(w, "no audit data available for the specified time range", http.StatusNotFound)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(events); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
 		return
 	}
+}
 
-	// Set pagination headers
-	w.Header().Set("X-Total-Count", strconv.Itoa(len(events)))
-	if len(events) >= limit {
-		w.Header().Set("X-Has-More", "true")
-		// Could add Link header with next page URL if implementing cursor pagination
-	} else {
-		w.Header().Set("X-Has-More", "false")
+// handleOwnedEvents returns the events of the objects owned by the object of
+// the given kind and name, e.g. the Pods of a ReplicaSet, optionally limited
+// to one namespace
+func (s *Server) handleOwnedEvents(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+	name := chi.URLParam(r, "name")
+
+	events, err := s.store.GetEventsByOwner(r.Context(), r.URL.Query().Get("namespace"), kind, name)
+	if clientGone(w, r, err) {
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to query owned events: %v", err))
+		return
+	}
+	if events == nil {
+		events = []*models.AuditEvent{}
 	}
 
-	// Return events as JSON array (matching existing client expectations)
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(events); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
+		return
+	}
+}
+
+// EventsPage is the envelope=true response for /api/v1/events. Count is the
+// number of items on the page, not of all matching events.
+type EventsPage struct {
+	Items      []*models.AuditEvent `json:"items"`
+	Count      int                  `json:"count"`
+	HasMore    bool                 `json:"hasMore"`
+	NextCursor string               `json:"nextCursor,omitempty"`
+	// Truncated is set when the response size cap left out events;
+	// NextCursor then resumes at the first of them
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// requireToken rejects requests that don't carry one of the configured auth
+// tokens. The admin token is accepted too, so admin requests need only one
+// Authorization header.
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || !s.validToken(token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSONError(w, http.StatusUnauthorized, errorCodeUnauthorized, "missing or invalid bearer token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validToken reports whether token is one of the auth tokens or the admin
+// token. Every token is compared so the time taken doesn't reveal which one
+// matched.
+func (s *Server) validToken(token string) bool {
+	valid := 0
+	for _, candidate := range s.authTokens {
+		if candidate == "" {
+			continue
+		}
+		valid |= subtle.ConstantTimeCompare([]byte(token), []byte(candidate))
+	}
+	if s.adminToken != "" {
+		valid |= subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken))
+	}
+	return valid == 1
+}
+
+// statusClientClosedRequest is the nginx status for a request the client
+// abandoned before the response was written
+const statusClientClosedRequest = 499
+
+// clientGone reports whether err is a store query canceled because the client
+// disconnected, and if so records statusClientClosedRequest. Nobody reads the
+// response, the status only shows up in logs and metrics.
+func clientGone(w http.ResponseWriter, r *http.Request, err error) bool {
+	if !errors.Is(err, context.Canceled) || r.Context().Err() == nil {
+		return false
+	}
+	w.WriteHeader(statusClientClosedRequest)
+	return true
+}
+
+// Codes of the JSON error responses, for clients to tell failures apart
+// without matching messages
+const (
+	errorCodeBadRequest   = "bad_request"
+	errorCodeUnauthorized = "unauthorized"
+	errorCodeForbidden    = "forbidden"
+	errorCodeNotFound     = "not_found"
+	errorCodeInternal     = "internal"
+	errorCodeUnavailable  = "unavailable"
+	// errorCodeNoData is a 404 for a query that matched no events, as
+	// opposed to errorCodeNotFound for an unknown route
+	errorCodeNoData = "no_data"
+)
+
+// errorResponse is the body of every error response
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// writeJSONError writes msg as a {"error": msg, "code": code} body with the
+// given status
+func writeJSONError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: msg, Code: code})
+}
+
+// requireAdmin rejects requests that don't carry the configured admin token
+func (s *Server) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.adminToken == "" {
+			writeJSONError(w, http.StatusForbidden, errorCodeForbidden, "admin endpoints are disabled (no adminToken configured)")
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			writeJSONError(w, http.StatusUnauthorized, errorCodeUnauthorized, "unauthorized")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleDeleteEvents purges all stored events for a namespace
+func (s *Server) handleDeleteEvents(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" {
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, "namespace is required")
+		return
+	}
+
+	deleted, err := s.store.DeleteNamespace(r.Context(), namespace)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Delete failed after removing %d events: %v", deleted, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"namespace": namespace,
+		"deleted":   deleted,
+	})
+}
+
+// backupVersionTrailer carries the since version of the next incremental
+// backup, which is only known once the backup is written
+const backupVersionTrailer = "X-Backup-Version"
+
+// handleBackup streams a backup of the store. since, the X-Backup-Version of
+// a previous backup, limits it to the entries written after that backup.
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	var since uint64
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		var err error
+		since, err = strconv.ParseUint(sinceStr, 10, 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, "invalid since: expected a backup version")
+			return
+		}
+	}
+
+	// Backups of a large store outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to start backup: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="watch-events.backup"`)
+	w.Header().Set("Trailer", backupVersionTrailer)
+
+	version, err := s.store.Backup(w, since)
+	if err != nil {
+		// A failed backup is truncated and has no version trailer
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Backup failed: %v", err))
+		return
+	}
+	w.Header().Set(backupVersionTrailer, strconv.FormatUint(version, 10))
+}
+
+// handleRestore loads a backup from the request body into the store. The
+// store holds back the watchers' writes until the backup is loaded.
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	// Restores of a large backup outlive the server's read and write timeouts
+	rc := http.NewResponseController(w)
+	for _, setDeadline := range []func(time.Time) error{rc.SetReadDeadline, rc.SetWriteDeadline} {
+		if err := setDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to start restore: %v", err))
+			return
+		}
+	}
+
+	if err := s.store.Restore(r.Body); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Restore failed: %v", err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeadLetters lists the events whose store failed and that wait to be
+// retried (admin)
+func (s *Server) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	letters := []watchers.DeadLetter{}
+	if s.dead != nil {
+		letters = append(letters, s.dead.DeadLetters()...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(letters); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
+		return
+	}
+}
+
+// handleStats reports the store size, key count and the time span of the
+// stored events (admin)
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.store.Stats(r.Context())
+	if clientGone(w, r, err) {
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Stats failed: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
 		return
 	}
 }
@@ -134,7 +958,9 @@ type ObjectEventsResponse struct {
 	RelatedEvents []*models.AuditEvent `json:"relatedEvents"`
 }
 
-// handleObjectHistory returns all events for a specific object in two sections
+// handleObjectHistory returns all events for a specific object in two sections.
+// latest=true returns only the most recent watch event, with its body, and
+// slim=true strips ObjectChanges from the other returned events.
 func (s *Server) handleObjectHistory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -144,26 +970,55 @@ func (s *Server) handleObjectHistory(w http.ResponseWriter, r *http.Request) {
 
 	// Validate parameters
 	if namespace == "" || resourceType == "" || name == "" {
-		http.Error(w, "namespace, resourceType, and name are required", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, errorCodeBadRequest, "namespace, resourceType, and name are required")
 		return
 	}
 
 	// Get direct watch events for this object
 	watchEvents, err := s.store.GetObjectHistory(ctx, namespace, resourceType, name)
+	if clientGone(w, r, err) {
+		return
+	}
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to query object history: %v", err), http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to query object history: %v", err))
 		return
 	}
 
 	// Get related Event objects (where involvedObject points to this object)
 	// Convert resourceType to Kind (pods -> Pod)
-	kind := resourceTypeToKind(resourceType)
+	kind := models.ResourceTypeToKind(resourceType)
+	if s.kinds != nil {
+		kind = s.kinds.Kind(resourceType)
+	}
 	relatedEvents, err := s.store.GetRelatedEvents(ctx, namespace, kind, name)
+	if clientGone(w, r, err) {
+		return
+	}
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to query related events: %v", err), http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to query related events: %v", err))
+		return
+	}
+
+	// Return 404 if no data found at all
+	if len(watchEvents) == 0 && len(relatedEvents) == 0 {
+		writeJSONError(w, http.StatusNotFound, errorCodeNoData, "no events found for this object")
 		return
 	}
 
+	latest := r.URL.Query().Get("latest") == "true"
+	slim := r.URL.Query().Get("slim") == "true"
+
+	// Events are stored in time order, so the last one is the most recent
+	if latest && len(watchEvents) > 0 {
+		watchEvents = watchEvents[len(watchEvents)-1:]
+	}
+	if slim {
+		if !latest {
+			stripObjectChanges(watchEvents)
+		}
+		stripObjectChanges(relatedEvents)
+	}
+
 	// Build response with two sections
 	response := ObjectEventsResponse{
 		Namespace:     namespace,
@@ -173,20 +1028,73 @@ func (s *Server) handleObjectHistory(w http.ResponseWriter, r *http.Request) {
 		RelatedEvents: relatedEvents,
 	}
 
-	// Return 404 if no data found at all
-	if len(watchEvents) == 0 && len(relatedEvents) == 0 {
-		http.Error(w, "no events found for this object", http.StatusNotFound)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
 		return
 	}
+}
+
+// stripObjectChanges drops the stored object bodies, keeping the event fields
+func stripObjectChanges(events []*models.AuditEvent) {
+	for _, event := range events {
+		event.ObjectChanges = nil
+	}
+}
+
+// WatchedResourceStatus describes a watched resource type and how many events it has stored
+type WatchedResourceStatus struct {
+	watchers.WatchedResource
+	EventCount int `json:"eventCount"`
+}
+
+// handleWatched lists the actively watched resource types with their stored event counts.
+// Since reports the oldest stored event when it predates the current watcher
+// (e.g. after a restart).
+func (s *Server) handleWatched(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.store.StatsByResourceType(r.Context())
+	if clientGone(w, r, err) {
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to count events: %v", err))
+		return
+	}
+
+	// Read-only servers run without watchers
+	var watched []watchers.WatchedResource
+	if s.watched != nil {
+		watched = s.watched.WatchedResources()
+	}
+	response := make([]WatchedResourceStatus, 0, len(watched))
+	for _, resource := range watched {
+		stat := stats[resource.ResourceType]
+		if !stat.Oldest.IsZero() && stat.Oldest.Before(resource.Since) {
+			resource.Since = stat.Oldest
+		}
+		response = append(response, WatchedResourceStatus{
+			WatchedResource: resource,
+			EventCount:      stat.Count,
+		})
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
 		return
 	}
 }
 
-// handleHealth provides a health check endpoint
+// handleStorage reports BadgerDB storage sizes
+func (s *Server) handleStorage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.store.Metrics()); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, errorCodeInternal, fmt.Sprintf("Failed to encode response: %v", err))
+		return
+	}
+}
+
+// handleHealth is the liveness probe: the process is up and serving
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -195,39 +1103,29 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// resourceTypeToKind converts resource type (plural lowercase) to Kind (singular capitalized)
-func resourceTypeToKind(resourceType string) string {
-	// Handle special cases
-	irregularSingulars := map[string]string{
-		"endpoints":                 "Endpoints",
-		"ingresses":                 "Ingress",
-		"networkpolicies":           "NetworkPolicy",
-		"poddisruptionbudgets":      "PodDisruptionBudget",
-		"priorityclasses":           "PriorityClass",
-		"storageclasses":            "StorageClass",
-		"customresourcedefinitions": "CustomResourceDefinition",
-	}
-
-	if singular, ok := irregularSingulars[resourceType]; ok {
-		return singular
+// handleReady is the readiness probe: queries can be answered once the store
+// is open and, when watchers run, their caches have synced. It answers 503
+// with the reason until then.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	var reason string
+	switch {
+	case s.store.Closed():
+		reason = "store is closed"
+	case s.synced != nil && !s.synced.Load():
+		reason = "watcher caches have not synced yet"
 	}
 
-	// Simple singularization rules
-	singular := resourceType
-	if strings.HasSuffix(singular, "ies") {
-		singular = strings.TrimSuffix(singular, "ies") + "y"
-	} else if strings.HasSuffix(singular, "ses") {
-		singular = strings.TrimSuffix(singular, "ses")
-	} else if strings.HasSuffix(singular, "es") {
-		singular = strings.TrimSuffix(singular, "es")
-	} else if strings.HasSuffix(singular, "s") {
-		singular = strings.TrimSuffix(singular, "s")
-	}
-
-	// Capitalize first letter
-	if len(singular) > 0 {
-		return strings.ToUpper(singular[:1]) + singular[1:]
+	w.Header().Set("Content-Type", "application/json")
+	if reason != "" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{
+			"status": "not ready",
+			"reason": reason,
+		})
+		return
 	}
-
-	return singular
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"status": "ready",
+	})
 }
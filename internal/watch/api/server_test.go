@@ -0,0 +1,1108 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/moritz/mcp-toolkit/internal/audit"
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	"github.com/moritz/mcp-toolkit/internal/watch/storage"
+	"github.com/moritz/mcp-toolkit/internal/watch/watchers"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newTestServer returns a server backed by a temporary store holding one pod
+// event per name
+func newTestServer(t *testing.T, names ...string) *Server {
+	t.Helper()
+
+	store, err := storage.NewStore(t.TempDir(), 1, false, nil)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	for _, name := range names {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Pod")
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		obj.SetUID(types.UID(name))
+
+		event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+		if err != nil {
+			t.Fatalf("failed to transform %s: %v", name, err)
+		}
+		if err := store.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatalf("failed to store %s: %v", name, err)
+		}
+	}
+
+	return NewServer(store, nil, nil, 1000, "", nil, nil)
+}
+
+func TestQueryEventsBareArray(t *testing.T) {
+	s := newTestServer(t, "a", "b")
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var events []models.AuditEvent
+	if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+		t.Fatalf("expected a JSON array: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("expected 2 events, got %d", len(events))
+	}
+	if rec.Header().Get("X-Has-More") != "false" {
+		t.Errorf("expected X-Has-More=false, got %q", rec.Header().Get("X-Has-More"))
+	}
+}
+
+func TestQueryEventsEnvelope(t *testing.T) {
+	s := newTestServer(t, "a", "b", "c")
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?envelope=true&limit=2", nil))
+
+	var page EventsPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("expected an envelope: %v", err)
+	}
+	if len(page.Items) != 2 || page.Count != 2 || !page.HasMore || page.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?envelope=true&limit=2&cursor="+page.NextCursor, nil))
+
+	page = EventsPage{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("expected an envelope: %v", err)
+	}
+	if len(page.Items) != 1 || page.HasMore || page.NextCursor != "" {
+		t.Errorf("unexpected second page: %+v", page)
+	}
+}
+
+func TestQueryEventsOrder(t *testing.T) {
+	s := newTestServer(t, "a", "b", "c")
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?envelope=true&order=desc", nil))
+
+	var page EventsPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("expected an envelope: %v", err)
+	}
+	if len(page.Items) != 3 || page.Items[0].ResourceName != "c" || page.Items[2].ResourceName != "a" {
+		t.Errorf("expected newest first, got %+v", page.Items)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?order=sideways", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid order, got %d", rec.Code)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	store, err := storage.NewStore(t.TempDir(), 1, false, nil)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	broadcaster := watchers.NewBroadcaster()
+	server := httptest.NewServer(NewServer(store, nil, broadcaster, 1000, "", nil, nil))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/events/stream?namespace=default&resourceType=pods")
+	if err != nil {
+		t.Fatalf("stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %q", ct)
+	}
+
+	// The headers are flushed after subscribing, so nothing published now is missed
+	broadcaster.Publish(&models.AuditEvent{Namespace: "kube-system", ResourceType: "pods", ResourceName: "other"})
+	broadcaster.Publish(&models.AuditEvent{Namespace: "default", ResourceType: "pods", ResourceName: "web"})
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read stream: %v", err)
+	}
+	var event models.AuditEvent
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+		t.Fatalf("expected a data line, got %q: %v", line, err)
+	}
+	if event.ResourceName != "web" {
+		t.Errorf("expected only the matching event, got %+v", event)
+	}
+}
+
+func TestStreamEventsWithoutWatchers(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/stream", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without watchers, got %d", rec.Code)
+	}
+}
+
+func TestQueryEventsEnvelopeEmpty(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?envelope=true", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for an empty envelope, got %d", rec.Code)
+	}
+	var page EventsPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("expected an envelope: %v", err)
+	}
+	if page.Items == nil || page.Count != 0 || page.HasMore {
+		t.Errorf("unexpected empty page: %+v", page)
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminToken string
+		header     string
+		want       int
+	}{
+		{name: "disabled", adminToken: "", header: "Bearer secret", want: http.StatusForbidden},
+		{name: "valid", adminToken: "secret", header: "Bearer secret", want: http.StatusOK},
+		{name: "wrong token", adminToken: "secret", header: "Bearer other", want: http.StatusUnauthorized},
+		{name: "missing scheme", adminToken: "secret", header: "secret", want: http.StatusUnauthorized},
+		{name: "missing header", adminToken: "secret", header: "", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{adminToken: tt.adminToken}
+			handler := s.requireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/events?namespace=a", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestStorageEndpoints(t *testing.T) {
+	s := newTestServer(t, "a")
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/storage", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 from /api/v1/storage, got %d", rec.Code)
+	}
+	var metrics storage.Metrics
+	if err := json.Unmarshal(rec.Body.Bytes(), &metrics); err != nil {
+		t.Fatalf("failed to decode storage metrics: %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), "watch_store_vlog_size_bytes") {
+		t.Errorf("expected storage gauges in /metrics output")
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	store, err := storage.NewStore(t.TempDir(), 1, false, registry)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	watcherMgr := watchers.NewManager(nil, store, &config.Config{}, registry, logr.Discard())
+
+	for _, name := range []string{"a", "b"} {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Pod")
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		obj.SetUID(types.UID(name))
+
+		event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+		if err != nil {
+			t.Fatalf("failed to transform %s: %v", name, err)
+		}
+		if err := store.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatalf("failed to store %s: %v", name, err)
+		}
+	}
+
+	s := NewServer(store, watcherMgr, watcherMgr, 1000, "", nil, registry)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`watch_events_stored_total{resource_type="pods",verb="create"} 2`,
+		"watch_store_event_duration_seconds_count 2",
+		"watch_active_watchers 0",
+		"watch_store_lsm_size_bytes",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in /metrics output:\n%s", want, body)
+		}
+	}
+}
+
+func TestEventSummary(t *testing.T) {
+	s := newTestServer(t, "a", "b", "c")
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/summary", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var summary EventSummaryResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("failed to decode summary: %v", err)
+	}
+	if summary.Total != 3 || summary.Counts["default"]["pods"] != 3 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/summary?start=yesterday", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid start, got %d", rec.Code)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	s := newTestServer(t)
+	var synced atomic.Bool
+	s.SetCacheSynced(&synced)
+
+	probe := func(path string) (int, map[string]string) {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: invalid body %q: %v", path, rec.Body.String(), err)
+		}
+		return rec.Code, body
+	}
+
+	if code, _ := probe("/healthz"); code != http.StatusOK {
+		t.Errorf("expected the liveness probe to pass before the caches synced, got %d", code)
+	}
+	if code, body := probe("/readyz"); code != http.StatusServiceUnavailable || body["reason"] != "watcher caches have not synced yet" {
+		t.Errorf("expected 503 before the caches synced, got %d %v", code, body)
+	}
+
+	synced.Store(true)
+	if code, body := probe("/readyz"); code != http.StatusOK || body["status"] != "ready" {
+		t.Errorf("expected 200 once the caches synced, got %d %v", code, body)
+	}
+
+	s.store.Close()
+	if code, body := probe("/readyz"); code != http.StatusServiceUnavailable || body["reason"] != "store is closed" {
+		t.Errorf("expected 503 with a closed store, got %d %v", code, body)
+	}
+	if code, _ := probe("/healthz"); code != http.StatusOK {
+		t.Errorf("expected the liveness probe to pass with a closed store, got %d", code)
+	}
+}
+
+func TestQueryCanceledByClient(t *testing.T) {
+	s := newTestServer(t, "a", "b", "c")
+
+	for _, path := range []string{"/api/v1/events", "/api/v1/events/default/pods/a", "/api/v1/events/summary"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+		if rec.Code != statusClientClosedRequest {
+			t.Errorf("%s: expected %d for a disconnected client, got %d", path, statusClientClosedRequest, rec.Code)
+		}
+	}
+}
+
+func TestQueryEventsSearch(t *testing.T) {
+	s := newTestServer(t, "web", "api", "api-canary")
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?q=DEFAULT/API+create", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var events []models.AuditEvent
+	if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+		t.Fatalf("failed to decode events: %v", err)
+	}
+	if len(events) != 2 || events[0].ResourceName != "api" || events[1].ResourceName != "api-canary" {
+		t.Errorf("expected the api pods, got %+v", events)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?q=default/api+kube-system", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without matches, got %d", rec.Code)
+	}
+}
+
+func TestAggregateEvents(t *testing.T) {
+	s := newTestServer(t, "a", "b")
+
+	for groupBy, key := range map[string]string{
+		"namespace":    "default",
+		"resourceType": "pods",
+		"verb":         "create",
+		"user":         models.SystemWatcherUser,
+	} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/aggregate?groupBy="+groupBy, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("groupBy=%s: expected 200, got %d", groupBy, rec.Code)
+		}
+		var aggregate EventAggregateResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &aggregate); err != nil {
+			t.Fatalf("failed to decode aggregate: %v", err)
+		}
+		if aggregate.GroupBy != groupBy || aggregate.Total != 2 || aggregate.Counts[key] != 2 || aggregate.Truncated {
+			t.Errorf("groupBy=%s: unexpected aggregate %+v", groupBy, aggregate)
+		}
+	}
+
+	for _, query := range []string{"", "?groupBy=name", "?groupBy=verb&start=yesterday"} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/aggregate"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	s := newTestServer(t, "a", "b")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	start := now.Add(-time.Hour).Format(time.RFC3339)
+	end := now.Add(time.Minute).Format(time.RFC3339)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/histogram?bucket=10m&verb=create&start="+start+"&end="+end, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var histogram EventHistogramResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &histogram); err != nil {
+		t.Fatalf("failed to decode histogram: %v", err)
+	}
+	// The last bucket is cut short at the end
+	if histogram.Bucket != "10m0s" || len(histogram.Buckets) != 7 || histogram.Total != 2 {
+		t.Fatalf("unexpected histogram %+v", histogram)
+	}
+	sum := 0
+	for i, bucket := range histogram.Buckets {
+		if want := histogram.Start.Add(time.Duration(i) * 10 * time.Minute); !bucket.Start.Equal(want) {
+			t.Errorf("bucket %d starts at %s, want %s", i, bucket.Start, want)
+		}
+		sum += bucket.Count
+	}
+	if sum != histogram.Total {
+		t.Errorf("buckets add up to %d, want %d", sum, histogram.Total)
+	}
+
+	for _, query := range []string{"", "?bucket=5m", "?start=" + start + "&bucket=soon", "?start=" + start + "&bucket=0s", "?start=" + start + "&bucket=1s"} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/histogram"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
+
+// storePodUpdate stores a MODIFIED event for the default/<name> pod with a
+// version label at the given time
+func storePodUpdate(t *testing.T, s *Server, name, version string, at time.Time) {
+	t.Helper()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(types.UID(name))
+	obj.SetLabels(map[string]string{"version": version})
+
+	event, err := models.TransformWatchEvent(obj, models.EventTypeModified)
+	if err != nil {
+		t.Fatalf("failed to transform %s: %v", name, err)
+	}
+	event.Timestamp = at
+	if err := s.store.StoreEvent(context.Background(), event, obj); err != nil {
+		t.Fatalf("failed to store %s: %v", name, err)
+	}
+}
+
+func getObjectHistory(t *testing.T, s *Server, query string) ObjectEventsResponse {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/default/pods/web"+query, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response ObjectEventsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode history: %v", err)
+	}
+	return response
+}
+
+func TestObjectHistorySlimAndLatest(t *testing.T) {
+	s := newTestServer(t, "web")
+	now := time.Now()
+	storePodUpdate(t, s, "web", "v2", now.Add(time.Second))
+	storePodUpdate(t, s, "web", "v3", now.Add(2*time.Second))
+
+	full := getObjectHistory(t, s, "")
+	if len(full.WatchEvents) != 3 {
+		t.Fatalf("expected 3 watch events, got %d", len(full.WatchEvents))
+	}
+	for _, event := range full.WatchEvents {
+		if event.ObjectChanges == nil {
+			t.Errorf("full history should include object bodies")
+		}
+	}
+
+	slim := getObjectHistory(t, s, "?slim=true")
+	if len(slim.WatchEvents) != 3 {
+		t.Fatalf("expected 3 slim watch events, got %d", len(slim.WatchEvents))
+	}
+	for _, event := range slim.WatchEvents {
+		if event.ObjectChanges != nil {
+			t.Errorf("slim history should not include object bodies")
+		}
+		if event.Verb == "" || event.Timestamp.IsZero() {
+			t.Errorf("slim history should keep event fields, got %+v", event)
+		}
+	}
+
+	latest := getObjectHistory(t, s, "?latest=true&slim=true")
+	if len(latest.WatchEvents) != 1 {
+		t.Fatalf("expected only the latest watch event, got %d", len(latest.WatchEvents))
+	}
+	metadata, _ := latest.WatchEvents[0].ObjectChanges["metadata"].(map[string]any)
+	labels, _ := metadata["labels"].(map[string]any)
+	if labels["version"] != "v3" {
+		t.Errorf("expected the latest event body with version v3, got %v", latest.WatchEvents[0].ObjectChanges)
+	}
+}
+
+func TestOwnedEvents(t *testing.T) {
+	s := newTestServer(t)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetNamespace("default")
+	obj.SetName("web-5d8f-x2k9p")
+	obj.SetUID("pod-uid")
+	obj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-5d8f", UID: "rs-uid"}})
+	event, err := models.TransformWatchEvent(obj, models.EventTypeAdded, models.OwnerEnricher{})
+	if err != nil {
+		t.Fatalf("failed to transform pod: %v", err)
+	}
+	if err := s.store.StoreEvent(context.Background(), event, obj); err != nil {
+		t.Fatalf("failed to store pod: %v", err)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/owned/ReplicaSet/web-5d8f":                   1,
+		"/api/v1/owned/ReplicaSet/web-5d8f?namespace=default": 1,
+		"/api/v1/owned/ReplicaSet/web-5d8f?namespace=other":   0,
+		"/api/v1/owned/ReplicaSet/api-7c4b":                   0,
+	} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", path, rec.Code, rec.Body.String())
+		}
+		var events []models.AuditEvent
+		if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+			t.Fatalf("%s: invalid response %q: %v", path, rec.Body.String(), err)
+		}
+		if len(events) != want {
+			t.Errorf("%s: expected %d events, got %d", path, want, len(events))
+		}
+		if want > 0 && events[0].ResourceName != "web-5d8f-x2k9p" {
+			t.Errorf("%s: expected the owned pod, got %s", path, events[0].ResourceName)
+		}
+	}
+}
+
+func TestRecentEvents(t *testing.T) {
+	s := newTestServer(t)
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		storePodUpdate(t, s, "web", fmt.Sprintf("v%d", i), now.Add(time.Duration(i)*time.Second))
+	}
+	s.maxLimit = 3
+
+	get := func(query string) (int, []models.AuditEvent) {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/recent"+query, nil))
+		var events []models.AuditEvent
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+				t.Fatalf("failed to decode events: %v", err)
+			}
+		}
+		return rec.Code, events
+	}
+
+	code, events := get("?limit=2")
+	if code != http.StatusOK || len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d (status %d)", len(events), code)
+	}
+	if !events[0].Timestamp.After(events[1].Timestamp) {
+		t.Errorf("expected newest first, got %s then %s", events[0].Timestamp, events[1].Timestamp)
+	}
+
+	// The limit is capped at the server's max query limit
+	if _, events := get("?limit=50"); len(events) != 3 {
+		t.Errorf("expected limit capped at 3, got %d events", len(events))
+	}
+
+	if code, _ := get("?limit=zero"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid limit, got %d", code)
+	}
+}
+
+func TestQueryEventsMaxResponseBytes(t *testing.T) {
+	s := newTestServer(t)
+	for i := 0; i < 50; i++ {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Pod")
+		obj.SetNamespace("default")
+		obj.SetName(fmt.Sprintf("web-%02d", i))
+		obj.SetUID(types.UID(fmt.Sprintf("uid-%02d", i)))
+		obj.SetAnnotations(map[string]string{"payload": strings.Repeat("x", 10_000)})
+
+		event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+		if err != nil {
+			t.Fatalf("failed to transform: %v", err)
+		}
+		if err := s.store.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatalf("failed to store: %v", err)
+		}
+	}
+	const maxBytes = 100_000
+	s.SetMaxResponseBytes(maxBytes)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events", nil))
+	var events []models.AuditEvent
+	if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+		t.Fatalf("expected a complete JSON array: %v", err)
+	}
+	if len(events) == 0 || len(events) >= 50 || rec.Body.Len() > maxBytes {
+		t.Errorf("expected the array to be cut below %d bytes, got %d events in %d bytes", maxBytes, len(events), rec.Body.Len())
+	}
+	if got := rec.Result().Trailer.Get("X-Truncated"); got != "true" {
+		t.Errorf("expected the X-Truncated trailer, got %q", got)
+	}
+
+	// Envelope pages resume at the first event left out
+	seen := make(map[string]bool)
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 50 {
+			t.Fatal("pagination did not terminate")
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?envelope=true&cursor="+cursor, nil))
+		if rec.Body.Len() > maxBytes {
+			t.Fatalf("expected at most %d bytes, got %d", maxBytes, rec.Body.Len())
+		}
+		var page EventsPage
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("expected an envelope: %v", err)
+		}
+		if page.Truncated != page.HasMore || page.Count != len(page.Items) {
+			t.Fatalf("unexpected page metadata: %+v", page)
+		}
+		for _, event := range page.Items {
+			if seen[event.ResourceName] {
+				t.Fatalf("event %s returned twice", event.ResourceName)
+			}
+			seen[event.ResourceName] = true
+		}
+		if !page.HasMore {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(seen) != 50 {
+		t.Errorf("expected all 50 events across pages, got %d", len(seen))
+	}
+
+	// Not even one event fits
+	s.SetMaxResponseBytes(1_000)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?envelope=true", nil))
+	var page EventsPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("expected an envelope: %v", err)
+	}
+	if len(page.Items) != 0 || !page.Truncated {
+		t.Errorf("expected an empty truncated page, got %+v", page)
+	}
+}
+
+func TestAuthTokens(t *testing.T) {
+	store, err := storage.NewStore(t.TempDir(), 1, false, nil)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	s := NewServer(store, nil, nil, 1000, "admin", []string{"reader", "other"}, nil)
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{name: "missing header", path: "/api/v1/storage", header: "", want: http.StatusUnauthorized},
+		{name: "wrong token", path: "/api/v1/storage", header: "Bearer wrong", want: http.StatusUnauthorized},
+		{name: "empty token", path: "/api/v1/storage", header: "Bearer ", want: http.StatusUnauthorized},
+		{name: "missing scheme", path: "/api/v1/storage", header: "reader", want: http.StatusUnauthorized},
+		{name: "valid token", path: "/api/v1/storage", header: "Bearer reader", want: http.StatusOK},
+		{name: "second token", path: "/api/v1/storage", header: "Bearer other", want: http.StatusOK},
+		{name: "admin token", path: "/api/v1/storage", header: "Bearer admin", want: http.StatusOK},
+		{name: "metrics", path: "/metrics", header: "", want: http.StatusUnauthorized},
+		{name: "health exempt", path: "/health", header: "", want: http.StatusOK},
+		{name: "liveness exempt", path: "/healthz", header: "", want: http.StatusOK},
+		{name: "readiness exempt", path: "/readyz", header: "", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("expected status %d, got %d", tt.want, rec.Code)
+			}
+			if rec.Code == http.StatusUnauthorized {
+				var body map[string]string
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+					t.Errorf("expected a JSON error body, got %q", rec.Body.String())
+				}
+			}
+		})
+	}
+
+	// Without tokens the API stays open
+	rec := httptest.NewRecorder()
+	newTestServer(t).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/storage", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected an unauthenticated server to answer 200, got %d", rec.Code)
+	}
+}
+
+// deadLetters is a fixed DeadLetterLister
+type deadLetters []watchers.DeadLetter
+
+func (d deadLetters) DeadLetters() []watchers.DeadLetter {
+	return d
+}
+
+func TestStats(t *testing.T) {
+	s := newTestServer(t, "a", "b")
+	s.adminToken = "admin"
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/stats", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without the admin token, got %d", rec.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer admin")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var stats storage.StoreStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+	if stats.KeyCount == 0 || stats.OldestEvent == nil || stats.NewestEvent == nil {
+		t.Errorf("expected keys and a time span, got %s", rec.Body.String())
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	s := newTestServer(t)
+	s.adminToken = "admin"
+
+	get := func() []watchers.DeadLetter {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/deadletter", nil)
+		req.Header.Set("Authorization", "Bearer admin")
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var letters []watchers.DeadLetter
+		if err := json.Unmarshal(rec.Body.Bytes(), &letters); err != nil {
+			t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+		}
+		return letters
+	}
+
+	if letters := get(); letters == nil || len(letters) != 0 {
+		t.Errorf("expected an empty list without watchers, got %v", letters)
+	}
+
+	s.SetDeadLetters(deadLetters{{Fingerprint: "abc", Verb: "create", ResourceType: "pods", ResourceName: "web", Attempts: 2, LastError: "disk full"}})
+	if letters := get(); len(letters) != 1 || letters[0].Fingerprint != "abc" || letters[0].Attempts != 2 {
+		t.Errorf("expected the pending dead letter, got %+v", letters)
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	source := newTestServer(t, "a", "b")
+	source.adminToken = "admin"
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/admin/backup", nil)
+	req.Header.Set("Authorization", "Bearer admin")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("backup request failed: %v", err)
+	}
+	backup, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d (err %v): %s", resp.StatusCode, err, backup)
+	}
+	version := resp.Trailer.Get("X-Backup-Version")
+	if version == "" || version == "0" {
+		t.Errorf("expected a backup version trailer, got %q", version)
+	}
+
+	target := newTestServer(t)
+	target.adminToken = "admin"
+	restore := httptest.NewRequest(http.MethodPost, "/api/v1/admin/restore", bytes.NewReader(backup))
+	restore.Header.Set("Authorization", "Bearer admin")
+	rec := httptest.NewRecorder()
+	target.ServeHTTP(rec, restore)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 from restore, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	target.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events", nil))
+	var events []models.AuditEvent
+	if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(events) != 2 || events[0].ResourceName != "a" || events[1].ResourceName != "b" {
+		t.Errorf("expected events a and b after the restore, got %+v", events)
+	}
+
+	// An incremental backup without new events is empty
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/api/v1/admin/backup?since="+version, nil)
+	req.Header.Set("Authorization", "Bearer admin")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("incremental backup request failed: %v", err)
+	}
+	incremental, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if len(incremental) != 0 || resp.Trailer.Get("X-Backup-Version") != version {
+		t.Errorf("expected an empty backup at version %s, got %d bytes at version %q", version, len(incremental), resp.Trailer.Get("X-Backup-Version"))
+	}
+
+	rec = httptest.NewRecorder()
+	invalid := httptest.NewRequest(http.MethodGet, "/api/v1/admin/backup?since=-1", nil)
+	invalid.Header.Set("Authorization", "Bearer admin")
+	source.ServeHTTP(rec, invalid)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid since, got %d", rec.Code)
+	}
+}
+
+func TestGzipResponses(t *testing.T) {
+	s := newTestServer(t)
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		storePodUpdate(t, s, "web", fmt.Sprintf("v%d", i), start.Add(time.Duration(i)*time.Minute))
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	// A transport left to itself would negotiate gzip and hide it
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	get := func(path, acceptEncoding string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := get("/api/v1/events/default/pods/web", "gzip, deflate")
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzip response, got %q", resp.Header.Get("Content-Encoding"))
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", ct)
+	}
+	body, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("invalid gzip body: %v", err)
+	}
+	var history ObjectEventsResponse
+	if err := json.NewDecoder(body).Decode(&history); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(history.WatchEvents) != 30 {
+		t.Errorf("expected 30 events, got %d", len(history.WatchEvents))
+	}
+
+	if resp := get("/api/v1/events/default/pods/web", ""); resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("expected no compression without Accept-Encoding, got %q", resp.Header.Get("Content-Encoding"))
+	}
+	if resp := get("/api/v1/events/default/pods/web", "gzip;q=0"); resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("expected no compression for a refused gzip, got %q", resp.Header.Get("Content-Encoding"))
+	}
+	if resp := get("/health", "gzip"); resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("expected a small response to be sent uncompressed, got %q", resp.Header.Get("Content-Encoding"))
+	}
+
+	// The audit client decompresses transparently, trailers included
+	auditClient := audit.NewClient(server.URL)
+	events, err := auditClient.QueryEvents(context.Background(), audit.QueryOptions{})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	if len(events) != 30 {
+		t.Errorf("expected 30 events, got %d", len(events))
+	}
+	s.SetMaxResponseBytes(2_000)
+	events, err = auditClient.QueryEvents(context.Background(), audit.QueryOptions{})
+	if !errors.Is(err, audit.ErrResponseTruncated) || len(events) == 0 || len(events) == 30 {
+		t.Errorf("expected a truncated response, got %d events and %v", len(events), err)
+	}
+}
+
+func TestQueryEventsNamespaces(t *testing.T) {
+	s := newTestServer(t)
+	for _, namespace := range []string{"app", "app-canary", "app-staging"} {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Pod")
+		obj.SetNamespace(namespace)
+		obj.SetName("web")
+		obj.SetUID(types.UID(namespace))
+		event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.store.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"namespace=app", 1},
+		{"namespace=app,app-canary", 2},
+		{"namespace=app&namespace=app-staging", 2},
+		{"namespace=app,+app-canary,", 2},
+		{"namespace=", 3},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?"+tt.query, nil))
+
+		var events []models.AuditEvent
+		if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+			t.Fatalf("%s: invalid response %q: %v", tt.query, rec.Body.String(), err)
+		}
+		if len(events) != tt.want {
+			t.Errorf("%s: expected %d events, got %d", tt.query, tt.want, len(events))
+		}
+	}
+}
+
+func TestQueryEventsRepeatedVerb(t *testing.T) {
+	s := newTestServer(t, "a", "b")
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"verb=create&verb=delete", 2},
+		{"verb=update&verb=delete", 0},
+		// An empty value doesn't filter
+		{"verb=", 2},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?envelope=true&"+tt.query, nil))
+
+		var page EventsPage
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("%s: expected an envelope: %v", tt.query, err)
+		}
+		if len(page.Items) != tt.want {
+			t.Errorf("%s: expected %d events, got %d", tt.query, tt.want, len(page.Items))
+		}
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	s := newTestServer(t, "web")
+
+	tests := []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{path: "/api/v1/events?start=yesterday", status: http.StatusBadRequest, code: errorCodeBadRequest},
+		{path: "/api/v1/events?namespace=kube-system", status: http.StatusNotFound, code: errorCodeNoData},
+		{path: "/api/v1/events/default/pods/gone", status: http.StatusNotFound, code: errorCodeNoData},
+		{path: "/api/v1/nope", status: http.StatusNotFound, code: errorCodeNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.status, rec.Code)
+			continue
+		}
+		var body errorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error == "" || body.Code != tt.code {
+			t.Errorf("%s: expected a %s error envelope, got %q", tt.path, tt.code, rec.Body.String())
+		}
+	}
+
+	// The audit client tells empty results from other errors by the code
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := audit.NewClient(server.URL)
+	if _, err := client.GetNamespaceEvents(context.Background(), "kube-system", time.Now().Add(-time.Hour), time.Now()); !errors.Is(err, audit.ErrNoData) {
+		t.Errorf("expected ErrNoData, got %v", err)
+	}
+	_, err := client.QueryEvents(context.Background(), audit.QueryOptions{Cursor: "bogus"})
+	var apiErr *audit.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != errorCodeBadRequest || strings.Contains(apiErr.Message, "{") {
+		t.Errorf("expected a decoded bad_request error, got %v", err)
+	}
+}
+
+func TestQueryEventsLimit(t *testing.T) {
+	s := newTestServer(t, "a", "b", "c")
+	s.maxLimit = 2
+
+	tests := []struct {
+		query   string
+		status  int
+		limit   string
+		clamped bool
+	}{
+		{query: "", status: http.StatusOK, limit: "2"},
+		{query: "limit=0", status: http.StatusOK, limit: "2"},
+		{query: "limit=1", status: http.StatusOK, limit: "1"},
+		{query: "limit=2", status: http.StatusOK, limit: "2"},
+		{query: "limit=3", status: http.StatusOK, limit: "2", clamped: true},
+		{query: "limit=-1", status: http.StatusBadRequest},
+		{query: "limit=lots", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?"+tt.query, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%q: expected %d, got %d", tt.query, tt.status, rec.Code)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		if got := rec.Header().Get("X-Limit"); got != tt.limit {
+			t.Errorf("%q: expected X-Limit %s, got %q", tt.query, tt.limit, got)
+		}
+		if clamped := rec.Header().Get("X-Limit-Clamped") == "true"; clamped != tt.clamped {
+			t.Errorf("%q: expected clamped %v, got %v", tt.query, tt.clamped, clamped)
+		}
+		var events []models.AuditEvent
+		if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+			t.Fatalf("%q: failed to decode events: %v", tt.query, err)
+		}
+		if want, _ := strconv.Atoi(tt.limit); len(events) != want {
+			t.Errorf("%q: expected %d events, got %d", tt.query, want, len(events))
+		}
+	}
+}
@@ -1,10 +1,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // Config represents the watch server configuration
@@ -15,6 +20,179 @@ type Config struct {
 	RetentionDays int             `yaml:"retentionDays"`
 	ServerPort    int             `yaml:"serverPort"`
 	MaxQueryLimit int             `yaml:"maxQueryLimit"`
+	Storage       StorageConfig   `yaml:"storage"`
+
+	// AggregateScanBudget caps how long /api/v1/events/aggregate scans
+	// before returning partial counts flagged as truncated (e.g. "10s").
+	// Defaults to 10s.
+	AggregateScanBudget time.Duration `yaml:"aggregateScanBudget"`
+
+	// MaxResponseBytes caps the size of an /api/v1/events response. Events
+	// past the cap are left out and the response is flagged as truncated.
+	// Defaults to 64MiB; a negative value disables the cap.
+	MaxResponseBytes int64 `yaml:"maxResponseBytes"`
+
+	// AdminToken enables the admin endpoints (e.g. namespace purge) when set.
+	// Requests must send it as "Authorization: Bearer <token>".
+	AdminToken string `yaml:"adminToken"`
+
+	// AuthTokens require every API request but the health probes to send one
+	// of them as "Authorization: Bearer <token>". The API is unauthenticated
+	// when neither AuthTokens nor AuthTokenFile is set.
+	AuthTokens []string `yaml:"authTokens"`
+
+	// AuthTokenFile adds the tokens listed in a file, one per line, e.g. a
+	// mounted Secret. Blank lines and lines starting with # are ignored.
+	AuthTokenFile string `yaml:"authTokenFile"`
+
+	// LabelAnnotations copies object labels into stored event annotations,
+	// keyed by label with the annotation key as value
+	// (e.g. "team.example.com/owner": "team"). Existing object annotations
+	// with the same key are kept.
+	LabelAnnotations map[string]string `yaml:"labelAnnotations"`
+
+	// IndexOwners records each object's first ownerReference on its events
+	// and indexes the events by owner UID. Off by default; it adds a key per
+	// owned event. Events are indexed by owner kind and name regardless, see
+	// /api/v1/owned.
+	IndexOwners bool `yaml:"indexOwners"`
+
+	// ReadOnly serves the API from an existing store without watching the
+	// cluster, e.g. to scale out queries against a replicated BadgerDB
+	// directory or a restored backup. No Kubernetes connection is made.
+	ReadOnly bool `yaml:"readOnly"`
+
+	// SkipNoOpUpdates drops update events that change nothing but
+	// resourceVersion, managedFields or NoOpIgnoreFields, as delivered by
+	// informer relists and resyncs. Defaults to true.
+	SkipNoOpUpdates bool `yaml:"skipNoOpUpdates"`
+
+	// NoOpIgnoreFields lists further dotted field paths (e.g. "status") that
+	// don't make an update worth storing on their own. Empty by default:
+	// the diagnostic tools rely on status transitions of pods, nodes and
+	// volumes.
+	NoOpIgnoreFields []string `yaml:"noOpIgnoreFields"`
+
+	// WorkerCount is the number of workers transforming and storing watch
+	// events off the informer goroutines. Defaults to 4.
+	WorkerCount int `yaml:"workerCount"`
+
+	// QueueSize is the number of watch events buffered for the workers.
+	// Defaults to 1000.
+	QueueSize int `yaml:"queueSize"`
+
+	// QueueFullPolicy decides what happens to a watch event when the queue
+	// is full: QueueFullBlock (the default) waits up to a second for room
+	// before dropping it, QueueFullDrop drops it right away. Dropped events
+	// are logged.
+	QueueFullPolicy string `yaml:"queueFullPolicy"`
+
+	// BatchSize is the number of watch events the workers collect before
+	// storing them in a single write. Defaults to 100; 1 stores every event
+	// in its own transaction.
+	BatchSize int `yaml:"batchSize"`
+
+	// FlushInterval is the longest a watch event waits for its batch to
+	// fill up before it is stored anyway. Defaults to 100ms.
+	FlushInterval time.Duration `yaml:"flushInterval"`
+
+	// DedupWindowSeconds collapses a Kubernetes Event that repeats the same
+	// message for the same object within this many seconds of its first
+	// occurrence into one record with an occurrenceCount, instead of a
+	// record per update. Other resources are not deduplicated. Disabled
+	// when zero.
+	DedupWindowSeconds int `yaml:"dedupWindowSeconds"`
+
+	// DeadLetterSize is the number of events whose store failed that are
+	// kept in memory and retried with backoff. Events that don't fit are
+	// dropped and logged. Defaults to 1000.
+	DeadLetterSize int `yaml:"deadLetterSize"`
+
+	// DeadLetterMaxRetries is how often a failed store is retried before
+	// the event is dropped and logged. Defaults to 5; negative disables
+	// retries.
+	DeadLetterMaxRetries int `yaml:"deadLetterMaxRetries"`
+
+	// LogFormat is LogFormatConsole (the default, human-readable development
+	// output) or LogFormatJSON for log collectors
+	LogFormat string `yaml:"logFormat"`
+
+	// LogLevel is the minimum level logged: debug, info, warn or error.
+	// Defaults to debug for console and info for JSON logs.
+	LogLevel string `yaml:"logLevel"`
+
+	// MaxDiscoveredCRDs caps the CRDs watched through discoverCRDs; CRDs
+	// beyond the cap are skipped with a warning. Every CRD starts an
+	// informer, which on large clusters can exhaust the API server's watch
+	// capacity. Zero means no cap.
+	MaxDiscoveredCRDs int `yaml:"maxDiscoveredCRDs"`
+
+	// DiscoverCRDGroups limits discovery to CRDs of matching API groups
+	// (path.Match patterns, e.g. "*.example.com"); empty allows all.
+	DiscoverCRDGroups []string `yaml:"discoverCRDGroups"`
+
+	// IgnoreCRDGroups excludes CRDs of matching API groups from discovery.
+	// It takes precedence over DiscoverCRDGroups.
+	IgnoreCRDGroups []string `yaml:"ignoreCRDGroups"`
+}
+
+// DefaultAggregateScanBudget is the default AggregateScanBudget
+const DefaultAggregateScanBudget = 10 * time.Second
+
+// DefaultMaxResponseBytes is the default MaxResponseBytes
+const DefaultMaxResponseBytes = 64 << 20
+
+// DefaultGCIntervalMinutes is the default Storage.GCIntervalMinutes
+const DefaultGCIntervalMinutes = 60
+
+// DefaultGCDiscardRatio is the default Storage.GCDiscardRatio
+const DefaultGCDiscardRatio = 0.5
+
+// DefaultBatchSize is the default BatchSize
+const DefaultBatchSize = 100
+
+// DefaultFlushInterval is the default FlushInterval
+const DefaultFlushInterval = 100 * time.Millisecond
+
+// DefaultDeadLetterSize is the default DeadLetterSize
+const DefaultDeadLetterSize = 1000
+
+// DefaultDeadLetterMaxRetries is the default DeadLetterMaxRetries
+const DefaultDeadLetterMaxRetries = 5
+
+// Log formats
+const (
+	LogFormatConsole = "console"
+	LogFormatJSON    = "json"
+)
+
+// Queue full policies
+const (
+	QueueFullBlock = "block"
+	QueueFullDrop  = "drop"
+)
+
+// StorageConfig holds BadgerDB tuning options
+type StorageConfig struct {
+	// SyncWrites makes every write fsync before returning. This trades write
+	// throughput (roughly an order of magnitude on spinning disks) for not
+	// losing the most recent events on a crash.
+	SyncWrites bool `yaml:"syncWrites"`
+
+	// SweepInterval enables a background sweep that deletes keys older than
+	// the retention period (e.g. "1h"). BadgerDB's TTL hides expired events,
+	// but their keys linger until compaction and slow down time-range
+	// queries. Disabled when zero.
+	SweepInterval time.Duration `yaml:"sweepInterval"`
+
+	// GCIntervalMinutes is how often the value log is garbage collected.
+	// Defaults to 60.
+	GCIntervalMinutes int `yaml:"gcIntervalMinutes"`
+
+	// GCDiscardRatio is the share of stale data, between 0 and 1 exclusive,
+	// a value log file needs before GC rewrites it. Lower values reclaim
+	// more space at the cost of more rewrites. Defaults to 0.5.
+	GCDiscardRatio float64 `yaml:"gcDiscardRatio"`
 }
 
 // ResourceWatch defines a Kubernetes resource type to watch
@@ -24,6 +202,36 @@ type ResourceWatch struct {
 	Kind       string `yaml:"kind"`
 	Plural     string `yaml:"plural"`
 	Namespaced bool   `yaml:"namespaced"`
+
+	// RedactFields lists dotted field paths (e.g. "data") whose values are
+	// stored as "sha256:<hex>" hashes instead of their contents. Map keys
+	// are kept. Defaults to data and stringData for Secrets; set an empty
+	// list to store Secrets unredacted.
+	RedactFields []string `yaml:"redactFields"`
+
+	// RetentionDays overrides the global retentionDays for events of this
+	// resource, e.g. to keep Deployments longer than high-volume Pods.
+	// Zero uses the global value.
+	RetentionDays int `yaml:"retentionDays"`
+
+	// LabelSelector limits the recorded objects to those matching a label
+	// selector (e.g. "tier=critical"). Listing a kind several times records
+	// objects matching any of its selectors.
+	LabelSelector string `yaml:"labelSelector"`
+
+	// FieldSelector limits the recorded objects to those matching a field
+	// selector over dotted string field paths (e.g. "status.phase!=Running"
+	// or "type=Warning"), on top of LabelSelector. Unlike the API server,
+	// any string field can be selected on; missing fields read as empty.
+	FieldSelector string `yaml:"fieldSelector"`
+}
+
+// Redactions returns the field paths to redact for the resource
+func (r ResourceWatch) Redactions() []string {
+	if r.RedactFields == nil && r.Kind == "Secret" {
+		return []string{"data", "stringData"}
+	}
+	return r.RedactFields
 }
 
 // LoadConfig reads configuration from a YAML file
@@ -33,7 +241,9 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var cfg Config
+	// Defaults for fields whose zero value is meaningful; they are kept
+	// unless the file sets them
+	cfg := Config{SkipNoOpUpdates: true}
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config YAML: %w", err)
 	}
@@ -48,21 +258,172 @@ func LoadConfig(path string) (*Config, error) {
 	if cfg.MaxQueryLimit == 0 {
 		cfg.MaxQueryLimit = 1000
 	}
+	if cfg.AggregateScanBudget == 0 {
+		cfg.AggregateScanBudget = DefaultAggregateScanBudget
+	}
+	if cfg.MaxResponseBytes == 0 {
+		cfg.MaxResponseBytes = DefaultMaxResponseBytes
+	}
 	if cfg.StoragePath == "" {
 		cfg.StoragePath = "/data/watch-events"
 	}
+	if cfg.Storage.GCIntervalMinutes == 0 {
+		cfg.Storage.GCIntervalMinutes = DefaultGCIntervalMinutes
+	}
+	if cfg.Storage.GCDiscardRatio == 0 {
+		cfg.Storage.GCDiscardRatio = DefaultGCDiscardRatio
+	}
+	if cfg.WorkerCount == 0 {
+		cfg.WorkerCount = 4
+	}
+	if cfg.QueueSize == 0 {
+		cfg.QueueSize = 1000
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.DeadLetterSize == 0 {
+		cfg.DeadLetterSize = DefaultDeadLetterSize
+	}
+	if cfg.DeadLetterMaxRetries == 0 {
+		cfg.DeadLetterMaxRetries = DefaultDeadLetterMaxRetries
+	}
+	switch cfg.QueueFullPolicy {
+	case "":
+		cfg.QueueFullPolicy = QueueFullBlock
+	case QueueFullBlock, QueueFullDrop:
+	default:
+		return nil, fmt.Errorf("invalid queueFullPolicy %q: must be %q or %q", cfg.QueueFullPolicy, QueueFullBlock, QueueFullDrop)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
 
+// Validate checks the configuration for values that would only fail at
+// runtime and returns all problems found, joined. A kind may be listed
+// several times, with different label or field selectors, but only for one version:
+// watching two versions would record every event twice.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.ServerPort < 1 || c.ServerPort > 65535 {
+		errs = append(errs, fmt.Errorf("serverPort %d: must be between 1 and 65535", c.ServerPort))
+	}
+	if c.RetentionDays < 0 {
+		errs = append(errs, fmt.Errorf("retentionDays %d: must not be negative", c.RetentionDays))
+	}
+	if c.Storage.GCIntervalMinutes < 1 {
+		errs = append(errs, fmt.Errorf("storage.gcIntervalMinutes %d: must be positive", c.Storage.GCIntervalMinutes))
+	}
+	if c.Storage.GCDiscardRatio <= 0 || c.Storage.GCDiscardRatio >= 1 {
+		errs = append(errs, fmt.Errorf("storage.gcDiscardRatio %g: must be between 0 and 1 exclusive", c.Storage.GCDiscardRatio))
+	}
+	if c.DedupWindowSeconds < 0 {
+		errs = append(errs, fmt.Errorf("dedupWindowSeconds %d: must not be negative", c.DedupWindowSeconds))
+	}
+	if c.FlushInterval < 0 {
+		errs = append(errs, fmt.Errorf("flushInterval %s: must not be negative", c.FlushInterval))
+	}
+
+	type groupKind struct{ group, kind string }
+	versions := make(map[groupKind]string)
+	type selectorPair struct{ labels, fields string }
+	selectors := make(map[groupKind]map[selectorPair]bool)
+	for i, resource := range c.Resources {
+		if resource.Version == "" || resource.Kind == "" {
+			errs = append(errs, fmt.Errorf("resources[%d]: version and kind are required", i))
+			continue
+		}
+		if resource.RetentionDays < 0 {
+			errs = append(errs, fmt.Errorf("resources[%d] (%s): retentionDays %d must not be negative", i, resource.Kind, resource.RetentionDays))
+		}
+		if _, err := labels.Parse(resource.LabelSelector); err != nil {
+			errs = append(errs, fmt.Errorf("resources[%d] (%s): invalid labelSelector %q: %w", i, resource.Kind, resource.LabelSelector, err))
+		}
+		if _, err := fields.ParseSelector(resource.FieldSelector); err != nil {
+			errs = append(errs, fmt.Errorf("resources[%d] (%s): invalid fieldSelector %q: %w", i, resource.Kind, resource.FieldSelector, err))
+		}
+
+		key := groupKind{resource.Group, resource.Kind}
+		if version, ok := versions[key]; ok && version != resource.Version {
+			errs = append(errs, fmt.Errorf("resources[%d] (%s): listed with versions %s and %s", i, resource.Kind, version, resource.Version))
+			continue
+		}
+		versions[key] = resource.Version
+		if selectors[key] == nil {
+			selectors[key] = make(map[selectorPair]bool)
+		}
+		pair := selectorPair{resource.LabelSelector, resource.FieldSelector}
+		if selectors[key][pair] {
+			errs = append(errs, fmt.Errorf("resources[%d] (%s): duplicate entry", i, resource.Kind))
+		}
+		selectors[key][pair] = true
+	}
+	return errors.Join(errs...)
+}
+
+// LoadAuthTokens returns AuthTokens together with the tokens of
+// AuthTokenFile
+func (c *Config) LoadAuthTokens() ([]string, error) {
+	// An empty token would let "Bearer " through
+	var tokens []string
+	for _, token := range c.AuthTokens {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	if c.AuthTokenFile == "" {
+		return tokens, nil
+	}
+
+	data, err := os.ReadFile(c.AuthTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth token file: %w", err)
+	}
+	fileTokens := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokens = append(tokens, line)
+		fileTokens++
+	}
+	// An empty file would silently leave the API unauthenticated
+	if fileTokens == 0 {
+		return nil, fmt.Errorf("auth token file %s lists no tokens", c.AuthTokenFile)
+	}
+	return tokens, nil
+}
+
 // DefaultConfig returns a configuration with common Kubernetes resources
 func DefaultConfig() *Config {
 	return &Config{
-		DiscoverCRDs:  true,
-		StoragePath:   "/data/watch-events",
-		RetentionDays: 14,
-		ServerPort:    8000,
-		MaxQueryLimit: 1000,
+		DiscoverCRDs:        true,
+		StoragePath:         "/data/watch-events",
+		RetentionDays:       14,
+		ServerPort:          8000,
+		MaxQueryLimit:       1000,
+		SkipNoOpUpdates:     true,
+		AggregateScanBudget: DefaultAggregateScanBudget,
+		MaxResponseBytes:    DefaultMaxResponseBytes,
+		Storage: StorageConfig{
+			GCIntervalMinutes: DefaultGCIntervalMinutes,
+			GCDiscardRatio:    DefaultGCDiscardRatio,
+		},
+		WorkerCount:          4,
+		QueueSize:            1000,
+		QueueFullPolicy:      QueueFullBlock,
+		BatchSize:            DefaultBatchSize,
+		FlushInterval:        DefaultFlushInterval,
+		DeadLetterSize:       DefaultDeadLetterSize,
+		DeadLetterMaxRetries: DefaultDeadLetterMaxRetries,
 		Resources: []ResourceWatch{
 			{Group: "", Version: "v1", Kind: "Pod", Plural: "pods", Namespaced: true},
 			{Group: "", Version: "v1", Kind: "Node", Plural: "nodes", Namespaced: false},
@@ -73,14 +434,23 @@ func DefaultConfig() *Config {
 			{Group: "", Version: "v1", Kind: "PersistentVolume", Plural: "persistentvolumes", Namespaced: false},
 			{Group: "", Version: "v1", Kind: "Event", Plural: "events", Namespaced: true},
 			{Group: "", Version: "v1", Kind: "Namespace", Plural: "namespaces", Namespaced: false},
+			{Group: "", Version: "v1", Kind: "ServiceAccount", Plural: "serviceaccounts", Namespaced: true},
 			{Group: "apps", Version: "v1", Kind: "Deployment", Plural: "deployments", Namespaced: true},
 			{Group: "apps", Version: "v1", Kind: "ReplicaSet", Plural: "replicasets", Namespaced: true},
 			{Group: "apps", Version: "v1", Kind: "StatefulSet", Plural: "statefulsets", Namespaced: true},
 			{Group: "apps", Version: "v1", Kind: "DaemonSet", Plural: "daemonsets", Namespaced: true},
 			{Group: "batch", Version: "v1", Kind: "Job", Plural: "jobs", Namespaced: true},
 			{Group: "batch", Version: "v1", Kind: "CronJob", Plural: "cronjobs", Namespaced: true},
+			{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler", Plural: "horizontalpodautoscalers", Namespaced: true},
 			{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress", Plural: "ingresses", Namespaced: true},
 			{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy", Plural: "networkpolicies", Namespaced: true},
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role", Plural: "roles", Namespaced: true},
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole", Plural: "clusterroles", Namespaced: false},
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding", Plural: "rolebindings", Namespaced: true},
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding", Plural: "clusterrolebindings", Namespaced: false},
+			{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration", Plural: "mutatingwebhookconfigurations", Namespaced: false},
+			{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration", Plural: "validatingwebhookconfigurations", Namespaced: false},
+			{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService", Plural: "apiservices", Namespaced: false},
 		},
 	}
 }
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	pod := ResourceWatch{Version: "v1", Kind: "Pod"}
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		// want lists substrings of the expected errors; none for a valid config
+		want []string
+	}{
+		{name: "default config", modify: func(*Config) {}},
+		{
+			name: "kind listed with several selectors",
+			modify: func(c *Config) {
+				critical := pod
+				critical.LabelSelector = "tier=critical"
+				c.Resources = []ResourceWatch{pod, critical}
+			},
+		},
+		{name: "zero port", modify: func(c *Config) { c.ServerPort = 0 }, want: []string{"serverPort 0"}},
+		{name: "port out of range", modify: func(c *Config) { c.ServerPort = 70000 }, want: []string{"serverPort 70000"}},
+		{name: "negative retention", modify: func(c *Config) { c.RetentionDays = -1 }, want: []string{"retentionDays -1"}},
+		{name: "zero GC interval", modify: func(c *Config) { c.Storage.GCIntervalMinutes = 0 }, want: []string{"storage.gcIntervalMinutes 0"}},
+		{name: "zero GC discard ratio", modify: func(c *Config) { c.Storage.GCDiscardRatio = 0 }, want: []string{"storage.gcDiscardRatio 0"}},
+		{name: "GC discard ratio of 1", modify: func(c *Config) { c.Storage.GCDiscardRatio = 1 }, want: []string{"storage.gcDiscardRatio 1"}},
+		{name: "GC discard ratio above 1", modify: func(c *Config) { c.Storage.GCDiscardRatio = 1.5 }, want: []string{"storage.gcDiscardRatio 1.5"}},
+		{name: "negative dedup window", modify: func(c *Config) { c.DedupWindowSeconds = -1 }, want: []string{"dedupWindowSeconds -1"}},
+		{name: "negative flush interval", modify: func(c *Config) { c.FlushInterval = -time.Second }, want: []string{"flushInterval -1s"}},
+		{
+			name:   "negative resource retention",
+			modify: func(c *Config) { c.Resources = []ResourceWatch{{Version: "v1", Kind: "Pod", RetentionDays: -3}} },
+			want:   []string{"resources[0] (Pod): retentionDays -3"},
+		},
+		{
+			name:   "missing kind",
+			modify: func(c *Config) { c.Resources = []ResourceWatch{{Version: "v1"}} },
+			want:   []string{"resources[0]: version and kind are required"},
+		},
+		{
+			name:   "missing version",
+			modify: func(c *Config) { c.Resources = []ResourceWatch{{Kind: "Pod"}} },
+			want:   []string{"resources[0]: version and kind are required"},
+		},
+		{
+			name: "invalid selector",
+			modify: func(c *Config) {
+				c.Resources = []ResourceWatch{{Version: "v1", Kind: "Pod", LabelSelector: "tier in ("}}
+			},
+			want: []string{"invalid labelSelector"},
+		},
+		{
+			name: "invalid field selector",
+			modify: func(c *Config) {
+				c.Resources = []ResourceWatch{{Version: "v1", Kind: "Pod", FieldSelector: "status.phase"}}
+			},
+			want: []string{"invalid fieldSelector"},
+		},
+		{
+			name:   "duplicate entry",
+			modify: func(c *Config) { c.Resources = []ResourceWatch{pod, pod} },
+			want:   []string{"resources[1] (Pod): duplicate entry"},
+		},
+		{
+			name: "kind listed with several field selectors",
+			modify: func(c *Config) {
+				running := pod
+				running.FieldSelector = "status.phase=Running"
+				c.Resources = []ResourceWatch{pod, running}
+			},
+		},
+		{
+			name: "several versions of a kind",
+			modify: func(c *Config) {
+				c.Resources = []ResourceWatch{
+					{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"},
+					{Group: "autoscaling", Version: "v1", Kind: "HorizontalPodAutoscaler"},
+				}
+			},
+			want: []string{"listed with versions v2 and v1"},
+		},
+		{
+			name: "all problems reported",
+			modify: func(c *Config) {
+				c.ServerPort = -1
+				c.RetentionDays = -1
+				c.Resources = []ResourceWatch{{Kind: "Pod"}, pod, pod}
+			},
+			want: []string{"serverPort -1", "retentionDays -1", "resources[0]", "resources[2] (Pod): duplicate entry"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q in %q", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigGC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resources.yaml")
+	if err := os.WriteFile(path, []byte("storage:\n  gcIntervalMinutes: 15\n  gcDiscardRatio: 0.2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Storage.GCIntervalMinutes != 15 || cfg.Storage.GCDiscardRatio != 0.2 {
+		t.Errorf("expected the configured GC settings, got %+v", cfg.Storage)
+	}
+
+	if err := os.WriteFile(path, []byte("storage:\n  gcDiscardRatio: 1.5\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "gcDiscardRatio 1.5") {
+		t.Errorf("expected an out-of-range discard ratio to be rejected, got %v", err)
+	}
+}
+
+func TestLoadConfigValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resources.yaml")
+	if err := os.WriteFile(path, []byte("retentionDays: -5\nresources:\n  - kind: Pod\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "retentionDays -5") || !strings.Contains(err.Error(), "version and kind") {
+		t.Errorf("expected both problems to be reported, got %v", err)
+	}
+}
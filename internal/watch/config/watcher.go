@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay is how long the watcher waits for a burst of file events,
+// e.g. an editor's write and rename, to settle before reloading
+const reloadDelay = 200 * time.Millisecond
+
+// Watcher reloads the configuration file whenever it changes. It watches the
+// file's directory rather than the file, so it also follows ConfigMap
+// mounts, which swap a symlink instead of writing the file.
+type Watcher struct {
+	path     string
+	onChange func(*Config)
+	onError  func(error)
+
+	// applied is the file content last loaded or rejected, so events that
+	// don't change it are ignored
+	applied []byte
+}
+
+// NewWatcher creates a watcher for the configuration file at path. onChange
+// receives each valid new configuration; onError receives files that fail
+// to load or validate, while the running configuration stays in effect.
+func NewWatcher(path string, onChange func(*Config), onError func(error)) *Watcher {
+	return &Watcher{path: path, onChange: onChange, onError: onError}
+}
+
+// Run watches the file until ctx is cancelled. The content at the time Run
+// starts is taken as the running configuration.
+func (w *Watcher) Run(ctx context.Context) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	if err := fsWatcher.Add(filepath.Dir(w.path)); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", filepath.Dir(w.path), err)
+	}
+	w.applied, _ = os.ReadFile(w.path)
+
+	var timer *time.Timer
+	var reload <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			if timer == nil {
+				timer = time.NewTimer(reloadDelay)
+			} else {
+				timer.Reset(reloadDelay)
+			}
+			reload = timer.C
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.onError(fmt.Errorf("config watcher: %w", err))
+		case <-reload:
+			reload = nil
+			w.reload()
+		}
+	}
+}
+
+// reload loads the file if its content changed and passes it on
+func (w *Watcher) reload() {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		// Mid-swap or deleted: wait for the next event
+		return
+	}
+	if bytes.Equal(data, w.applied) {
+		return
+	}
+	w.applied = data
+
+	cfg, err := LoadConfig(w.path)
+	if err != nil {
+		w.onError(fmt.Errorf("invalid configuration, keeping the running one: %w", err))
+		return
+	}
+	w.onChange(cfg)
+}
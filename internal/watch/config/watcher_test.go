@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const podsConfig = `resources:
+  - version: v1
+    kind: Pod
+`
+
+func TestWatcherReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resources.yaml")
+	if err := os.WriteFile(path, []byte(podsConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan *Config, 1)
+	errs := make(chan error, 1)
+	watcher := NewWatcher(path, func(cfg *Config) { changes <- cfg }, func(err error) { errs <- err })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- watcher.Run(ctx) }()
+	// Let the watcher register before writing
+	time.Sleep(50 * time.Millisecond)
+
+	added := podsConfig + "  - group: apps\n    version: v1\n    kind: Deployment\n"
+	if err := os.WriteFile(path, []byte(added), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case cfg := <-changes:
+		if len(cfg.Resources) != 2 || cfg.Resources[1].Kind != "Deployment" {
+			t.Errorf("unexpected resources %+v", cfg.Resources)
+		}
+	case err := <-errs:
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload after the file changed")
+	}
+
+	// An invalid file is reported and not applied
+	invalid := podsConfig + "    labelSelector: \"tier in (\"\n"
+	if err := os.WriteFile(path, []byte(invalid), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case cfg := <-changes:
+		t.Fatalf("invalid configuration applied: %+v", cfg.Resources)
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "labelSelector") {
+			t.Errorf("expected a labelSelector error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error for the invalid file")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+}
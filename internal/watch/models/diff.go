@@ -0,0 +1,99 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// FieldChange is a single field modified by an update. Old or New is nil
+// when the field was added or removed.
+type FieldChange struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// IsNoOpUpdate reports whether newObj differs from oldObj only in fields that
+// are not stored (resourceVersion, managedFields, ...) or in ignoreFields,
+// given as dotted paths such as "status". Informer resyncs deliver such
+// updates for every object.
+func IsNoOpUpdate(oldObj, newObj *unstructured.Unstructured, ignoreFields []string) bool {
+	before, after := cleanObject(oldObj), cleanObject(newObj)
+	for _, field := range ignoreFields {
+		path := strings.Split(field, ".")
+		unstructured.RemoveNestedField(before, path...)
+		unstructured.RemoveNestedField(after, path...)
+	}
+	return reflect.DeepEqual(before, after)
+}
+
+// mergePatch returns the JSON merge patch (RFC 7386) turning before into
+// after. Nested objects are diffed recursively; any other changed value,
+// including lists, is replaced whole. The patch is empty, not nil, when the
+// objects are equal.
+func mergePatch(before, after map[string]any) map[string]any {
+	patch := make(map[string]any)
+	for key, newValue := range after {
+		oldValue, ok := before[key]
+		if !ok {
+			patch[key] = newValue
+			continue
+		}
+
+		oldMap, oldIsMap := oldValue.(map[string]any)
+		newMap, newIsMap := newValue.(map[string]any)
+		if oldIsMap && newIsMap {
+			if nested := mergePatch(oldMap, newMap); len(nested) > 0 {
+				patch[key] = nested
+			}
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			patch[key] = newValue
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	return patch
+}
+
+// changedFields lists the leaf fields that differ between before and after,
+// sorted by their dotted path
+func changedFields(before, after map[string]any, prefix string) []FieldChange {
+	var changes []FieldChange
+	keys := make(map[string]bool)
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		oldValue, newValue := before[key], after[key]
+		oldMap, oldIsMap := oldValue.(map[string]any)
+		newMap, newIsMap := newValue.(map[string]any)
+		switch {
+		case oldIsMap && newIsMap:
+			changes = append(changes, changedFields(oldMap, newMap, path)...)
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, FieldChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Enricher attaches derived fields to an audit event before it is stored
+type Enricher interface {
+	Enrich(obj *unstructured.Unstructured, event *AuditEvent)
+}
+
+// OwnerEnricher records the object's first ownerReference as the event's
+// Owner, e.g. the ReplicaSet of a Pod, so the store can index events by
+// owner UID. Owners are always in the object's namespace or cluster-scoped;
+// the namespace of the owned object is recorded.
+type OwnerEnricher struct{}
+
+// Enrich implements Enricher
+func (OwnerEnricher) Enrich(obj *unstructured.Unstructured, event *AuditEvent) {
+	refs := obj.GetOwnerReferences()
+	if len(refs) == 0 || refs[0].UID == "" {
+		return
+	}
+	event.Owner = &ObjectReference{
+		Kind:      refs[0].Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      refs[0].Name,
+		UID:       string(refs[0].UID),
+	}
+}
+
+// LabelEnricher copies selected object labels into event annotations,
+// e.g. mapping a team ownership label to a "team" annotation.
+// The object's own annotations take precedence: a derived value is never
+// written over an existing annotation with the same key.
+type LabelEnricher struct {
+	mapping map[string]string
+}
+
+// NewLabelEnricher creates an enricher from a label key to annotation key mapping
+func NewLabelEnricher(mapping map[string]string) *LabelEnricher {
+	return &LabelEnricher{mapping: mapping}
+}
+
+// Enrich implements Enricher
+func (e *LabelEnricher) Enrich(obj *unstructured.Unstructured, event *AuditEvent) {
+	labels := obj.GetLabels()
+	for labelKey, annotationKey := range e.mapping {
+		value, ok := labels[labelKey]
+		if !ok {
+			continue
+		}
+		if _, exists := event.Annotations[annotationKey]; exists {
+			continue
+		}
+		if event.Annotations == nil {
+			event.Annotations = make(map[string]string)
+		}
+		event.Annotations[annotationKey] = value
+	}
+}
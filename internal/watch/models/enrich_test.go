@@ -0,0 +1,121 @@
+package models
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newPod(labels, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetNamespace("default")
+	obj.SetName("web")
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func TestLabelEnricher(t *testing.T) {
+	enricher := NewLabelEnricher(map[string]string{"team.example.com/owner": "team"})
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		want        map[string]string
+	}{
+		{
+			name:   "mapped label present",
+			labels: map[string]string{"team.example.com/owner": "payments"},
+			want:   map[string]string{"team": "payments"},
+		},
+		{
+			name:        "mapped label absent",
+			labels:      map[string]string{"app": "web"},
+			annotations: map[string]string{"note": "x"},
+			want:        map[string]string{"note": "x"},
+		},
+		{
+			name:   "nil annotations",
+			labels: map[string]string{"team.example.com/owner": "payments"},
+			want:   map[string]string{"team": "payments"},
+		},
+		{
+			name:        "existing annotation wins",
+			labels:      map[string]string{"team.example.com/owner": "payments"},
+			annotations: map[string]string{"team": "platform"},
+			want:        map[string]string{"team": "platform"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := newPod(tt.labels, tt.annotations)
+			event := &AuditEvent{Annotations: obj.GetAnnotations()}
+
+			enricher.Enrich(obj, event)
+
+			if len(event.Annotations) != len(tt.want) {
+				t.Fatalf("expected annotations %v, got %v", tt.want, event.Annotations)
+			}
+			for key, value := range tt.want {
+				if event.Annotations[key] != value {
+					t.Errorf("expected annotation %s=%s, got %q", key, value, event.Annotations[key])
+				}
+			}
+		})
+	}
+}
+
+// recordingEnricher records the events it was called with
+type recordingEnricher struct {
+	events []*AuditEvent
+}
+
+func (e *recordingEnricher) Enrich(obj *unstructured.Unstructured, event *AuditEvent) {
+	e.events = append(e.events, event)
+}
+
+func TestTransformWatchEventRunsEnrichers(t *testing.T) {
+	first := &recordingEnricher{}
+	second := &recordingEnricher{}
+
+	event, err := TransformWatchEvent(newPod(nil, nil), EventTypeAdded, first, second)
+	if err != nil {
+		t.Fatalf("TransformWatchEvent failed: %v", err)
+	}
+
+	for i, enricher := range []*recordingEnricher{first, second} {
+		if len(enricher.events) != 1 || enricher.events[0] != event {
+			t.Errorf("enricher %d was not called with the built event", i)
+		}
+	}
+}
+
+func TestOwnerEnricher(t *testing.T) {
+	pod := newPod(nil, nil)
+	pod.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-5d8f", UID: "rs-uid"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "cm-uid"},
+	})
+
+	event, err := TransformWatchEvent(pod, EventTypeAdded, OwnerEnricher{})
+	if err != nil {
+		t.Fatalf("TransformWatchEvent failed: %v", err)
+	}
+	want := ObjectReference{Kind: "ReplicaSet", Namespace: "default", Name: "web-5d8f", UID: "rs-uid"}
+	if event.Owner == nil || *event.Owner != want {
+		t.Errorf("expected owner %+v, got %+v", want, event.Owner)
+	}
+
+	event, err = TransformWatchEvent(newPod(nil, nil), EventTypeAdded, OwnerEnricher{})
+	if err != nil {
+		t.Fatalf("TransformWatchEvent failed: %v", err)
+	}
+	if event.Owner != nil {
+		t.Errorf("expected no owner for an unowned pod, got %+v", event.Owner)
+	}
+}
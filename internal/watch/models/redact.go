@@ -0,0 +1,144 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Redactor replaces sensitive field values of stored objects with a stable
+// hash, e.g. the data of Secrets. Map keys are kept, so it remains visible
+// which keys exist and which of them changed, but not their contents.
+type Redactor struct {
+	fields map[string][][]string
+}
+
+// NewRedactor creates a redactor from a kind to dotted field paths mapping,
+// e.g. "Secret": ["data", "stringData"]
+func NewRedactor(fields map[string][]string) *Redactor {
+	r := &Redactor{fields: make(map[string][][]string)}
+	for kind, paths := range fields {
+		for _, path := range paths {
+			r.fields[kind] = append(r.fields[kind], strings.Split(path, "."))
+		}
+	}
+	return r
+}
+
+// lastAppliedAnnotation holds the full object as last applied by kubectl,
+// redacted fields included
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Enrich implements Enricher. It redacts the stored object as well as the
+// diff and changed fields of updates. Objects of kinds with redacted fields
+// also have their last-applied-configuration annotation hashed, as it
+// repeats the redacted values.
+func (r *Redactor) Enrich(obj *unstructured.Unstructured, event *AuditEvent) {
+	paths := r.fields[obj.GetKind()]
+	if len(paths) > 0 {
+		redactLastApplied(event)
+	}
+	for _, path := range paths {
+		redactPath(event.ObjectChanges, path)
+		redactPath(event.Diff, path)
+
+		prefix := strings.Join(path, ".")
+		for i, change := range event.ChangedFields {
+			if change.Path == prefix || strings.HasPrefix(change.Path, prefix+".") {
+				event.ChangedFields[i].Old = redactValue(change.Old)
+				event.ChangedFields[i].New = redactValue(change.New)
+			}
+		}
+	}
+}
+
+// redactLastApplied hashes the last-applied-configuration annotation in the
+// event's annotations, stored object, diff and changed fields
+func redactLastApplied(event *AuditEvent) {
+	if value, ok := event.Annotations[lastAppliedAnnotation]; ok {
+		// The annotations may be shared with the object, so copy them
+		annotations := make(map[string]string, len(event.Annotations))
+		for key, v := range event.Annotations {
+			annotations[key] = v
+		}
+		annotations[lastAppliedAnnotation] = hashValue(value)
+		event.Annotations = annotations
+	}
+
+	path := []string{"metadata", "annotations", lastAppliedAnnotation}
+	redactPath(event.ObjectChanges, path)
+	redactPath(event.Diff, path)
+
+	changePath := strings.Join(path, ".")
+	for i, change := range event.ChangedFields {
+		// An annotations map that was added or removed is reported whole
+		if change.Path == changePath || change.Path == "metadata.annotations" {
+			event.ChangedFields[i].Old = redactLastAppliedValue(change.Old, change.Path)
+			event.ChangedFields[i].New = redactLastAppliedValue(change.New, change.Path)
+		}
+	}
+}
+
+// redactLastAppliedValue redacts the annotation within a changed value at
+// changePath: the annotation itself or the annotations map
+func redactLastAppliedValue(value any, changePath string) any {
+	if changePath != "metadata.annotations" {
+		return redactValue(value)
+	}
+	if annotations, ok := value.(map[string]any); ok {
+		redactPath(annotations, []string{lastAppliedAnnotation})
+	}
+	return value
+}
+
+// redactPath replaces the value at path in obj with its redacted form
+func redactPath(obj map[string]any, path []string) {
+	for _, field := range path[:len(path)-1] {
+		next, ok := obj[field].(map[string]any)
+		if !ok {
+			return
+		}
+		obj = next
+	}
+
+	last := path[len(path)-1]
+	if value, ok := obj[last]; ok {
+		obj[last] = redactValue(value)
+	}
+}
+
+// redactValue hashes every leaf of value, keeping map keys. nil stays nil so
+// removed fields in merge patches remain recognizable.
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			redacted[key] = redactValue(item)
+		}
+		return redacted
+	default:
+		return hashValue(v)
+	}
+}
+
+// hashValue returns "sha256:<hex>" of a value. Strings are hashed as is,
+// other values by their JSON encoding.
+func hashValue(value any) string {
+	data, ok := value.(string)
+	if !ok {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			encoded = []byte(fmt.Sprint(value))
+		}
+		data = string(encoded)
+	}
+	sum := sha256.Sum256([]byte(data))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
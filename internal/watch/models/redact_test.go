@@ -0,0 +1,80 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newSecret(data map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{"data": data, "type": "Opaque"}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Secret")
+	obj.SetNamespace("default")
+	obj.SetName("db")
+	return obj
+}
+
+func TestRedactorSecretData(t *testing.T) {
+	redactor := NewRedactor(map[string][]string{"Secret": {"data", "stringData"}})
+	secret := newSecret(map[string]any{"password": "aHVudGVyMg==", "user": "YWRtaW4="})
+
+	event, err := TransformWatchEvent(secret, EventTypeAdded, redactor)
+	if err != nil {
+		t.Fatalf("TransformWatchEvent failed: %v", err)
+	}
+
+	data := event.ObjectChanges["data"].(map[string]any)
+	if len(data) != 2 {
+		t.Fatalf("expected both keys to be kept, got %v", data)
+	}
+	for key, value := range data {
+		if hash, _ := value.(string); !strings.HasPrefix(hash, "sha256:") {
+			t.Errorf("expected %s to be hashed, got %v", key, value)
+		}
+	}
+	if event.ObjectChanges["type"] != "Opaque" {
+		t.Errorf("expected fields outside data to be kept, got %v", event.ObjectChanges["type"])
+	}
+
+	// The informer's object must not be touched
+	if got := secret.Object["data"].(map[string]any)["password"]; got != "aHVudGVyMg==" {
+		t.Errorf("original object was mutated: %v", got)
+	}
+
+	// Hashes are stable across events, so unchanged values compare equal
+	again, err := TransformWatchEvent(secret, EventTypeModified, redactor)
+	if err != nil {
+		t.Fatalf("TransformWatchEvent failed: %v", err)
+	}
+	if again.ObjectChanges["data"].(map[string]any)["password"] != data["password"] {
+		t.Error("expected the same hash for the same value")
+	}
+	if data["password"] == data["user"] {
+		t.Error("expected different hashes for different values")
+	}
+}
+
+func TestRedactorUpdateDiff(t *testing.T) {
+	redactor := NewRedactor(map[string][]string{"Secret": {"data"}})
+	old := newSecret(map[string]any{"password": "b2xk", "user": "YWRtaW4="})
+	updated := newSecret(map[string]any{"password": "bmV3", "user": "YWRtaW4="})
+
+	event, err := TransformUpdateEvent(old, updated, redactor)
+	if err != nil {
+		t.Fatalf("TransformUpdateEvent failed: %v", err)
+	}
+
+	diff := event.Diff["data"].(map[string]any)
+	if len(diff) != 1 || diff["password"] != hashValue("bmV3") {
+		t.Errorf("expected only the hashed password in the diff, got %v", diff)
+	}
+	if len(event.ChangedFields) != 1 {
+		t.Fatalf("expected one changed field, got %+v", event.ChangedFields)
+	}
+	change := event.ChangedFields[0]
+	if change.Path != "data.password" || change.Old != hashValue("b2xk") || change.New != hashValue("bmV3") {
+		t.Errorf("expected hashed old and new values, got %+v", change)
+	}
+}
@@ -1,9 +1,12 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -35,8 +38,47 @@ type AuditEvent struct {
 	Stage          string            `json:"stage"`
 	RequestURI     string            `json:"requestURI"`
 	SourceIPs      []string          `json:"sourceIPs,omitempty"`
+
+	// Diff is a JSON merge patch from the previous to the updated object of
+	// an update event; removed fields are null
+	Diff map[string]any `json:"diff,omitempty"`
+	// ChangedFields lists the leaf fields the update modified with their old
+	// and new values, sorted by path
+	ChangedFields []FieldChange `json:"changedFields,omitempty"`
+
+	// Owner is the object's first ownerReference, set by OwnerEnricher
+	Owner *ObjectReference `json:"owner,omitempty"`
+	// OwnerRefs are all of the object's ownerReferences, e.g. the
+	// ReplicaSet of a Pod
+	OwnerRefs []ObjectReference `json:"ownerRefs,omitempty"`
+
+	// EventInfo holds the reason, type and note of a Kubernetes Event
+	EventInfo *EventInfo `json:"eventInfo,omitempty"`
+	// OccurrenceCount is how often a Kubernetes Event repeated within the
+	// dedup window (see config DedupWindowSeconds) and was collapsed into
+	// this record; zero when dedup is disabled
+	OccurrenceCount int `json:"occurrenceCount,omitempty"`
+}
+
+// EventInfo describes what a Kubernetes Event reports, from core/v1 or
+// events.k8s.io/v1 fields
+type EventInfo struct {
+	Reason string `json:"reason,omitempty"`
+	// Type is Normal or Warning
+	Type string `json:"type,omitempty"`
+	// Note is the human-readable message of the Event
+	Note string `json:"note,omitempty"`
+	// Count is how often the Event occurred, for Events the kubelet and
+	// controllers deduplicate
+	Count     int64     `json:"count,omitempty"`
+	FirstSeen time.Time `json:"firstSeen,omitzero"`
+	LastSeen  time.Time `json:"lastSeen,omitzero"`
 }
 
+// ErrMissingKind is returned by TransformWatchEvent for objects without a Kind,
+// which would otherwise be indexed under a meaningless resource type
+var ErrMissingKind = errors.New("object has no kind")
+
 // EventType represents the type of watch event
 type EventType string
 
@@ -47,11 +89,15 @@ const (
 )
 
 // TransformWatchEvent converts an unstructured Kubernetes object and event type
-// into an AuditEvent format suitable for storage and API responses
-func TransformWatchEvent(obj *unstructured.Unstructured, eventType EventType) (*AuditEvent, error) {
+// into an AuditEvent format suitable for storage and API responses.
+// Enrichers run in order after the event has been built.
+func TransformWatchEvent(obj *unstructured.Unstructured, eventType EventType, enrichers ...Enricher) (*AuditEvent, error) {
 	if obj == nil {
 		return nil, fmt.Errorf("object cannot be nil")
 	}
+	if obj.GetKind() == "" {
+		return nil, fmt.Errorf("%w: %s/%s", ErrMissingKind, obj.GetNamespace(), obj.GetName())
+	}
 
 	// Map event type to verb
 	verb := mapEventTypeToVerb(eventType)
@@ -60,7 +106,7 @@ func TransformWatchEvent(obj *unstructured.Unstructured, eventType EventType) (*
 	namespace := obj.GetNamespace()
 	name := obj.GetName()
 	kind := obj.GetKind()
-	resourceType := kindToResourceType(kind)
+	resourceType := KindToResourceType(kind)
 
 	// Clean the object by removing unnecessary fields
 	cleanedObject := cleanObject(obj)
@@ -80,11 +126,58 @@ func TransformWatchEvent(obj *unstructured.Unstructured, eventType EventType) (*
 		Stage:          StageResponseComplete,
 		RequestURI:     buildRequestURI(namespace, resourceType, name),
 		SourceIPs:      []string{}, // Watch events don't have source IPs
+		OwnerRefs:      ownerRefs(obj),
+	}
+
+	// Events carry their own message, which says far more than the verb
+	if info := extractEventInfo(obj); info != nil {
+		event.EventInfo = info
+		if info.Note != "" {
+			event.Message = info.Note
+			if info.Reason != "" {
+				event.Message = info.Reason + ": " + info.Note
+			}
+		}
+	}
+
+	for _, enricher := range enrichers {
+		enricher.Enrich(obj, event)
 	}
 
 	return event, nil
 }
 
+// TransformUpdateEvent converts a modified object into an update AuditEvent
+// carrying the diff from the previous object. Fields dropped by cleanObject
+// (resourceVersion, managedFields, ...) are not compared, so an update that
+// only touches them has an empty Diff. Without a previous object the event
+// has no diff. Enrichers run after the diff has been computed, so they can
+// redact it.
+func TransformUpdateEvent(oldObj, newObj *unstructured.Unstructured, enrichers ...Enricher) (*AuditEvent, error) {
+	event, err := TransformWatchEvent(newObj, EventTypeModified)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldObj != nil {
+		before, after := cleanObject(oldObj), cleanObject(newObj)
+		event.Diff = mergePatch(before, after)
+		event.ChangedFields = changedFields(before, after, "")
+	}
+
+	for _, enricher := range enrichers {
+		enricher.Enrich(newObj, event)
+	}
+
+	return event, nil
+}
+
+// Unchanged reports whether an update event carries a diff that is empty,
+// i.e. the update only touched fields that are not stored
+func (e *AuditEvent) Unchanged() bool {
+	return e.Verb == "update" && e.Diff != nil && len(e.Diff) == 0
+}
+
 // mapEventTypeToVerb converts watch event types to audit verbs
 func mapEventTypeToVerb(eventType EventType) string {
 	switch eventType {
@@ -99,9 +192,25 @@ func mapEventTypeToVerb(eventType EventType) string {
 	}
 }
 
-// kindToResourceType converts a Kind (e.g., "Pod") to resource type (e.g., "pods")
-// This is a simple pluralization - may need enhancement for irregular plurals
-func kindToResourceType(kind string) string {
+// SetResourceType changes the resource type of an event along with the
+// message and request URI derived from it, e.g. once the authoritative
+// resource type of its Kind is known. The message of a Kubernetes Event is
+// its own and stays.
+func (e *AuditEvent) SetResourceType(resourceType string) {
+	if resourceType == "" || resourceType == e.ResourceType {
+		return
+	}
+	if e.Message == formatMessage(e.Verb, e.ResourceType, e.Namespace, e.ResourceName) {
+		e.Message = formatMessage(e.Verb, resourceType, e.Namespace, e.ResourceName)
+	}
+	e.ResourceType = resourceType
+	e.RequestURI = buildRequestURI(e.Namespace, resourceType, e.ResourceName)
+}
+
+// KindToResourceType converts a Kind (e.g., "Pod") to resource type (e.g., "pods")
+// by simple pluralization rules. It is the fallback for Kinds the cluster's
+// discovery API can't resolve.
+func KindToResourceType(kind string) string {
 	lower := strings.ToLower(kind)
 
 	// Handle special cases
@@ -129,6 +238,45 @@ func kindToResourceType(kind string) string {
 	return lower + "s"
 }
 
+// ResourceTypeToKind converts a resource type (e.g., "pods") to Kind (e.g.,
+// "Pod") by simple singularization rules. It is the fallback for resource
+// types the cluster's discovery API can't resolve.
+func ResourceTypeToKind(resourceType string) string {
+	// Handle special cases
+	irregularSingulars := map[string]string{
+		"endpoints":                 "Endpoints",
+		"ingresses":                 "Ingress",
+		"networkpolicies":           "NetworkPolicy",
+		"poddisruptionbudgets":      "PodDisruptionBudget",
+		"priorityclasses":           "PriorityClass",
+		"storageclasses":            "StorageClass",
+		"customresourcedefinitions": "CustomResourceDefinition",
+	}
+
+	if singular, ok := irregularSingulars[resourceType]; ok {
+		return singular
+	}
+
+	// Simple singularization rules
+	singular := resourceType
+	if strings.HasSuffix(singular, "ies") {
+		singular = strings.TrimSuffix(singular, "ies") + "y"
+	} else if strings.HasSuffix(singular, "ses") {
+		singular = strings.TrimSuffix(singular, "ses")
+	} else if strings.HasSuffix(singular, "es") {
+		singular = strings.TrimSuffix(singular, "es")
+	} else if strings.HasSuffix(singular, "s") {
+		singular = strings.TrimSuffix(singular, "s")
+	}
+
+	// Capitalize first letter
+	if len(singular) > 0 {
+		return strings.ToUpper(singular[:1]) + singular[1:]
+	}
+
+	return singular
+}
+
 // cleanObject removes fields that are not needed for audit purposes
 // This reduces storage size and removes noise
 func cleanObject(obj *unstructured.Unstructured) map[string]any {
@@ -150,9 +298,19 @@ func cleanObject(obj *unstructured.Unstructured) map[string]any {
 // formatMessage creates a human-readable message for the audit event
 func formatMessage(verb, resourceType, namespace, name string) string {
 	if namespace == "" {
-		return fmt.Sprintf("%s %s %s", strings.Title(verb), resourceType, name)
+		return fmt.Sprintf("%s %s %s", capitalize(verb), resourceType, name)
+	}
+	return fmt.Sprintf("%s %s %s/%s", capitalize(verb), resourceType, namespace, name)
+}
+
+// capitalize upper-cases the first letter of s. Verbs are single lowercase
+// words, so this matches what the deprecated strings.Title produced for them.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
 	}
-	return fmt.Sprintf("%s %s %s/%s", strings.Title(verb), resourceType, namespace, name)
+	return string(unicode.ToUpper(r)) + s[size:]
 }
 
 // buildRequestURI constructs a Kubernetes API request URI
@@ -195,10 +353,70 @@ func ExtractInvolvedObject(obj *unstructured.Unstructured) *ObjectReference {
 	}
 }
 
+// extractEventInfo returns the EventInfo of a Kubernetes Event, reading the
+// core/v1 fields first and the events.k8s.io/v1 ones otherwise. It returns
+// nil for other objects.
+func extractEventInfo(obj *unstructured.Unstructured) *EventInfo {
+	if obj.GetKind() != "Event" {
+		return nil
+	}
+
+	info := &EventInfo{
+		Reason:    firstString(obj, []string{"reason"}),
+		Type:      firstString(obj, []string{"type"}),
+		Note:      firstString(obj, []string{"message"}, []string{"note"}),
+		FirstSeen: firstTime(obj, []string{"firstTimestamp"}, []string{"deprecatedFirstTimestamp"}, []string{"eventTime"}),
+		LastSeen:  firstTime(obj, []string{"lastTimestamp"}, []string{"series", "lastObservedTime"}, []string{"deprecatedLastTimestamp"}, []string{"eventTime"}),
+	}
+	for _, path := range [][]string{{"count"}, {"series", "count"}, {"deprecatedCount"}} {
+		if count, found, err := unstructured.NestedInt64(obj.Object, path...); found && err == nil && count > 0 {
+			info.Count = count
+			break
+		}
+	}
+	return info
+}
+
+// firstString returns the first non-empty string field of obj at paths
+func firstString(obj *unstructured.Unstructured, paths ...[]string) string {
+	for _, path := range paths {
+		if value, _, _ := unstructured.NestedString(obj.Object, path...); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// firstTime returns the first timestamp of obj at paths that parses
+func firstTime(obj *unstructured.Unstructured, paths ...[]string) time.Time {
+	for _, path := range paths {
+		value, _, _ := unstructured.NestedString(obj.Object, path...)
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ownerRefs returns the ownerReferences of obj. Owners are always in the
+// object's namespace or cluster-scoped; the namespace of obj is recorded.
+func ownerRefs(obj *unstructured.Unstructured) []ObjectReference {
+	var refs []ObjectReference
+	for _, ref := range obj.GetOwnerReferences() {
+		refs = append(refs, ObjectReference{
+			Kind:      ref.Kind,
+			Namespace: obj.GetNamespace(),
+			Name:      ref.Name,
+			UID:       string(ref.UID),
+		})
+	}
+	return refs
+}
+
 // ObjectReference represents a reference to a Kubernetes object
 type ObjectReference struct {
-	Kind      string
-	Namespace string
-	Name      string
-	UID       string
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
 }
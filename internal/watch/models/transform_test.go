@@ -0,0 +1,248 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSetResourceType(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("example.com/v1")
+	obj.SetKind("Fish")
+	obj.SetNamespace("default")
+	obj.SetName("nemo")
+
+	event, err := TransformWatchEvent(obj, EventTypeAdded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	event.SetResourceType("fish")
+
+	if event.ResourceType != "fish" || event.Message != "Create fish default/nemo" ||
+		event.RequestURI != "/api/v1/namespaces/default/fish/nemo" {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
+
+func TestTransformWatchEventEventInfo(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"involvedObject": map[string]any{"kind": "Pod", "namespace": "default", "name": "web-1"},
+		"reason":         "FailedMount",
+		"type":           "Warning",
+		"message":        `MountVolume.SetUp failed for volume "data": secret "db" not found`,
+		"count":          int64(7),
+		"firstTimestamp": "2025-03-04T10:00:00Z",
+		"lastTimestamp":  "2025-03-04T10:12:30Z",
+	}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Event")
+	obj.SetNamespace("default")
+	obj.SetName("web-1.182a")
+
+	event, err := TransformWatchEvent(obj, EventTypeModified)
+	if err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+	want := &EventInfo{
+		Reason:    "FailedMount",
+		Type:      "Warning",
+		Note:      `MountVolume.SetUp failed for volume "data": secret "db" not found`,
+		Count:     7,
+		FirstSeen: time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC),
+		LastSeen:  time.Date(2025, 3, 4, 10, 12, 30, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(event.EventInfo, want) {
+		t.Errorf("expected event info %+v, got %+v", want, event.EventInfo)
+	}
+	if wantMessage := `FailedMount: MountVolume.SetUp failed for volume "data": secret "db" not found`; event.Message != wantMessage {
+		t.Errorf("expected message %q, got %q", wantMessage, event.Message)
+	}
+
+	// The Event's message survives a resource type change
+	event.SetResourceType("events.v1")
+	if !strings.HasPrefix(event.Message, "FailedMount: ") {
+		t.Errorf("expected the event message to be kept, got %q", event.Message)
+	}
+}
+
+func TestTransformWatchEventEventsV1(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"reason":    "BackOff",
+		"type":      "Warning",
+		"note":      "Back-off restarting failed container",
+		"eventTime": "2025-03-04T10:00:00.123456Z",
+		"series":    map[string]any{"count": int64(3), "lastObservedTime": "2025-03-04T10:05:00.000000Z"},
+	}}
+	obj.SetAPIVersion("events.k8s.io/v1")
+	obj.SetKind("Event")
+	obj.SetNamespace("default")
+	obj.SetName("web-1.182b")
+
+	event, err := TransformWatchEvent(obj, EventTypeAdded)
+	if err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+	info := event.EventInfo
+	if info == nil || info.Note != "Back-off restarting failed container" || info.Count != 3 ||
+		!info.FirstSeen.Equal(time.Date(2025, 3, 4, 10, 0, 0, 123456000, time.UTC)) ||
+		!info.LastSeen.Equal(time.Date(2025, 3, 4, 10, 5, 0, 0, time.UTC)) {
+		t.Errorf("unexpected event info %+v", info)
+	}
+	if event.Message != "BackOff: Back-off restarting failed container" {
+		t.Errorf("unexpected message %q", event.Message)
+	}
+
+	// Other kinds keep the generic message
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace("default")
+	pod.SetName("web-1")
+	event, err = TransformWatchEvent(pod, EventTypeAdded)
+	if err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+	if event.EventInfo != nil || event.Message != "Create pods default/web-1" {
+		t.Errorf("expected a generic pod event, got %q and %+v", event.Message, event.EventInfo)
+	}
+}
+
+func TestTransformWatchEventOwnerRefs(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetNamespace("default")
+	obj.SetName("web-5d8f-x2k9p")
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-5d8f", UID: "rs-uid"},
+		{APIVersion: "example.com/v1", Kind: "Fish", Name: "nemo", UID: "fish-uid"},
+	})
+
+	event, err := TransformWatchEvent(obj, EventTypeAdded)
+	if err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+	want := []ObjectReference{
+		{Kind: "ReplicaSet", Namespace: "default", Name: "web-5d8f", UID: "rs-uid"},
+		{Kind: "Fish", Namespace: "default", Name: "nemo", UID: "fish-uid"},
+	}
+	if !reflect.DeepEqual(event.OwnerRefs, want) {
+		t.Errorf("expected owner references %+v, got %+v", want, event.OwnerRefs)
+	}
+	// Only enabled indexing records the Owner
+	if event.Owner != nil {
+		t.Errorf("expected no Owner without OwnerEnricher, got %+v", event.Owner)
+	}
+}
+
+func TestTransformWatchEventMissingKind(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetNamespace("default")
+	obj.SetName("web")
+
+	event, err := TransformWatchEvent(obj, EventTypeDeleted)
+	if !errors.Is(err, ErrMissingKind) {
+		t.Fatalf("expected ErrMissingKind, got %v", err)
+	}
+	if event != nil {
+		t.Errorf("expected no event, got %+v", event)
+	}
+}
+
+func TestFormatMessage(t *testing.T) {
+	tests := []struct {
+		verb, namespace, want string
+	}{
+		{verb: "create", namespace: "default", want: "Create pods default/web"},
+		{verb: "update", namespace: "default", want: "Update pods default/web"},
+		{verb: "delete", namespace: "default", want: "Delete pods default/web"},
+		{verb: "delete", want: "Delete pods web"},
+		{verb: "", namespace: "default", want: " pods default/web"},
+	}
+
+	for _, tt := range tests {
+		if got := formatMessage(tt.verb, "pods", tt.namespace, "web"); got != tt.want {
+			t.Errorf("formatMessage(%q, %q) = %q, want %q", tt.verb, tt.namespace, got, tt.want)
+		}
+	}
+}
+
+// deploymentObject returns a deployment with the given resourceVersion and spec
+func deploymentObject(resourceVersion string, spec map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind("Deployment")
+	obj.SetNamespace("default")
+	obj.SetName("web")
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
+
+func TestTransformUpdateEventDiff(t *testing.T) {
+	old := deploymentObject("1", map[string]any{
+		"replicas": int64(2),
+		"paused":   true,
+		"template": map[string]any{"spec": map[string]any{"image": "web:1", "restartPolicy": "Always"}},
+	})
+	updated := deploymentObject("2", map[string]any{
+		"replicas": int64(2),
+		"strategy": map[string]any{"type": "Recreate"},
+		"template": map[string]any{"spec": map[string]any{"image": "web:2", "restartPolicy": "Always"}},
+	})
+
+	event, err := TransformUpdateEvent(old, updated)
+	if err != nil {
+		t.Fatalf("TransformUpdateEvent failed: %v", err)
+	}
+	if event.Verb != "update" || event.Unchanged() {
+		t.Fatalf("expected a changed update event, got %+v", event)
+	}
+
+	wantDiff := map[string]any{"spec": map[string]any{
+		"paused":   nil,
+		"strategy": map[string]any{"type": "Recreate"},
+		"template": map[string]any{"spec": map[string]any{"image": "web:2"}},
+	}}
+	if !reflect.DeepEqual(event.Diff, wantDiff) {
+		t.Errorf("unexpected diff:\n got %v\nwant %v", event.Diff, wantDiff)
+	}
+
+	wantFields := []FieldChange{
+		{Path: "spec.paused", Old: true},
+		{Path: "spec.strategy", New: map[string]any{"type": "Recreate"}},
+		{Path: "spec.template.spec.image", Old: "web:1", New: "web:2"},
+	}
+	if !reflect.DeepEqual(event.ChangedFields, wantFields) {
+		t.Errorf("unexpected changed fields:\n got %+v\nwant %+v", event.ChangedFields, wantFields)
+	}
+}
+
+func TestTransformUpdateEventResourceVersionOnly(t *testing.T) {
+	spec := map[string]any{"replicas": int64(2)}
+	old := deploymentObject("1", spec)
+	updated := deploymentObject("2", spec)
+	updated.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+
+	event, err := TransformUpdateEvent(old, updated)
+	if err != nil {
+		t.Fatalf("TransformUpdateEvent failed: %v", err)
+	}
+	if !event.Unchanged() || len(event.ChangedFields) != 0 {
+		t.Errorf("expected an empty diff, got %v / %+v", event.Diff, event.ChangedFields)
+	}
+
+	// Without the previous object there is nothing to compare
+	event, err = TransformUpdateEvent(nil, updated)
+	if err != nil {
+		t.Fatalf("TransformUpdateEvent failed: %v", err)
+	}
+	if event.Unchanged() || event.Diff != nil {
+		t.Errorf("expected no diff without a previous object, got %v", event.Diff)
+	}
+}
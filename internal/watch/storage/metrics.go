@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// storeMetrics instruments the write path of a Store
+type storeMetrics struct {
+	stored  *prometheus.CounterVec
+	latency prometheus.Histogram
+}
+
+// newStoreMetrics creates the write metrics and registers them with reg,
+// which may be nil to leave them unregistered
+func newStoreMetrics(reg prometheus.Registerer) *storeMetrics {
+	m := &storeMetrics{
+		stored: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "watch_events_stored_total",
+			Help: "Events stored, by resource type and verb.",
+		}, []string{"resource_type", "verb"}),
+		latency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "watch_store_event_duration_seconds",
+			Help:    "Latency of storing an event, including failed writes.",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 4, 9),
+		}),
+	}
+	if reg != nil {
+		reg.MustRegister(m.stored, m.latency)
+	}
+	return m
+}
+
+// observe records a StoreEvent call that started at start. A nil
+// storeMetrics records nothing.
+func (m *storeMetrics) observe(resourceType, verb string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.latency.Observe(time.Since(start).Seconds())
+	if err == nil {
+		m.stored.WithLabelValues(resourceType, verb).Inc()
+	}
+}
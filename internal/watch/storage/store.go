@@ -1,14 +1,24 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	badger "github.com/dgraph-io/badger/v4"
+	"github.com/go-logr/logr"
 	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -16,102 +26,458 @@ import (
 type Store struct {
 	db            *badger.DB
 	retentionDays int
-}
+	syncWrites    bool
+	readOnly      bool
 
-// NewStore creates a new BadgerDB store
-func NewStore(path string, retentionDays int) (*Store, error) {
-	opts := badger.DefaultOptions(path)
-	opts.SyncWrites = false // Async writes for better performance
-	opts.NumVersionsToKeep = 1
-	opts.ValueLogFileSize = 256 << 20 // 256 MB value log files
-	opts.ValueLogMaxEntries = 500000
+	// secondaryIndexes is set once the verb and user indexes cover every
+	// stored event; until then queries scan the time index
+	secondaryIndexes atomic.Bool
 
-	db, err := badger.Open(opts)
+	// writes is held by every write and exclusively by Restore, as BadgerDB
+	// must not run other transactions while it loads a backup. Writers such
+	// as the watchers' queue and batcher wait for the restore to finish.
+	writes sync.RWMutex
+
+	// retentionOverrides replaces the retention period for individual
+	// resource types, keyed by resource type
+	retentionOverrides map[string]time.Duration
+
+	// aggregateBudget caps the scan time of AggregateEvents; zero means no cap
+	aggregateBudget time.Duration
+
+	// gcInterval and gcDiscardRatio configure value log GC, see SetGC
+	gcInterval     time.Duration
+	gcDiscardRatio float64
+
+	metrics *storeMetrics
+
+	// log receives the errors of background work, see SetLogger
+	log logr.Logger
+}
+
+// NewStore creates a new BadgerDB store. Its write metrics are registered
+// with reg, which may be nil.
+func NewStore(path string, retentionDays int, syncWrites bool, reg prometheus.Registerer) (*Store, error) {
+	db, err := badger.Open(badgerOptions(path, syncWrites))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open BadgerDB: %w", err)
 	}
 
-	return &Store{
+	s := &Store{
 		db:            db,
 		retentionDays: retentionDays,
-	}, nil
+		syncWrites:    syncWrites,
+		metrics:       newStoreMetrics(reg),
+	}
+	if err := s.backfillSecondaryIndexes(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill verb and user indexes: %w", err)
+	}
+	return s, nil
+}
+
+// NewReadOnlyStore opens an existing BadgerDB store for queries only, e.g. a
+// replicated directory or a restored backup. Writes fail and no GC runs. The
+// directory must have been closed cleanly by its writer.
+func NewReadOnlyStore(path string) (*Store, error) {
+	opts := badgerOptions(path, false)
+	opts.ReadOnly = true
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open BadgerDB read-only: %w", err)
+	}
+
+	s := &Store{
+		db:       db,
+		readOnly: true,
+	}
+	// A read-only store cannot backfill, so queries fall back to the time
+	// index unless the writer completed the verb and user indexes
+	indexed, err := s.hasSecondaryIndexes()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to read the index marker: %w", err)
+	}
+	s.secondaryIndexes.Store(indexed)
+	return s, nil
+}
+
+// SetRetentionOverrides keeps the events of the given resource types (e.g.
+// "pods") for a different period than the store default. It must be called
+// before events are stored.
+func (s *Store) SetRetentionOverrides(overrides map[string]time.Duration) {
+	s.retentionOverrides = overrides
+}
+
+// retention returns how long events of resourceType are kept
+func (s *Store) retention(resourceType string) time.Duration {
+	if ttl, ok := s.retentionOverrides[resourceType]; ok {
+		return ttl
+	}
+	return time.Duration(s.retentionDays) * 24 * time.Hour
+}
+
+// SetAggregateBudget caps how long AggregateEvents scans before returning
+// truncated counts. Zero disables the cap.
+func (s *Store) SetAggregateBudget(budget time.Duration) {
+	s.aggregateBudget = budget
+}
+
+// Value log GC defaults, used until SetGC configures others
+const (
+	defaultGCInterval     = time.Hour
+	defaultGCDiscardRatio = 0.5
+)
+
+// SetGC sets how often StartGCRoutine runs value log GC and the share of
+// stale data a value log file needs before GC rewrites it
+func (s *Store) SetGC(interval time.Duration, discardRatio float64) {
+	s.gcInterval = interval
+	s.gcDiscardRatio = discardRatio
+}
+
+// SetLogger sets where errors of background work, such as GC and retention
+// sweeps, are logged. Without one they are discarded.
+func (s *Store) SetLogger(log logr.Logger) {
+	s.log = log
+}
+
+// ReadOnly reports whether the store was opened for queries only
+func (s *Store) ReadOnly() bool {
+	return s.readOnly
+}
+
+// badgerOptions builds the BadgerDB options used by NewStore
+func badgerOptions(path string, syncWrites bool) badger.Options {
+	opts := badger.DefaultOptions(path)
+	opts.SyncWrites = syncWrites // Async by default for better performance
+	opts.NumVersionsToKeep = 1
+	opts.ValueLogFileSize = 256 << 20 // 256 MB value log files
+	opts.ValueLogMaxEntries = 500000
+	return opts
 }
 
 // Close closes the database
 func (s *Store) Close() error {
+	if !s.readOnly && !s.db.IsClosed() {
+		// A single value log GC pass reclaims the space freed since the last
+		// run while delaying shutdown by at most one file rewrite. Closing
+		// flushes the memtables and syncs the value log.
+		err := s.db.RunValueLogGC(s.discardRatio())
+		if err != nil && !errors.Is(err, badger.ErrNoRewrite) && !errors.Is(err, badger.ErrRejected) {
+			s.log.Error(err, "Value log GC failed")
+		}
+	}
 	return s.db.Close()
 }
 
+// Closed reports whether the database has been closed
+func (s *Store) Closed() bool {
+	return s.db.IsClosed()
+}
+
 // StoreEvent stores an audit event with appropriate indexes
-func (s *Store) StoreEvent(ctx context.Context, event *models.AuditEvent, obj *unstructured.Unstructured) error {
+func (s *Store) StoreEvent(ctx context.Context, event *models.AuditEvent, obj *unstructured.Unstructured) (err error) {
+	defer func(start time.Time) {
+		s.metrics.observe(event.ResourceType, event.Verb, start, err)
+	}(time.Now())
+
+	entries, err := s.eventEntries(event, obj, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return s.update(func(txn *badger.Txn) error {
+		for _, entry := range entries {
+			if err := txn.SetEntry(entry); err != nil {
+				return fmt.Errorf("failed to store %s: %w", entry.Key, err)
+			}
+		}
+		return nil
+	})
+}
+
+// StoreEventBatch stores events, with objs[i] the object of events[i], in a
+// single write batch instead of a transaction per event. It is meant for
+// high event rates, e.g. during a rollout; on error none, some or all of the
+// events may have been stored.
+func (s *Store) StoreEventBatch(ctx context.Context, events []*models.AuditEvent, objs []*unstructured.Unstructured) (err error) {
+	if len(events) != len(objs) {
+		return fmt.Errorf("got %d events but %d objects", len(events), len(objs))
+	}
+	defer func(start time.Time) {
+		for _, event := range events {
+			s.metrics.observe(event.ResourceType, event.Verb, start, err)
+		}
+	}(time.Now())
+
+	s.writes.RLock()
+	defer s.writes.RUnlock()
+	batch := s.db.NewWriteBatch()
+	defer batch.Cancel()
+
+	now := time.Now()
+	for i, event := range events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entries, err := s.eventEntries(event, objs[i], now)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := batch.SetEntry(entry); err != nil {
+				return fmt.Errorf("failed to store %s: %w", entry.Key, err)
+			}
+		}
+	}
+	return batch.Flush()
+}
+
+// update runs fn in a read-write transaction once no restore is running
+func (s *Store) update(fn func(txn *badger.Txn) error) error {
+	s.writes.RLock()
+	defer s.writes.RUnlock()
+	return s.db.Update(fn)
+}
+
+// eventEntries returns the index entries of event: the time and object
+// indexes, the verb, user and owner name indexes, the owner UID index when
+// the event records its owner, and the reference index of Kubernetes events. All
+// entries share the TTL of the event's resource type, counted from now, so
+// they expire together.
+func (s *Store) eventEntries(event *models.AuditEvent, obj *unstructured.Unstructured, now time.Time) ([]*badger.Entry, error) {
 	// Serialize the event
 	data, err := json.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
 	}
 
-	ttl := time.Duration(s.retentionDays) * 24 * time.Hour
-	expiresAt := uint64(time.Now().Add(ttl).Unix())
+	expiresAt := uint64(now.Add(s.retention(event.ResourceType)).Unix())
 	uid := string(obj.GetUID())
 
-	return s.db.Update(func(txn *badger.Txn) error {
-		// Primary time-based index for time-range queries
-		timeKey := fmt.Sprintf("events/%s/%s/%s/%s/%s",
-			event.Timestamp.Format(time.RFC3339),
+	var entries []*badger.Entry
+	add := func(key []byte) {
+		entries = append(entries, &badger.Entry{Key: key, Value: data, ExpiresAt: expiresAt})
+	}
+
+	// Primary time-based index for time-range queries
+	timeKey := fmt.Sprintf("events/%s/%s/%s/%s/%s",
+		formatKeyTime(event.Timestamp),
+		event.Namespace,
+		event.ResourceType,
+		event.ResourceName,
+		uid)
+	add([]byte(timeKey))
+
+	// Object-based index for object history queries
+	add([]byte(fmt.Sprintf("objects/%s/%s/%s/%s/%s",
+		event.Namespace,
+		event.ResourceType,
+		event.ResourceName,
+		formatKeyTime(event.Timestamp),
+		uid)))
+
+	// Verb and user indexes for queries filtering on them. They hold the
+	// time index key rather than a copy of the event.
+	for _, key := range secondaryIndexKeys(event.Verb, event.User, timeKey) {
+		entries = append(entries, &badger.Entry{Key: key, Value: []byte(timeKey), ExpiresAt: expiresAt})
+	}
+
+	// Owner UID index for ownership queries, written when the event records
+	// its owner (see config IndexOwners)
+	if event.Owner != nil && event.Owner.UID != "" {
+		add([]byte(fmt.Sprintf("byOwner/%s/%s/%s/%s/%s/%s",
+			event.Owner.UID,
+			formatKeyTime(event.Timestamp),
 			event.Namespace,
 			event.ResourceType,
 			event.ResourceName,
-			uid)
+			uid)))
+	}
+
+	// Owner name index, so owners are found by kind and name without knowing
+	// their UID. Like the verb and user indexes it holds the time index key.
+	for _, ref := range event.OwnerRefs {
+		key := ownerNameIndexPrefix(ref.Kind, ref.Name) + strings.TrimPrefix(timeKey, timeIndexPrefix)
+		entries = append(entries, &badger.Entry{Key: []byte(key), Value: []byte(timeKey), ExpiresAt: expiresAt})
+	}
 
-		if err := txn.SetEntry(&badger.Entry{
-			Key:       []byte(timeKey),
-			Value:     data,
-			ExpiresAt: expiresAt,
-		}); err != nil {
-			return fmt.Errorf("failed to store time index: %w", err)
+	// Special handling for Event objects - create reference index
+	if event.ResourceType == "events" {
+		if involvedObj := models.ExtractInvolvedObject(obj); involvedObj != nil {
+			add([]byte(fmt.Sprintf("eventRefs/%s/%s/%s/%s/%s",
+				involvedObj.Namespace,
+				involvedObj.Kind,
+				involvedObj.Name,
+				formatKeyTime(event.Timestamp),
+				uid)))
 		}
+	}
 
-		// Object-based index for object history queries
-		objectKey := fmt.Sprintf("objects/%s/%s/%s/%s/%s",
-			event.Namespace,
-			event.ResourceType,
-			event.ResourceName,
-			event.Timestamp.Format(time.RFC3339),
-			uid)
-
-		if err := txn.SetEntry(&badger.Entry{
-			Key:       []byte(objectKey),
-			Value:     data,
-			ExpiresAt: expiresAt,
-		}); err != nil {
-			return fmt.Errorf("failed to store object index: %w", err)
-		}
-
-		// Special handling for Event objects - create reference index
-		if event.ResourceType == "events" {
-			involvedObj := models.ExtractInvolvedObject(obj)
-			if involvedObj != nil {
-				refKey := fmt.Sprintf("eventRefs/%s/%s/%s/%s/%s",
-					involvedObj.Namespace,
-					involvedObj.Kind,
-					involvedObj.Name,
-					event.Timestamp.Format(time.RFC3339),
-					uid)
-
-				if err := txn.SetEntry(&badger.Entry{
-					Key:       []byte(refKey),
-					Value:     data,
-					ExpiresAt: expiresAt,
-				}); err != nil {
-					return fmt.Errorf("failed to store event reference: %w", err)
+	return entries, nil
+}
+
+// timeIndexPrefix is the key prefix of the primary time index
+const timeIndexPrefix = "events/"
+
+// verbIndexPrefix returns the key prefix of the verb index for verb. The verb
+// and user indexes hold the time index keys under a per-value prefix:
+// byVerb/{verb}/{timestamp}/{namespace}/{resourceType}/{resourceName}/{uid}.
+func verbIndexPrefix(verb string) string {
+	return "byVerb/" + url.PathEscape(verb) + "/"
+}
+
+// ownerNameIndexPrefix returns the key prefix of the owner name index for the
+// owner kind and name:
+// owners/{ownerKind}/{ownerName}/{timestamp}/{namespace}/{resourceType}/{resourceName}/{uid}.
+func ownerNameIndexPrefix(kind, name string) string {
+	return "owners/" + kind + "/" + name + "/"
+}
+
+// userIndexPrefix returns the key prefix of the user index for user. User
+// names may contain slashes, so they are escaped.
+func userIndexPrefix(user string) string {
+	return "byUser/" + url.PathEscape(user) + "/"
+}
+
+// secondaryIndexKeys returns the verb and user index keys of the event
+// stored under timeKey
+func secondaryIndexKeys(verb, user, timeKey string) [][]byte {
+	suffix := strings.TrimPrefix(timeKey, timeIndexPrefix)
+	return [][]byte{
+		[]byte(verbIndexPrefix(verb) + suffix),
+		[]byte(userIndexPrefix(user) + suffix),
+	}
+}
+
+// secondaryIndexesMarker records that the verb and user indexes cover every
+// stored event
+const secondaryIndexesMarker = "meta/secondaryIndexes"
+
+// hasSecondaryIndexes reports whether the secondary indexes marker is stored
+func (s *Store) hasSecondaryIndexes() (bool, error) {
+	err := s.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(secondaryIndexesMarker))
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// backfillSecondaryIndexes adds verb and user index entries for the events
+// stored before those indexes existed. It runs once per store.
+func (s *Store) backfillSecondaryIndexes(ctx context.Context) error {
+	indexed, err := s.hasSecondaryIndexes()
+	if err != nil {
+		return err
+	}
+	if indexed {
+		s.secondaryIndexes.Store(true)
+		return nil
+	}
+	return s.buildSecondaryIndexes(ctx)
+}
+
+// buildSecondaryIndexes writes the verb and user index entries of every
+// stored event, in batches, keeping each event's expiry, and then sets the
+// marker
+func (s *Store) buildSecondaryIndexes(ctx context.Context) error {
+	seek := []byte(timeIndexPrefix)
+	for seek != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var entries []*badger.Entry
+		next := seek
+		seek = nil
+		err := s.db.View(func(txn *badger.Txn) error {
+			iter := txn.NewIterator(badger.DefaultIteratorOptions)
+			defer iter.Close()
+
+			for iter.Seek(next); iter.ValidForPrefix([]byte(timeIndexPrefix)); iter.Next() {
+				item := iter.Item()
+				if len(entries) >= deleteBatchSize {
+					seek = item.KeyCopy(nil)
+					return nil
+				}
+
+				data, err := item.ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+				var event struct {
+					Verb string `json:"verb"`
+					User string `json:"user"`
+				}
+				if err := json.Unmarshal(data, &event); err != nil {
+					continue
+				}
+				timeKey := item.KeyCopy(nil)
+				for _, key := range secondaryIndexKeys(event.Verb, event.User, string(timeKey)) {
+					entries = append(entries, &badger.Entry{Key: key, Value: timeKey, ExpiresAt: item.ExpiresAt()})
 				}
 			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 
-		return nil
+		err = s.db.Update(func(txn *badger.Txn) error {
+			for _, entry := range entries {
+				if err := txn.SetEntry(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(secondaryIndexesMarker), nil)
 	})
+	if err != nil {
+		return err
+	}
+	s.secondaryIndexes.Store(true)
+	return nil
+}
+
+// keyTimeLayout is the timestamp layout used in index keys. It is fixed-width
+// with nanosecond precision so keys sort chronologically at sub-second
+// resolution.
+const keyTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// formatKeyTime formats t in UTC for use in an index key
+func formatKeyTime(t time.Time) string {
+	return t.UTC().Format(keyTimeLayout)
 }
 
+// parseKeyTime parses an index key timestamp. Keys written before nanosecond
+// precision use plain RFC3339, which the RFC3339Nano layout also accepts.
+func parseKeyTime(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrInvalidOrder is returned for a sort order other than OrderAsc or OrderDesc
+var ErrInvalidOrder = errors.New("invalid order, expected asc or desc")
+
+// Sort orders of QueryOptions.Order
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
 // QueryOptions defines parameters for querying events
 type QueryOptions struct {
 	StartTime    time.Time
@@ -122,95 +488,317 @@ type QueryOptions struct {
 	Verb         string
 	User         string
 	Limit        int
+
+	// Verbs keeps events with any of the verbs. Verb, if set, counts as one
+	// more of them.
+	Verbs []string
+
+	// Namespaces keeps events in any of the namespaces. Namespace, if set,
+	// counts as one more of them.
+	Namespaces []string
+
+	// Search keeps events whose message or object contains every
+	// whitespace-separated term of it, ignoring case
+	Search string
+
+	// Cursor resumes a query after the last event of a previous page
+	Cursor string
+
+	// Order is OrderAsc (oldest first, the default) or OrderDesc
+	Order string
+}
+
+// verbs returns the verbs an event must have one of, or nil for any verb
+func (o QueryOptions) verbs() []string {
+	if o.Verb == "" {
+		return o.Verbs
+	}
+	return append([]string{o.Verb}, o.Verbs...)
+}
+
+// matchesVerb reports whether verb is one of the verbs filtered on
+func matchesVerb(verbs []string, verb string) bool {
+	return len(verbs) == 0 || slices.Contains(verbs, verb)
+}
+
+// namespaces returns the namespaces an event must be in one of, or nil for
+// any namespace
+func (o QueryOptions) namespaces() []string {
+	if o.Namespace == "" {
+		return o.Namespaces
+	}
+	return append([]string{o.Namespace}, o.Namespaces...)
 }
 
 // QueryEvents retrieves events based on query options
 func (s *Store) QueryEvents(ctx context.Context, opts QueryOptions) ([]*models.AuditEvent, error) {
+	events, _, err := s.QueryEventsPage(ctx, opts)
+	return events, err
+}
+
+// QueryEventsPage retrieves a page of events and returns a cursor for the
+// next page, or "" when the limit was not reached. Queries filtering on user
+// or verb scan that index instead of the whole time index.
+func (s *Store) QueryEventsPage(ctx context.Context, opts QueryOptions) ([]*models.AuditEvent, string, error) {
+	return s.queryEventsPage(ctx, opts, s.queryIndex(opts))
+}
+
+// queryIndex picks the index prefix to scan for opts. Users are usually far
+// more selective than the handful of verbs, so the user index wins when both
+// are filtered on. Several verbs are filtered while scanning the time index,
+// which keeps the events in time order, as are all queries while the verb
+// and user indexes are incomplete.
+func (s *Store) queryIndex(opts QueryOptions) string {
+	verbs := opts.verbs()
+	switch {
+	case !s.secondaryIndexes.Load():
+		return timeIndexPrefix
+	case opts.User != "":
+		return userIndexPrefix(opts.User)
+	case len(verbs) == 1:
+		return verbIndexPrefix(verbs[0])
+	default:
+		return timeIndexPrefix
+	}
+}
+
+// queryEventsPage runs a query over the index under prefix, whose keys all
+// continue with {timestamp}/{namespace}/{resourceType}/{resourceName}/{uid}
+func (s *Store) queryEventsPage(ctx context.Context, opts QueryOptions, prefix string) ([]*models.AuditEvent, string, error) {
 	var events []*models.AuditEvent
+	var nextCursor string
 	count := 0
 	limit := opts.Limit
 	if limit <= 0 {
 		limit = 1000 // Default max
 	}
 
+	terms := searchTerms(opts.Search)
+	verbs := opts.verbs()
+	namespaces := opts.namespaces()
+
+	var reverse bool
+	switch opts.Order {
+	case "", OrderAsc:
+	case OrderDesc:
+		reverse = true
+	default:
+		return nil, "", ErrInvalidOrder
+	}
+
+	var after []byte
+	if opts.Cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(opts.Cursor)
+		if err != nil || !strings.HasPrefix(string(decoded), prefix) {
+			return nil, "", ErrInvalidCursor
+		}
+		after = decoded
+	}
+
 	err := s.db.View(func(txn *badger.Txn) error {
 		iterOpts := badger.DefaultIteratorOptions
 		iterOpts.PrefetchValues = true
 		iterOpts.PrefetchSize = 100
+		iterOpts.Reverse = reverse
 
 		iter := txn.NewIterator(iterOpts)
 		defer iter.Close()
 
-		// Build prefix for time-based search
-		prefix := "events/"
-		if !opts.StartTime.IsZero() {
-			prefix += opts.StartTime.Format(time.RFC3339)
+		// Build prefix for time-based search. A reverse iterator seeks to the
+		// last key at or before the seek key, so descending queries start
+		// past every key of the end timestamp (or the whole index).
+		var seek []byte
+		if reverse {
+			end := prefix + "\xff"
+			if !opts.EndTime.IsZero() {
+				end = prefix + formatKeyTime(opts.EndTime) + "\xff"
+			}
+			seek = []byte(end)
+			if after != nil && string(after) < end {
+				seek = after
+			}
+		} else {
+			start := prefix
+			if !opts.StartTime.IsZero() {
+				start += formatKeyTime(opts.StartTime)
+			}
+			seek = []byte(start)
+			if after != nil && string(after) > start {
+				seek = after
+			}
 		}
 
-		for iter.Seek([]byte(prefix)); iter.ValidForPrefix([]byte("events/")); iter.Next() {
+		for iter.Seek(seek); iter.ValidForPrefix([]byte(prefix)); iter.Next() {
 			if count >= limit {
 				break
 			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 
 			item := iter.Item()
 			key := string(item.Key())
 
-			// Parse key: events/{timestamp}/{namespace}/{resourceType}/{resourceName}/{uid}
-			parts := strings.Split(key, "/")
-			if len(parts) < 6 {
+			// The cursor key itself was the last event of the previous page
+			if after != nil && key == string(after) {
+				continue
+			}
+
+			// Parse key: {prefix}{timestamp}/{namespace}/{resourceType}/{resourceName}/{uid}
+			parts := strings.Split(strings.TrimPrefix(key, prefix), "/")
+			if len(parts) < 5 {
 				continue
 			}
 
-			timestamp, err := time.Parse(time.RFC3339, parts[1])
+			timestamp, err := parseKeyTime(parts[0])
 			if err != nil {
 				continue
 			}
 
-			// Filter by time range
+			// Filter by time range. Keys are sorted by time, so we can stop
+			// once the iteration direction leaves the range.
 			if !opts.EndTime.IsZero() && timestamp.After(opts.EndTime) {
-				break // Keys are sorted by time, so we can stop
+				if reverse {
+					continue
+				}
+				break
 			}
 			if !opts.StartTime.IsZero() && timestamp.Before(opts.StartTime) {
+				if reverse {
+					break
+				}
 				continue
 			}
 
 			// Filter by namespace
-			if opts.Namespace != "" && parts[2] != opts.Namespace {
+			if len(namespaces) > 0 && !slices.Contains(namespaces, parts[1]) {
 				continue
 			}
 
 			// Filter by resource type
-			if opts.ResourceType != "" && parts[3] != opts.ResourceType {
+			if opts.ResourceType != "" && parts[2] != opts.ResourceType {
 				continue
 			}
 
 			// Filter by resource name
-			if opts.ResourceName != "" && parts[4] != opts.ResourceName {
+			if opts.ResourceName != "" && parts[3] != opts.ResourceName {
 				continue
 			}
 
 			// Get the event data
-			err = item.Value(func(val []byte) error {
+			val, err := eventValue(txn, item)
+			if err != nil {
+				return err
+			}
+			if val == nil {
+				continue
+			}
+			var event models.AuditEvent
+			if err := json.Unmarshal(val, &event); err != nil {
+				return err
+			}
+
+			// Filter by verb
+			if !matchesVerb(verbs, event.Verb) {
+				continue
+			}
+
+			// Filter by user
+			if opts.User != "" && event.User != opts.User {
+				continue
+			}
+
+			// Filter by search terms
+			if len(terms) > 0 && !matchesSearch(&event, terms) {
+				continue
+			}
+
+			events = append(events, &event)
+			count++
+			if count >= limit {
+				nextCursor = base64.RawURLEncoding.EncodeToString(item.KeyCopy(nil))
+			}
+		}
+
+		return nil
+	})
+
+	return events, nextCursor, err
+}
+
+// eventValue returns the event stored under an index item. The verb, user and
+// owner name indexes hold the time index key of the event, which is resolved;
+// their entries written before that hold the event itself. It returns nil when
+// the event no longer exists.
+func eventValue(txn *badger.Txn, item *badger.Item) ([]byte, error) {
+	val, err := item.ValueCopy(nil)
+	if err != nil || !bytes.HasPrefix(val, []byte(timeIndexPrefix)) {
+		return val, err
+	}
+	event, err := txn.Get(val)
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return event.ValueCopy(nil)
+}
+
+// searchTerms splits a search query into lowercase terms
+func searchTerms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// matchesSearch reports whether every term occurs in the event's message or
+// its JSON-encoded object, ignoring case
+func matchesSearch(event *models.AuditEvent, terms []string) bool {
+	text := strings.ToLower(event.Message)
+	if len(event.ObjectChanges) > 0 {
+		if object, err := json.Marshal(event.ObjectChanges); err == nil {
+			text += "\n" + strings.ToLower(string(object))
+		}
+	}
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// RecentEvents returns up to limit of the most recent events, newest first,
+// by iterating the time index in reverse
+func (s *Store) RecentEvents(ctx context.Context, limit int) ([]*models.AuditEvent, error) {
+	var events []*models.AuditEvent
+	if limit <= 0 {
+		return events, nil
+	}
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOpts := badger.DefaultIteratorOptions
+		iterOpts.Reverse = true
+		iterOpts.PrefetchSize = min(limit, 100)
+
+		iter := txn.NewIterator(iterOpts)
+		defer iter.Close()
+
+		// In reverse mode Seek finds the last key <= the seek key, so start
+		// just past every key in the time index
+		prefix := []byte("events/")
+		for iter.Seek([]byte("events/\xff")); iter.ValidForPrefix(prefix) && len(events) < limit; iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			err := iter.Item().Value(func(val []byte) error {
 				var event models.AuditEvent
 				if err := json.Unmarshal(val, &event); err != nil {
 					return err
 				}
-
-				// Filter by verb
-				if opts.Verb != "" && event.Verb != opts.Verb {
-					return nil
-				}
-
-				// Filter by user
-				if opts.User != "" && event.User != opts.User {
-					return nil
-				}
-
 				events = append(events, &event)
-				count++
 				return nil
 			})
-
 			if err != nil {
 				return err
 			}
@@ -237,6 +825,10 @@ func (s *Store) GetObjectHistory(ctx context.Context, namespace, resourceType, n
 		prefix := fmt.Sprintf("objects/%s/%s/%s/", namespace, resourceType, name)
 
 		for iter.Seek([]byte(prefix)); iter.ValidForPrefix([]byte(prefix)); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			item := iter.Item()
 
 			err := item.Value(func(val []byte) error {
@@ -274,6 +866,10 @@ func (s *Store) GetRelatedEvents(ctx context.Context, namespace, kind, name stri
 		prefix := fmt.Sprintf("eventRefs/%s/%s/%s/", namespace, kind, name)
 
 		for iter.Seek([]byte(prefix)); iter.ValidForPrefix([]byte(prefix)); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			item := iter.Item()
 
 			err := item.Value(func(val []byte) error {
@@ -296,25 +892,964 @@ func (s *Store) GetRelatedEvents(ctx context.Context, namespace, kind, name stri
 	return events, err
 }
 
-// RunGC runs BadgerDB garbage collection
-func (s *Store) RunGC(ctx context.Context, discardRatio float64) error {
-	return s.db.RunValueLogGC(discardRatio)
-}
+// GetEventsByOwner returns the events of the objects owned by the object of
+// the given kind and name, e.g. the Pods of a ReplicaSet, oldest first. An
+// empty namespace matches owners of that name in every namespace.
+func (s *Store) GetEventsByOwner(ctx context.Context, namespace, kind, name string) ([]*models.AuditEvent, error) {
+	if kind == "" || name == "" {
+		return nil, fmt.Errorf("owner kind and name are required")
+	}
 
-// StartGCRoutine starts a background goroutine for periodic GC
-func (s *Store) StartGCRoutine(ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
+	var events []*models.AuditEvent
 
-	for {
-		select {
-		case <-ctx.Done():
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOpts := badger.DefaultIteratorOptions
+		iterOpts.PrefetchValues = true
+
+		iter := txn.NewIterator(iterOpts)
+		defer iter.Close()
+
+		prefix := []byte(ownerNameIndexPrefix(kind, name))
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			// The namespace is the fifth key segment
+			item := iter.Item()
+			if namespace != "" {
+				parts := strings.Split(string(item.Key()), "/")
+				if len(parts) < 8 || parts[4] != namespace {
+					continue
+				}
+			}
+
+			val, err := eventValue(txn, item)
+			if err != nil {
+				return err
+			}
+			if val == nil {
+				continue
+			}
+			var event models.AuditEvent
+			if err := json.Unmarshal(val, &event); err != nil {
+				return err
+			}
+			events = append(events, &event)
+		}
+
+		return nil
+	})
+
+	return events, err
+}
+
+// GetOwnerEvents returns the events of the objects owned by the object with
+// ownerUID, oldest first. Only events stored with IndexOwners enabled are
+// found. Owners are followed one level: the Pods of a Deployment are found
+// through the UIDs of its ReplicaSets.
+func (s *Store) GetOwnerEvents(ctx context.Context, ownerUID string) ([]*models.AuditEvent, error) {
+	if ownerUID == "" {
+		return nil, fmt.Errorf("owner UID is required")
+	}
+
+	var events []*models.AuditEvent
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOpts := badger.DefaultIteratorOptions
+		iterOpts.PrefetchValues = true
+
+		iter := txn.NewIterator(iterOpts)
+		defer iter.Close()
+
+		// Key: byOwner/{ownerUID}/{timestamp}/{namespace}/{resourceType}/{resourceName}/{uid}
+		prefix := []byte(fmt.Sprintf("byOwner/%s/", ownerUID))
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			err := iter.Item().Value(func(val []byte) error {
+				var event models.AuditEvent
+				if err := json.Unmarshal(val, &event); err != nil {
+					return err
+				}
+				events = append(events, &event)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return events, err
+}
+
+// ResourceTypeStats summarizes the stored events for one resource type
+type ResourceTypeStats struct {
+	Count  int
+	Oldest time.Time
+}
+
+// StatsByResourceType returns the number of stored events and the oldest event
+// timestamp per resource type. It scans every key in the time index (keys only),
+// so its cost grows with the number of stored events.
+func (s *Store) StatsByResourceType(ctx context.Context) (map[string]ResourceTypeStats, error) {
+	stats := make(map[string]ResourceTypeStats)
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOpts := badger.DefaultIteratorOptions
+		iterOpts.PrefetchValues = false
+
+		iter := txn.NewIterator(iterOpts)
+		defer iter.Close()
+
+		// Key-only scan: events/{timestamp}/{namespace}/{resourceType}/{resourceName}/{uid}
+		prefix := []byte("events/")
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			parts := strings.Split(string(iter.Item().Key()), "/")
+			if len(parts) < 6 {
+				continue
+			}
+
+			stat := stats[parts[3]]
+			// Keys are time-ordered, so the first key seen is the oldest
+			if stat.Count == 0 {
+				if timestamp, err := parseKeyTime(parts[1]); err == nil {
+					stat.Oldest = timestamp
+				}
+			}
+			stat.Count++
+			stats[parts[3]] = stat
+		}
+
+		return nil
+	})
+
+	return stats, err
+}
+
+// EventSummary counts events per namespace and resource type. Cluster-scoped
+// resources are counted under the empty namespace.
+type EventSummary map[string]map[string]int
+
+// SummarizeEvents counts the events in a time range per namespace and
+// resource type with a single key-only scan of the time index. A zero start
+// or end leaves that side of the range open.
+func (s *Store) SummarizeEvents(ctx context.Context, startTime, endTime time.Time) (EventSummary, error) {
+	summary := make(EventSummary)
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOpts := badger.DefaultIteratorOptions
+		iterOpts.PrefetchValues = false
+
+		iter := txn.NewIterator(iterOpts)
+		defer iter.Close()
+
+		prefix := []byte("events/")
+		seek := prefix
+		if !startTime.IsZero() {
+			seek = []byte("events/" + formatKeyTime(startTime))
+		}
+
+		// Key-only scan: events/{timestamp}/{namespace}/{resourceType}/{resourceName}/{uid}
+		for iter.Seek(seek); iter.ValidForPrefix(prefix); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			parts := strings.Split(string(iter.Item().Key()), "/")
+			if len(parts) < 6 {
+				continue
+			}
+
+			timestamp, err := parseKeyTime(parts[1])
+			if err != nil {
+				continue
+			}
+			if !endTime.IsZero() && timestamp.After(endTime) {
+				break // Keys are sorted by time, so we can stop
+			}
+			if !startTime.IsZero() && timestamp.Before(startTime) {
+				continue
+			}
+
+			namespace, resourceType := parts[2], parts[3]
+			if summary[namespace] == nil {
+				summary[namespace] = make(map[string]int)
+			}
+			summary[namespace][resourceType]++
+		}
+
+		return nil
+	})
+
+	return summary, err
+}
+
+// Total returns the number of events across all namespaces and resource types
+func (s EventSummary) Total() int {
+	total := 0
+	for _, counts := range s {
+		for _, count := range counts {
+			total += count
+		}
+	}
+	return total
+}
+
+// Dimensions AggregateEvents can group by
+const (
+	GroupByNamespace    = "namespace"
+	GroupByResourceType = "resourceType"
+	GroupByVerb         = "verb"
+	GroupByUser         = "user"
+)
+
+// ErrInvalidGroupBy is returned for an unknown AggregateEvents dimension
+var ErrInvalidGroupBy = errors.New("invalid groupBy, expected namespace, resourceType, verb or user")
+
+// EventAggregate holds event counts per value of one dimension
+type EventAggregate struct {
+	Counts map[string]int
+
+	// Truncated is set when the scan budget ran out before the end of the
+	// range; Counts then covers the events up to that point
+	Truncated bool
+}
+
+// aggregateDeadlineCheck is the number of keys scanned between checks of the
+// aggregate scan budget
+const aggregateDeadlineCheck = 1024
+
+// AggregateEvents counts the events matching opts per value of groupBy by
+// scanning the time index. Only the verb and user of each event are decoded,
+// and only when grouping or filtering by them. Limit, Cursor and Order are
+// ignored; the scan stops early with Truncated set when it exceeds the
+// store's aggregate budget.
+func (s *Store) AggregateEvents(ctx context.Context, opts QueryOptions, groupBy string) (*EventAggregate, error) {
+	switch groupBy {
+	case GroupByNamespace, GroupByResourceType, GroupByVerb, GroupByUser:
+	default:
+		return nil, ErrInvalidGroupBy
+	}
+	decode := groupBy == GroupByVerb || groupBy == GroupByUser
+
+	aggregate := &EventAggregate{Counts: make(map[string]int)}
+	truncated, err := s.scanEventKeys(ctx, opts, decode, func(event scannedEvent) {
+		switch groupBy {
+		case GroupByNamespace:
+			aggregate.Counts[event.namespace]++
+		case GroupByResourceType:
+			aggregate.Counts[event.resourceType]++
+		case GroupByVerb:
+			aggregate.Counts[event.verb]++
+		case GroupByUser:
+			aggregate.Counts[event.user]++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	aggregate.Truncated = truncated
+
+	return aggregate, nil
+}
+
+// MaxHistogramBuckets caps the number of buckets HistogramEvents returns
+const MaxHistogramBuckets = 1000
+
+// ErrInvalidHistogram is returned for a HistogramEvents range or bucket
+// width that doesn't yield between 1 and MaxHistogramBuckets buckets
+var ErrInvalidHistogram = errors.New("invalid histogram")
+
+// HistogramBucket is the number of events from Start up to, not including,
+// the start of the next bucket
+type HistogramBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// EventHistogram holds event counts per time bucket
+type EventHistogram struct {
+	Buckets []HistogramBucket
+
+	// Truncated is set when the scan budget ran out before the end of the
+	// range; the buckets then cover the events up to that point
+	Truncated bool
+}
+
+// HistogramEvents counts the events matching opts per bucket of width bucket
+// from opts.StartTime to opts.EndTime in one scan of the time index. Buckets
+// start at opts.StartTime; the last one is cut short at opts.EndTime and also
+// counts events at opts.EndTime. Like AggregateEvents, it ignores Limit,
+// Cursor and Order and stops early with Truncated set when it exceeds the
+// store's aggregate budget.
+func (s *Store) HistogramEvents(ctx context.Context, opts QueryOptions, bucket time.Duration) (*EventHistogram, error) {
+	if opts.StartTime.IsZero() || opts.EndTime.IsZero() || !opts.EndTime.After(opts.StartTime) {
+		return nil, fmt.Errorf("%w: start must be before end", ErrInvalidHistogram)
+	}
+	if bucket <= 0 {
+		return nil, fmt.Errorf("%w: bucket must be positive", ErrInvalidHistogram)
+	}
+	window := opts.EndTime.Sub(opts.StartTime)
+	n := int64(window / bucket)
+	if window%bucket != 0 {
+		n++
+	}
+	if n > MaxHistogramBuckets {
+		return nil, fmt.Errorf("%w: %s buckets over %s exceed the maximum of %d", ErrInvalidHistogram, bucket, window, MaxHistogramBuckets)
+	}
+
+	histogram := &EventHistogram{Buckets: make([]HistogramBucket, n)}
+	for i := range histogram.Buckets {
+		histogram.Buckets[i].Start = opts.StartTime.Add(time.Duration(i) * bucket)
+	}
+	truncated, err := s.scanEventKeys(ctx, opts, false, func(event scannedEvent) {
+		i := min(int(event.timestamp.Sub(opts.StartTime)/bucket), len(histogram.Buckets)-1)
+		histogram.Buckets[i].Count++
+	})
+	if err != nil {
+		return nil, err
+	}
+	histogram.Truncated = truncated
+
+	return histogram, nil
+}
+
+// scannedEvent is the part of an event scanEventKeys reads. Verb and user
+// are only set when decoded.
+type scannedEvent struct {
+	timestamp    time.Time
+	namespace    string
+	resourceType string
+	verb         string
+	user         string
+}
+
+// scanEventKeys calls count for each event matching opts, scanning the time
+// index between opts.StartTime and opts.EndTime, both inclusive. Events are
+// only decoded when decode is set or opts filters by verb or user. It reports
+// whether the scan stopped early because it exceeded the store's aggregate
+// budget.
+func (s *Store) scanEventKeys(ctx context.Context, opts QueryOptions, decode bool, count func(scannedEvent)) (bool, error) {
+	verbs := opts.verbs()
+	namespaces := opts.namespaces()
+	decode = decode || len(verbs) > 0 || opts.User != ""
+
+	var deadline time.Time
+	if s.aggregateBudget > 0 {
+		deadline = time.Now().Add(s.aggregateBudget)
+	}
+
+	truncated := false
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOpts := badger.DefaultIteratorOptions
+		iterOpts.PrefetchValues = decode
+
+		iter := txn.NewIterator(iterOpts)
+		defer iter.Close()
+
+		prefix := []byte("events/")
+		seek := prefix
+		if !opts.StartTime.IsZero() {
+			seek = []byte("events/" + formatKeyTime(opts.StartTime))
+		}
+
+		scanned := 0
+		// Key: events/{timestamp}/{namespace}/{resourceType}/{resourceName}/{uid}
+		for iter.Seek(seek); iter.ValidForPrefix(prefix); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			scanned++
+			if !deadline.IsZero() && scanned%aggregateDeadlineCheck == 0 && time.Now().After(deadline) {
+				truncated = true
+				return nil
+			}
+
+			item := iter.Item()
+			parts := strings.Split(string(item.Key()), "/")
+			if len(parts) < 6 {
+				continue
+			}
+
+			timestamp, err := parseKeyTime(parts[1])
+			if err != nil {
+				continue
+			}
+			if !opts.EndTime.IsZero() && timestamp.After(opts.EndTime) {
+				break // Keys are sorted by time, so we can stop
+			}
+			if !opts.StartTime.IsZero() && timestamp.Before(opts.StartTime) {
+				continue
+			}
+
+			event := scannedEvent{timestamp: timestamp, namespace: parts[2], resourceType: parts[3]}
+			if len(namespaces) > 0 && !slices.Contains(namespaces, event.namespace) {
+				continue
+			}
+			if opts.ResourceType != "" && event.resourceType != opts.ResourceType {
+				continue
+			}
+			if opts.ResourceName != "" && parts[4] != opts.ResourceName {
+				continue
+			}
+
+			if decode {
+				var decoded struct {
+					Verb string `json:"verb"`
+					User string `json:"user"`
+				}
+				if err := item.Value(func(val []byte) error {
+					return json.Unmarshal(val, &decoded)
+				}); err != nil {
+					return err
+				}
+				if !matchesVerb(verbs, decoded.Verb) {
+					continue
+				}
+				if opts.User != "" && decoded.User != opts.User {
+					continue
+				}
+				event.verb, event.user = decoded.Verb, decoded.User
+			}
+
+			count(event)
+		}
+
+		return nil
+	})
+	return truncated, err
+}
+
+// deleteBatchSize bounds the number of keys removed per transaction
+const deleteBatchSize = 1000
+
+// DeleteNamespace removes all events for a namespace from every index and
+// returns the number of events deleted. On error the count reflects the
+// batches that were already committed.
+func (s *Store) DeleteNamespace(ctx context.Context, namespace string) (int, error) {
+	if namespace == "" {
+		return 0, fmt.Errorf("namespace is required")
+	}
+
+	var keys [][]byte
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOpts := badger.DefaultIteratorOptions
+		iterOpts.PrefetchValues = false
+
+		iter := txn.NewIterator(iterOpts)
+		defer iter.Close()
+
+		// Time index: namespace is the third key segment, so scan the whole index
+		prefix := []byte("events/")
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			key := iter.Item().KeyCopy(nil)
+			parts := strings.Split(string(key), "/")
+			if len(parts) >= 6 && parts[2] == namespace {
+				keys = append(keys, key)
+			}
+		}
+
+		// Object index: namespace is the key prefix
+		prefix = []byte(fmt.Sprintf("objects/%s/", namespace))
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			keys = append(keys, iter.Item().KeyCopy(nil))
+		}
+
+		// Owner index: namespace is the fourth key segment
+		prefix = []byte("byOwner/")
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			key := iter.Item().KeyCopy(nil)
+			parts := strings.Split(string(key), "/")
+			if len(parts) >= 7 && parts[3] == namespace {
+				keys = append(keys, key)
+			}
+		}
+
+		// Owner name index: namespace is the fifth key segment
+		prefix = []byte("owners/")
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			key := iter.Item().KeyCopy(nil)
+			parts := strings.Split(string(key), "/")
+			if len(parts) >= 8 && parts[4] == namespace {
+				keys = append(keys, key)
+			}
+		}
+
+		// Verb and user indexes: namespace is the fourth key segment
+		for _, index := range []string{"byVerb/", "byUser/"} {
+			prefix = []byte(index)
+			for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				key := iter.Item().KeyCopy(nil)
+				parts := strings.Split(string(key), "/")
+				if len(parts) >= 7 && parts[3] == namespace {
+					keys = append(keys, key)
+				}
+			}
+		}
+
+		// Event references are keyed by the involved object, which may live in
+		// another namespace (e.g. Nodes), so match on the stored Event instead
+		prefix = []byte("eventRefs/")
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			item := iter.Item()
+			err := item.Value(func(val []byte) error {
+				var event models.AuditEvent
+				if err := json.Unmarshal(val, &event); err != nil {
+					return err
+				}
+				if event.Namespace == namespace {
+					keys = append(keys, item.KeyCopy(nil))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan namespace keys: %w", err)
+	}
+
+	deleted := 0
+	for start := 0; start < len(keys); start += deleteBatchSize {
+		if err := ctx.Err(); err != nil {
+			return deleted, fmt.Errorf("namespace purge interrupted: %w", err)
+		}
+
+		batch := keys[start:min(start+deleteBatchSize, len(keys))]
+		err := s.update(func(txn *badger.Txn) error {
+			for _, key := range batch {
+				if err := txn.Delete(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete namespace keys: %w", err)
+		}
+
+		// Only time-index keys count as events; the other indexes are duplicates
+		for _, key := range batch {
+			if strings.HasPrefix(string(key), "events/") {
+				deleted++
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// sweepIndexes lists each index prefix with the key segment holding the
+// event timestamp. Only the time index is ordered by it.
+var sweepIndexes = []struct {
+	prefix  string
+	segment int
+	// typeSegment is the resource type segment; the eventRefs index only
+	// holds Kubernetes events and has none
+	typeSegment int
+	ordered     bool
+}{
+	{prefix: "events/", segment: 1, typeSegment: 3, ordered: true},
+	{prefix: "objects/", segment: 4, typeSegment: 2},
+	{prefix: "eventRefs/", segment: 4, typeSegment: -1},
+	{prefix: "byOwner/", segment: 2, typeSegment: 4},
+	{prefix: "owners/", segment: 3, typeSegment: 5},
+	{prefix: "byVerb/", segment: 2, typeSegment: 4},
+	{prefix: "byUser/", segment: 2, typeSegment: 4},
+}
+
+// SweepExpired deletes the keys of every index whose event timestamp is
+// before cutoff and returns the number of events removed. BadgerDB's TTL only
+// hides expired values; their keys stay in the LSM tree until compaction and
+// are still visited by time-range scans. Keys are read and deleted in small
+// batches, each in its own transaction, so queries are not blocked for long.
+func (s *Store) SweepExpired(ctx context.Context, cutoff time.Time) (int, error) {
+	return s.sweep(ctx, func(string) time.Time { return cutoff }, cutoff)
+}
+
+// sweepRetention deletes the keys whose event is older than the retention
+// period of its resource type
+func (s *Store) sweepRetention(ctx context.Context, now time.Time) (int, error) {
+	cutoff := func(resourceType string) time.Time {
+		return now.Add(-s.retention(resourceType))
+	}
+
+	// Nothing at or after the latest cutoff has expired
+	latest := cutoff("")
+	for resourceType := range s.retentionOverrides {
+		if c := cutoff(resourceType); c.After(latest) {
+			latest = c
+		}
+	}
+	return s.sweep(ctx, cutoff, latest)
+}
+
+// sweep deletes the keys of every index whose event timestamp is before the
+// cutoff of its resource type. latest is the latest of all cutoffs.
+func (s *Store) sweep(ctx context.Context, cutoff func(resourceType string) time.Time, latest time.Time) (int, error) {
+	deleted := 0
+	for _, index := range sweepIndexes {
+		n, err := s.sweepIndex(ctx, index.prefix, index.segment, index.typeSegment, index.ordered, cutoff, latest)
+		if index.prefix == "events/" {
+			deleted += n
+		}
+		if err != nil {
+			return deleted, fmt.Errorf("failed to sweep %s index: %w", strings.TrimSuffix(index.prefix, "/"), err)
+		}
+	}
+	return deleted, nil
+}
+
+// sweepIndex deletes the keys under prefix whose timestamp segment is before
+// the cutoff of their resource type. An ordered index is only scanned up to
+// the first key at or after latest; the others are scanned entirely.
+func (s *Store) sweepIndex(ctx context.Context, prefix string, segment, typeSegment int, ordered bool, cutoff func(string) time.Time, latest time.Time) (int, error) {
+	deleted := 0
+	seek := []byte(prefix)
+	for seek != nil {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+
+		var batch [][]byte
+		next := seek
+		seek = nil
+		err := s.db.View(func(txn *badger.Txn) error {
+			iterOpts := badger.DefaultIteratorOptions
+			iterOpts.PrefetchValues = false
+			iter := txn.NewIterator(iterOpts)
+			defer iter.Close()
+
+			visited := 0
+			for iter.Seek(next); iter.ValidForPrefix([]byte(prefix)); iter.Next() {
+				key := iter.Item().KeyCopy(nil)
+				if visited == deleteBatchSize {
+					seek = key
+					return nil
+				}
+				visited++
+
+				parts := strings.Split(string(key), "/")
+				if len(parts) <= max(segment, typeSegment) {
+					continue
+				}
+				timestamp, err := parseKeyTime(parts[segment])
+				if err != nil {
+					continue
+				}
+				if ordered && !timestamp.Before(latest) {
+					return nil
+				}
+
+				resourceType := "events"
+				if typeSegment >= 0 {
+					resourceType = parts[typeSegment]
+				}
+				if timestamp.Before(cutoff(resourceType)) {
+					batch = append(batch, key)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return deleted, err
+		}
+
+		if len(batch) == 0 {
+			continue
+		}
+		err = s.update(func(txn *badger.Txn) error {
+			for _, key := range batch {
+				if err := txn.Delete(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += len(batch)
+	}
+	return deleted, nil
+}
+
+// StartSweepRoutine periodically deletes keys older than the retention period
+// of their resource type until ctx is canceled. It returns immediately for
+// read-only stores.
+func (s *Store) StartSweepRoutine(ctx context.Context, interval time.Duration) {
+	if s.readOnly || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			err := s.RunGC(ctx, 0.5) // Discard 50% stale data
-			if err != nil && err != badger.ErrNoRewrite {
+			if _, err := s.sweepRetention(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				s.log.Error(err, "Retention sweep failed")
+			}
+		}
+	}
+}
+
+// Metrics summarizes BadgerDB storage health
+type Metrics struct {
+	// LSMSize is the on-disk size of the LSM tree (keys and small values)
+	LSMSize int64 `json:"lsmSize"`
+	// VLogSize is the on-disk size of the value log
+	VLogSize int64 `json:"vlogSize"`
+	// PendingGCBytes estimates stale LSM data that compaction has yet to reclaim
+	PendingGCBytes int64          `json:"pendingGCBytes"`
+	Levels         []LevelMetrics `json:"levels"`
+}
+
+// LevelMetrics describes one LSM level
+type LevelMetrics struct {
+	Level     int   `json:"level"`
+	NumTables int   `json:"numTables"`
+	Size      int64 `json:"size"`
+	StaleSize int64 `json:"staleSize"`
+}
+
+// Metrics returns storage sizes from BadgerDB's in-memory bookkeeping. It does
+// not scan keys; sizes are refreshed by BadgerDB about once a minute.
+func (s *Store) Metrics() Metrics {
+	lsm, vlog := s.db.Size()
+	metrics := Metrics{
+		LSMSize:  lsm,
+		VLogSize: vlog,
+	}
+
+	for _, level := range s.db.Levels() {
+		metrics.PendingGCBytes += level.StaleDatSize
+		metrics.Levels = append(metrics.Levels, LevelMetrics{
+			Level:     level.Level,
+			NumTables: level.NumTables,
+			Size:      level.Size,
+			StaleSize: level.StaleDatSize,
+		})
+	}
+
+	return metrics
+}
+
+// statsKeyScanLimit bounds the keys Stats counts, so it stays cheap on large
+// stores
+const statsKeyScanLimit = 1_000_000
+
+// StoreStats describes how large the store has grown
+type StoreStats struct {
+	// LSMSize is the on-disk size of the LSM tree (keys and small values)
+	LSMSize int64 `json:"lsmSize"`
+	// VLogSize is the on-disk size of the value log
+	VLogSize int64 `json:"vlogSize"`
+	// KeyCount counts the live keys of all indexes, up to
+	// statsKeyScanLimit; KeyCountCapped is set when there are more
+	KeyCount       int  `json:"keyCount"`
+	KeyCountCapped bool `json:"keyCountCapped,omitempty"`
+	// OldestEvent and NewestEvent are the timestamps of the first and last
+	// keys of the time index, unset when no events are stored
+	OldestEvent *time.Time `json:"oldestEvent,omitempty"`
+	NewestEvent *time.Time `json:"newestEvent,omitempty"`
+}
+
+// Stats returns the store sizes from BadgerDB's bookkeeping (refreshed about
+// once a minute), a key count from a scan of up to statsKeyScanLimit keys,
+// and the time span of the stored events from the first and last keys of the
+// time index.
+func (s *Store) Stats(ctx context.Context) (StoreStats, error) {
+	var stats StoreStats
+	stats.LSMSize, stats.VLogSize = s.db.Size()
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOpts := badger.DefaultIteratorOptions
+		iterOpts.PrefetchValues = false
+
+		iter := txn.NewIterator(iterOpts)
+		defer iter.Close()
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if stats.KeyCount == statsKeyScanLimit {
+				stats.KeyCountCapped = true
+				break
+			}
+			stats.KeyCount++
+		}
+
+		prefix := []byte(timeIndexPrefix)
+		iter.Seek(prefix)
+		if !iter.ValidForPrefix(prefix) {
+			return nil
+		}
+		stats.OldestEvent = timeKeyTimestamp(iter.Item().Key())
+
+		// In reverse mode Seek finds the last key <= the seek key, so start
+		// just past every key in the time index
+		iterOpts.Reverse = true
+		reverse := txn.NewIterator(iterOpts)
+		defer reverse.Close()
+		reverse.Seek([]byte(timeIndexPrefix + "\xff"))
+		if reverse.ValidForPrefix(prefix) {
+			stats.NewestEvent = timeKeyTimestamp(reverse.Item().Key())
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// timeKeyTimestamp returns the timestamp of a time index key, or nil when it
+// can't be parsed
+func timeKeyTimestamp(key []byte) *time.Time {
+	parts := strings.SplitN(strings.TrimPrefix(string(key), timeIndexPrefix), "/", 2)
+	timestamp, err := parseKeyTime(parts[0])
+	if err != nil {
+		return nil
+	}
+	return &timestamp
+}
+
+// restorePendingWrites is the number of pending writes Restore batches
+const restorePendingWrites = 256
+
+// Backup writes the entries written after version since to w, in BadgerDB's
+// backup format, and returns the version to pass as since for the next
+// incremental backup. since is zero for a full backup.
+func (s *Store) Backup(w io.Writer, since uint64) (uint64, error) {
+	// BadgerDB skips versions up to since, despite documenting otherwise
+	version, err := s.db.Backup(w, since)
+	if err != nil {
+		return 0, fmt.Errorf("failed to back up store: %w", err)
+	}
+	// version is that of the last dumped entry, or zero when nothing changed
+	return max(version, since), nil
+}
+
+// Restore loads a backup written by Backup. Entries keep their version and
+// expiry; stored entries missing from the backup are kept. Incremental
+// backups are restored in the order they were taken. Writes wait until the
+// restore has finished.
+func (s *Store) Restore(r io.Reader) error {
+	if s.readOnly {
+		return errors.New("cannot restore into a read-only store")
+	}
+	s.writes.Lock()
+	defer s.writes.Unlock()
+
+	// The backup may predate the verb and user indexes or hold events
+	// without them, so queries scan the time index until they are rebuilt
+	s.secondaryIndexes.Store(false)
+	if err := s.db.Load(r, restorePendingWrites); err != nil {
+		return fmt.Errorf("failed to restore store: %w", err)
+	}
+	if err := s.buildSecondaryIndexes(context.Background()); err != nil {
+		return fmt.Errorf("failed to rebuild verb and user indexes: %w", err)
+	}
+	return nil
+}
+
+// RunGC runs BadgerDB value log garbage collection with the configured
+// discard ratio. Every pass rewrites at most one file, so it runs passes
+// until there is nothing left to rewrite and returns how many rewrote one.
+func (s *Store) RunGC(ctx context.Context) (int, error) {
+	discardRatio := s.discardRatio()
+	rewritten := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return rewritten, err
+		}
+		err := s.db.RunValueLogGC(discardRatio)
+		if errors.Is(err, badger.ErrNoRewrite) {
+			return rewritten, nil
+		}
+		if err != nil {
+			return rewritten, err
+		}
+		rewritten++
+	}
+}
+
+// discardRatio returns the configured GC discard ratio or the default
+func (s *Store) discardRatio() float64 {
+	if s.gcDiscardRatio == 0 {
+		return defaultGCDiscardRatio
+	}
+	return s.gcDiscardRatio
+}
+
+// syncInterval bounds the crash-loss window when writes are async
+const syncInterval = 10 * time.Second
+
+// StartGCRoutine starts a background goroutine for periodic GC. It returns
+// immediately for read-only stores, which cannot be compacted or synced.
+func (s *Store) StartGCRoutine(ctx context.Context) {
+	if s.readOnly {
+		return
+	}
+
+	interval := s.gcInterval
+	if interval <= 0 {
+		interval = defaultGCInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// With async writes, flush periodically to bound the loss window on crash
+	var syncC <-chan time.Time
+	if !s.syncWrites {
+		syncTicker := time.NewTicker(syncInterval)
+		defer syncTicker.Stop()
+		syncC = syncTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunGC(ctx); err != nil && ctx.Err() == nil {
 				// Log error but continue
-				fmt.Printf("GC error: %v\n", err)
+				s.log.Error(err, "Value log GC failed")
+			}
+		case <-syncC:
+			if err := s.db.Sync(); err != nil {
+				s.log.Error(err, "Sync failed")
 			}
 		}
 	}
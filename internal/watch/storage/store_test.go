@@ -0,0 +1,1583 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestBadgerOptionsSyncWrites(t *testing.T) {
+	path := t.TempDir()
+
+	if !badgerOptions(path, true).SyncWrites {
+		t.Error("expected SyncWrites to be enabled")
+	}
+	if badgerOptions(path, false).SyncWrites {
+		t.Error("expected SyncWrites to be disabled")
+	}
+}
+
+// newTestStore opens a store in a temporary directory
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	opts := badgerOptions(t.TempDir(), false)
+	opts.Logger = nil
+	db, err := badger.Open(opts)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// The verb and user indexes of an empty store are complete
+	s := &Store{db: db, retentionDays: 1}
+	s.secondaryIndexes.Store(true)
+	return s
+}
+
+// storeObject transforms and stores obj as an ADDED event
+func storeObject(t *testing.T, s *Store, obj *unstructured.Unstructured) {
+	t.Helper()
+
+	event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+	if err != nil {
+		t.Fatalf("failed to transform %s: %v", obj.GetName(), err)
+	}
+	if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+		t.Fatalf("failed to store %s: %v", obj.GetName(), err)
+	}
+}
+
+func newObject(kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetUID(types.UID(namespace + "-" + name))
+	return obj
+}
+
+func newEventFor(namespace, name string, involved *unstructured.Unstructured) *unstructured.Unstructured {
+	obj := newObject("Event", namespace, name)
+	obj.Object["involvedObject"] = map[string]any{
+		"kind":      involved.GetKind(),
+		"namespace": involved.GetNamespace(),
+		"name":      involved.GetName(),
+	}
+	return obj
+}
+
+// keysWithPrefix lists all stored keys under prefix
+func keysWithPrefix(t *testing.T, s *Store, prefix string) []string {
+	t.Helper()
+
+	var keys []string
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOpts := badger.DefaultIteratorOptions
+		iterOpts.PrefetchValues = false
+		iter := txn.NewIterator(iterOpts)
+		defer iter.Close()
+
+		for iter.Seek([]byte(prefix)); iter.ValidForPrefix([]byte(prefix)); iter.Next() {
+			keys = append(keys, string(iter.Item().Key()))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to list keys: %v", err)
+	}
+	return keys
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	s := newTestStore(t)
+
+	podA := newObject("Pod", "a", "web")
+	podB := newObject("Pod", "b", "web")
+	node := newObject("Node", "", "node-1")
+	storeObject(t, s, podA)
+	storeObject(t, s, podB)
+	storeObject(t, s, newEventFor("a", "web.1", podA))
+	storeObject(t, s, newEventFor("b", "web.1", podB))
+	// An Event in namespace a about a cluster-scoped object
+	storeObject(t, s, newEventFor("a", "node-1.1", node))
+
+	deleted, err := s.DeleteNamespace(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("DeleteNamespace failed: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("expected 3 deleted events, got %d", deleted)
+	}
+
+	for _, key := range keysWithPrefix(t, s, "events/") {
+		if strings.Split(key, "/")[2] == "a" {
+			t.Errorf("time index key for namespace a survived: %s", key)
+		}
+	}
+	if keys := keysWithPrefix(t, s, "objects/a/"); len(keys) != 0 {
+		t.Errorf("object index keys for namespace a survived: %v", keys)
+	}
+	if keys := keysWithPrefix(t, s, "eventRefs/a/"); len(keys) != 0 {
+		t.Errorf("event reference keys for namespace a survived: %v", keys)
+	}
+	if keys := keysWithPrefix(t, s, "eventRefs//Node/"); len(keys) != 0 {
+		t.Errorf("node event reference from namespace a survived: %v", keys)
+	}
+
+	if keys := keysWithPrefix(t, s, "events/"); len(keys) != 2 {
+		t.Errorf("expected 2 time index keys for namespace b, got %v", keys)
+	}
+	if keys := keysWithPrefix(t, s, "objects/b/"); len(keys) != 2 {
+		t.Errorf("expected 2 object index keys for namespace b, got %v", keys)
+	}
+	if keys := keysWithPrefix(t, s, "eventRefs/b/"); len(keys) != 1 {
+		t.Errorf("expected 1 event reference key for namespace b, got %v", keys)
+	}
+}
+
+func TestOwnerIndex(t *testing.T) {
+	s := newTestStore(t)
+
+	storeOwned := func(namespace, name, ownerUID string, at time.Time) {
+		obj := newObject("Pod", namespace, name)
+		event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+		if err != nil {
+			t.Fatalf("failed to transform %s: %v", name, err)
+		}
+		event.Timestamp = at
+		event.Owner = &models.ObjectReference{Kind: "ReplicaSet", Namespace: namespace, Name: "rs", UID: ownerUID}
+		if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatalf("failed to store %s: %v", name, err)
+		}
+	}
+
+	now := time.Now()
+	storeOwned("a", "web-1", "rs-a", now.Add(-time.Minute))
+	storeOwned("a", "web-0", "rs-a", now.Add(-2*time.Minute))
+	storeOwned("b", "api-0", "rs-b", now.Add(-48*time.Hour))
+	// Unowned events are not indexed
+	storeObject(t, s, newObject("Pod", "a", "standalone"))
+
+	events, err := s.GetOwnerEvents(context.Background(), "rs-a")
+	if err != nil {
+		t.Fatalf("GetOwnerEvents failed: %v", err)
+	}
+	if len(events) != 2 || events[0].ResourceName != "web-0" || events[1].ResourceName != "web-1" {
+		t.Errorf("expected both owned pods oldest first, got %+v", events)
+	}
+	if keys := keysWithPrefix(t, s, "byOwner/"); len(keys) != 3 {
+		t.Errorf("expected 3 owner index keys, got %v", keys)
+	}
+
+	if _, err := s.SweepExpired(context.Background(), now.Add(-24*time.Hour)); err != nil {
+		t.Fatalf("SweepExpired failed: %v", err)
+	}
+	if keys := keysWithPrefix(t, s, "byOwner/rs-b/"); len(keys) != 0 {
+		t.Errorf("expired owner index keys survived: %v", keys)
+	}
+
+	if _, err := s.DeleteNamespace(context.Background(), "a"); err != nil {
+		t.Fatalf("DeleteNamespace failed: %v", err)
+	}
+	if keys := keysWithPrefix(t, s, "byOwner/"); len(keys) != 0 {
+		t.Errorf("owner index keys for namespace a survived: %v", keys)
+	}
+}
+
+func TestOwnerNameIndex(t *testing.T) {
+	s := newTestStore(t)
+
+	storeOwned := func(namespace, name, owner string, at time.Time) {
+		obj := newObject("Pod", namespace, name)
+		obj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: owner, UID: types.UID(namespace + "-" + owner)}})
+		// The owner name index does not need the OwnerEnricher
+		event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+		if err != nil {
+			t.Fatalf("failed to transform %s: %v", name, err)
+		}
+		event.Timestamp = at
+		if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatalf("failed to store %s: %v", name, err)
+		}
+	}
+
+	now := time.Now()
+	storeOwned("a", "web-1", "web-5d8f", now.Add(-time.Minute))
+	storeOwned("a", "web-0", "web-5d8f", now.Add(-2*time.Minute))
+	// Owner names are only unique within a namespace
+	storeOwned("b", "web-0", "web-5d8f", now.Add(-48*time.Hour))
+	storeOwned("a", "api-0", "api-7c4b", now.Add(-time.Minute))
+
+	events, err := s.GetEventsByOwner(context.Background(), "a", "ReplicaSet", "web-5d8f")
+	if err != nil {
+		t.Fatalf("GetEventsByOwner failed: %v", err)
+	}
+	if len(events) != 2 || events[0].ResourceName != "web-0" || events[1].ResourceName != "web-1" {
+		t.Errorf("expected both pods of the ReplicaSet in namespace a oldest first, got %+v", events)
+	}
+	if len(events) > 0 && (len(events[0].OwnerRefs) != 1 || events[0].OwnerRefs[0].Name != "web-5d8f") {
+		t.Errorf("expected the owner reference on the event, got %+v", events[0].OwnerRefs)
+	}
+
+	events, err = s.GetEventsByOwner(context.Background(), "", "ReplicaSet", "web-5d8f")
+	if err != nil {
+		t.Fatalf("GetEventsByOwner failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("expected the pods of both namespaces, got %d events", len(events))
+	}
+
+	if _, err := s.SweepExpired(context.Background(), now.Add(-24*time.Hour)); err != nil {
+		t.Fatalf("SweepExpired failed: %v", err)
+	}
+	if keys := keysWithPrefix(t, s, "owners/"); len(keys) != 3 {
+		t.Errorf("expected the expired owner name index key to be swept, got %v", keys)
+	}
+
+	if _, err := s.DeleteNamespace(context.Background(), "a"); err != nil {
+		t.Fatalf("DeleteNamespace failed: %v", err)
+	}
+	if keys := keysWithPrefix(t, s, "owners/"); len(keys) != 0 {
+		t.Errorf("owner name index keys for namespace a survived: %v", keys)
+	}
+}
+
+func TestDeleteNamespaceCanceled(t *testing.T) {
+	s := newTestStore(t)
+	storeObject(t, s, newObject("Pod", "a", "web"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := s.DeleteNamespace(ctx, "a"); err == nil {
+		t.Fatal("expected error for canceled context")
+	}
+	if keys := keysWithPrefix(t, s, "objects/a/"); len(keys) != 1 {
+		t.Errorf("expected object to survive canceled purge, got %v", keys)
+	}
+}
+
+// cancelAfterContext reports itself canceled once Err has been called more
+// than n times, so a scan is canceled after its first n items
+type cancelAfterContext struct {
+	context.Context
+	n     int
+	calls int
+}
+
+func (c *cancelAfterContext) Err() error {
+	c.calls++
+	if c.calls > c.n {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestQueriesCanceled(t *testing.T) {
+	s := newTestStore(t)
+	pod := newObject("Pod", "a", "web")
+	for i := 0; i < 10; i++ {
+		storeObject(t, s, pod)
+		storeObject(t, s, newEventFor("a", fmt.Sprintf("web.%d", i), pod))
+	}
+
+	queries := map[string]func(ctx context.Context) error{
+		"QueryEvents": func(ctx context.Context) error {
+			_, err := s.QueryEvents(ctx, QueryOptions{})
+			return err
+		},
+		"GetObjectHistory": func(ctx context.Context) error {
+			_, err := s.GetObjectHistory(ctx, "a", "pods", "web")
+			return err
+		},
+		"GetRelatedEvents": func(ctx context.Context) error {
+			_, err := s.GetRelatedEvents(ctx, "a", "Pod", "web")
+			return err
+		},
+	}
+	for name, query := range queries {
+		ctx := &cancelAfterContext{Context: context.Background(), n: 1}
+		if err := query(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+		if ctx.calls != 2 {
+			t.Errorf("%s: expected the scan to stop at the second item, checked %d times", name, ctx.calls)
+		}
+	}
+}
+
+func TestDeleteNamespaceRequiresNamespace(t *testing.T) {
+	s := newTestStore(t)
+
+	if _, err := s.DeleteNamespace(context.Background(), ""); err == nil {
+		t.Fatal("expected error for empty namespace")
+	}
+}
+
+func TestStatsByResourceType(t *testing.T) {
+	s := newTestStore(t)
+	storeObject(t, s, newObject("Pod", "a", "web"))
+	storeObject(t, s, newObject("Pod", "b", "web"))
+	storeObject(t, s, newObject("ConfigMap", "a", "settings"))
+
+	stats, err := s.StatsByResourceType(context.Background())
+	if err != nil {
+		t.Fatalf("StatsByResourceType failed: %v", err)
+	}
+
+	if stats["pods"].Count != 2 {
+		t.Errorf("expected 2 pod events, got %d", stats["pods"].Count)
+	}
+	if stats["configmaps"].Count != 1 {
+		t.Errorf("expected 1 configmap event, got %d", stats["configmaps"].Count)
+	}
+	if stats["pods"].Oldest.IsZero() {
+		t.Error("expected oldest pod event timestamp to be set")
+	}
+}
+
+func TestStats(t *testing.T) {
+	s := newTestStore(t)
+
+	stats, err := s.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.KeyCount != 0 || stats.OldestEvent != nil || stats.NewestEvent != nil {
+		t.Errorf("expected no keys and no time span for an empty store, got %+v", stats)
+	}
+
+	start := time.Date(2025, 3, 4, 10, 0, 0, 123456789, time.UTC)
+	for i := 0; i < 10; i++ {
+		obj := newObject("Pod", "default", fmt.Sprintf("web-%d", i))
+		event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Stored out of order, the time index sorts them
+		event.Timestamp = start.Add(time.Duration((i*7)%10) * time.Hour)
+		if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err = s.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.OldestEvent == nil || !stats.OldestEvent.Equal(start) {
+		t.Errorf("expected the oldest event at %s, got %v", start, stats.OldestEvent)
+	}
+	if newest := start.Add(9 * time.Hour); stats.NewestEvent == nil || !stats.NewestEvent.Equal(newest) {
+		t.Errorf("expected the newest event at %s, got %v", newest, stats.NewestEvent)
+	}
+	// Time, object, verb and user index keys per event
+	if stats.KeyCount != 40 || stats.KeyCountCapped {
+		t.Errorf("expected 40 keys, got %d (capped %v)", stats.KeyCount, stats.KeyCountCapped)
+	}
+}
+
+func TestQueryEventsPageCursor(t *testing.T) {
+	s := newTestStore(t)
+	for _, name := range []string{"a", "b", "c"} {
+		storeObject(t, s, newObject("Pod", "default", name))
+	}
+
+	first, cursor, err := s.QueryEventsPage(context.Background(), QueryOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("first page failed: %v", err)
+	}
+	if len(first) != 2 || cursor == "" {
+		t.Fatalf("expected 2 events and a cursor, got %d events, cursor %q", len(first), cursor)
+	}
+
+	second, cursor, err := s.QueryEventsPage(context.Background(), QueryOptions{Limit: 2, Cursor: cursor})
+	if err != nil {
+		t.Fatalf("second page failed: %v", err)
+	}
+	if len(second) != 1 || cursor != "" {
+		t.Fatalf("expected 1 event and no cursor, got %d events, cursor %q", len(second), cursor)
+	}
+	if second[0].ResourceName != "c" {
+		t.Errorf("expected second page to resume at c, got %s", second[0].ResourceName)
+	}
+
+	if _, _, err := s.QueryEventsPage(context.Background(), QueryOptions{Cursor: "!!"}); err != ErrInvalidCursor {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestQueryEventsSearch(t *testing.T) {
+	s := newTestStore(t)
+
+	store := func(name, message, image string) {
+		obj := newObject("Pod", "default", name)
+		obj.Object["spec"] = map[string]any{"containers": []any{map[string]any{"image": image}}}
+		event, err := models.TransformWatchEvent(obj, models.EventTypeModified)
+		if err != nil {
+			t.Fatalf("failed to transform %s: %v", name, err)
+		}
+		event.Message = message
+		if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatalf("failed to store %s: %v", name, err)
+		}
+	}
+	store("mount", "Warning FailedMount: secret db-creds not found", "nginx:1.25")
+	store("pull", "Warning Failed: ErrImagePull registry.example.com/api:2.0", "registry.example.com/api:2.0")
+	store("ok", "Started container web", "nginx:1.25")
+	store("scheduled", "Successfully assigned default/ok to node-1", "redis:7")
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "failedmount", want: []string{"mount"}},
+		// Object contents are searched too
+		{query: "NGINX:1.25", want: []string{"mount", "ok"}},
+		// Every word must match
+		{query: "warning nginx", want: []string{"mount"}},
+		{query: "  warning   registry.example.com ", want: []string{"pull"}},
+		{query: "warning redis", want: nil},
+		{query: "", want: []string{"mount", "pull", "ok", "scheduled"}},
+	}
+	for _, tt := range tests {
+		events, err := s.QueryEvents(context.Background(), QueryOptions{Search: tt.query})
+		if err != nil {
+			t.Fatalf("QueryEvents(%q) failed: %v", tt.query, err)
+		}
+		var names []string
+		for _, event := range events {
+			names = append(names, event.ResourceName)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(tt.want) {
+			t.Errorf("QueryEvents(%q) = %v, want %v", tt.query, names, tt.want)
+		}
+	}
+
+	// The limit counts matching events only
+	events, _, err := s.QueryEventsPage(context.Background(), QueryOptions{Search: "warning", Limit: 1})
+	if err != nil {
+		t.Fatalf("QueryEventsPage failed: %v", err)
+	}
+	if len(events) != 1 || events[0].ResourceName != "mount" {
+		t.Errorf("expected the first match only, got %+v", events)
+	}
+}
+
+func TestQueryEventsOrder(t *testing.T) {
+	s := newTestStore(t)
+	base := time.Date(2025, 3, 4, 11, 0, 0, 0, time.UTC)
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		storeObjectAt(t, s, newObject("Pod", "default", name), base.Add(time.Duration(i)*time.Minute))
+	}
+
+	names := func(events []*models.AuditEvent) string {
+		var out []string
+		for _, event := range events {
+			out = append(out, event.ResourceName)
+		}
+		return strings.Join(out, ",")
+	}
+	query := QueryOptions{StartTime: base.Add(time.Minute), EndTime: base.Add(3 * time.Minute)}
+
+	asc, err := s.QueryEvents(context.Background(), query)
+	if err != nil {
+		t.Fatalf("ascending query failed: %v", err)
+	}
+	query.Order = OrderDesc
+	desc, err := s.QueryEvents(context.Background(), query)
+	if err != nil {
+		t.Fatalf("descending query failed: %v", err)
+	}
+	if names(asc) != "b,c,d" || names(desc) != "d,c,b" {
+		t.Errorf("expected b,c,d and d,c,b, got %s and %s", names(asc), names(desc))
+	}
+
+	// Descending pages resume below the cursor, across the whole index
+	first, cursor, err := s.QueryEventsPage(context.Background(), QueryOptions{Order: OrderDesc, Limit: 3})
+	if err != nil {
+		t.Fatalf("first descending page failed: %v", err)
+	}
+	second, cursor, err := s.QueryEventsPage(context.Background(), QueryOptions{Order: OrderDesc, Limit: 3, Cursor: cursor})
+	if err != nil {
+		t.Fatalf("second descending page failed: %v", err)
+	}
+	if names(first) != "e,d,c" || names(second) != "b,a" || cursor != "" {
+		t.Errorf("expected e,d,c then b,a, got %s then %s (cursor %q)", names(first), names(second), cursor)
+	}
+
+	if _, _, err := s.QueryEventsPage(context.Background(), QueryOptions{Order: "newest"}); err != ErrInvalidOrder {
+		t.Errorf("expected ErrInvalidOrder, got %v", err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	path := t.TempDir()
+	s, err := NewStore(path, 1, false, nil)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		storeObject(t, s, newObject("Pod", "default", fmt.Sprintf("web-%d", i)))
+	}
+	s.Close()
+
+	// BadgerDB computes on-disk sizes when the database is opened
+	s, err = NewStore(path, 1, false, nil)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer s.Close()
+
+	metrics := s.Metrics()
+	if metrics.LSMSize+metrics.VLogSize == 0 {
+		t.Errorf("expected non-zero storage size, got %+v", metrics)
+	}
+	if len(metrics.Levels) == 0 {
+		t.Error("expected LSM level metrics")
+	}
+}
+
+func TestSummarizeEvents(t *testing.T) {
+	s := newTestStore(t)
+
+	storeObject(t, s, newObject("Pod", "a", "web-0"))
+	storeObject(t, s, newObject("Pod", "a", "web-1"))
+	storeObject(t, s, newObject("ConfigMap", "a", "settings"))
+	storeObject(t, s, newObject("Pod", "b", "api-0"))
+	storeObject(t, s, newObject("Node", "", "node-1"))
+
+	summary, err := s.SummarizeEvents(context.Background(), time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("SummarizeEvents failed: %v", err)
+	}
+
+	events, err := s.QueryEvents(context.Background(), QueryOptions{})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	if summary.Total() != len(events) {
+		t.Errorf("summary total %d does not match raw event count %d", summary.Total(), len(events))
+	}
+
+	if summary["a"]["pods"] != 2 || summary["a"]["configmaps"] != 1 || summary["b"]["pods"] != 1 || summary[""]["nodes"] != 1 {
+		t.Errorf("unexpected summary: %v", summary)
+	}
+
+	// A range entirely in the future matches nothing
+	future := time.Now().Add(time.Hour)
+	summary, err = s.SummarizeEvents(context.Background(), future, future.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SummarizeEvents failed: %v", err)
+	}
+	if summary.Total() != 0 {
+		t.Errorf("expected no events in a future range, got %v", summary)
+	}
+}
+
+// storeAction stores obj as an event with the given verb and user
+func storeAction(t *testing.T, s *Store, obj *unstructured.Unstructured, verb, user string) {
+	t.Helper()
+
+	event, err := models.TransformWatchEvent(obj, models.EventTypeModified)
+	if err != nil {
+		t.Fatalf("failed to transform %s: %v", obj.GetName(), err)
+	}
+	event.Verb = verb
+	event.User = user
+	if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+		t.Fatalf("failed to store %s: %v", obj.GetName(), err)
+	}
+}
+
+func TestAggregateEvents(t *testing.T) {
+	s := newTestStore(t)
+
+	storeAction(t, s, newObject("Pod", "a", "web-0"), "create", "alice")
+	storeAction(t, s, newObject("Pod", "a", "web-1"), "update", "alice")
+	storeAction(t, s, newObject("ConfigMap", "a", "settings"), "update", "bob")
+	storeAction(t, s, newObject("Pod", "b", "api-0"), "delete", "bob")
+	storeAction(t, s, newObject("Node", "", "node-1"), "update", "alice")
+
+	tests := []struct {
+		groupBy string
+		opts    QueryOptions
+		want    map[string]int
+	}{
+		{groupBy: GroupByNamespace, want: map[string]int{"a": 3, "b": 1, "": 1}},
+		{groupBy: GroupByResourceType, want: map[string]int{"pods": 3, "configmaps": 1, "nodes": 1}},
+		{groupBy: GroupByVerb, want: map[string]int{"create": 1, "update": 3, "delete": 1}},
+		{groupBy: GroupByUser, want: map[string]int{"alice": 3, "bob": 2}},
+		// Filters apply to key and value fields alike
+		{groupBy: GroupByVerb, opts: QueryOptions{Namespace: "a"}, want: map[string]int{"create": 1, "update": 2}},
+		{groupBy: GroupByNamespace, opts: QueryOptions{User: "bob"}, want: map[string]int{"a": 1, "b": 1}},
+	}
+	for _, tt := range tests {
+		aggregate, err := s.AggregateEvents(context.Background(), tt.opts, tt.groupBy)
+		if err != nil {
+			t.Fatalf("AggregateEvents(%s) failed: %v", tt.groupBy, err)
+		}
+		if aggregate.Truncated {
+			t.Errorf("AggregateEvents(%s) unexpectedly truncated", tt.groupBy)
+		}
+		if fmt.Sprint(aggregate.Counts) != fmt.Sprint(tt.want) {
+			t.Errorf("AggregateEvents(%s, %+v) = %v, want %v", tt.groupBy, tt.opts, aggregate.Counts, tt.want)
+		}
+	}
+
+	if _, err := s.AggregateEvents(context.Background(), QueryOptions{}, "name"); !errors.Is(err, ErrInvalidGroupBy) {
+		t.Errorf("expected ErrInvalidGroupBy, got %v", err)
+	}
+}
+
+func TestHistogramEvents(t *testing.T) {
+	s := newTestStore(t)
+
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	end := start.Add(12 * time.Minute)
+	for i, offset := range []time.Duration{
+		-time.Second,                 // before the range
+		0,                            // first bucket, on its start
+		5*time.Minute - time.Second,  // first bucket, at its end
+		5 * time.Minute,              // second bucket, on its start
+		7 * time.Minute,              // second bucket
+		12 * time.Minute,             // on end: the cut-short last bucket
+		12*time.Minute + time.Second, // after the range
+	} {
+		storeObjectAt(t, s, newObject("Pod", "default", fmt.Sprintf("web-%d", i)), start.Add(offset))
+	}
+
+	histogram, err := s.HistogramEvents(context.Background(), QueryOptions{StartTime: start, EndTime: end}, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("HistogramEvents failed: %v", err)
+	}
+
+	want := []HistogramBucket{
+		{Start: start, Count: 2},
+		{Start: start.Add(5 * time.Minute), Count: 2},
+		{Start: start.Add(10 * time.Minute), Count: 1},
+	}
+	if fmt.Sprint(histogram.Buckets) != fmt.Sprint(want) {
+		t.Errorf("got buckets %v, want %v", histogram.Buckets, want)
+	}
+
+	// The buckets add up to the events in the range
+	aggregate, err := s.AggregateEvents(context.Background(), QueryOptions{StartTime: start, EndTime: end}, GroupByNamespace)
+	if err != nil {
+		t.Fatalf("AggregateEvents failed: %v", err)
+	}
+	total := 0
+	for _, bucket := range histogram.Buckets {
+		total += bucket.Count
+	}
+	if total != aggregate.Counts["default"] || total != 5 {
+		t.Errorf("expected the buckets to add up to 5 events, got %d (aggregate %d)", total, aggregate.Counts["default"])
+	}
+
+	for _, tt := range []struct {
+		opts   QueryOptions
+		bucket time.Duration
+	}{
+		{QueryOptions{StartTime: start, EndTime: end}, 0},
+		{QueryOptions{StartTime: end, EndTime: start}, time.Minute},
+		{QueryOptions{EndTime: end}, time.Minute},
+		{QueryOptions{StartTime: start, EndTime: start.Add(24 * time.Hour)}, time.Second},
+	} {
+		if _, err := s.HistogramEvents(context.Background(), tt.opts, tt.bucket); !errors.Is(err, ErrInvalidHistogram) {
+			t.Errorf("HistogramEvents(%+v, %s): expected ErrInvalidHistogram, got %v", tt.opts, tt.bucket, err)
+		}
+	}
+}
+
+func TestQueryEventsNamespaces(t *testing.T) {
+	s := newTestStore(t)
+	for _, namespace := range []string{"app", "app-canary", "app-staging"} {
+		storeObject(t, s, newObject("Pod", namespace, "web"))
+		storeObject(t, s, newObject("ConfigMap", namespace, "settings"))
+	}
+
+	tests := []struct {
+		name string
+		opts QueryOptions
+		want int
+	}{
+		{"all namespaces", QueryOptions{}, 6},
+		{"single namespace", QueryOptions{Namespace: "app"}, 2},
+		{"two namespaces", QueryOptions{Namespaces: []string{"app", "app-canary"}}, 4},
+		{"namespace counts as one more", QueryOptions{Namespace: "app-staging", Namespaces: []string{"app"}}, 4},
+		{"with resource type", QueryOptions{Namespaces: []string{"app", "app-canary"}, ResourceType: "pods"}, 2},
+		{"unknown namespace", QueryOptions{Namespaces: []string{"other"}}, 0},
+	}
+	for _, tt := range tests {
+		events, err := s.QueryEvents(context.Background(), tt.opts)
+		if err != nil {
+			t.Fatalf("%s: QueryEvents failed: %v", tt.name, err)
+		}
+		if len(events) != tt.want {
+			t.Errorf("%s: expected %d events, got %d", tt.name, tt.want, len(events))
+		}
+		for _, event := range events {
+			if namespaces := tt.opts.namespaces(); len(namespaces) > 0 && !slices.Contains(namespaces, event.Namespace) {
+				t.Errorf("%s: unexpected event in %s", tt.name, event.Namespace)
+			}
+		}
+	}
+
+	aggregate, err := s.AggregateEvents(context.Background(), QueryOptions{Namespaces: []string{"app", "app-staging"}}, GroupByNamespace)
+	if err != nil {
+		t.Fatalf("AggregateEvents failed: %v", err)
+	}
+	if len(aggregate.Counts) != 2 || aggregate.Counts["app"] != 2 || aggregate.Counts["app-staging"] != 2 {
+		t.Errorf("expected the two namespaces to be aggregated, got %+v", aggregate)
+	}
+}
+
+func TestQueryEventsVerbs(t *testing.T) {
+	s := newTestStore(t)
+
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	verbs := []string{"delete", "get", "create", "update", "create", "patch", "list", "delete"}
+	for i, verb := range verbs {
+		obj := newObject("Pod", "default", fmt.Sprintf("web-%d", i))
+		event, err := models.TransformWatchEvent(obj, models.EventTypeModified)
+		if err != nil {
+			t.Fatalf("failed to transform: %v", err)
+		}
+		event.Verb = verb
+		event.Timestamp = start.Add(time.Duration(i) * time.Second)
+		if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatalf("failed to store: %v", err)
+		}
+	}
+
+	mutations := QueryOptions{Verbs: []string{"create", "update", "patch", "delete"}}
+	events, err := s.QueryEvents(context.Background(), mutations)
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	var names []string
+	for _, event := range events {
+		names = append(names, event.ResourceName)
+	}
+	if got := strings.Join(names, ","); got != "web-0,web-2,web-3,web-4,web-5,web-7" {
+		t.Errorf("expected the mutations in time order, got %s", got)
+	}
+
+	// The limit applies across the verbs, and the cursor resumes after it
+	mutations.Limit = 4
+	page, cursor, err := s.QueryEventsPage(context.Background(), mutations)
+	if err != nil {
+		t.Fatalf("QueryEventsPage failed: %v", err)
+	}
+	if len(page) != 4 || page[3].ResourceName != "web-4" || cursor == "" {
+		t.Fatalf("expected the first 4 mutations and a cursor, got %d events", len(page))
+	}
+	mutations.Cursor = cursor
+	page, cursor, err = s.QueryEventsPage(context.Background(), mutations)
+	if err != nil {
+		t.Fatalf("QueryEventsPage failed: %v", err)
+	}
+	if len(page) != 2 || page[0].ResourceName != "web-5" || cursor != "" {
+		t.Errorf("expected the remaining 2 mutations, got %d events", len(page))
+	}
+
+	// Verb counts as one more of the verbs
+	events, err = s.QueryEvents(context.Background(), QueryOptions{Verb: "get", Verbs: []string{"list"}})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("expected the get and list events, got %d", len(events))
+	}
+
+	aggregate, err := s.AggregateEvents(context.Background(), QueryOptions{Verbs: []string{"create", "delete"}}, GroupByVerb)
+	if err != nil {
+		t.Fatalf("AggregateEvents failed: %v", err)
+	}
+	if fmt.Sprint(aggregate.Counts) != fmt.Sprint(map[string]int{"create": 2, "delete": 2}) {
+		t.Errorf("unexpected counts %v", aggregate.Counts)
+	}
+}
+
+func TestAggregateEventsBudget(t *testing.T) {
+	s := newTestStore(t)
+	for i := 0; i < aggregateDeadlineCheck+10; i++ {
+		storeObject(t, s, newObject("Pod", "default", fmt.Sprintf("web-%d", i)))
+	}
+
+	// A budget that has run out by the first check stops the scan there
+	s.SetAggregateBudget(time.Nanosecond)
+	aggregate, err := s.AggregateEvents(context.Background(), QueryOptions{}, GroupByNamespace)
+	if err != nil {
+		t.Fatalf("AggregateEvents failed: %v", err)
+	}
+	if !aggregate.Truncated || aggregate.Counts["default"] >= aggregateDeadlineCheck {
+		t.Errorf("expected a truncated partial count, got %+v", aggregate)
+	}
+
+	s.SetAggregateBudget(0)
+	aggregate, err = s.AggregateEvents(context.Background(), QueryOptions{}, GroupByNamespace)
+	if err != nil {
+		t.Fatalf("AggregateEvents failed: %v", err)
+	}
+	if aggregate.Truncated || aggregate.Counts["default"] != aggregateDeadlineCheck+10 {
+		t.Errorf("expected a full count without budget, got %+v", aggregate)
+	}
+}
+
+// storeObjectAt stores obj as an ADDED event with the given timestamp
+func storeObjectAt(t *testing.T, s *Store, obj *unstructured.Unstructured, at time.Time) {
+	t.Helper()
+
+	event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+	if err != nil {
+		t.Fatalf("failed to transform %s: %v", obj.GetName(), err)
+	}
+	event.Timestamp = at
+	if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+		t.Fatalf("failed to store %s: %v", obj.GetName(), err)
+	}
+}
+
+func TestQueryEventsSubSecondOrdering(t *testing.T) {
+	s := newTestStore(t)
+
+	second := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	storeObjectAt(t, s, newObject("Pod", "default", "late"), second.Add(900*time.Millisecond))
+	storeObjectAt(t, s, newObject("Pod", "default", "early"), second.Add(100*time.Millisecond))
+	storeObjectAt(t, s, newObject("Pod", "default", "whole"), second)
+	storeObjectAt(t, s, newObject("Pod", "default", "middle"), second.Add(500*time.Millisecond))
+	// A later second in a non-UTC zone must still sort after all of the above
+	storeObjectAt(t, s, newObject("Pod", "default", "next"), second.Add(time.Second).In(time.FixedZone("CET", 3600)))
+
+	events, err := s.QueryEvents(context.Background(), QueryOptions{})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+
+	var names []string
+	for _, event := range events {
+		names = append(names, event.ResourceName)
+	}
+	want := "whole,early,middle,late,next"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("events returned in order %s, want %s", got, want)
+	}
+
+	// A sub-second start time excludes earlier events in the same second
+	events, err = s.QueryEvents(context.Background(), QueryOptions{
+		StartTime: second.Add(200 * time.Millisecond),
+		EndTime:   second.Add(800 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].ResourceName != "middle" {
+		t.Errorf("expected only middle in the sub-second range, got %d events", len(events))
+	}
+}
+
+func TestQueryEventsLegacyKeyFormat(t *testing.T) {
+	s := newTestStore(t)
+
+	at := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	event := &models.AuditEvent{Timestamp: at, Verb: "create", Namespace: "default", ResourceType: "pods", ResourceName: "legacy"}
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+	// Keys written before nanosecond precision used plain RFC3339
+	err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte("events/"+at.Format(time.RFC3339)+"/default/pods/legacy/uid-1"), data)
+	})
+	if err != nil {
+		t.Fatalf("failed to write legacy key: %v", err)
+	}
+	storeObjectAt(t, s, newObject("Pod", "default", "current"), at.Add(time.Second))
+
+	events, err := s.QueryEvents(context.Background(), QueryOptions{StartTime: at, EndTime: at.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	if len(events) != 2 || events[0].ResourceName != "legacy" || events[1].ResourceName != "current" {
+		t.Errorf("expected legacy then current, got %d events", len(events))
+	}
+}
+
+func TestRecentEvents(t *testing.T) {
+	s := newTestStore(t)
+
+	base := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	for i, name := range []string{"first", "second", "third", "fourth"} {
+		storeObjectAt(t, s, newObject("Pod", "default", name), base.Add(time.Duration(i)*time.Minute))
+	}
+
+	events, err := s.RecentEvents(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("RecentEvents failed: %v", err)
+	}
+
+	var names []string
+	for _, event := range events {
+		names = append(names, event.ResourceName)
+	}
+	if got, want := strings.Join(names, ","), "fourth,third,second"; got != want {
+		t.Errorf("RecentEvents returned %s, want %s", got, want)
+	}
+
+	events, err = s.RecentEvents(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("RecentEvents failed: %v", err)
+	}
+	if len(events) != 4 {
+		t.Errorf("expected all 4 events below the limit, got %d", len(events))
+	}
+}
+
+func TestSweepExpired(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now()
+	old := now.Add(-3 * 24 * time.Hour)
+
+	node := newObject("Node", "", "node-1")
+	storeObjectAt(t, s, node, old)
+	storeObjectAt(t, s, newEventFor("default", "node-1.old", node), old)
+	storeObjectAt(t, s, newObject("Pod", "default", "fresh"), now)
+	storeObjectAt(t, s, newEventFor("default", "node-1.fresh", node), now)
+
+	deleted, err := s.SweepExpired(context.Background(), now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 events swept, got %d", deleted)
+	}
+
+	for _, prefix := range []string{"events/", "objects/", "eventRefs/"} {
+		for _, key := range keysWithPrefix(t, s, prefix) {
+			if strings.Contains(key, formatKeyTime(old)) {
+				t.Errorf("expected expired key %s to be swept", key)
+			}
+		}
+	}
+	if keys := keysWithPrefix(t, s, "events/"); len(keys) != 2 {
+		t.Errorf("expected 2 fresh events to remain, got %v", keys)
+	}
+	if keys := keysWithPrefix(t, s, "eventRefs/"); len(keys) != 1 {
+		t.Errorf("expected 1 fresh event reference to remain, got %v", keys)
+	}
+}
+
+func TestRetentionOverrides(t *testing.T) {
+	s := newTestStore(t)
+	s.SetRetentionOverrides(map[string]time.Duration{
+		"pods":        time.Second,
+		"events":      time.Second,
+		"deployments": time.Hour,
+	})
+
+	pod := newObject("Pod", "default", "web-1")
+	storeObject(t, s, pod)
+	storeObject(t, s, newEventFor("default", "web-1.started", pod))
+	storeObject(t, s, newObject("Deployment", "default", "web"))
+
+	// TTLs have one second resolution
+	time.Sleep(2100 * time.Millisecond)
+
+	for _, prefix := range []string{"events/", "objects/"} {
+		keys := keysWithPrefix(t, s, prefix)
+		if len(keys) != 1 || !strings.Contains(keys[0], "/deployments/") {
+			t.Errorf("expected only the deployment under %s, got %v", prefix, keys)
+		}
+	}
+	if keys := keysWithPrefix(t, s, "eventRefs/"); len(keys) != 0 {
+		t.Errorf("expected the event reference to expire with its event, got %v", keys)
+	}
+}
+
+func TestStoreEventBatch(t *testing.T) {
+	s := newTestStore(t)
+	s.SetRetentionOverrides(map[string]time.Duration{"events": time.Hour})
+
+	var events []*models.AuditEvent
+	var objs []*unstructured.Unstructured
+	for i := 0; i < 50; i++ {
+		objs = append(objs, newObject("Pod", "default", fmt.Sprintf("web-%d", i)))
+	}
+	objs = append(objs, newEventFor("default", "web-0.started", objs[0]))
+	for _, obj := range objs {
+		event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+		if err != nil {
+			t.Fatalf("failed to transform %s: %v", obj.GetName(), err)
+		}
+		events = append(events, event)
+	}
+
+	before := time.Now()
+	if err := s.StoreEventBatch(context.Background(), events, objs); err != nil {
+		t.Fatalf("batch store failed: %v", err)
+	}
+
+	stored, err := s.QueryEvents(context.Background(), QueryOptions{})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(stored) != len(events) {
+		t.Errorf("expected %d events, got %d", len(events), len(stored))
+	}
+	history, err := s.GetObjectHistory(context.Background(), "default", "pods", "web-7")
+	if err != nil || len(history) != 1 {
+		t.Errorf("expected the history of web-7, got %v (%v)", history, err)
+	}
+	related, err := s.GetRelatedEvents(context.Background(), "default", "Pod", "web-0")
+	if err != nil || len(related) != 1 {
+		t.Errorf("expected the event of web-0, got %v (%v)", related, err)
+	}
+
+	// Every index entry carries the TTL of its resource type
+	err = s.db.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iter.Close()
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			key := string(iter.Item().Key())
+			retention := 24 * time.Hour
+			if strings.Contains(key, "/events/") || strings.HasPrefix(key, "eventRefs/") {
+				retention = time.Hour
+			}
+			expiresAt := time.Unix(int64(iter.Item().ExpiresAt()), 0)
+			if expiresAt.Before(before.Add(retention).Truncate(time.Second)) || expiresAt.After(time.Now().Add(retention)) {
+				t.Errorf("%s expires at %s, expected %s after the store", key, expiresAt, retention)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.StoreEventBatch(context.Background(), events, objs[1:]); err == nil {
+		t.Error("expected an error for mismatched events and objects")
+	}
+}
+
+func TestRunGC(t *testing.T) {
+	s := newTestStore(t)
+	storeObject(t, s, newObject("Pod", "default", "web"))
+
+	// Nothing to rewrite ends the GC loop without an error
+	if n, err := s.RunGC(context.Background()); err != nil || n != 0 {
+		t.Errorf("expected no rewrites and no error, got %d and %v", n, err)
+	}
+
+	// The configured ratio is passed to BadgerDB, which rejects this one
+	s.SetGC(time.Minute, 1.5)
+	if _, err := s.RunGC(context.Background()); !errors.Is(err, badger.ErrInvalidRequest) {
+		t.Errorf("expected the configured discard ratio to be used, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.RunGC(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a canceled GC to stop, got %v", err)
+	}
+}
+
+func TestSweepRetentionOverrides(t *testing.T) {
+	s := newTestStore(t)
+	s.SetRetentionOverrides(map[string]time.Duration{"deployments": 90 * 24 * time.Hour})
+	now := time.Now()
+	old := now.Add(-3 * 24 * time.Hour)
+
+	storeObjectAt(t, s, newObject("Pod", "default", "web-1"), old)
+	storeObjectAt(t, s, newObject("Deployment", "default", "web"), old)
+
+	deleted, err := s.sweepRetention(context.Background(), now)
+	if err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected only the pod to be swept, got %d", deleted)
+	}
+	for _, prefix := range []string{"events/", "objects/"} {
+		keys := keysWithPrefix(t, s, prefix)
+		if len(keys) != 1 || !strings.Contains(keys[0], "/deployments/") {
+			t.Errorf("expected the deployment to be kept under %s, got %v", prefix, keys)
+		}
+	}
+}
+
+func TestQueryEventsSecondaryIndexes(t *testing.T) {
+	s := newTestStore(t)
+
+	start := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	actions := []struct {
+		namespace, name, verb, user string
+	}{
+		{"a", "web-0", "create", "alice"},
+		{"a", "web-1", "delete", "alice"},
+		{"b", "api-0", "delete", "bob"},
+		{"a", "web-2", "update", "bob"},
+		{"b", "api-1", "delete", "alice"},
+		// User names may contain slashes
+		{"a", "web-3", "delete", "https://issuer.example.com/#carol"},
+	}
+	for i, action := range actions {
+		obj := newObject("Pod", action.namespace, action.name)
+		event, err := models.TransformWatchEvent(obj, models.EventTypeModified)
+		if err != nil {
+			t.Fatalf("failed to transform %s: %v", action.name, err)
+		}
+		event.Timestamp = start.Add(time.Duration(i) * time.Minute)
+		event.Verb = action.verb
+		event.User = action.user
+		if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+			t.Fatalf("failed to store %s: %v", action.name, err)
+		}
+	}
+
+	names := func(events []*models.AuditEvent) string {
+		var names []string
+		for _, event := range events {
+			names = append(names, event.ResourceName)
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		opts  QueryOptions
+		index string
+		want  string
+	}{
+		{opts: QueryOptions{Verb: "delete"}, index: "byVerb/", want: "web-1,api-0,api-1,web-3"},
+		{opts: QueryOptions{User: "alice"}, index: "byUser/", want: "web-0,web-1,api-1"},
+		{opts: QueryOptions{User: "alice", Verb: "delete"}, index: "byUser/", want: "web-1,api-1"},
+		{opts: QueryOptions{Verb: "delete", Namespace: "b"}, index: "byVerb/", want: "api-0,api-1"},
+		{opts: QueryOptions{Verb: "delete", Order: OrderDesc, Limit: 2}, index: "byVerb/", want: "web-3,api-1"},
+		{opts: QueryOptions{Verb: "delete", StartTime: start.Add(2 * time.Minute), EndTime: start.Add(4 * time.Minute)}, index: "byVerb/", want: "api-0,api-1"},
+		{opts: QueryOptions{User: "https://issuer.example.com/#carol"}, index: "byUser/", want: "web-3"},
+		{opts: QueryOptions{Namespace: "a"}, index: "events/", want: "web-0,web-1,web-2,web-3"},
+	}
+	for _, tt := range tests {
+		if index := s.queryIndex(tt.opts); !strings.HasPrefix(index, tt.index) {
+			t.Errorf("%+v: expected the %s index, got %s", tt.opts, tt.index, index)
+		}
+		indexed, _, err := s.QueryEventsPage(context.Background(), tt.opts)
+		if err != nil {
+			t.Fatalf("QueryEventsPage(%+v) failed: %v", tt.opts, err)
+		}
+		if got := names(indexed); got != tt.want {
+			t.Errorf("QueryEventsPage(%+v) = %s, want %s", tt.opts, got, tt.want)
+		}
+
+		// The time index scan returns the same events
+		scanned, _, err := s.queryEventsPage(context.Background(), tt.opts, timeIndexPrefix)
+		if err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		if names(scanned) != names(indexed) {
+			t.Errorf("%+v: scan returned %s, index returned %s", tt.opts, names(scanned), names(indexed))
+		}
+	}
+
+	// Cursors page through the index
+	page, cursor, err := s.QueryEventsPage(context.Background(), QueryOptions{Verb: "delete", Limit: 3})
+	if err != nil || cursor == "" {
+		t.Fatalf("expected a first page with cursor, got %v, %q", err, cursor)
+	}
+	rest, _, err := s.QueryEventsPage(context.Background(), QueryOptions{Verb: "delete", Limit: 3, Cursor: cursor})
+	if err != nil {
+		t.Fatalf("QueryEventsPage failed: %v", err)
+	}
+	if got := names(page) + "|" + names(rest); got != "web-1,api-0,api-1|web-3" {
+		t.Errorf("unexpected pages %s", got)
+	}
+	// A time index cursor does not apply to the verb index
+	_, timeCursor, _ := s.QueryEventsPage(context.Background(), QueryOptions{Limit: 1})
+	if _, _, err := s.QueryEventsPage(context.Background(), QueryOptions{Verb: "delete", Cursor: timeCursor}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+
+	if _, err := s.DeleteNamespace(context.Background(), "a"); err != nil {
+		t.Fatalf("DeleteNamespace failed: %v", err)
+	}
+	for _, prefix := range []string{"byVerb/", "byUser/"} {
+		for _, key := range keysWithPrefix(t, s, prefix) {
+			if strings.Split(key, "/")[3] == "a" {
+				t.Errorf("%s key for namespace a survived: %s", prefix, key)
+			}
+		}
+	}
+
+	if _, err := s.SweepExpired(context.Background(), start.Add(time.Hour)); err != nil {
+		t.Fatalf("SweepExpired failed: %v", err)
+	}
+	if keys := append(keysWithPrefix(t, s, "byVerb/"), keysWithPrefix(t, s, "byUser/")...); len(keys) != 0 {
+		t.Errorf("expired index keys survived: %v", keys)
+	}
+}
+
+// storeUnindexedEvent writes a delete of the pod name by alice to the time
+// index only, as stores did before the verb and user indexes existed
+func storeUnindexedEvent(t *testing.T, s *Store, name string) {
+	t.Helper()
+
+	at := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	event := &models.AuditEvent{Timestamp: at, Verb: "delete", User: "alice", Namespace: "default", ResourceType: "pods", ResourceName: name}
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+	err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte("events/"+formatKeyTime(at)+"/default/pods/"+name+"/uid-1"), data)
+	})
+	if err != nil {
+		t.Fatalf("failed to write event: %v", err)
+	}
+}
+
+func TestSecondaryIndexValues(t *testing.T) {
+	s := newTestStore(t)
+	storeObject(t, s, newObject("Pod", "default", "a"))
+
+	timeKeys := keysWithPrefix(t, s, timeIndexPrefix)
+	if len(timeKeys) != 1 {
+		t.Fatalf("expected one time index key, got %v", timeKeys)
+	}
+	err := s.db.View(func(txn *badger.Txn) error {
+		for _, key := range append(keysWithPrefix(t, s, "byVerb/"), keysWithPrefix(t, s, "byUser/")...) {
+			item, err := txn.Get([]byte(key))
+			if err != nil {
+				return err
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if string(value) != timeKeys[0] {
+				t.Errorf("%s holds %q, expected the time index key", key, value)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read index entries: %v", err)
+	}
+}
+
+func TestReadOnlyStoreWithoutSecondaryIndexes(t *testing.T) {
+	dir := t.TempDir()
+	opts := badgerOptions(dir, false)
+	opts.Logger = nil
+	db, err := badger.Open(opts)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	storeUnindexedEvent(t, &Store{db: db}, "old")
+	if err := db.Close(); err != nil {
+		t.Fatalf("failed to close store: %v", err)
+	}
+
+	s, err := NewReadOnlyStore(dir)
+	if err != nil {
+		t.Fatalf("NewReadOnlyStore failed: %v", err)
+	}
+	defer s.Close()
+
+	// Queries scan the time index instead of the missing verb and user indexes
+	for _, opts := range []QueryOptions{{Verb: "delete"}, {User: "alice"}} {
+		if index := s.queryIndex(opts); index != timeIndexPrefix {
+			t.Errorf("%+v: expected the time index, got %s", opts, index)
+		}
+		events, err := s.QueryEvents(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("QueryEvents failed: %v", err)
+		}
+		if len(events) != 1 {
+			t.Errorf("%+v: expected the stored event, got %d events", opts, len(events))
+		}
+	}
+}
+
+func TestRestorePausesWrites(t *testing.T) {
+	source := newTestStore(t)
+	storeObject(t, source, newObject("Pod", "default", "a"))
+	var backup bytes.Buffer
+	if _, err := source.Backup(&backup, 0); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+
+	// The restore reads the backup from a pipe; once it has read the first
+	// byte it is running and waits for the rest
+	target := newTestStore(t)
+	pr, pw := io.Pipe()
+	restored := make(chan error, 1)
+	go func() { restored <- target.Restore(pr) }()
+	if _, err := pw.Write(backup.Next(1)); err != nil {
+		t.Fatalf("failed to write backup: %v", err)
+	}
+
+	obj := newObject("Pod", "default", "b")
+	event, err := models.TransformWatchEvent(obj, models.EventTypeAdded)
+	if err != nil {
+		t.Fatalf("failed to transform b: %v", err)
+	}
+	written := make(chan error, 1)
+	go func() { written <- target.StoreEvent(context.Background(), event, obj) }()
+	select {
+	case <-written:
+		t.Fatal("event stored while the restore was running")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if _, err := io.Copy(pw, &backup); err != nil {
+		t.Fatalf("failed to write backup: %v", err)
+	}
+	pw.Close()
+	if err := <-restored; err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if err := <-written; err != nil {
+		t.Fatalf("failed to store b: %v", err)
+	}
+	if got := eventNames(t, target); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("expected events a and b, got %v", got)
+	}
+}
+
+func TestRestoreBackfillsSecondaryIndexes(t *testing.T) {
+	// A backup of a store written before the verb and user indexes existed
+	source := newTestStore(t)
+	storeUnindexedEvent(t, source, "old")
+	var backup bytes.Buffer
+	if _, err := source.Backup(&backup, 0); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+
+	target := newTestStore(t)
+	if err := target.Restore(&backup); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if keys := keysWithPrefix(t, target, "byVerb/"); len(keys) != 1 {
+		t.Errorf("expected the restored event in the verb index, got %v", keys)
+	}
+	for _, opts := range []QueryOptions{{Verb: "delete"}, {User: "alice"}} {
+		events, err := target.QueryEvents(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("QueryEvents failed: %v", err)
+		}
+		if len(events) != 1 || events[0].ResourceName != "old" {
+			t.Errorf("%+v: expected the restored event, got %d events", opts, len(events))
+		}
+	}
+}
+
+func TestBackfillSecondaryIndexes(t *testing.T) {
+	s := newTestStore(t)
+
+	storeUnindexedEvent(t, s, "old")
+
+	if err := s.backfillSecondaryIndexes(context.Background()); err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+	for _, opts := range []QueryOptions{{Verb: "delete"}, {User: "alice"}} {
+		events, err := s.QueryEvents(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("QueryEvents failed: %v", err)
+		}
+		if len(events) != 1 || events[0].ResourceName != "old" {
+			t.Errorf("%+v: expected the backfilled event, got %d events", opts, len(events))
+		}
+	}
+
+	// The backfill runs once
+	if err := s.db.DropPrefix([]byte("byVerb/")); err != nil {
+		t.Fatalf("DropPrefix failed: %v", err)
+	}
+	if err := s.backfillSecondaryIndexes(context.Background()); err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+	if keys := keysWithPrefix(t, s, "byVerb/"); len(keys) != 0 {
+		t.Errorf("expected the second backfill to be skipped, got %v", keys)
+	}
+}
+
+// benchmarkStore returns a store seeded with n pod events, of which every
+// hundredth is a delete by one of ten users; the rest are updates by the
+// watcher
+func benchmarkStore(b *testing.B, n int) *Store {
+	b.Helper()
+
+	opts := badgerOptions(b.TempDir(), false)
+	opts.Logger = nil
+	db, err := badger.Open(opts)
+	if err != nil {
+		b.Fatalf("failed to open store: %v", err)
+	}
+	b.Cleanup(func() { db.Close() })
+	s := &Store{db: db, retentionDays: 1}
+	s.secondaryIndexes.Store(true)
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < n; i++ {
+		obj := newObject("Pod", fmt.Sprintf("ns-%d", i%20), fmt.Sprintf("pod-%d", i))
+		event, err := models.TransformWatchEvent(obj, models.EventTypeModified)
+		if err != nil {
+			b.Fatal(err)
+		}
+		event.Timestamp = start.Add(time.Duration(i) * time.Millisecond)
+		if i%100 == 0 {
+			event.Verb = "delete"
+			event.User = fmt.Sprintf("user-%d", i/100%10)
+		}
+		if err := s.StoreEvent(context.Background(), event, obj); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return s
+}
+
+func BenchmarkQueryEventsByVerb(b *testing.B) {
+	s := benchmarkStore(b, 100_000)
+	opts := QueryOptions{Verb: "delete", Limit: 1000}
+
+	b.Run("indexed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := s.QueryEventsPage(context.Background(), opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := s.queryEventsPage(context.Background(), opts, timeIndexPrefix); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkQueryEventsByUser(b *testing.B) {
+	s := benchmarkStore(b, 100_000)
+	opts := QueryOptions{User: "user-3", Limit: 1000}
+
+	b.Run("indexed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := s.QueryEventsPage(context.Background(), opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := s.queryEventsPage(context.Background(), opts, timeIndexPrefix); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkStoreEvent compares storing events one transaction at a time to
+// storing them in batches of 100
+func BenchmarkStoreEvent(b *testing.B) {
+	const batchSize = 100
+	events := make([]*models.AuditEvent, batchSize)
+	objs := make([]*unstructured.Unstructured, batchSize)
+	for i := range events {
+		objs[i] = newObject("Pod", "default", fmt.Sprintf("pod-%d", i))
+		event, err := models.TransformWatchEvent(objs[i], models.EventTypeModified)
+		if err != nil {
+			b.Fatal(err)
+		}
+		events[i] = event
+	}
+
+	b.Run("single", func(b *testing.B) {
+		s := benchmarkStore(b, 0)
+		for i := 0; i < b.N; i++ {
+			for j, event := range events {
+				if err := s.StoreEvent(context.Background(), event, objs[j]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(b.N*batchSize)/b.Elapsed().Seconds(), "events/s")
+	})
+	b.Run("batch", func(b *testing.B) {
+		s := benchmarkStore(b, 0)
+		for i := 0; i < b.N; i++ {
+			if err := s.StoreEventBatch(context.Background(), events, objs); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(b.N*batchSize)/b.Elapsed().Seconds(), "events/s")
+	})
+}
+
+// eventNames returns the names of all stored events, oldest first
+func eventNames(t *testing.T, s *Store) []string {
+	t.Helper()
+
+	events, err := s.QueryEvents(context.Background(), QueryOptions{Order: "asc"})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		names = append(names, event.ResourceName)
+	}
+	return names
+}
+
+func TestBackupRestore(t *testing.T) {
+	source := newTestStore(t)
+	storeObject(t, source, newObject("Pod", "default", "a"))
+	storeObject(t, source, newObject("Pod", "kube-system", "b"))
+
+	var full bytes.Buffer
+	since, err := source.Backup(&full, 0)
+	if err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+
+	storeObject(t, source, newObject("ConfigMap", "default", "c"))
+	var incremental bytes.Buffer
+	if _, err := source.Backup(&incremental, since); err != nil {
+		t.Fatalf("incremental backup failed: %v", err)
+	}
+
+	target := newTestStore(t)
+	if err := target.Restore(&full); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if got := eventNames(t, target); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("expected events a and b after the full restore, got %v", got)
+	}
+
+	if err := target.Restore(&incremental); err != nil {
+		t.Fatalf("incremental restore failed: %v", err)
+	}
+	if got, want := eventNames(t, target), eventNames(t, source); !slices.Equal(got, want) {
+		t.Errorf("expected %v after the incremental restore, got %v", want, got)
+	}
+
+	// Secondary indexes are restored as well
+	history, err := target.GetObjectHistory(context.Background(), "default", "configmaps", "c")
+	if err != nil || len(history) != 1 {
+		t.Errorf("expected the object history of c, got %d events (err %v)", len(history), err)
+	}
+}
@@ -0,0 +1,97 @@
+package watchers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// batchStoreFunc persists several transformed events at once, like
+// storage.Store.StoreEventBatch
+type batchStoreFunc func(ctx context.Context, events []*models.AuditEvent, objs []*unstructured.Unstructured) error
+
+// eventBatcher collects the events of all workers and stores them in one
+// write once size events accumulated or the flush interval passed, instead
+// of a transaction per event. Events are stored in the order they were
+// added, so the events of an object keep the order of their worker.
+type eventBatcher struct {
+	store batchStoreFunc
+	size  int
+	// stored is called with every event of a stored batch
+	stored func(*models.AuditEvent)
+	// failed is called with every event of a batch that failed to store
+	failed func(*models.AuditEvent, *unstructured.Unstructured, error)
+
+	// mu is held during flushes, so workers wait for a full batch to be
+	// written before adding to the next one
+	mu     sync.Mutex
+	events []*models.AuditEvent
+	objs   []*unstructured.Unstructured
+}
+
+// newEventBatcher creates a batcher storing up to size events at once
+func newEventBatcher(store batchStoreFunc, size int, stored func(*models.AuditEvent), failed func(*models.AuditEvent, *unstructured.Unstructured, error)) *eventBatcher {
+	return &eventBatcher{store: store, size: size, stored: stored, failed: failed}
+}
+
+// add queues event for the next batch and stores the batch when it is full
+func (b *eventBatcher) add(event *models.AuditEvent, obj *unstructured.Unstructured) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+	b.objs = append(b.objs, obj)
+	if len(b.events) >= b.size {
+		b.flushLocked(context.Background())
+	}
+}
+
+// flush stores the queued events, if any
+func (b *eventBatcher) flush(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushLocked(ctx)
+}
+
+// flushLocked is flush with b.mu held
+func (b *eventBatcher) flushLocked(ctx context.Context) {
+	if len(b.events) == 0 {
+		return
+	}
+	events, objs := b.events, b.objs
+	b.events, b.objs = nil, nil
+
+	if err := b.store(ctx, events, objs); err != nil {
+		for i, event := range events {
+			b.failed(event, objs[i], err)
+		}
+		return
+	}
+	for _, event := range events {
+		b.stored(event)
+	}
+}
+
+// run flushes the queued events every interval until ctx is canceled.
+// Events still queued then are lost, like the jobs of the work queue.
+func (b *eventBatcher) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.flush(ctx)
+		}
+	}
+}
+
+// len returns the number of events waiting for the next flush
+func (b *eventBatcher) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.events)
+}
@@ -0,0 +1,91 @@
+package watchers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// namedPod returns a running pod called name
+func namedPod(name string) *unstructured.Unstructured {
+	pod := testPod("1", "web:1", "Running")
+	pod.SetName(name)
+	return pod
+}
+
+func TestBatcherStoresFullBatches(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{BatchSize: 3, DeadLetterSize: 10, DeadLetterMaxRetries: 5})
+	sub := m.Subscribe(3, func(*models.AuditEvent) bool { return true })
+	defer sub.Close()
+
+	m.handleAdd(podGVK, namedPod("web-1"))
+	m.handleAdd(podGVK, namedPod("web-2"))
+	if n := storedEvents(t, store); n != 0 {
+		t.Fatalf("expected nothing stored before the batch is full, got %d events", n)
+	}
+	select {
+	case event := <-sub.Events():
+		t.Fatalf("expected nothing published before the batch is stored, got %+v", event)
+	default:
+	}
+
+	m.handleAdd(podGVK, namedPod("web-3"))
+	if n := storedEvents(t, store); n != 3 {
+		t.Errorf("expected the full batch to be stored, got %d events", n)
+	}
+	for i := 1; i <= 3; i++ {
+		select {
+		case event := <-sub.Events():
+			if want := fmt.Sprintf("web-%d", i); event.ResourceName != want {
+				t.Errorf("expected %s published in order, got %s", want, event.ResourceName)
+			}
+		default:
+			t.Fatalf("expected web-%d to be published", i)
+		}
+	}
+}
+
+func TestBatcherFlushInterval(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{BatchSize: 100})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.batcher.run(ctx, 10*time.Millisecond)
+
+	m.handleAdd(podGVK, namedPod("web-1"))
+	deadline := time.Now().Add(time.Second)
+	for storedEvents(t, store) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the partial batch to be stored after the flush interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := m.batcher.len(); n != 0 {
+		t.Errorf("expected the batch to be empty after the flush, got %d events", n)
+	}
+}
+
+func TestBatcherFailedBatch(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{BatchSize: 2, DeadLetterSize: 10, DeadLetterMaxRetries: 5})
+	m.batcher.store = func(context.Context, []*models.AuditEvent, []*unstructured.Unstructured) error {
+		return errors.New("disk on fire")
+	}
+
+	m.handleAdd(podGVK, namedPod("web-1"))
+	m.handleAdd(podGVK, namedPod("web-2"))
+	letters := m.DeadLetters()
+	if len(letters) != 2 || letters[0].ResourceName != "web-1" || letters[1].ResourceName != "web-2" {
+		t.Fatalf("expected both events of the failed batch to be queued for retry, got %+v", letters)
+	}
+
+	// Retries store the events one by one
+	m.deadLetters.retry(context.Background(), time.Now().Add(time.Minute))
+	if n := storedEvents(t, store); n != 2 {
+		t.Errorf("expected the retries to store both events, got %d", n)
+	}
+}
@@ -0,0 +1,85 @@
+package watchers
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+)
+
+// Broadcaster fans stored events out to live subscribers. Publishing never
+// blocks: a subscriber whose buffer is full misses the event, and the miss is
+// counted so the subscriber can report it.
+type Broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the events published after it was created
+type Subscription struct {
+	broadcaster *Broadcaster
+	match       func(*models.AuditEvent) bool
+	events      chan *models.AuditEvent
+	dropped     atomic.Int64
+}
+
+// NewBroadcaster creates a broadcaster without subscribers
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subscribers: make(map[*Subscription]struct{})}
+}
+
+// Subscribe registers a subscriber buffering up to buffer events. Only
+// events accepted by match are delivered (all when match is nil), so events
+// filtered out never count as dropped.
+func (b *Broadcaster) Subscribe(buffer int, match func(*models.AuditEvent) bool) *Subscription {
+	sub := &Subscription{
+		broadcaster: b,
+		match:       match,
+		events:      make(chan *models.AuditEvent, buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Publish hands event to every subscriber with room in its buffer
+func (b *Broadcaster) Publish(event *models.AuditEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		if sub.match != nil && !sub.match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Events returns the channel the subscription receives events on. It is
+// closed by Close.
+func (s *Subscription) Events() <-chan *models.AuditEvent {
+	return s.events
+}
+
+// TakeDropped returns the number of events missed since the last call
+func (s *Subscription) TakeDropped() int64 {
+	return s.dropped.Swap(0)
+}
+
+// Close unregisters the subscription and closes its channel
+func (s *Subscription) Close() {
+	b := s.broadcaster
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[s]; ok {
+		delete(b.subscribers, s)
+		close(s.events)
+	}
+}
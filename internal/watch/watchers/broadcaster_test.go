@@ -0,0 +1,41 @@
+package watchers
+
+import (
+	"testing"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+)
+
+func TestBroadcasterDropsForLaggingSubscriber(t *testing.T) {
+	b := NewBroadcaster()
+	slow := b.Subscribe(1, nil)
+	pods := b.Subscribe(10, func(event *models.AuditEvent) bool {
+		return event.ResourceType == "pods"
+	})
+	defer pods.Close()
+
+	for _, resourceType := range []string{"pods", "nodes", "pods"} {
+		b.Publish(&models.AuditEvent{ResourceType: resourceType})
+	}
+
+	if dropped := slow.TakeDropped(); dropped != 2 {
+		t.Errorf("expected 2 events dropped for the full subscriber, got %d", dropped)
+	}
+	if dropped := slow.TakeDropped(); dropped != 0 {
+		t.Errorf("expected the dropped count to reset, got %d", dropped)
+	}
+	if len(pods.Events()) != 2 || pods.TakeDropped() != 0 {
+		t.Errorf("expected 2 buffered pod events and no drops, got %d", len(pods.Events()))
+	}
+
+	slow.Close()
+	if _, ok := <-slow.Events(); !ok {
+		t.Error("expected the buffered event before the channel closes")
+	}
+	if _, ok := <-slow.Events(); ok {
+		t.Error("expected the channel to be closed")
+	}
+	// Closed subscribers no longer receive events
+	b.Publish(&models.AuditEvent{ResourceType: "pods"})
+	slow.Close()
+}
@@ -0,0 +1,159 @@
+package watchers
+
+import (
+	"context"
+	"path"
+	"sync"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// crdLimiter admits discovered CRDs up to a maximum. CRDs admitted once stay
+// admitted, so the CRD informer replaying existing CRDs doesn't count twice.
+type crdLimiter struct {
+	mu       sync.Mutex
+	max      int
+	admitted map[string]bool
+}
+
+func newCRDLimiter(max int) *crdLimiter {
+	return &crdLimiter{max: max, admitted: make(map[string]bool)}
+}
+
+// admit reports whether the CRD may be watched. A maximum of zero or less
+// admits every CRD.
+func (l *crdLimiter) admit(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.admitted[name] {
+		return true
+	}
+	if l.max > 0 && len(l.admitted) >= l.max {
+		return false
+	}
+	l.admitted[name] = true
+	return true
+}
+
+// release forgets an admitted CRD, e.g. once it was deleted, freeing its
+// place under the maximum
+func (l *crdLimiter) release(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.admitted, name)
+}
+
+// crdResource returns the resource to watch for a CRD. Only one version is
+// watched: the storage version when it is served, otherwise the first served
+// version. It reports false when no version is served.
+func crdResource(crd *apiextensionsv1.CustomResourceDefinition) (config.ResourceWatch, bool) {
+	version := ""
+	for _, v := range crd.Spec.Versions {
+		if !v.Served {
+			continue
+		}
+		if v.Storage {
+			version = v.Name
+			break
+		}
+		if version == "" {
+			version = v.Name
+		}
+	}
+	if version == "" {
+		return config.ResourceWatch{}, false
+	}
+
+	return config.ResourceWatch{
+		Group:      crd.Spec.Group,
+		Version:    version,
+		Kind:       crd.Spec.Names.Kind,
+		Plural:     crd.Spec.Names.Plural,
+		Namespaced: crd.Spec.Scope == apiextensionsv1.NamespaceScoped,
+	}, true
+}
+
+// crdGroupAllowed reports whether CRDs of group are discovered. Patterns are
+// matched with path.Match, e.g. "*.example.com". The denylist wins; an empty
+// allowlist allows every group.
+func crdGroupAllowed(group string, allow, deny []string) bool {
+	for _, pattern := range deny {
+		if ok, _ := path.Match(pattern, group); ok {
+			return false
+		}
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	for _, pattern := range allow {
+		if ok, _ := path.Match(pattern, group); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// watchCRD adds a watcher for a discovered CRD unless it is configured
+// explicitly, its group is filtered out or the discovery cap is reached
+func (m *Manager) watchCRD(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) {
+	if m.isResourceConfigured(crd.Spec.Group, crd.Spec.Names.Kind) {
+		return
+	}
+	if !crdGroupAllowed(crd.Spec.Group, m.config.DiscoverCRDGroups, m.config.IgnoreCRDGroups) {
+		return
+	}
+
+	resource, ok := crdResource(crd)
+	if !ok {
+		return
+	}
+	if !m.crds.admit(crd.Name) {
+		m.log.Info("Not watching CRD, maxDiscoveredCRDs reached", "crd", crd.Name, "maxDiscoveredCRDs", m.config.MaxDiscoveredCRDs)
+		return
+	}
+
+	if err := m.addWatcher(ctx, resource); err != nil {
+		m.log.Error(err, "Failed to watch CRD", "crd", crd.Name)
+	}
+}
+
+// rewatchCRD moves the watcher of a discovered CRD to the version crdResource
+// now picks, or removes it when no version is served anymore
+func (m *Manager) rewatchCRD(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) {
+	if m.isResourceConfigured(crd.Spec.Group, crd.Spec.Names.Kind) {
+		return
+	}
+
+	resource, served := crdResource(crd)
+	gk := schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
+	for _, version := range m.watchedVersions(gk) {
+		if served && version == resource.Version {
+			continue
+		}
+		if err := m.removeWatcher(ctx, gk.WithVersion(version)); err != nil {
+			m.log.Error(err, "Failed to stop watching CRD version", "crd", crd.Name, "version", version)
+		}
+	}
+	if served {
+		m.watchCRD(ctx, crd)
+	}
+}
+
+// unwatchCRD removes the watchers of a deleted CRD. Explicitly configured
+// types keep theirs so they resume once the CRD is installed again.
+func (m *Manager) unwatchCRD(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) {
+	if m.isResourceConfigured(crd.Spec.Group, crd.Spec.Names.Kind) {
+		return
+	}
+
+	gk := schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
+	for _, version := range m.watchedVersions(gk) {
+		if err := m.removeWatcher(ctx, gk.WithVersion(version)); err != nil {
+			m.log.Error(err, "Failed to stop watching CRD version", "crd", crd.Name, "version", version)
+		}
+	}
+	m.crds.release(crd.Name)
+}
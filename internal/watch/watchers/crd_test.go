@@ -0,0 +1,202 @@
+package watchers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"github.com/moritz/mcp-toolkit/internal/watch/storage"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func newCRD(versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	crd.Name = "widgets.example.com"
+	crd.Spec.Group = "example.com"
+	crd.Spec.Names.Kind = "Widget"
+	crd.Spec.Names.Plural = "widgets"
+	crd.Spec.Scope = apiextensionsv1.NamespaceScoped
+	crd.Spec.Versions = versions
+	return crd
+}
+
+func TestCRDResourceVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []apiextensionsv1.CustomResourceDefinitionVersion
+		want     string
+	}{
+		{
+			name: "storage version",
+			versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v2", Served: true},
+			},
+			want: "v1",
+		},
+		{
+			name: "storage version not served",
+			versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Storage: true},
+				{Name: "v1beta1", Served: true},
+				{Name: "v1", Served: true},
+			},
+			want: "v1beta1",
+		},
+		{
+			name: "nothing served",
+			versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Storage: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, ok := crdResource(newCRD(tt.versions...))
+			if ok != (tt.want != "") || resource.Version != tt.want {
+				t.Errorf("expected version %q, got %q (ok=%v)", tt.want, resource.Version, ok)
+			}
+			if ok && (resource.Kind != "Widget" || resource.Plural != "widgets" || !resource.Namespaced) {
+				t.Errorf("unexpected resource %+v", resource)
+			}
+		})
+	}
+}
+
+func TestCRDLimiter(t *testing.T) {
+	l := newCRDLimiter(2)
+	if !l.admit("a.example.com") || !l.admit("b.example.com") {
+		t.Fatal("expected the first two CRDs to be admitted")
+	}
+	if l.admit("c.example.com") {
+		t.Error("expected the third CRD to be skipped")
+	}
+	// Replays of admitted CRDs don't count against the cap
+	if !l.admit("a.example.com") {
+		t.Error("expected an admitted CRD to stay admitted")
+	}
+
+	unlimited := newCRDLimiter(0)
+	for _, name := range []string{"a", "b", "c"} {
+		if !unlimited.admit(name) {
+			t.Errorf("expected %s to be admitted without a cap", name)
+		}
+	}
+}
+
+func TestCRDGroupAllowed(t *testing.T) {
+	allow := []string{"*.example.com", "cert-manager.io"}
+	deny := []string{"internal.example.com"}
+
+	tests := map[string]bool{
+		"apps.example.com":      true,
+		"cert-manager.io":       true,
+		"internal.example.com":  false,
+		"monitoring.coreos.com": false,
+	}
+	for group, want := range tests {
+		if got := crdGroupAllowed(group, allow, deny); got != want {
+			t.Errorf("crdGroupAllowed(%q) = %v, want %v", group, got, want)
+		}
+	}
+	if !crdGroupAllowed("monitoring.coreos.com", nil, deny) {
+		t.Error("expected an empty allowlist to allow every group")
+	}
+}
+
+// fakeManager serves the watcher manager fake informers instead of a cluster
+type fakeManager struct {
+	manager.Manager
+	cache *informertest.FakeInformers
+}
+
+func (f fakeManager) GetCache() ctrlcache.Cache {
+	return f.cache
+}
+
+func (f fakeManager) GetConfig() *rest.Config {
+	return &rest.Config{}
+}
+
+// newFakeInformerManager creates a manager whose watchers register on fake
+// informers
+func newFakeInformerManager(t *testing.T, cfg *config.Config) (*Manager, *informertest.FakeInformers) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	informers := &informertest.FakeInformers{Scheme: scheme}
+	store, err := storage.NewStore(t.TempDir(), 1, false, nil)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	m := NewManager(fakeManager{cache: informers}, store, cfg, nil, logr.Discard())
+	m.resources = newResourceMapper(nil)
+	return m, informers
+}
+
+func TestCRDWatcherLifecycle(t *testing.T) {
+	m, informers := newFakeInformerManager(t, &config.Config{DiscoverCRDs: true})
+
+	ctx := context.Background()
+	if err := m.watchCRDChanges(ctx); err != nil {
+		t.Fatalf("watchCRDChanges failed: %v", err)
+	}
+	crdInformer, err := informers.FakeInformerFor(ctx, &apiextensionsv1.CustomResourceDefinition{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v1 := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	v2 := v1.GroupKind().WithVersion("v2")
+	watching := func(want ...schema.GroupVersionKind) {
+		t.Helper()
+		if len(m.handlers) != len(want) || len(m.WatchedResources()) != len(want) {
+			t.Fatalf("expected %d watchers, got handlers %v, watched %+v", len(want), m.handlers, m.WatchedResources())
+		}
+		for _, gvk := range want {
+			if _, ok := m.handlers[gvk]; !ok {
+				t.Errorf("expected a handler for %s, got %v", gvk, m.handlers)
+			}
+			if _, ok := informers.InformersByGVK[gvk]; !ok {
+				t.Errorf("expected an informer for %s", gvk)
+			}
+		}
+	}
+
+	crd := newCRD(apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true})
+	crdInformer.Add(crd)
+	watching(v1)
+
+	// A newly served storage version moves the watcher
+	updated := newCRD(
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true},
+		apiextensionsv1.CustomResourceDefinitionVersion{Name: "v2", Served: true, Storage: true},
+	)
+	crdInformer.Update(crd, updated)
+	watching(v2)
+	if _, ok := informers.InformersByGVK[v1]; ok {
+		t.Error("expected the v1 informer to be stopped")
+	}
+
+	crdInformer.Delete(updated)
+	watching()
+	if _, ok := informers.InformersByGVK[v2]; ok {
+		t.Error("expected the v2 informer to be stopped")
+	}
+	if m.crds.admitted[updated.Name] {
+		t.Error("expected the deleted CRD to be released from the discovery cap")
+	}
+}
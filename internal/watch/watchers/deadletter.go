@@ -0,0 +1,211 @@
+package watchers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// deadLetterRetryInterval is how often due dead letters are retried
+	deadLetterRetryInterval = time.Second
+	// deadLetterBaseBackoff is the wait before the first retry; it doubles
+	// with every failed retry up to deadLetterMaxBackoff
+	deadLetterBaseBackoff = time.Second
+	deadLetterMaxBackoff  = time.Minute
+)
+
+// DeadLetter describes a watch event whose store failed and that waits to
+// be retried
+type DeadLetter struct {
+	// Fingerprint identifies the event in logs, e.g. when it is dropped
+	Fingerprint  string    `json:"fingerprint"`
+	Verb         string    `json:"verb"`
+	Namespace    string    `json:"namespace,omitempty"`
+	ResourceType string    `json:"resourceType"`
+	ResourceName string    `json:"resourceName"`
+	Timestamp    time.Time `json:"timestamp"`
+	// Attempts counts the failed stores, including the first one
+	Attempts  int       `json:"attempts"`
+	NextRetry time.Time `json:"nextRetry"`
+	LastError string    `json:"lastError"`
+}
+
+// deadLetter is a queued event with the object it is stored with
+type deadLetter struct {
+	DeadLetter
+	event *models.AuditEvent
+	obj   *unstructured.Unstructured
+}
+
+// storeFunc persists a transformed event, like storage.Store.StoreEvent
+type storeFunc func(ctx context.Context, event *models.AuditEvent, obj *unstructured.Unstructured) error
+
+// deadLetterQueue holds the events whose store failed and retries them with
+// exponential backoff. It is bounded: events that don't fit, and events whose
+// maxRetries retries all failed, are dropped and logged with their fingerprint.
+type deadLetterQueue struct {
+	store      storeFunc
+	size       int
+	maxRetries int
+	// stored is called with every event a retry stored
+	stored func(*models.AuditEvent)
+	log    logr.Logger
+
+	mu      sync.Mutex
+	pending []*deadLetter
+	dropped int64
+}
+
+// newDeadLetterQueue creates a queue of up to size events, each retried up
+// to maxRetries times, that logs dropped events to log
+func newDeadLetterQueue(store storeFunc, size, maxRetries int, stored func(*models.AuditEvent), log logr.Logger) *deadLetterQueue {
+	return &deadLetterQueue{store: store, size: size, maxRetries: maxRetries, stored: stored, log: log}
+}
+
+// add queues event after its first store failed with err, at now
+func (q *deadLetterQueue) add(event *models.AuditEvent, obj *unstructured.Unstructured, err error, now time.Time) {
+	letter := &deadLetter{
+		DeadLetter: DeadLetter{
+			Fingerprint:  fingerprint(event),
+			Verb:         event.Verb,
+			Namespace:    event.Namespace,
+			ResourceType: event.ResourceType,
+			ResourceName: event.ResourceName,
+			Timestamp:    event.Timestamp,
+			Attempts:     1,
+			NextRetry:    now.Add(deadLetterBackoff(1)),
+			LastError:    err.Error(),
+		},
+		event: event,
+		obj:   obj,
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	switch {
+	case q.maxRetries <= 0:
+		q.drop(letter, "retries disabled")
+		return
+	case len(q.pending) >= q.size:
+		q.drop(letter, "dead-letter queue full")
+		return
+	}
+	q.pending = append(q.pending, letter)
+}
+
+// retry stores the events whose backoff expired by now. Events that fail
+// again wait twice as long, until maxRetries retries failed.
+func (q *deadLetterQueue) retry(ctx context.Context, now time.Time) {
+	q.mu.Lock()
+	var due []*deadLetter
+	remaining := q.pending[:0]
+	for _, letter := range q.pending {
+		if letter.NextRetry.After(now) {
+			remaining = append(remaining, letter)
+		} else {
+			due = append(due, letter)
+		}
+	}
+	q.pending = remaining
+	q.mu.Unlock()
+
+	// Stores run unlocked so a slow store doesn't block new dead letters
+	var retry []*deadLetter
+	for _, letter := range due {
+		if err := q.store(ctx, letter.event, letter.obj); err != nil {
+			letter.Attempts++
+			letter.LastError = err.Error()
+			letter.NextRetry = now.Add(deadLetterBackoff(letter.Attempts))
+			retry = append(retry, letter)
+			continue
+		}
+		if q.stored != nil {
+			q.stored(letter.event)
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, letter := range retry {
+		if letter.Attempts > q.maxRetries {
+			q.drop(letter, fmt.Sprintf("gave up after %d retries", q.maxRetries))
+			continue
+		}
+		q.pending = append(q.pending, letter)
+	}
+}
+
+// deadLetterBackoff returns the wait after the given number of failed stores
+func deadLetterBackoff(attempts int) time.Duration {
+	backoff := deadLetterBaseBackoff
+	for i := 1; i < attempts && backoff < deadLetterMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, deadLetterMaxBackoff)
+}
+
+// drop logs a dead letter that won't be stored. q.mu must be held.
+func (q *deadLetterQueue) drop(letter *deadLetter, reason string) {
+	q.dropped++
+	q.log.Error(nil, "Dropped event", "reason", reason,
+		"verb", letter.Verb, "resourceType", letter.ResourceType, "namespace", letter.Namespace, "name", letter.ResourceName,
+		"fingerprint", letter.Fingerprint, "lastError", letter.LastError)
+}
+
+// run retries due dead letters every deadLetterRetryInterval until ctx is
+// canceled. Events still pending then are lost.
+func (q *deadLetterQueue) run(ctx context.Context) {
+	ticker := time.NewTicker(deadLetterRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			q.retry(ctx, now)
+		}
+	}
+}
+
+// len returns the number of events waiting to be retried
+func (q *deadLetterQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// droppedCount returns the number of events dropped so far
+func (q *deadLetterQueue) droppedCount() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// list returns the events waiting to be retried
+func (q *deadLetterQueue) list() []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	letters := make([]DeadLetter, len(q.pending))
+	for i, letter := range q.pending {
+		letters[i] = letter.DeadLetter
+	}
+	return letters
+}
+
+// fingerprint returns a short hash of the event, identifying it in logs
+// without its content
+func fingerprint(event *models.AuditEvent) string {
+	h := fnv.New64a()
+	if data, err := json.Marshal(event); err == nil {
+		h.Write(data)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
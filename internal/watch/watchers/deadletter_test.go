@@ -0,0 +1,110 @@
+package watchers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// failingStore fails the first failures stores and then passes them on to store
+func failingStore(store storeFunc, failures int) (storeFunc, *int) {
+	attempts := 0
+	return func(ctx context.Context, event *models.AuditEvent, obj *unstructured.Unstructured) error {
+		attempts++
+		if attempts <= failures {
+			return errors.New("disk on fire")
+		}
+		return store(ctx, event, obj)
+	}, &attempts
+}
+
+func TestDeadLetterRetriesUntilStored(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{DeadLetterSize: 10, DeadLetterMaxRetries: 5})
+	var attempts *int
+	m.storeEvent, attempts = failingStore(m.storeEvent, 2)
+	sub := m.Subscribe(1, func(*models.AuditEvent) bool { return true })
+	defer sub.Close()
+
+	m.handleAdd(podGVK, testPod("1", "web:1", "Running"))
+	if n := storedEvents(t, store); n != 0 {
+		t.Fatalf("expected the failed store to store nothing, got %d events", n)
+	}
+	letters := m.DeadLetters()
+	if len(letters) != 1 || letters[0].Attempts != 1 || letters[0].ResourceName != "web" || letters[0].LastError != "disk on fire" {
+		t.Fatalf("expected the event to be queued for retry, got %+v", letters)
+	}
+
+	// Not due yet
+	now := time.Now()
+	m.deadLetters.retry(context.Background(), now)
+	if *attempts != 1 {
+		t.Fatalf("expected no retry before the backoff expired, got %d attempts", *attempts)
+	}
+
+	// The second attempt fails and backs off longer
+	now = now.Add(deadLetterBackoff(1) + time.Millisecond)
+	m.deadLetters.retry(context.Background(), now)
+	letters = m.DeadLetters()
+	if len(letters) != 1 || letters[0].Attempts != 2 || !letters[0].NextRetry.Equal(now.Add(2*time.Second)) {
+		t.Fatalf("expected the second failure to double the backoff, got %+v", letters)
+	}
+
+	// The third attempt succeeds and drains the queue
+	m.deadLetters.retry(context.Background(), now.Add(time.Minute))
+	if *attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", *attempts)
+	}
+	if n := m.deadLetters.len(); n != 0 {
+		t.Errorf("expected the queue to drain, %d events left", n)
+	}
+	if n := storedEvents(t, store); n != 1 {
+		t.Errorf("expected the retried event to be stored, got %d events", n)
+	}
+	select {
+	case event := <-sub.Events():
+		if event.ResourceName != "web" {
+			t.Errorf("unexpected event published: %+v", event)
+		}
+	default:
+		t.Error("expected the stored retry to be published")
+	}
+}
+
+func TestDeadLetterDroppedAfterMaxRetries(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{DeadLetterSize: 1, DeadLetterMaxRetries: 2})
+	m.storeEvent, _ = failingStore(m.storeEvent, 100)
+
+	m.handleAdd(podGVK, testPod("1", "web:1", "Running"))
+	// The queue holds a single event, the second is dropped right away
+	other := testPod("1", "web:1", "Running")
+	other.SetName("api")
+	m.handleAdd(podGVK, other)
+	if n := m.deadLetters.len(); n != 1 || m.deadLetters.droppedCount() != 1 {
+		t.Fatalf("expected 1 queued and 1 dropped event, got %d and %d", n, m.deadLetters.droppedCount())
+	}
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		now = now.Add(time.Hour)
+		m.deadLetters.retry(context.Background(), now)
+	}
+	if n := m.deadLetters.len(); n != 0 || m.deadLetters.droppedCount() != 2 {
+		t.Errorf("expected the event to be dropped after 2 retries, %d left and %d dropped", n, m.deadLetters.droppedCount())
+	}
+	if n := storedEvents(t, store); n != 0 {
+		t.Errorf("expected nothing stored, got %d events", n)
+	}
+}
+
+func TestDeadLetterBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 100: time.Minute} {
+		if got := deadLetterBackoff(attempts); got != want {
+			t.Errorf("deadLetterBackoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package watchers
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+)
+
+// dedupKey identifies repeats of an event: the same object reporting the
+// same message
+type dedupKey struct {
+	namespace    string
+	resourceType string
+	name         string
+	message      uint64
+}
+
+// dedupEntry is the record repeats are collapsed into until expires
+type dedupEntry struct {
+	event   *models.AuditEvent
+	expires time.Time
+}
+
+// eventDeduper collapses Kubernetes Events that repeat within a window into
+// the record of their first occurrence. A single failure, e.g. an image pull
+// back-off, updates its Event every few seconds; instead of a record per
+// update, the first record is rewritten with the latest state and an
+// incremented OccurrenceCount. Other resources are never collapsed: their
+// messages don't describe what changed.
+type eventDeduper struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	seen   map[dedupKey]*dedupEntry
+	pruned time.Time
+}
+
+// newEventDeduper creates a deduper collapsing repeats within window of the
+// first occurrence
+func newEventDeduper(window time.Duration) *eventDeduper {
+	return &eventDeduper{window: window, seen: make(map[dedupKey]*dedupEntry)}
+}
+
+// collapse returns the event to store for event, received at now. A first
+// occurrence is returned with an OccurrenceCount of 1 and starts a window; a
+// repeat within it is returned as the first record, keeping its timestamp
+// and verb so it overwrites that record, with the latest object and the
+// count incremented.
+func (d *eventDeduper) collapse(event *models.AuditEvent, now time.Time) *models.AuditEvent {
+	if event.EventInfo == nil || event.Verb == "delete" {
+		return event
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(event.Message))
+	key := dedupKey{event.Namespace, event.ResourceType, event.ResourceName, h.Sum64()}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.prune(now)
+
+	if entry, ok := d.seen[key]; ok && now.Before(entry.expires) {
+		repeat := *event
+		repeat.Timestamp = entry.event.Timestamp
+		repeat.Verb = entry.event.Verb
+		repeat.OccurrenceCount = entry.event.OccurrenceCount + 1
+		entry.event = &repeat
+		return &repeat
+	}
+
+	event.OccurrenceCount = 1
+	d.seen[key] = &dedupEntry{event: event, expires: now.Add(d.window)}
+	return event
+}
+
+// prune forgets the entries whose window ended, at most once per window.
+// d.mu must be held.
+func (d *eventDeduper) prune(now time.Time) {
+	if now.Sub(d.pruned) < d.window {
+		return
+	}
+	d.pruned = now
+	for key, entry := range d.seen {
+		if !now.Before(entry.expires) {
+			delete(d.seen, key)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package watchers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	"github.com/moritz/mcp-toolkit/internal/watch/storage"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var eventGVK = schema.GroupVersionKind{Version: "v1", Kind: "Event"}
+
+// backOffEvent returns an image pull back-off Event seen count times
+func backOffEvent(count int64) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"involvedObject": map[string]any{"kind": "Pod", "namespace": "default", "name": "web"},
+		"reason":         "BackOff",
+		"type":           "Warning",
+		"message":        `Back-off pulling image "web:2"`,
+		"count":          count,
+	}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Event")
+	obj.SetNamespace("default")
+	obj.SetName("web.17f3")
+	obj.SetUID("event-uid")
+	return obj
+}
+
+// storedRecords returns all stored events
+func storedRecords(t *testing.T, store *storage.Store) []*models.AuditEvent {
+	t.Helper()
+	events, err := store.QueryEvents(context.Background(), storage.QueryOptions{
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	return events
+}
+
+func TestDedupCollapsesRepeatedEvents(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{DedupWindowSeconds: 60})
+
+	m.handleAdd(eventGVK, backOffEvent(1))
+	for count := int64(2); count <= 5; count++ {
+		m.handleUpdate(eventGVK, backOffEvent(count-1), backOffEvent(count))
+	}
+
+	records := storedRecords(t, store)
+	if len(records) != 1 {
+		t.Fatalf("expected one record, got %d", len(records))
+	}
+	record := records[0]
+	if record.OccurrenceCount != 5 || record.Verb != "create" || record.EventInfo == nil || record.EventInfo.Count != 5 {
+		t.Errorf("expected the create record with 5 occurrences and the latest state, got %+v", record)
+	}
+	history, err := store.GetObjectHistory(context.Background(), "default", "events", "web.17f3")
+	if err != nil || len(history) != 1 || history[0].OccurrenceCount != 5 {
+		t.Errorf("expected the object history to hold the updated record, got %+v (%v)", history, err)
+	}
+
+	// A different message is recorded on its own
+	other := backOffEvent(6)
+	other.Object["message"] = "Successfully pulled image"
+	other.Object["reason"] = "Pulled"
+	m.handleUpdate(eventGVK, backOffEvent(5), other)
+	if n := len(storedRecords(t, store)); n != 2 {
+		t.Errorf("expected a new record for a new message, got %d records", n)
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	d := newEventDeduper(time.Minute)
+	event := func() *models.AuditEvent {
+		e, err := models.TransformWatchEvent(backOffEvent(1), models.EventTypeModified)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+
+	start := time.Now()
+	first := d.collapse(event(), start)
+	if repeat := d.collapse(event(), start.Add(59*time.Second)); repeat.OccurrenceCount != 2 || !repeat.Timestamp.Equal(first.Timestamp) {
+		t.Errorf("expected a repeat within the window to update the first record, got %+v", repeat)
+	}
+	if later := d.collapse(event(), start.Add(time.Minute)); later.OccurrenceCount != 1 {
+		t.Errorf("expected a repeat after the window to start a new record, got %+v", later)
+	}
+
+	// Other resources are never collapsed
+	pod, err := models.TransformWatchEvent(testPod("1", "web:1", "Running"), models.EventTypeModified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.collapse(pod, start); got != pod || got.OccurrenceCount != 0 {
+		t.Errorf("expected pods to pass through, got %+v", got)
+	}
+}
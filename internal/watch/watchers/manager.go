@@ -2,37 +2,200 @@ package watchers
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/moritz/mcp-toolkit/internal/watch/config"
 	"github.com/moritz/mcp-toolkit/internal/watch/models"
 	"github.com/moritz/mcp-toolkit/internal/watch/storage"
+	"github.com/prometheus/client_golang/prometheus"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/tools/cache"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
+// suppressedLogInterval is how often the number of dropped no-op updates is logged
+const suppressedLogInterval = 5 * time.Minute
+
 // Manager manages all resource watchers
 type Manager struct {
 	mgr    manager.Manager
 	store  *storage.Store
 	config *config.Config
+	log    logr.Logger
+
+	// storeEvent persists events, store.StoreEvent unless replaced in tests
+	storeEvent storeFunc
+	// deadLetters retries the events storeEvent failed to store
+	deadLetters *deadLetterQueue
+	// batcher stores events in batches, nil when BatchSize disables them
+	batcher *eventBatcher
+	// dedup collapses repeated Kubernetes Events, nil when disabled
+	dedup *eventDeduper
+
+	// configured is the list of configured resources, replaced by Reconcile
+	configuredMu sync.RWMutex
+	configured   []config.ResourceWatch
+
+	enrichers []models.Enricher
+	// redactions are the redacted fields by Kind the enrichers were built with
+	redactions map[string][]string
+
+	registry  *watcherRegistry
+	selectors *objectSelectors
+
+	// handlers holds the informer and event handler registration of each
+	// watched type, so the watcher can be removed again
+	handlersMu sync.Mutex
+	handlers   map[schema.GroupVersionKind]watcherHandle
+
+	queue *workQueue
+
+	crds *crdLimiter
+
+	resources *resourceMapper
+
+	broadcaster *Broadcaster
+
+	// suppressedUpdates counts the no-op updates dropped since last logged
+	suppressedUpdates atomic.Int64
 }
 
-// NewManager creates a new watcher manager
-func NewManager(mgr manager.Manager, store *storage.Store, cfg *config.Config) *Manager {
-	return &Manager{
-		mgr:    mgr,
-		store:  store,
-		config: cfg,
+// watcherHandle is the event handler a watcher registered on its informer
+type watcherHandle struct {
+	informer     ctrlcache.Informer
+	registration cache.ResourceEventHandlerRegistration
+}
+
+// WatchedResource describes a resource type with an active watcher
+type WatchedResource struct {
+	Group        string    `json:"group"`
+	Version      string    `json:"version"`
+	Kind         string    `json:"kind"`
+	ResourceType string    `json:"resourceType"`
+	Since        time.Time `json:"since"`
+}
+
+// NewManager creates a new watcher manager. Its metrics are registered with
+// reg, which may be nil, and it logs to log.
+func NewManager(mgr manager.Manager, store *storage.Store, cfg *config.Config, reg prometheus.Registerer, log logr.Logger) *Manager {
+	var enrichers []models.Enricher
+	if len(cfg.LabelAnnotations) > 0 {
+		enrichers = append(enrichers, models.NewLabelEnricher(cfg.LabelAnnotations))
+	}
+
+	if cfg.IndexOwners {
+		enrichers = append(enrichers, models.OwnerEnricher{})
+	}
+
+	redactions := configuredRedactions(cfg.Resources)
+	if len(redactions) > 0 {
+		enrichers = append(enrichers, models.NewRedactor(redactions))
 	}
+
+	// Without discovery, resource types are derived from Kinds
+	var discoveryClient discovery.DiscoveryInterface
+	if mgr != nil {
+		client, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+		if err != nil {
+			log.Error(err, "Failed to create discovery client, deriving resource types from Kinds")
+		} else {
+			discoveryClient = client
+		}
+	}
+
+	m := &Manager{
+		mgr:         mgr,
+		store:       store,
+		config:      cfg,
+		log:         log,
+		configured:  cfg.Resources,
+		enrichers:   enrichers,
+		redactions:  redactions,
+		registry:    newWatcherRegistry(),
+		selectors:   newObjectSelectors(),
+		handlers:    make(map[schema.GroupVersionKind]watcherHandle),
+		queue:       newWorkQueue(cfg.WorkerCount, cfg.QueueSize, cfg.QueueFullPolicy),
+		crds:        newCRDLimiter(cfg.MaxDiscoveredCRDs),
+		resources:   newResourceMapper(discoveryClient),
+		broadcaster: NewBroadcaster(),
+	}
+	m.storeEvent = store.StoreEvent
+	m.deadLetters = newDeadLetterQueue(func(ctx context.Context, event *models.AuditEvent, obj *unstructured.Unstructured) error {
+		return m.storeEvent(ctx, event, obj)
+	}, cfg.DeadLetterSize, cfg.DeadLetterMaxRetries, m.broadcaster.Publish, log)
+	if cfg.DedupWindowSeconds > 0 {
+		m.dedup = newEventDeduper(time.Duration(cfg.DedupWindowSeconds) * time.Second)
+	}
+	if cfg.BatchSize > 1 {
+		m.batcher = newEventBatcher(store.StoreEventBatch, cfg.BatchSize, m.broadcaster.Publish, func(event *models.AuditEvent, u *unstructured.Unstructured, err error) {
+			m.log.Error(err, "Failed to store event, queued for retry", "verb", event.Verb, "namespace", u.GetNamespace(), "name", u.GetName())
+			m.deadLetters.add(event, u, err, time.Now())
+		})
+	}
+	if reg != nil {
+		reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "watch_active_watchers",
+			Help: "Resource types with an active watcher.",
+		}, func() float64 { return float64(m.registry.len()) }))
+		reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "watch_deadletter_depth",
+			Help: "Events whose store failed, waiting to be retried.",
+		}, func() float64 { return float64(m.deadLetters.len()) }))
+		reg.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "watch_deadletter_dropped_total",
+			Help: "Events dropped because the dead-letter queue was full or their retries failed.",
+		}, func() float64 { return float64(m.deadLetters.droppedCount()) }))
+	}
+	return m
+}
+
+// WatchedResources returns the resource types that currently have active watchers
+func (m *Manager) WatchedResources() []WatchedResource {
+	return m.registry.list()
+}
+
+// Kind returns the Kind of a resource type as served by the cluster, e.g.
+// Endpoints for endpoints
+func (m *Manager) Kind(resourceType string) string {
+	return m.resources.Kind(resourceType)
+}
+
+// DeadLetters returns the events whose store failed and that wait to be retried
+func (m *Manager) DeadLetters() []DeadLetter {
+	return m.deadLetters.list()
+}
+
+// Subscribe streams the events stored from now on that match accepts. The
+// subscription must be closed when no longer needed.
+func (m *Manager) Subscribe(buffer int, match func(*models.AuditEvent) bool) *Subscription {
+	return m.broadcaster.Subscribe(buffer, match)
 }
 
 // Start initializes all watchers based on configuration
 func (m *Manager) Start(ctx context.Context) error {
+	m.queue.start(ctx)
+	go m.deadLetters.run(ctx)
+	if m.batcher != nil {
+		go m.batcher.run(ctx, m.config.FlushInterval)
+	}
+
+	// Resource types are resolved by the cluster's discovery API, which also
+	// covers CRDs installed before startup
+	if err := m.resources.refresh(); err != nil {
+		m.log.Error(err, "Failed to map resource types, deriving them from Kinds")
+	}
+
 	// Register watchers for configured resources
 	for _, resource := range m.config.Resources {
 		if err := m.addWatcher(ctx, resource); err != nil {
@@ -44,14 +207,129 @@ func (m *Manager) Start(ctx context.Context) error {
 	if m.config.DiscoverCRDs {
 		if err := m.discoverCRDs(ctx); err != nil {
 			// Log error but don't fail - CRDs might not be available
-			fmt.Printf("Warning: failed to discover CRDs: %v\n", err)
+			m.log.Error(err, "Failed to discover CRDs")
 		}
 	}
 
+	if m.config.SkipNoOpUpdates {
+		go m.logSuppressedUpdates(ctx, suppressedLogInterval)
+	}
+
 	return nil
 }
 
-// addWatcher adds a watcher for a specific resource type
+// Stop stops accepting informer events and stores the ones already
+// received: the queued events are handled, the pending batch is written and
+// the dead letters get a last retry regardless of their backoff. It returns
+// an error when events were left unstored, e.g. because ctx ended first.
+// Stop must be called before the context passed to Start is cancelled.
+func (m *Manager) Stop(ctx context.Context) error {
+	var errs []error
+	if err := m.queue.stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if m.batcher != nil {
+		m.batcher.flush(ctx)
+	}
+	m.deadLetters.retry(ctx, time.Now().Add(deadLetterMaxBackoff))
+	if n := m.deadLetters.len(); n > 0 {
+		errs = append(errs, fmt.Errorf("%d events failed to store", n))
+	}
+	return errors.Join(errs...)
+}
+
+// Reconcile applies the resources of a reloaded configuration: types newly
+// listed are watched, types no longer listed stop being watched and the label
+// and field selectors of the others are replaced. Other settings only take effect on
+// restart.
+func (m *Manager) Reconcile(cfg *config.Config) error {
+	ctx := context.Background()
+
+	// The redactor is built once; watching a type before its fields are
+	// redacted would store them in the clear
+	if redactions := configuredRedactions(cfg.Resources); !maps.EqualFunc(redactions, m.redactions, slices.Equal) {
+		return errors.New("redactFields changed: restart to apply the new configuration")
+	}
+
+	m.configuredMu.Lock()
+	previous := resourcesByGVK(m.configured)
+	m.configured = cfg.Resources
+	m.configuredMu.Unlock()
+	current := resourcesByGVK(cfg.Resources)
+
+	var errs []error
+	for gvk := range previous {
+		if _, ok := current[gvk]; !ok {
+			if err := m.removeWatcher(ctx, gvk); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove watcher for %s: %w", gvk.Kind, err))
+			}
+		}
+	}
+
+	for gvk, resources := range current {
+		if _, ok := previous[gvk]; ok {
+			if err := m.selectors.replace(gvk, resources); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update %s: %w", gvk.Kind, err))
+			}
+			continue
+		}
+
+		// The type may already be watched as a discovered CRD, selecting
+		// every object
+		m.selectors.remove(gvk)
+		for _, resource := range resources {
+			if err := m.addWatcher(ctx, resource); err != nil {
+				errs = append(errs, fmt.Errorf("failed to add watcher for %s: %w", resource.Kind, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// configuredRedactions returns the fields to redact by Kind
+func configuredRedactions(resources []config.ResourceWatch) map[string][]string {
+	redactions := make(map[string][]string)
+	for _, resource := range resources {
+		if fields := resource.Redactions(); len(fields) > 0 {
+			redactions[resource.Kind] = fields
+		}
+	}
+	return redactions
+}
+
+// resourcesByGVK groups configured resources by their GVK; a type listed
+// several times has several entries
+func resourcesByGVK(resources []config.ResourceWatch) map[schema.GroupVersionKind][]config.ResourceWatch {
+	byGVK := make(map[schema.GroupVersionKind][]config.ResourceWatch)
+	for _, resource := range resources {
+		gvk := schema.GroupVersionKind{Group: resource.Group, Version: resource.Version, Kind: resource.Kind}
+		byGVK[gvk] = append(byGVK[gvk], resource)
+	}
+	return byGVK
+}
+
+// logSuppressedUpdates periodically logs how many no-op updates were dropped
+// until ctx is cancelled
+func (m *Manager) logSuppressedUpdates(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := m.suppressedUpdates.Swap(0); n > 0 {
+				m.log.Info("Suppressed no-op updates", "count", n, "interval", interval)
+			}
+		}
+	}
+}
+
+// addWatcher adds a watcher for a specific resource type. Resource types that
+// are already watched, e.g. when the CRD informer replays existing CRDs, are
+// skipped so their handlers are not registered twice.
 func (m *Manager) addWatcher(ctx context.Context, resource config.ResourceWatch) error {
 	gvk := schema.GroupVersionKind{
 		Group:   resource.Group,
@@ -59,6 +337,27 @@ func (m *Manager) addWatcher(ctx context.Context, resource config.ResourceWatch)
 		Kind:    resource.Kind,
 	}
 
+	// The selector applies even when the type is already watched, e.g. when
+	// it is configured more than once with different selectors
+	if err := m.selectors.add(gvk, resource); err != nil {
+		return err
+	}
+
+	m.resources.declare(gvk, resource.Plural)
+
+	// Reserve the registry entry first so concurrent callers can't both
+	// register handlers for the same type
+	key := gvk.String()
+	if !m.registry.add(key, WatchedResource{
+		Group:        resource.Group,
+		Version:      resource.Version,
+		Kind:         resource.Kind,
+		ResourceType: m.resources.ResourceType(gvk),
+		Since:        time.Now(),
+	}) {
+		return nil
+	}
+
 	// Create an unstructured object for this resource type
 	obj := &unstructured.Unstructured{}
 	obj.SetGroupVersionKind(gvk)
@@ -66,85 +365,217 @@ func (m *Manager) addWatcher(ctx context.Context, resource config.ResourceWatch)
 	// Get or create an informer for this resource type
 	informer, err := m.mgr.GetCache().GetInformer(ctx, obj)
 	if err != nil {
+		m.registry.remove(key)
 		return fmt.Errorf("failed to get informer: %w", err)
 	}
 
 	// Add event handlers
-	_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			m.handleAdd(obj)
+			m.enqueue("Add", obj, func() { m.handleAdd(gvk, obj) })
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
-			m.handleUpdate(oldObj, newObj)
+			m.enqueue("Update", newObj, func() { m.handleUpdate(gvk, oldObj, newObj) })
 		},
 		DeleteFunc: func(obj interface{}) {
-			m.handleDelete(obj)
+			m.enqueue("Delete", obj, func() { m.handleDelete(gvk, obj) })
 		},
 	})
 
 	if err != nil {
+		m.registry.remove(key)
 		return fmt.Errorf("failed to add event handler: %w", err)
 	}
 
-	fmt.Printf("Started watching %s/%s (%s)\n", resource.Group, resource.Version, resource.Kind)
+	m.handlersMu.Lock()
+	m.handlers[gvk] = watcherHandle{informer: informer, registration: registration}
+	m.handlersMu.Unlock()
+
+	m.log.Info("Started watching", "group", resource.Group, "version", resource.Version, "kind", resource.Kind)
+	return nil
+}
+
+// removeWatcher stops watching gvk: its event handler is removed, its
+// informer stopped and the type dropped from the watched set. Types without
+// a watcher are ignored.
+func (m *Manager) removeWatcher(ctx context.Context, gvk schema.GroupVersionKind) error {
+	m.handlersMu.Lock()
+	handle, ok := m.handlers[gvk]
+	delete(m.handlers, gvk)
+	m.handlersMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	m.registry.remove(gvk.String())
+	m.selectors.remove(gvk)
+
+	if err := handle.informer.RemoveEventHandler(handle.registration); err != nil {
+		return fmt.Errorf("failed to remove event handler: %w", err)
+	}
+	// The informer only served this watcher
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := m.mgr.GetCache().RemoveInformer(ctx, obj); err != nil {
+		return fmt.Errorf("failed to stop informer: %w", err)
+	}
+
+	m.log.Info("Stopped watching", "group", gvk.Group, "version", gvk.Version, "kind", gvk.Kind)
 	return nil
 }
 
+// watchedVersions returns the versions of a group and kind that are watched
+func (m *Manager) watchedVersions(gk schema.GroupKind) []string {
+	m.handlersMu.Lock()
+	defer m.handlersMu.Unlock()
+
+	var versions []string
+	for gvk := range m.handlers {
+		if gvk.GroupKind() == gk {
+			versions = append(versions, gvk.Version)
+		}
+	}
+	return versions
+}
+
+// enqueue queues the handling of an informer callback for obj, logging it
+// when the queue is full and the event is dropped
+func (m *Manager) enqueue(action string, obj interface{}, handle func()) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		key = ""
+	}
+	if !m.queue.enqueue(key, handle) {
+		if m.queue.isStopped() {
+			m.log.Info("Watchers stopping, dropped event", "action", action, "key", key)
+			return
+		}
+		m.log.Error(nil, "Event queue full, dropped event", "action", action, "key", key)
+	}
+}
+
+// withKind returns u with the informer's GVK set when the object itself
+// carries no Kind, as happens with some partial or tombstoned objects. The
+// informer's cached object is copied rather than modified.
+func withKind(u *unstructured.Unstructured, gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	if u.GetKind() != "" {
+		return u
+	}
+	u = u.DeepCopy()
+	u.SetGroupVersionKind(gvk)
+	return u
+}
+
 // handleAdd handles object creation events
-func (m *Manager) handleAdd(obj interface{}) {
+func (m *Manager) handleAdd(gvk schema.GroupVersionKind, obj interface{}) {
 	u, ok := obj.(*unstructured.Unstructured)
 	if !ok {
-		fmt.Printf("Warning: received non-unstructured object in Add event\n")
+		m.log.Info("Received non-unstructured object", "action", "Add")
+		return
+	}
+	u = withKind(u, gvk)
+	if !m.selectors.matches(gvk, u) {
 		return
 	}
 
-	event, err := models.TransformWatchEvent(u, models.EventTypeAdded)
+	event, err := models.TransformWatchEvent(u, models.EventTypeAdded, m.enrichers...)
 	if err != nil {
-		fmt.Printf("Error transforming Add event for %s/%s: %v\n", u.GetNamespace(), u.GetName(), err)
+		m.log.Error(err, "Failed to transform event", "action", "Add", "namespace", u.GetNamespace(), "name", u.GetName())
 		return
 	}
+	event.SetResourceType(m.resources.ResourceType(gvk))
 
-	if err := m.store.StoreEvent(context.Background(), event, u); err != nil {
-		fmt.Printf("Error storing Add event for %s/%s: %v\n", u.GetNamespace(), u.GetName(), err)
-	}
+	m.persist("Add", event, u)
 }
 
 // handleUpdate handles object modification events
-func (m *Manager) handleUpdate(oldObj, newObj interface{}) {
+func (m *Manager) handleUpdate(gvk schema.GroupVersionKind, oldObj, newObj interface{}) {
 	u, ok := newObj.(*unstructured.Unstructured)
 	if !ok {
-		fmt.Printf("Warning: received non-unstructured object in Update event\n")
+		m.log.Info("Received non-unstructured object", "action", "Update")
 		return
 	}
+	u = withKind(u, gvk)
 
-	event, err := models.TransformWatchEvent(u, models.EventTypeModified)
-	if err != nil {
-		fmt.Printf("Error transforming Update event for %s/%s: %v\n", u.GetNamespace(), u.GetName(), err)
+	// The previous object is only used for the diff, so an unexpected type
+	// still records the update, without a diff
+	var old *unstructured.Unstructured
+	if o, ok := oldObj.(*unstructured.Unstructured); ok {
+		old = withKind(o, gvk)
+	}
+
+	// Updates that move an object out of the selection are still recorded
+	if !m.selectors.matches(gvk, u, old) {
 		return
 	}
 
-	if err := m.store.StoreEvent(context.Background(), event, u); err != nil {
-		fmt.Printf("Error storing Update event for %s/%s: %v\n", u.GetNamespace(), u.GetName(), err)
+	if m.config.SkipNoOpUpdates && old != nil && models.IsNoOpUpdate(old, u, m.config.NoOpIgnoreFields) {
+		m.suppressedUpdates.Add(1)
+		return
+	}
+
+	event, err := models.TransformUpdateEvent(old, u, m.enrichers...)
+	if err != nil {
+		m.log.Error(err, "Failed to transform event", "action", "Update", "namespace", u.GetNamespace(), "name", u.GetName())
+		return
 	}
+	event.SetResourceType(m.resources.ResourceType(gvk))
+
+	m.persist("Update", event, u)
 }
 
 // handleDelete handles object deletion events
-func (m *Manager) handleDelete(obj interface{}) {
+func (m *Manager) handleDelete(gvk schema.GroupVersionKind, obj interface{}) {
+	// Deletions missed while disconnected arrive wrapped in a tombstone
+	// holding the last state the informer saw, which the event records as
+	// the deleted object
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		u, ok := tombstone.Obj.(*unstructured.Unstructured)
+		if !ok {
+			m.log.Info("Received tombstone without a known object", "action", "Delete", "key", tombstone.Key)
+			return
+		}
+		obj = u
+	}
+
 	u, ok := obj.(*unstructured.Unstructured)
 	if !ok {
-		fmt.Printf("Warning: received non-unstructured object in Delete event\n")
+		m.log.Info("Received non-unstructured object", "action", "Delete")
+		return
+	}
+	u = withKind(u, gvk)
+	if !m.selectors.matches(gvk, u) {
 		return
 	}
 
-	event, err := models.TransformWatchEvent(u, models.EventTypeDeleted)
+	event, err := models.TransformWatchEvent(u, models.EventTypeDeleted, m.enrichers...)
 	if err != nil {
-		fmt.Printf("Error transforming Delete event for %s/%s: %v\n", u.GetNamespace(), u.GetName(), err)
+		m.log.Error(err, "Failed to transform event", "action", "Delete", "namespace", u.GetNamespace(), "name", u.GetName())
 		return
 	}
+	event.SetResourceType(m.resources.ResourceType(gvk))
 
-	if err := m.store.StoreEvent(context.Background(), event, u); err != nil {
-		fmt.Printf("Error storing Delete event for %s/%s: %v\n", u.GetNamespace(), u.GetName(), err)
+	m.persist("Delete", event, u)
+}
+
+// persist stores event and publishes it to subscribers, right away or with
+// the next batch. Repeated Kubernetes Events update the record of their first
+// occurrence when dedup is enabled. Events that fail to store are queued for
+// retries and published once a retry stored them.
+func (m *Manager) persist(action string, event *models.AuditEvent, u *unstructured.Unstructured) {
+	if m.dedup != nil {
+		event = m.dedup.collapse(event, time.Now())
+	}
+	if m.batcher != nil {
+		m.batcher.add(event, u)
+		return
+	}
+	if err := m.storeEvent(context.Background(), event, u); err != nil {
+		m.log.Error(err, "Failed to store event, queued for retry", "action", action, "namespace", u.GetNamespace(), "name", u.GetName())
+		m.deadLetters.add(event, u, err, time.Now())
+		return
 	}
+	m.broadcaster.Publish(event)
 }
 
 // discoverCRDs discovers installed CRDs and adds watchers for them
@@ -157,36 +588,13 @@ func (m *Manager) discoverCRDs(ctx context.Context) error {
 		return fmt.Errorf("failed to list CRDs: %w", err)
 	}
 
-	for _, crd := range crdList.Items {
-		// Skip if already in configured resources
-		if m.isResourceConfigured(crd.Spec.Group, crd.Spec.Names.Kind) {
-			continue
-		}
-
-		// Add watchers for each served version
-		for _, version := range crd.Spec.Versions {
-			if !version.Served {
-				continue
-			}
-
-			resource := config.ResourceWatch{
-				Group:      crd.Spec.Group,
-				Version:    version.Name,
-				Kind:       crd.Spec.Names.Kind,
-				Plural:     crd.Spec.Names.Plural,
-				Namespaced: crd.Spec.Scope == apiextensionsv1.NamespaceScoped,
-			}
-
-			if err := m.addWatcher(ctx, resource); err != nil {
-				fmt.Printf("Warning: failed to watch CRD %s: %v\n", crd.Name, err)
-				continue
-			}
-		}
+	for i := range crdList.Items {
+		m.watchCRD(ctx, &crdList.Items[i])
 	}
 
 	// Also watch for new CRDs being created
 	if err := m.watchCRDChanges(ctx); err != nil {
-		fmt.Printf("Warning: failed to watch CRD changes: %v\n", err)
+		m.log.Error(err, "Failed to watch CRD changes")
 	}
 
 	return nil
@@ -194,7 +602,10 @@ func (m *Manager) discoverCRDs(ctx context.Context) error {
 
 // isResourceConfigured checks if a resource is already in the configuration
 func (m *Manager) isResourceConfigured(group, kind string) bool {
-	for _, resource := range m.config.Resources {
+	m.configuredMu.RLock()
+	defer m.configuredMu.RUnlock()
+
+	for _, resource := range m.configured {
 		if resource.Group == group && resource.Kind == kind {
 			return true
 		}
@@ -202,7 +613,8 @@ func (m *Manager) isResourceConfigured(group, kind string) bool {
 	return false
 }
 
-// watchCRDChanges watches for CRD creation/updates and adds watchers dynamically
+// watchCRDChanges watches for CRD creation, updates and deletion and adds,
+// moves or removes watchers dynamically
 func (m *Manager) watchCRDChanges(ctx context.Context) error {
 	crd := &apiextensionsv1.CustomResourceDefinition{}
 	informer, err := m.mgr.GetCache().GetInformer(ctx, crd)
@@ -217,38 +629,54 @@ func (m *Manager) watchCRDChanges(ctx context.Context) error {
 				return
 			}
 
-			// Add watchers for this new CRD
-			for _, version := range crd.Spec.Versions {
-				if !version.Served {
-					continue
-				}
-
-				resource := config.ResourceWatch{
-					Group:      crd.Spec.Group,
-					Version:    version.Name,
-					Kind:       crd.Spec.Names.Kind,
-					Plural:     crd.Spec.Names.Plural,
-					Namespaced: crd.Spec.Scope == apiextensionsv1.NamespaceScoped,
-				}
-
-				if err := m.addWatcher(context.Background(), resource); err != nil {
-					fmt.Printf("Warning: failed to watch new CRD %s: %v\n", crd.Name, err)
-				}
+			// Add a watcher for this new CRD
+			m.refreshResourceTypes(crd)
+			m.watchCRD(context.Background(), crd)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCRD, ok := oldObj.(*apiextensionsv1.CustomResourceDefinition)
+			if !ok {
+				return
+			}
+			crd, ok := newObj.(*apiextensionsv1.CustomResourceDefinition)
+			if !ok {
+				return
+			}
+
+			// Only a change of the watched version matters, e.g. a newly
+			// served version becoming the storage version
+			oldResource, _ := crdResource(oldCRD)
+			if resource, _ := crdResource(crd); resource.Version == oldResource.Version {
+				return
 			}
+			m.refreshResourceTypes(crd)
+			m.rewatchCRD(context.Background(), crd)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+			if !ok {
+				return
+			}
+
+			m.unwatchCRD(context.Background(), crd)
 		},
 	})
 
 	return err
 }
 
-// KindToResourceType converts a Kind to a resource type (plural lowercase)
-func KindToResourceType(kind string) string {
-	lower := strings.ToLower(kind)
-	if strings.HasSuffix(lower, "s") {
-		return lower + "es"
+// refreshResourceTypes refreshes the resource type mapping when it doesn't
+// know the CRD's watched version yet, as for CRDs created since startup
+func (m *Manager) refreshResourceTypes(crd *apiextensionsv1.CustomResourceDefinition) {
+	resource, ok := crdResource(crd)
+	if !ok {
+		return
 	}
-	if strings.HasSuffix(lower, "y") {
-		return strings.TrimSuffix(lower, "y") + "ies"
+	gvk := schema.GroupVersionKind{Group: resource.Group, Version: resource.Version, Kind: resource.Kind}
+	if err := m.resources.refreshUnlessKnown(gvk); err != nil && !errors.Is(err, errNoDiscovery) {
+		m.log.Error(err, "Failed to refresh resource types for CRD", "crd", crd.Name)
 	}
-	return lower + "s"
 }
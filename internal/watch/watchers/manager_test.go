@@ -0,0 +1,385 @@
+package watchers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"github.com/moritz/mcp-toolkit/internal/watch/storage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+var podGVK = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+func newTestManager(t *testing.T, cfg *config.Config) (*Manager, *storage.Store) {
+	t.Helper()
+	store, err := storage.NewStore(t.TempDir(), 1, false, nil)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return NewManager(nil, store, cfg, nil, logr.Discard()), store
+}
+
+func testPod(resourceVersion, image, phase string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"spec":   map[string]any{"containers": []any{map[string]any{"name": "web", "image": image}}},
+		"status": map[string]any{"phase": phase},
+	}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetNamespace("default")
+	obj.SetName("web")
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
+
+func storedEvents(t *testing.T, store *storage.Store) int {
+	t.Helper()
+	events, err := store.QueryEvents(context.Background(), storage.QueryOptions{
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	return len(events)
+}
+
+func TestHandleUpdateSkipsNoOpResync(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{SkipNoOpUpdates: true})
+
+	// A resync delivers the cached object again
+	pod := testPod("1", "web:1", "Running")
+	m.handleUpdate(podGVK, pod, pod.DeepCopy())
+	// A relist may bump only the resourceVersion
+	m.handleUpdate(podGVK, pod, testPod("2", "web:1", "Running"))
+
+	if n := storedEvents(t, store); n != 0 {
+		t.Fatalf("expected no-op updates to be dropped, got %d events", n)
+	}
+	if n := m.suppressedUpdates.Load(); n != 2 {
+		t.Errorf("expected 2 suppressed updates, got %d", n)
+	}
+
+	m.handleUpdate(podGVK, pod, testPod("3", "web:2", "Running"))
+	if n := storedEvents(t, store); n != 1 {
+		t.Errorf("expected the spec change to be stored, got %d events", n)
+	}
+}
+
+func TestHandleUpdateIgnoreFields(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{SkipNoOpUpdates: true, NoOpIgnoreFields: []string{"status"}})
+
+	m.handleUpdate(podGVK, testPod("1", "web:1", "Pending"), testPod("2", "web:1", "Running"))
+	if n := storedEvents(t, store); n != 0 {
+		t.Errorf("expected the status-only update to be dropped, got %d events", n)
+	}
+}
+
+func TestHandleUpdateKeepsNoOpWhenDisabled(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{})
+
+	pod := testPod("1", "web:1", "Running")
+	m.handleUpdate(podGVK, pod, pod.DeepCopy())
+	if n := storedEvents(t, store); n != 1 {
+		t.Errorf("expected the update to be stored, got %d events", n)
+	}
+}
+
+func TestOwnedPodRetrievableByOwner(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{IndexOwners: true})
+
+	owned := testPod("1", "web:1", "Running")
+	owned.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-5d8f", UID: "rs-uid"}})
+	m.handleAdd(podGVK, owned)
+	m.handleUpdate(podGVK, owned, func() *unstructured.Unstructured {
+		updated := owned.DeepCopy()
+		updated.SetResourceVersion("2")
+		updated.SetLabels(map[string]string{"ready": "true"})
+		return updated
+	}())
+
+	unowned := testPod("1", "web:1", "Running")
+	unowned.SetName("standalone")
+	m.handleAdd(podGVK, unowned)
+
+	events, err := store.GetOwnerEvents(context.Background(), "rs-uid")
+	if err != nil {
+		t.Fatalf("GetOwnerEvents failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Verb != "create" || events[1].Verb != "update" {
+		t.Fatalf("expected the owned pod's create and update, got %+v", events)
+	}
+	if owner := events[0].Owner; owner == nil || owner.Kind != "ReplicaSet" || owner.Name != "web-5d8f" {
+		t.Errorf("expected the ReplicaSet owner to be recorded, got %+v", owner)
+	}
+
+	events, err = store.GetEventsByOwner(context.Background(), "default", "ReplicaSet", "web-5d8f")
+	if err != nil {
+		t.Fatalf("GetEventsByOwner failed: %v", err)
+	}
+	if len(events) != 2 || events[0].ResourceName != "web" {
+		t.Errorf("expected the owned pod's events by owner name, got %+v", events)
+	}
+}
+
+func TestOwnerUIDNotIndexedByDefault(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{})
+
+	owned := testPod("1", "web:1", "Running")
+	owned.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-5d8f", UID: "rs-uid"}})
+	m.handleAdd(podGVK, owned)
+
+	events, err := store.GetOwnerEvents(context.Background(), "rs-uid")
+	if err != nil {
+		t.Fatalf("GetOwnerEvents failed: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected no owner index without indexOwners, got %d events", len(events))
+	}
+
+	// The owner name index is always written
+	events, err = store.GetEventsByOwner(context.Background(), "", "ReplicaSet", "web-5d8f")
+	if err != nil {
+		t.Fatalf("GetEventsByOwner failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("expected the owned pod by owner name without indexOwners, got %d events", len(events))
+	}
+}
+
+func TestQueuedEventsArePersisted(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{WorkerCount: 4, QueueSize: 1000})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.queue.start(ctx)
+
+	const producers, perProducer = 10, 50
+	var wg sync.WaitGroup
+	start := time.Now()
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				pod := testPod("1", "web:1", "Running")
+				pod.SetName(fmt.Sprintf("web-%d-%d", p, i))
+				m.enqueue("Add", pod, func() { m.handleAdd(podGVK, pod) })
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	// Callbacks only queue the work, so they return long before it is stored
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected callbacks to return quickly, took %s", elapsed)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		n := storedEvents(t, store)
+		if n == producers*perProducer {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d stored events, got %d", producers*perProducer, n)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestStopDrainsQueuedEvents(t *testing.T) {
+	// A batch larger than the events and a long flush interval leave the
+	// last events to the final flush
+	m, store := newTestManager(t, &config.Config{WorkerCount: 4, QueueSize: 1000, BatchSize: 1000, FlushInterval: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.queue.start(ctx)
+
+	const n = 200
+	for i := 0; i < n; i++ {
+		pod := testPod("1", "web:1", "Running")
+		pod.SetName(fmt.Sprintf("web-%d", i))
+		if !m.queue.enqueue(pod.GetName(), func() { m.handleAdd(podGVK, pod) }) {
+			t.Fatalf("failed to queue %s", pod.GetName())
+		}
+	}
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer stopCancel()
+	if err := m.Stop(stopCtx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if got := storedEvents(t, store); got != n {
+		t.Errorf("expected all %d queued events to be stored, got %d", n, got)
+	}
+	if m.queue.enqueue("default/late", func() {}) {
+		t.Error("expected a stopped queue to reject new events")
+	}
+}
+
+func TestStopRetriesDeadLetters(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{DeadLetterSize: 10, DeadLetterMaxRetries: 5})
+	m.storeEvent, _ = failingStore(m.storeEvent, 1)
+	m.queue.start(context.Background())
+
+	// The failed store waits for its backoff, Stop retries it right away
+	m.handleAdd(podGVK, testPod("1", "web:1", "Running"))
+	if err := m.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if got := storedEvents(t, store); got != 1 {
+		t.Errorf("expected the dead letter to be stored, got %d events", got)
+	}
+}
+
+func TestHandleDeleteTombstone(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{})
+
+	// The informer missed the deletion and only knows the last state
+	pod := testPod("7", "web:2", "Running")
+	m.handleDelete(podGVK, cache.DeletedFinalStateUnknown{Key: "default/web", Obj: pod})
+
+	events, err := store.QueryEvents(context.Background(), storage.QueryOptions{})
+	if err != nil {
+		t.Fatalf("QueryEvents failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected one delete event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Verb != "delete" || event.Namespace != "default" || event.ResourceName != "web" || event.ResourceType != "pods" {
+		t.Errorf("expected a delete of pods default/web, got %s of %s %s/%s", event.Verb, event.ResourceType, event.Namespace, event.ResourceName)
+	}
+	containers, _, _ := unstructured.NestedSlice(event.ObjectChanges, "spec", "containers")
+	if len(containers) != 1 || containers[0].(map[string]any)["image"] != "web:2" {
+		t.Errorf("expected the last known pod in ObjectChanges, got %v", event.ObjectChanges)
+	}
+
+	// Tombstones of unknown objects are skipped
+	m.handleDelete(podGVK, cache.DeletedFinalStateUnknown{Key: "default/gone"})
+	if got := storedEvents(t, store); got != 1 {
+		t.Errorf("expected the empty tombstone to be skipped, got %d events", got)
+	}
+}
+
+func TestWorkQueueDropPolicy(t *testing.T) {
+	q := newWorkQueue(1, 1, config.QueueFullDrop)
+
+	// Without running workers the single slot fills up
+	if !q.enqueue("default/web", func() {}) {
+		t.Fatal("expected the first job to be queued")
+	}
+	if q.enqueue("default/web", func() {}) {
+		t.Error("expected the second job to be dropped")
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	deployment := config.ResourceWatch{Group: "apps", Version: "v1", Kind: "Deployment"}
+	pod := config.ResourceWatch{Version: "v1", Kind: "Pod"}
+	cfg := &config.Config{Resources: []config.ResourceWatch{pod, deployment}}
+	m, informers := newFakeInformerManager(t, cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	deploymentGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	serviceGVK := schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+	labeled := testPod("1", "nginx:1.27", "Running")
+	labeled.SetLabels(map[string]string{"tier": "critical"})
+
+	// Deployments are dropped, Services added and Pods narrowed to a selector
+	selected := pod
+	selected.LabelSelector = "tier=critical"
+	service := config.ResourceWatch{Version: "v1", Kind: "Service"}
+	if err := m.Reconcile(&config.Config{Resources: []config.ResourceWatch{selected, service}}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if _, ok := m.handlers[deploymentGVK]; ok {
+		t.Error("expected the Deployment watcher to be removed")
+	}
+	if _, ok := informers.InformersByGVK[deploymentGVK]; ok {
+		t.Error("expected the Deployment informer to be stopped")
+	}
+	if _, ok := m.handlers[serviceGVK]; !ok {
+		t.Error("expected a Service watcher")
+	}
+	var kinds []string
+	for _, resource := range m.WatchedResources() {
+		kinds = append(kinds, resource.Kind)
+	}
+	if fmt.Sprint(kinds) != "[Pod Service]" {
+		t.Errorf("expected Pods and Services to be watched, got %v", kinds)
+	}
+	if !m.selectors.matches(podGVK, labeled) || m.selectors.matches(podGVK, testPod("1", "nginx:1.27", "Running")) {
+		t.Error("expected the new Pod selector to apply")
+	}
+
+	// Redactions are fixed at startup
+	secret := config.ResourceWatch{Version: "v1", Kind: "Secret"}
+	if err := m.Reconcile(&config.Config{Resources: []config.ResourceWatch{selected, service, secret}}); err == nil {
+		t.Error("expected adding Secrets, which are redacted by default, to require a restart")
+	}
+	if _, ok := m.handlers[schema.GroupVersionKind{Version: "v1", Kind: "Secret"}]; ok {
+		t.Error("expected no Secret watcher")
+	}
+}
+
+// appliedSecret is a Secret as created by kubectl apply, which copies the
+// applied manifest, data included, into an annotation
+func appliedSecret(password string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"type":       "Opaque",
+		"data":       map[string]any{"password": password},
+		"stringData": map[string]any{"token": "plain-" + password},
+	}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Secret")
+	obj.SetNamespace("default")
+	obj.SetName("db")
+	obj.SetAnnotations(map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": fmt.Sprintf(
+			`{"apiVersion":"v1","kind":"Secret","data":{"password":%q},"stringData":{"token":"plain-%s"}}`, password, password),
+	})
+	return obj
+}
+
+func TestAppliedSecretValuesNotStored(t *testing.T) {
+	secret := config.ResourceWatch{Version: "v1", Kind: "Secret"}
+	m, store := newTestManager(t, &config.Config{Resources: []config.ResourceWatch{secret}})
+	secretGVK := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+
+	m.handleAdd(secretGVK, appliedSecret("c2VjcmV0LW9uZQ=="))
+	m.handleUpdate(secretGVK, appliedSecret("c2VjcmV0LW9uZQ=="), appliedSecret("c2VjcmV0LXR3bw=="))
+
+	events := storedRecords(t, store)
+	if len(events) != 2 {
+		t.Fatalf("expected the create and update, got %d events", len(events))
+	}
+	data, err := json.Marshal(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"c2VjcmV0LW9uZQ==", "c2VjcmV0LXR3bw==", "plain-"} {
+		if strings.Contains(string(data), value) {
+			t.Errorf("secret value %q stored:\n%s", value, data)
+		}
+	}
+	if annotation := events[1].Annotations["kubectl.kubernetes.io/last-applied-configuration"]; !strings.HasPrefix(annotation, "sha256:") {
+		t.Errorf("expected the last-applied annotation to be hashed, got %q", annotation)
+	}
+}
@@ -0,0 +1,132 @@
+package watchers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/models"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/restmapper"
+)
+
+// errNoDiscovery is returned when refreshing a mapper without a discovery client
+var errNoDiscovery = errors.New("no discovery client")
+
+// resourceMapper converts between Kinds and resource types with a RESTMapper
+// built from the cluster's discovery API. Types discovery doesn't know fall
+// back to the plurals declared by the configuration and discovered CRDs, and
+// then to models' pluralization rules.
+type resourceMapper struct {
+	discovery discovery.DiscoveryInterface
+
+	mu sync.RWMutex
+	// mapper is nil until discovery succeeded once
+	mapper meta.RESTMapper
+	// plurals and kinds hold the declared plurals by GroupKind and the
+	// declared Kinds by plural
+	plurals map[schema.GroupKind]string
+	kinds   map[string]string
+}
+
+// newResourceMapper creates a mapper that resolves through client, which may
+// be nil to only use declared plurals and pluralization rules
+func newResourceMapper(client discovery.DiscoveryInterface) *resourceMapper {
+	return &resourceMapper{
+		discovery: client,
+		plurals:   make(map[schema.GroupKind]string),
+		kinds:     make(map[string]string),
+	}
+}
+
+// refresh rebuilds the mapping from the discovery API. The previous mapping
+// is kept when discovery fails.
+func (r *resourceMapper) refresh() error {
+	if r.discovery == nil {
+		return errNoDiscovery
+	}
+	// Groups that fail discovery, e.g. an unavailable aggregated API, are
+	// left out rather than failing the whole mapping
+	groups, err := restmapper.GetAPIGroupResources(r.discovery)
+	if err != nil {
+		return fmt.Errorf("failed to discover API resources: %w", err)
+	}
+
+	mapper := restmapper.NewDiscoveryRESTMapper(groups)
+	r.mu.Lock()
+	r.mapper = mapper
+	r.mu.Unlock()
+	return nil
+}
+
+// refreshUnlessKnown refreshes the mapping when discovery doesn't know gvk
+// yet, e.g. for a newly created CRD
+func (r *resourceMapper) refreshUnlessKnown(gvk schema.GroupVersionKind) error {
+	if r.known(gvk) {
+		return nil
+	}
+	return r.refresh()
+}
+
+// known reports whether discovery resolved gvk's Kind
+func (r *resourceMapper) known(gvk schema.GroupVersionKind) bool {
+	r.mu.RLock()
+	mapper := r.mapper
+	r.mu.RUnlock()
+	if mapper == nil {
+		return false
+	}
+	_, err := mapper.RESTMapping(gvk.GroupKind())
+	return err == nil
+}
+
+// declare records the plural of a Kind given by the configuration or a CRD,
+// used when discovery can't resolve it
+func (r *resourceMapper) declare(gvk schema.GroupVersionKind, plural string) {
+	if plural == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.plurals[gvk.GroupKind()] = plural
+	if _, ok := r.kinds[plural]; !ok {
+		r.kinds[plural] = gvk.Kind
+	}
+}
+
+// ResourceType returns the resource type of gvk, e.g. ingresses for Ingress
+func (r *resourceMapper) ResourceType(gvk schema.GroupVersionKind) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.mapper != nil {
+		if mapping, err := r.mapper.RESTMapping(gvk.GroupKind()); err == nil {
+			return mapping.Resource.Resource
+		}
+	}
+	if plural, ok := r.plurals[gvk.GroupKind()]; ok {
+		return plural
+	}
+	return models.KindToResourceType(gvk.Kind)
+}
+
+// Kind returns the Kind of a resource type, e.g. Endpoints for endpoints.
+// Events are stored without their group, so a resource type served by
+// several groups resolves to the group discovery lists first, the core
+// group before all others.
+func (r *resourceMapper) Kind(resourceType string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.mapper != nil {
+		if gvk, err := r.mapper.KindFor(schema.GroupVersionResource{Resource: resourceType}); err == nil {
+			return gvk.Kind
+		}
+	}
+	if kind, ok := r.kinds[resourceType]; ok {
+		return kind
+	}
+	return models.ResourceTypeToKind(resourceType)
+}
@@ -0,0 +1,114 @@
+package watchers
+
+import (
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var fishGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Fish"}
+
+// newFakeDiscovery serves the core, apps and networking groups
+func newFakeDiscovery() *fakediscovery.FakeDiscovery {
+	return &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true},
+			{Name: "endpoints", Kind: "Endpoints", Namespaced: true},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true},
+		}},
+		{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{
+			{Name: "ingresses", Kind: "Ingress", Namespaced: true},
+		}},
+	}}}
+}
+
+// fishResources is a custom group whose plural no pluralization rule derives
+var fishResources = &metav1.APIResourceList{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{
+	{Name: "fish", Kind: "Fish", Namespaced: true},
+}}
+
+func TestResourceMapperDiscovery(t *testing.T) {
+	client := newFakeDiscovery()
+	client.Resources = append(client.Resources, fishResources)
+	mapper := newResourceMapper(client)
+	if err := mapper.refresh(); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	tests := []struct {
+		gvk          schema.GroupVersionKind
+		resourceType string
+	}{
+		{podGVK, "pods"},
+		{schema.GroupVersionKind{Version: "v1", Kind: "Endpoints"}, "endpoints"},
+		{schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, "deployments"},
+		{schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}, "ingresses"},
+		{fishGVK, "fish"},
+	}
+	for _, tt := range tests {
+		if got := mapper.ResourceType(tt.gvk); got != tt.resourceType {
+			t.Errorf("ResourceType(%s) = %q, want %q", tt.gvk, got, tt.resourceType)
+		}
+		if got := mapper.Kind(tt.resourceType); got != tt.gvk.Kind {
+			t.Errorf("Kind(%q) = %q, want %q", tt.resourceType, got, tt.gvk.Kind)
+		}
+	}
+}
+
+func TestResourceMapperRefreshesForNewCRD(t *testing.T) {
+	client := newFakeDiscovery()
+	mapper := newResourceMapper(client)
+	if err := mapper.refresh(); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if mapper.known(fishGVK) {
+		t.Fatal("expected Fish to be unknown before the CRD is served")
+	}
+
+	client.Resources = append(client.Resources, fishResources)
+	if err := mapper.refreshUnlessKnown(fishGVK); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if got := mapper.ResourceType(fishGVK); got != "fish" {
+		t.Errorf("expected fish after the refresh, got %q", got)
+	}
+}
+
+func TestResourceMapperFallback(t *testing.T) {
+	client := newFakeDiscovery()
+	client.PrependReactor("get", "group", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	mapper := newResourceMapper(client)
+	if err := mapper.refresh(); err == nil {
+		t.Fatal("expected the discovery error")
+	}
+
+	// Pluralization rules
+	if got := mapper.ResourceType(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}); got != "deployments" {
+		t.Errorf("expected deployments, got %q", got)
+	}
+	if got := mapper.Kind("ingresses"); got != "Ingress" {
+		t.Errorf("expected Ingress, got %q", got)
+	}
+
+	// Declared plurals win over the rules
+	mapper.declare(fishGVK, "fish")
+	if got := mapper.ResourceType(fishGVK); got != "fish" {
+		t.Errorf("expected the declared plural fish, got %q", got)
+	}
+	if got := mapper.Kind("fish"); got != "Fish" {
+		t.Errorf("expected Fish, got %q", got)
+	}
+
+	if err := newResourceMapper(nil).refresh(); !errors.Is(err, errNoDiscovery) {
+		t.Errorf("expected errNoDiscovery without a client, got %v", err)
+	}
+}
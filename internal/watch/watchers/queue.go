@@ -0,0 +1,137 @@
+package watchers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+)
+
+// queueBlockTimeout is how long the block policy waits for room in a full
+// queue before dropping the job
+const queueBlockTimeout = time.Second
+
+// workQueue runs the transform and store work of informer callbacks on a
+// fixed set of workers, so a slow write doesn't stall the informers. Jobs
+// with the same key always run on the same worker, in order, so the events
+// of an object are stored in the order they were received.
+type workQueue struct {
+	shards []chan func()
+	policy string
+
+	// mu keeps enqueues from sending to the shards while stop closes them
+	mu      sync.RWMutex
+	stopped bool
+	workers sync.WaitGroup
+}
+
+// newWorkQueue creates a queue of workers workers sharing size buffered jobs
+func newWorkQueue(workers, size int, policy string) *workQueue {
+	workers = max(workers, 1)
+	perWorker := max(size/workers, 1)
+
+	q := &workQueue{shards: make([]chan func(), workers), policy: policy}
+	for i := range q.shards {
+		q.shards[i] = make(chan func(), perWorker)
+	}
+	return q
+}
+
+// start runs the workers until the queue is stopped or ctx is cancelled.
+// Jobs still queued when ctx is cancelled are discarded; stop runs them.
+func (q *workQueue) start(ctx context.Context) {
+	for _, shard := range q.shards {
+		q.workers.Add(1)
+		go func(jobs <-chan func()) {
+			defer q.workers.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job, ok := <-jobs:
+					if !ok {
+						return
+					}
+					job()
+				}
+			}
+		}(shard)
+	}
+}
+
+// stop rejects new jobs and waits for the workers to run the queued ones.
+// It returns an error when ctx ends first, leaving the rest to run in the
+// background.
+func (q *workQueue) stop(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.stopped {
+		q.stopped = true
+		for _, shard := range q.shards {
+			close(shard)
+		}
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d queued events not stored: %w", q.len(), ctx.Err())
+	}
+}
+
+// isStopped reports whether stop was called
+func (q *workQueue) isStopped() bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.stopped
+}
+
+// len returns the number of jobs waiting for a worker
+func (q *workQueue) len() int {
+	n := 0
+	for _, shard := range q.shards {
+		n += len(shard)
+	}
+	return n
+}
+
+// enqueue hands job to the worker of key. It reports false when the job was
+// dropped because the worker's queue stayed full or the queue was stopped.
+func (q *workQueue) enqueue(key string, job func()) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.stopped {
+		return false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	shard := q.shards[h.Sum32()%uint32(len(q.shards))]
+
+	select {
+	case shard <- job:
+		return true
+	default:
+	}
+	if q.policy == config.QueueFullDrop {
+		return false
+	}
+
+	timer := time.NewTimer(queueBlockTimeout)
+	defer timer.Stop()
+	select {
+	case shard <- job:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -0,0 +1,77 @@
+package watchers
+
+import (
+	"sort"
+	"sync"
+)
+
+// watcherRegistry is the set of active watchers keyed by GVK. It is safe for
+// concurrent use: the CRD informer registers watchers while API handlers list
+// them.
+type watcherRegistry struct {
+	mu      sync.RWMutex
+	watched map[string]WatchedResource
+}
+
+// newWatcherRegistry creates an empty registry
+func newWatcherRegistry() *watcherRegistry {
+	return &watcherRegistry{
+		watched: make(map[string]WatchedResource),
+	}
+}
+
+// add registers resource under key and reports whether it was added. An
+// already registered key keeps its original entry, including its Since time.
+func (r *watcherRegistry) add(key string, resource WatchedResource) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.watched[key]; ok {
+		return false
+	}
+	r.watched[key] = resource
+	return true
+}
+
+// remove unregisters key, e.g. when starting its watcher failed
+func (r *watcherRegistry) remove(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.watched, key)
+}
+
+// has reports whether key is registered
+func (r *watcherRegistry) has(key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.watched[key]
+	return ok
+}
+
+// list returns the registered resources sorted by resource type and version
+func (r *watcherRegistry) list() []WatchedResource {
+	r.mu.RLock()
+	resources := make([]WatchedResource, 0, len(r.watched))
+	for _, resource := range r.watched {
+		resources = append(resources, resource)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].ResourceType != resources[j].ResourceType {
+			return resources[i].ResourceType < resources[j].ResourceType
+		}
+		return resources[i].Version < resources[j].Version
+	})
+	return resources
+}
+
+// len returns the number of registered resources
+func (r *watcherRegistry) len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.watched)
+}
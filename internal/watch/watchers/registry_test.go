@@ -0,0 +1,58 @@
+package watchers
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatcherRegistryKeepsFirstEntry(t *testing.T) {
+	r := newWatcherRegistry()
+	first := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	if !r.add("v1, Kind=Pod", WatchedResource{Kind: "Pod", ResourceType: "pods", Since: first}) {
+		t.Fatal("expected first add to succeed")
+	}
+	if r.add("v1, Kind=Pod", WatchedResource{Kind: "Pod", ResourceType: "pods", Since: first.Add(time.Hour)}) {
+		t.Error("expected duplicate add to be rejected")
+	}
+
+	resources := r.list()
+	if len(resources) != 1 || !resources[0].Since.Equal(first) {
+		t.Errorf("expected the original entry to be kept, got %+v", resources)
+	}
+
+	r.remove("v1, Kind=Pod")
+	if r.has("v1, Kind=Pod") {
+		t.Error("expected entry to be removed")
+	}
+}
+
+// TestWatcherRegistryConcurrentAccess registers watchers while listing them;
+// run with -race to detect unsynchronized access.
+func TestWatcherRegistryConcurrentAccess(t *testing.T) {
+	r := newWatcherRegistry()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			key := fmt.Sprintf("example.com/v1, Kind=Kind%d", i)
+			r.add(key, WatchedResource{Group: "example.com", Version: "v1", ResourceType: fmt.Sprintf("kind%ds", i)})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			_ = r.list()
+			_ = r.has("example.com/v1, Kind=Kind0")
+		}
+	}()
+	wg.Wait()
+
+	if got := len(r.list()); got != 500 {
+		t.Errorf("expected 500 registered watchers, got %d", got)
+	}
+}
@@ -0,0 +1,135 @@
+package watchers
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// objectSelector selects the objects matching both the label and the field
+// selector of a configured resource
+type objectSelector struct {
+	labels labels.Selector
+	fields fields.Selector
+}
+
+// parseObjectSelector parses the label and field selector of resource
+func parseObjectSelector(resource config.ResourceWatch) (objectSelector, error) {
+	labelSelector, err := labels.Parse(resource.LabelSelector)
+	if err != nil {
+		return objectSelector{}, fmt.Errorf("invalid labelSelector %q: %w", resource.LabelSelector, err)
+	}
+	fieldSelector, err := fields.ParseSelector(resource.FieldSelector)
+	if err != nil {
+		return objectSelector{}, fmt.Errorf("invalid fieldSelector %q: %w", resource.FieldSelector, err)
+	}
+	return objectSelector{labels: labelSelector, fields: fieldSelector}, nil
+}
+
+// matches reports whether obj matches both selectors
+func (s objectSelector) matches(obj *unstructured.Unstructured) bool {
+	return s.labels.Matches(labels.Set(obj.GetLabels())) && s.fields.Matches(objectFields{obj})
+}
+
+// objectFields exposes the string fields of an unstructured object to field
+// selectors by their dotted paths, e.g. "status.phase". The API server only
+// supports a few fields per type; this evaluator resolves any string field.
+// Missing and non-string fields read as empty.
+type objectFields struct {
+	obj *unstructured.Unstructured
+}
+
+// Has reports whether field is a string field of the object
+func (f objectFields) Has(field string) bool {
+	_, found, err := unstructured.NestedString(f.obj.Object, strings.Split(field, ".")...)
+	return found && err == nil
+}
+
+// Get returns the value of field, or "" when it is not a string field
+func (f objectFields) Get(field string) string {
+	value, _, _ := unstructured.NestedString(f.obj.Object, strings.Split(field, ".")...)
+	return value
+}
+
+// objectSelectors holds the label and field selectors of the watched
+// resource types. Informers are shared per type, so the selectors are
+// applied when handling events. A type configured several times records
+// objects matching any of its entries; an entry without selectors records
+// every object.
+type objectSelectors struct {
+	mu     sync.RWMutex
+	byKind map[schema.GroupVersionKind][]objectSelector
+}
+
+func newObjectSelectors() *objectSelectors {
+	return &objectSelectors{byKind: make(map[schema.GroupVersionKind][]objectSelector)}
+}
+
+// add registers the selectors of resource for gvk. Empty selectors select
+// everything.
+func (s *objectSelectors) add(gvk schema.GroupVersionKind, resource config.ResourceWatch) error {
+	parsed, err := parseObjectSelector(resource)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byKind[gvk] = append(s.byKind[gvk], parsed)
+	return nil
+}
+
+// replace sets the selectors of gvk to those of resources, e.g. after a
+// configuration reload. The selectors stay unchanged if any of them is
+// invalid.
+func (s *objectSelectors) replace(gvk schema.GroupVersionKind, resources []config.ResourceWatch) error {
+	parsed := make([]objectSelector, 0, len(resources))
+	for _, resource := range resources {
+		p, err := parseObjectSelector(resource)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, p)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byKind[gvk] = parsed
+	return nil
+}
+
+// remove drops the selectors of gvk, e.g. when it is no longer watched
+func (s *objectSelectors) remove(gvk schema.GroupVersionKind) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.byKind, gvk)
+}
+
+// matches reports whether any of objs is selected for gvk. Types without
+// registered selectors select everything.
+func (s *objectSelectors) matches(gvk schema.GroupVersionKind, objs ...*unstructured.Unstructured) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	selectors, ok := s.byKind[gvk]
+	if !ok {
+		return true
+	}
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		for _, selector := range selectors {
+			if selector.matches(obj) {
+				return true
+			}
+		}
+	}
+	return false
+}
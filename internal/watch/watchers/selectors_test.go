@@ -0,0 +1,163 @@
+package watchers
+
+import (
+	"testing"
+
+	"github.com/moritz/mcp-toolkit/internal/watch/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func labeledPod(name string, labels map[string]string) *unstructured.Unstructured {
+	pod := testPod("1", "web:1", "Running")
+	pod.SetName(name)
+	pod.SetLabels(labels)
+	return pod
+}
+
+func TestLabelSelectorsMatchAny(t *testing.T) {
+	s := newObjectSelectors()
+	if err := s.add(podGVK, config.ResourceWatch{LabelSelector: "tier=critical"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := s.add(podGVK, config.ResourceWatch{LabelSelector: "team in (payments,search)"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{"none", map[string]string{"tier": "batch", "team": "infra"}, false},
+		{"unlabeled", nil, false},
+		{"first selector", map[string]string{"tier": "critical"}, true},
+		{"second selector", map[string]string{"team": "search"}, true},
+		{"both selectors", map[string]string{"tier": "critical", "team": "payments"}, true},
+	}
+	for _, tt := range tests {
+		if got := s.matches(podGVK, labeledPod(tt.name, tt.labels)); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// Kinds without selectors are not filtered
+	deployment := labeledPod("web", nil)
+	deployment.SetKind("Deployment")
+	if !s.matches(deployment.GroupVersionKind(), deployment) {
+		t.Error("expected a kind without selectors to match")
+	}
+}
+
+func TestLabelSelectorsEmptySelectsEverything(t *testing.T) {
+	s := newObjectSelectors()
+	if err := s.add(podGVK, config.ResourceWatch{LabelSelector: "tier=critical"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := s.add(podGVK, config.ResourceWatch{LabelSelector: ""}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if !s.matches(podGVK, labeledPod("web", nil)) {
+		t.Error("expected an entry without selector to select every pod")
+	}
+}
+
+func TestLabelSelectorsInvalid(t *testing.T) {
+	if err := newObjectSelectors().add(podGVK, config.ResourceWatch{LabelSelector: "tier in (critical"}); err == nil {
+		t.Error("expected an invalid selector to be rejected")
+	}
+}
+
+func TestHandlersApplyLabelSelectors(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{})
+	if err := m.selectors.add(podGVK, config.ResourceWatch{LabelSelector: "tier=critical"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := m.selectors.add(podGVK, config.ResourceWatch{LabelSelector: "team=payments"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	m.handleAdd(podGVK, labeledPod("batch", map[string]string{"tier": "batch"}))
+	if n := storedEvents(t, store); n != 0 {
+		t.Fatalf("expected unselected pod to be ignored, got %d events", n)
+	}
+
+	m.handleAdd(podGVK, labeledPod("api", map[string]string{"tier": "critical"}))
+	m.handleAdd(podGVK, labeledPod("checkout", map[string]string{"tier": "critical", "team": "payments"}))
+	if n := storedEvents(t, store); n != 2 {
+		t.Fatalf("expected 2 selected pods to be stored, got %d events", n)
+	}
+
+	// An update moving a pod out of the selection is still recorded, later
+	// changes to it are not
+	old := labeledPod("api", map[string]string{"tier": "critical"})
+	moved := labeledPod("api", map[string]string{"tier": "batch"})
+	moved.SetResourceVersion("2")
+	m.handleUpdate(podGVK, old, moved)
+	if n := storedEvents(t, store); n != 3 {
+		t.Fatalf("expected the update leaving the selection to be stored, got %d events", n)
+	}
+	m.handleDelete(podGVK, moved)
+	if n := storedEvents(t, store); n != 3 {
+		t.Errorf("expected the unselected delete to be ignored, got %d events", n)
+	}
+}
+
+func TestFieldSelectors(t *testing.T) {
+	pending := testPod("1", "web:1", "Pending")
+	running := testPod("1", "web:1", "Running")
+	warning := &unstructured.Unstructured{Object: map[string]any{
+		"type":           "Warning",
+		"reason":         "BackOff",
+		"involvedObject": map[string]any{"kind": "Pod", "name": "web"},
+	}}
+
+	tests := []struct {
+		selector string
+		obj      *unstructured.Unstructured
+		want     bool
+	}{
+		{"status.phase=Pending", pending, true},
+		{"status.phase=Pending", running, false},
+		{"status.phase==Running", running, true},
+		{"status.phase!=Running", pending, true},
+		{"status.phase!=Running", running, false},
+		{"metadata.name=web,status.phase=Running", running, true},
+		{"metadata.name=api,status.phase=Running", running, false},
+		{"type=Warning,involvedObject.kind=Pod", warning, true},
+		{"involvedObject.kind!=Pod", warning, false},
+		// Missing fields read as empty
+		{"status.reason!=Evicted", running, true},
+		{"status.reason=Evicted", running, false},
+		// So do non-string fields
+		{"spec.containers=web", running, false},
+	}
+	for _, tt := range tests {
+		s := newObjectSelectors()
+		if err := s.add(podGVK, config.ResourceWatch{FieldSelector: tt.selector}); err != nil {
+			t.Fatalf("add(%q) failed: %v", tt.selector, err)
+		}
+		if got := s.matches(podGVK, tt.obj); got != tt.want {
+			t.Errorf("%q on %s: expected %v, got %v", tt.selector, tt.obj.Object, tt.want, got)
+		}
+	}
+}
+
+func TestLabelAndFieldSelectorsCombined(t *testing.T) {
+	m, store := newTestManager(t, &config.Config{})
+	if err := m.selectors.add(podGVK, config.ResourceWatch{LabelSelector: "tier=critical", FieldSelector: "status.phase!=Running"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	failing := labeledPod("api", map[string]string{"tier": "critical"})
+	failing.Object["status"] = map[string]any{"phase": "Failed"}
+	m.handleAdd(podGVK, labeledPod("web", map[string]string{"tier": "critical"}))
+	m.handleAdd(podGVK, labeledPod("batch", map[string]string{"tier": "batch"}))
+	m.handleAdd(podGVK, failing)
+	if n := storedEvents(t, store); n != 1 {
+		t.Errorf("expected only the failed critical pod to be stored, got %d events", n)
+	}
+
+	if err := newObjectSelectors().add(podGVK, config.ResourceWatch{FieldSelector: "status.phase"}); err == nil {
+		t.Error("expected an invalid field selector to be rejected")
+	}
+}